@@ -4,6 +4,7 @@ import (
 	"net/http"
 	"strconv"
 
+	"github.com/lemonoa/LemonOA-Go/middleware"
 	"github.com/lemonoa/LemonOA-Go/model"
 	"github.com/lemonoa/LemonOA-Go/service"
 
@@ -23,6 +24,7 @@ func NewAddressBookController(addressBookService *service.AddressBookService) *A
 // RegisterRoutes 注册路由
 func (c *AddressBookController) RegisterRoutes(r *gin.Engine) {
 	api := r.Group("/api/address-book")
+	api.Use(middleware.JWT())
 	{
 		api.GET("/employees", c.GetEmployeeList)
 		api.POST("/employees", c.CreateEmployee)
@@ -42,12 +44,13 @@ func (c *AddressBookController) GetEmployeeList(ctx *gin.Context) {
 	page, _ := strconv.Atoi(ctx.DefaultQuery("page", "1"))
 	pageSize, _ := strconv.Atoi(ctx.DefaultQuery("page_size", "10"))
 
-	employees, total, err := c.addressBookService.GetEmployeeList(uint(departmentID), page, pageSize)
+	employees, total, err := c.addressBookService.WithContext(ctx.Request.Context()).GetEmployeeList(uint(departmentID), page, pageSize)
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 
+	middleware.MaskSensitive(ctx, employees)
 	ctx.JSON(http.StatusOK, gin.H{
 		"data":  employees,
 		"total": total,
@@ -58,12 +61,12 @@ func (c *AddressBookController) GetEmployeeList(ctx *gin.Context) {
 func (c *AddressBookController) CreateEmployee(ctx *gin.Context) {
 	var employee model.Employee
 	if err := ctx.ShouldBindJSON(&employee); err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		respondError(ctx, http.StatusBadRequest, err)
 		return
 	}
 
-	if err := c.addressBookService.CreateEmployee(&employee); err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	if err := c.addressBookService.WithContext(ctx.Request.Context()).CreateEmployee(&employee); err != nil {
+		respondError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -75,13 +78,13 @@ func (c *AddressBookController) UpdateEmployee(ctx *gin.Context) {
 	id, _ := strconv.ParseUint(ctx.Param("id"), 10, 32)
 	var employee model.Employee
 	if err := ctx.ShouldBindJSON(&employee); err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		respondError(ctx, http.StatusBadRequest, err)
 		return
 	}
 
 	employee.ID = uint(id)
-	if err := c.addressBookService.UpdateEmployee(&employee); err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	if err := c.addressBookService.WithContext(ctx.Request.Context()).UpdateEmployee(&employee); err != nil {
+		respondError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -91,8 +94,8 @@ func (c *AddressBookController) UpdateEmployee(ctx *gin.Context) {
 // DeleteEmployee 删除员工
 func (c *AddressBookController) DeleteEmployee(ctx *gin.Context) {
 	id, _ := strconv.ParseUint(ctx.Param("id"), 10, 32)
-	if err := c.addressBookService.DeleteEmployee(uint(id)); err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	if err := c.addressBookService.WithContext(ctx.Request.Context()).DeleteEmployee(uint(id)); err != nil {
+		respondError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -101,9 +104,9 @@ func (c *AddressBookController) DeleteEmployee(ctx *gin.Context) {
 
 // GetDepartmentList 获取部门列表
 func (c *AddressBookController) GetDepartmentList(ctx *gin.Context) {
-	departments, err := c.addressBookService.GetDepartmentList()
+	departments, err := c.addressBookService.WithContext(ctx.Request.Context()).GetDepartmentList()
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -114,12 +117,12 @@ func (c *AddressBookController) GetDepartmentList(ctx *gin.Context) {
 func (c *AddressBookController) CreateDepartment(ctx *gin.Context) {
 	var department model.Department
 	if err := ctx.ShouldBindJSON(&department); err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		respondError(ctx, http.StatusBadRequest, err)
 		return
 	}
 
-	if err := c.addressBookService.CreateDepartment(&department); err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	if err := c.addressBookService.WithContext(ctx.Request.Context()).CreateDepartment(&department); err != nil {
+		respondError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -131,13 +134,13 @@ func (c *AddressBookController) UpdateDepartment(ctx *gin.Context) {
 	id, _ := strconv.ParseUint(ctx.Param("id"), 10, 32)
 	var department model.Department
 	if err := ctx.ShouldBindJSON(&department); err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		respondError(ctx, http.StatusBadRequest, err)
 		return
 	}
 
 	department.ID = uint(id)
-	if err := c.addressBookService.UpdateDepartment(&department); err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	if err := c.addressBookService.WithContext(ctx.Request.Context()).UpdateDepartment(&department); err != nil {
+		respondError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -147,8 +150,8 @@ func (c *AddressBookController) UpdateDepartment(ctx *gin.Context) {
 // DeleteDepartment 删除部门
 func (c *AddressBookController) DeleteDepartment(ctx *gin.Context) {
 	id, _ := strconv.ParseUint(ctx.Param("id"), 10, 32)
-	if err := c.addressBookService.DeleteDepartment(uint(id)); err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	if err := c.addressBookService.WithContext(ctx.Request.Context()).DeleteDepartment(uint(id)); err != nil {
+		respondError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -7,6 +7,8 @@ import (
 	"github.com/lemonoa/LemonOA-Go/model"
 	"github.com/lemonoa/LemonOA-Go/service"
 
+	"github.com/lemonoa/LemonOA-Go/middleware"
+
 	"github.com/gin-gonic/gin"
 )
 
@@ -23,6 +25,7 @@ func NewApprovalController(approvalService *service.ApprovalService) *ApprovalCo
 // RegisterRoutes 注册路由
 func (c *ApprovalController) RegisterRoutes(r *gin.Engine) {
 	api := r.Group("/api/approvals")
+	api.Use(middleware.JWT(), middleware.DataScope())
 	{
 		// 审批类型管理
 		api.GET("/types", c.GetApprovalTypeList)
@@ -55,9 +58,9 @@ func (c *ApprovalController) RegisterRoutes(r *gin.Engine) {
 
 // GetApprovalTypeList 获取审批类型列表
 func (c *ApprovalController) GetApprovalTypeList(ctx *gin.Context) {
-	types, err := c.approvalService.GetApprovalTypeList()
+	types, err := c.approvalService.WithContext(ctx.Request.Context()).GetApprovalTypeList()
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -68,12 +71,12 @@ func (c *ApprovalController) GetApprovalTypeList(ctx *gin.Context) {
 func (c *ApprovalController) CreateApprovalType(ctx *gin.Context) {
 	var approvalType model.ApprovalType
 	if err := ctx.ShouldBindJSON(&approvalType); err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		respondError(ctx, http.StatusBadRequest, err)
 		return
 	}
 
-	if err := c.approvalService.CreateApprovalType(&approvalType); err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	if err := c.approvalService.WithContext(ctx.Request.Context()).CreateApprovalType(&approvalType); err != nil {
+		respondError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -85,13 +88,13 @@ func (c *ApprovalController) UpdateApprovalType(ctx *gin.Context) {
 	id, _ := strconv.ParseUint(ctx.Param("id"), 10, 32)
 	var approvalType model.ApprovalType
 	if err := ctx.ShouldBindJSON(&approvalType); err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		respondError(ctx, http.StatusBadRequest, err)
 		return
 	}
 
 	approvalType.ID = uint(id)
-	if err := c.approvalService.UpdateApprovalType(&approvalType); err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	if err := c.approvalService.WithContext(ctx.Request.Context()).UpdateApprovalType(&approvalType); err != nil {
+		respondError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -101,8 +104,8 @@ func (c *ApprovalController) UpdateApprovalType(ctx *gin.Context) {
 // DeleteApprovalType 删除审批类型
 func (c *ApprovalController) DeleteApprovalType(ctx *gin.Context) {
 	id, _ := strconv.ParseUint(ctx.Param("id"), 10, 32)
-	if err := c.approvalService.DeleteApprovalType(uint(id)); err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	if err := c.approvalService.WithContext(ctx.Request.Context()).DeleteApprovalType(uint(id)); err != nil {
+		respondError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -112,9 +115,9 @@ func (c *ApprovalController) DeleteApprovalType(ctx *gin.Context) {
 // GetApprovalFlowList 获取审批流程列表
 func (c *ApprovalController) GetApprovalFlowList(ctx *gin.Context) {
 	typeID, _ := strconv.ParseUint(ctx.Query("type_id"), 10, 32)
-	flows, err := c.approvalService.GetApprovalFlowList(uint(typeID))
+	flows, err := c.approvalService.WithContext(ctx.Request.Context()).GetApprovalFlowList(uint(typeID))
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -125,12 +128,12 @@ func (c *ApprovalController) GetApprovalFlowList(ctx *gin.Context) {
 func (c *ApprovalController) CreateApprovalFlow(ctx *gin.Context) {
 	var flow model.ApprovalFlow
 	if err := ctx.ShouldBindJSON(&flow); err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		respondError(ctx, http.StatusBadRequest, err)
 		return
 	}
 
-	if err := c.approvalService.CreateApprovalFlow(&flow); err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	if err := c.approvalService.WithContext(ctx.Request.Context()).CreateApprovalFlow(&flow); err != nil {
+		respondError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -142,13 +145,13 @@ func (c *ApprovalController) UpdateApprovalFlow(ctx *gin.Context) {
 	id, _ := strconv.ParseUint(ctx.Param("id"), 10, 32)
 	var flow model.ApprovalFlow
 	if err := ctx.ShouldBindJSON(&flow); err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		respondError(ctx, http.StatusBadRequest, err)
 		return
 	}
 
 	flow.ID = uint(id)
-	if err := c.approvalService.UpdateApprovalFlow(&flow); err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	if err := c.approvalService.WithContext(ctx.Request.Context()).UpdateApprovalFlow(&flow); err != nil {
+		respondError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -158,8 +161,8 @@ func (c *ApprovalController) UpdateApprovalFlow(ctx *gin.Context) {
 // DeleteApprovalFlow 删除审批流程
 func (c *ApprovalController) DeleteApprovalFlow(ctx *gin.Context) {
 	id, _ := strconv.ParseUint(ctx.Param("id"), 10, 32)
-	if err := c.approvalService.DeleteApprovalFlow(uint(id)); err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	if err := c.approvalService.WithContext(ctx.Request.Context()).DeleteApprovalFlow(uint(id)); err != nil {
+		respondError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -169,9 +172,9 @@ func (c *ApprovalController) DeleteApprovalFlow(ctx *gin.Context) {
 // GetApprovalNodeList 获取审批节点列表
 func (c *ApprovalController) GetApprovalNodeList(ctx *gin.Context) {
 	flowID, _ := strconv.ParseUint(ctx.Query("flow_id"), 10, 32)
-	nodes, err := c.approvalService.GetApprovalNodeList(uint(flowID))
+	nodes, err := c.approvalService.WithContext(ctx.Request.Context()).GetApprovalNodeList(uint(flowID))
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -182,12 +185,12 @@ func (c *ApprovalController) GetApprovalNodeList(ctx *gin.Context) {
 func (c *ApprovalController) CreateApprovalNode(ctx *gin.Context) {
 	var node model.ApprovalNode
 	if err := ctx.ShouldBindJSON(&node); err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		respondError(ctx, http.StatusBadRequest, err)
 		return
 	}
 
-	if err := c.approvalService.CreateApprovalNode(&node); err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	if err := c.approvalService.WithContext(ctx.Request.Context()).CreateApprovalNode(&node); err != nil {
+		respondError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -199,13 +202,13 @@ func (c *ApprovalController) UpdateApprovalNode(ctx *gin.Context) {
 	id, _ := strconv.ParseUint(ctx.Param("id"), 10, 32)
 	var node model.ApprovalNode
 	if err := ctx.ShouldBindJSON(&node); err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		respondError(ctx, http.StatusBadRequest, err)
 		return
 	}
 
 	node.ID = uint(id)
-	if err := c.approvalService.UpdateApprovalNode(&node); err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	if err := c.approvalService.WithContext(ctx.Request.Context()).UpdateApprovalNode(&node); err != nil {
+		respondError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -215,8 +218,8 @@ func (c *ApprovalController) UpdateApprovalNode(ctx *gin.Context) {
 // DeleteApprovalNode 删除审批节点
 func (c *ApprovalController) DeleteApprovalNode(ctx *gin.Context) {
 	id, _ := strconv.ParseUint(ctx.Param("id"), 10, 32)
-	if err := c.approvalService.DeleteApprovalNode(uint(id)); err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	if err := c.approvalService.WithContext(ctx.Request.Context()).DeleteApprovalNode(uint(id)); err != nil {
+		respondError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -225,15 +228,14 @@ func (c *ApprovalController) DeleteApprovalNode(ctx *gin.Context) {
 
 // GetApprovalRecordList 获取审批记录列表
 func (c *ApprovalController) GetApprovalRecordList(ctx *gin.Context) {
-	// TODO: 从JWT中获取userID
-	userID := uint(1)
+	userID := middleware.CurrentUserID(ctx)
 	status, _ := strconv.Atoi(ctx.Query("status"))
 	page, _ := strconv.Atoi(ctx.DefaultQuery("page", "1"))
 	pageSize, _ := strconv.Atoi(ctx.DefaultQuery("page_size", "10"))
 
-	records, total, err := c.approvalService.GetApprovalRecordList(userID, status, page, pageSize)
+	records, total, err := c.approvalService.WithContext(ctx.Request.Context()).GetApprovalRecordList(userID, status, page, pageSize, middleware.CurrentDataScope(ctx))
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -247,15 +249,15 @@ func (c *ApprovalController) GetApprovalRecordList(ctx *gin.Context) {
 func (c *ApprovalController) CreateApprovalRecord(ctx *gin.Context) {
 	var record model.ApprovalRecord
 	if err := ctx.ShouldBindJSON(&record); err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		respondError(ctx, http.StatusBadRequest, err)
 		return
 	}
 
-	// TODO: 从JWT中获取userID
-	record.ApplicantID = uint(1)
+	// 申请人为当前登录用户
+	record.ApplicantID = middleware.CurrentUserID(ctx)
 
-	if err := c.approvalService.CreateApprovalRecord(&record); err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	if err := c.approvalService.WithContext(ctx.Request.Context()).CreateApprovalRecord(&record); err != nil {
+		respondError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -270,15 +272,14 @@ func (c *ApprovalController) ApproveRecord(ctx *gin.Context) {
 		Comment string `json:"comment"`
 	}
 	if err := ctx.ShouldBindJSON(&req); err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		respondError(ctx, http.StatusBadRequest, err)
 		return
 	}
 
-	// TODO: 从JWT中获取userID
-	approverID := uint(1)
+	approverID := middleware.CurrentUserID(ctx)
 
-	if err := c.approvalService.ApproveRecord(uint(id), req.NodeID, approverID, req.Comment); err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	if err := c.approvalService.WithContext(ctx.Request.Context()).ApproveRecord(uint(id), req.NodeID, approverID, req.Comment); err != nil {
+		respondError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -293,15 +294,14 @@ func (c *ApprovalController) RejectRecord(ctx *gin.Context) {
 		Comment string `json:"comment" binding:"required"`
 	}
 	if err := ctx.ShouldBindJSON(&req); err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		respondError(ctx, http.StatusBadRequest, err)
 		return
 	}
 
-	// TODO: 从JWT中获取userID
-	approverID := uint(1)
+	approverID := middleware.CurrentUserID(ctx)
 
-	if err := c.approvalService.RejectRecord(uint(id), req.NodeID, approverID, req.Comment); err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	if err := c.approvalService.WithContext(ctx.Request.Context()).RejectRecord(uint(id), req.NodeID, approverID, req.Comment); err != nil {
+		respondError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -310,14 +310,13 @@ func (c *ApprovalController) RejectRecord(ctx *gin.Context) {
 
 // GetPendingApprovalList 获取待审批列表
 func (c *ApprovalController) GetPendingApprovalList(ctx *gin.Context) {
-	// TODO: 从JWT中获取userID
-	approverID := uint(1)
+	approverID := middleware.CurrentUserID(ctx)
 	page, _ := strconv.Atoi(ctx.DefaultQuery("page", "1"))
 	pageSize, _ := strconv.Atoi(ctx.DefaultQuery("page_size", "10"))
 
-	records, total, err := c.approvalService.GetPendingApprovalList(approverID, page, pageSize)
+	records, total, err := c.approvalService.WithContext(ctx.Request.Context()).GetPendingApprovalList(approverID, page, pageSize)
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -7,6 +7,8 @@ import (
 	"github.com/lemonoa/LemonOA-Go/model"
 	"github.com/lemonoa/LemonOA-Go/service"
 
+	"github.com/lemonoa/LemonOA-Go/middleware"
+
 	"github.com/gin-gonic/gin"
 )
 
@@ -23,6 +25,7 @@ func NewAssetController(assetService *service.AssetService) *AssetController {
 // RegisterRoutes 注册路由
 func (c *AssetController) RegisterRoutes(r *gin.Engine) {
 	api := r.Group("/api/assets")
+	api.Use(middleware.JWT(), middleware.DataScope())
 	{
 		// 资产管理
 		api.GET("", c.GetAssetList)
@@ -67,9 +70,9 @@ func (c *AssetController) GetAssetList(ctx *gin.Context) {
 	page, _ := strconv.Atoi(ctx.DefaultQuery("page", "1"))
 	pageSize, _ := strconv.Atoi(ctx.DefaultQuery("page_size", "10"))
 
-	assets, total, err := c.assetService.GetAssetList(uint(categoryID), uint(brandID), status, keyword, page, pageSize)
+	assets, total, err := c.assetService.WithContext(ctx.Request.Context()).GetAssetList(uint(categoryID), uint(brandID), status, keyword, page, pageSize, middleware.CurrentDataScope(ctx))
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -82,9 +85,9 @@ func (c *AssetController) GetAssetList(ctx *gin.Context) {
 // GetAssetByID 根据ID获取资产
 func (c *AssetController) GetAssetByID(ctx *gin.Context) {
 	id, _ := strconv.ParseUint(ctx.Param("id"), 10, 32)
-	asset, err := c.assetService.GetAssetByID(uint(id))
+	asset, err := c.assetService.WithContext(ctx.Request.Context()).GetAssetByID(uint(id), middleware.CurrentDataScope(ctx))
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -95,15 +98,12 @@ func (c *AssetController) GetAssetByID(ctx *gin.Context) {
 func (c *AssetController) CreateAsset(ctx *gin.Context) {
 	var asset model.Asset
 	if err := ctx.ShouldBindJSON(&asset); err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		respondError(ctx, http.StatusBadRequest, err)
 		return
 	}
 
-	// TODO: 从JWT中获取当前用户ID
-	asset.CreatedBy = uint(1)
-
-	if err := c.assetService.CreateAsset(&asset); err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	if err := c.assetService.WithContext(ctx.Request.Context()).CreateAsset(&asset); err != nil {
+		respondError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -115,13 +115,13 @@ func (c *AssetController) UpdateAsset(ctx *gin.Context) {
 	id, _ := strconv.ParseUint(ctx.Param("id"), 10, 32)
 	var asset model.Asset
 	if err := ctx.ShouldBindJSON(&asset); err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		respondError(ctx, http.StatusBadRequest, err)
 		return
 	}
 
 	asset.ID = uint(id)
-	if err := c.assetService.UpdateAsset(&asset); err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	if err := c.assetService.WithContext(ctx.Request.Context()).UpdateAsset(&asset); err != nil {
+		respondError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -131,8 +131,8 @@ func (c *AssetController) UpdateAsset(ctx *gin.Context) {
 // DeleteAsset 删除资产
 func (c *AssetController) DeleteAsset(ctx *gin.Context) {
 	id, _ := strconv.ParseUint(ctx.Param("id"), 10, 32)
-	if err := c.assetService.DeleteAsset(uint(id)); err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	if err := c.assetService.WithContext(ctx.Request.Context()).DeleteAsset(uint(id)); err != nil {
+		respondError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -146,9 +146,9 @@ func (c *AssetController) GetAssetRepairList(ctx *gin.Context) {
 	page, _ := strconv.Atoi(ctx.DefaultQuery("page", "1"))
 	pageSize, _ := strconv.Atoi(ctx.DefaultQuery("page_size", "10"))
 
-	repairs, total, err := c.assetService.GetAssetRepairList(uint(assetID), status, page, pageSize)
+	repairs, total, err := c.assetService.WithContext(ctx.Request.Context()).GetAssetRepairList(uint(assetID), status, page, pageSize, middleware.CurrentDataScope(ctx))
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -161,9 +161,9 @@ func (c *AssetController) GetAssetRepairList(ctx *gin.Context) {
 // GetAssetRepairByID 根据ID获取资产维修记录
 func (c *AssetController) GetAssetRepairByID(ctx *gin.Context) {
 	id, _ := strconv.ParseUint(ctx.Param("id"), 10, 32)
-	repair, err := c.assetService.GetAssetRepairByID(uint(id))
+	repair, err := c.assetService.WithContext(ctx.Request.Context()).GetAssetRepairByID(uint(id), middleware.CurrentDataScope(ctx))
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -174,15 +174,12 @@ func (c *AssetController) GetAssetRepairByID(ctx *gin.Context) {
 func (c *AssetController) CreateAssetRepair(ctx *gin.Context) {
 	var repair model.AssetRepair
 	if err := ctx.ShouldBindJSON(&repair); err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		respondError(ctx, http.StatusBadRequest, err)
 		return
 	}
 
-	// TODO: 从JWT中获取当前用户ID
-	repair.CreatedBy = uint(1)
-
-	if err := c.assetService.CreateAssetRepair(&repair); err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	if err := c.assetService.WithContext(ctx.Request.Context()).CreateAssetRepair(&repair); err != nil {
+		respondError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -194,13 +191,13 @@ func (c *AssetController) UpdateAssetRepair(ctx *gin.Context) {
 	id, _ := strconv.ParseUint(ctx.Param("id"), 10, 32)
 	var repair model.AssetRepair
 	if err := ctx.ShouldBindJSON(&repair); err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		respondError(ctx, http.StatusBadRequest, err)
 		return
 	}
 
 	repair.ID = uint(id)
-	if err := c.assetService.UpdateAssetRepair(&repair); err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	if err := c.assetService.WithContext(ctx.Request.Context()).UpdateAssetRepair(&repair); err != nil {
+		respondError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -210,8 +207,8 @@ func (c *AssetController) UpdateAssetRepair(ctx *gin.Context) {
 // DeleteAssetRepair 删除资产维修记录
 func (c *AssetController) DeleteAssetRepair(ctx *gin.Context) {
 	id, _ := strconv.ParseUint(ctx.Param("id"), 10, 32)
-	if err := c.assetService.DeleteAssetRepair(uint(id)); err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	if err := c.assetService.WithContext(ctx.Request.Context()).DeleteAssetRepair(uint(id)); err != nil {
+		respondError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -221,8 +218,8 @@ func (c *AssetController) DeleteAssetRepair(ctx *gin.Context) {
 // CompleteAssetRepair 完成资产维修
 func (c *AssetController) CompleteAssetRepair(ctx *gin.Context) {
 	id, _ := strconv.ParseUint(ctx.Param("id"), 10, 32)
-	if err := c.assetService.CompleteAssetRepair(uint(id)); err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	if err := c.assetService.WithContext(ctx.Request.Context()).CompleteAssetRepair(uint(id)); err != nil {
+		respondError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -237,9 +234,9 @@ func (c *AssetController) GetAssetBorrowList(ctx *gin.Context) {
 	page, _ := strconv.Atoi(ctx.DefaultQuery("page", "1"))
 	pageSize, _ := strconv.Atoi(ctx.DefaultQuery("page_size", "10"))
 
-	borrows, total, err := c.assetService.GetAssetBorrowList(uint(assetID), uint(borrowerID), status, page, pageSize)
+	borrows, total, err := c.assetService.WithContext(ctx.Request.Context()).GetAssetBorrowList(uint(assetID), uint(borrowerID), status, page, pageSize, middleware.CurrentDataScope(ctx))
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -252,9 +249,9 @@ func (c *AssetController) GetAssetBorrowList(ctx *gin.Context) {
 // GetAssetBorrowByID 根据ID获取资产领用记录
 func (c *AssetController) GetAssetBorrowByID(ctx *gin.Context) {
 	id, _ := strconv.ParseUint(ctx.Param("id"), 10, 32)
-	borrow, err := c.assetService.GetAssetBorrowByID(uint(id))
+	borrow, err := c.assetService.WithContext(ctx.Request.Context()).GetAssetBorrowByID(uint(id), middleware.CurrentDataScope(ctx))
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -265,15 +262,12 @@ func (c *AssetController) GetAssetBorrowByID(ctx *gin.Context) {
 func (c *AssetController) CreateAssetBorrow(ctx *gin.Context) {
 	var borrow model.AssetBorrow
 	if err := ctx.ShouldBindJSON(&borrow); err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		respondError(ctx, http.StatusBadRequest, err)
 		return
 	}
 
-	// TODO: 从JWT中获取当前用户ID
-	borrow.CreatedBy = uint(1)
-
-	if err := c.assetService.CreateAssetBorrow(&borrow); err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	if err := c.assetService.WithContext(ctx.Request.Context()).CreateAssetBorrow(&borrow); err != nil {
+		respondError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -285,13 +279,13 @@ func (c *AssetController) UpdateAssetBorrow(ctx *gin.Context) {
 	id, _ := strconv.ParseUint(ctx.Param("id"), 10, 32)
 	var borrow model.AssetBorrow
 	if err := ctx.ShouldBindJSON(&borrow); err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		respondError(ctx, http.StatusBadRequest, err)
 		return
 	}
 
 	borrow.ID = uint(id)
-	if err := c.assetService.UpdateAssetBorrow(&borrow); err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	if err := c.assetService.WithContext(ctx.Request.Context()).UpdateAssetBorrow(&borrow); err != nil {
+		respondError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -301,8 +295,8 @@ func (c *AssetController) UpdateAssetBorrow(ctx *gin.Context) {
 // DeleteAssetBorrow 删除资产领用记录
 func (c *AssetController) DeleteAssetBorrow(ctx *gin.Context) {
 	id, _ := strconv.ParseUint(ctx.Param("id"), 10, 32)
-	if err := c.assetService.DeleteAssetBorrow(uint(id)); err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	if err := c.assetService.WithContext(ctx.Request.Context()).DeleteAssetBorrow(uint(id)); err != nil {
+		respondError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -312,8 +306,8 @@ func (c *AssetController) DeleteAssetBorrow(ctx *gin.Context) {
 // ReturnAsset 归还资产
 func (c *AssetController) ReturnAsset(ctx *gin.Context) {
 	id, _ := strconv.ParseUint(ctx.Param("id"), 10, 32)
-	if err := c.assetService.ReturnAsset(uint(id)); err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	if err := c.assetService.WithContext(ctx.Request.Context()).ReturnAsset(uint(id)); err != nil {
+		respondError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -327,9 +321,9 @@ func (c *AssetController) GetAssetDisposalList(ctx *gin.Context) {
 	page, _ := strconv.Atoi(ctx.DefaultQuery("page", "1"))
 	pageSize, _ := strconv.Atoi(ctx.DefaultQuery("page_size", "10"))
 
-	disposals, total, err := c.assetService.GetAssetDisposalList(uint(assetID), status, page, pageSize)
+	disposals, total, err := c.assetService.WithContext(ctx.Request.Context()).GetAssetDisposalList(uint(assetID), status, page, pageSize, middleware.CurrentDataScope(ctx))
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -342,9 +336,9 @@ func (c *AssetController) GetAssetDisposalList(ctx *gin.Context) {
 // GetAssetDisposalByID 根据ID获取资产报废记录
 func (c *AssetController) GetAssetDisposalByID(ctx *gin.Context) {
 	id, _ := strconv.ParseUint(ctx.Param("id"), 10, 32)
-	disposal, err := c.assetService.GetAssetDisposalByID(uint(id))
+	disposal, err := c.assetService.WithContext(ctx.Request.Context()).GetAssetDisposalByID(uint(id), middleware.CurrentDataScope(ctx))
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -355,15 +349,12 @@ func (c *AssetController) GetAssetDisposalByID(ctx *gin.Context) {
 func (c *AssetController) CreateAssetDisposal(ctx *gin.Context) {
 	var disposal model.AssetDisposal
 	if err := ctx.ShouldBindJSON(&disposal); err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		respondError(ctx, http.StatusBadRequest, err)
 		return
 	}
 
-	// TODO: 从JWT中获取当前用户ID
-	disposal.CreatedBy = uint(1)
-
-	if err := c.assetService.CreateAssetDisposal(&disposal); err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	if err := c.assetService.WithContext(ctx.Request.Context()).CreateAssetDisposal(&disposal); err != nil {
+		respondError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -375,13 +366,13 @@ func (c *AssetController) UpdateAssetDisposal(ctx *gin.Context) {
 	id, _ := strconv.ParseUint(ctx.Param("id"), 10, 32)
 	var disposal model.AssetDisposal
 	if err := ctx.ShouldBindJSON(&disposal); err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		respondError(ctx, http.StatusBadRequest, err)
 		return
 	}
 
 	disposal.ID = uint(id)
-	if err := c.assetService.UpdateAssetDisposal(&disposal); err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	if err := c.assetService.WithContext(ctx.Request.Context()).UpdateAssetDisposal(&disposal); err != nil {
+		respondError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -391,8 +382,8 @@ func (c *AssetController) UpdateAssetDisposal(ctx *gin.Context) {
 // DeleteAssetDisposal 删除资产报废记录
 func (c *AssetController) DeleteAssetDisposal(ctx *gin.Context) {
 	id, _ := strconv.ParseUint(ctx.Param("id"), 10, 32)
-	if err := c.assetService.DeleteAssetDisposal(uint(id)); err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	if err := c.assetService.WithContext(ctx.Request.Context()).DeleteAssetDisposal(uint(id)); err != nil {
+		respondError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -402,8 +393,8 @@ func (c *AssetController) DeleteAssetDisposal(ctx *gin.Context) {
 // ApproveAssetDisposal 审批通过资产报废
 func (c *AssetController) ApproveAssetDisposal(ctx *gin.Context) {
 	id, _ := strconv.ParseUint(ctx.Param("id"), 10, 32)
-	if err := c.assetService.ApproveAssetDisposal(uint(id)); err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	if err := c.assetService.WithContext(ctx.Request.Context()).ApproveAssetDisposal(uint(id)); err != nil {
+		respondError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -413,8 +404,8 @@ func (c *AssetController) ApproveAssetDisposal(ctx *gin.Context) {
 // RejectAssetDisposal 驳回资产报废
 func (c *AssetController) RejectAssetDisposal(ctx *gin.Context) {
 	id, _ := strconv.ParseUint(ctx.Param("id"), 10, 32)
-	if err := c.assetService.RejectAssetDisposal(uint(id)); err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	if err := c.assetService.WithContext(ctx.Request.Context()).RejectAssetDisposal(uint(id)); err != nil {
+		respondError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -0,0 +1,212 @@
+package controller
+
+import (
+	"errors"
+	"net/http"
+	"net/url"
+	"strconv"
+
+	"github.com/lemonoa/LemonOA-Go/middleware"
+	"github.com/lemonoa/LemonOA-Go/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// multipart表单中文件以外的字段和分隔符允许占用的大小
+const multipartOverhead = 1 << 20
+
+// GetAttachmentList 获取附件列表
+func (c *SystemController) GetAttachmentList(ctx *gin.Context) {
+	module := ctx.Query("module")
+	relatedType := ctx.Query("related_type")
+	relatedID, _ := strconv.ParseUint(ctx.Query("related_id"), 10, 32)
+	page, _ := strconv.Atoi(ctx.DefaultQuery("page", "1"))
+	pageSize, _ := strconv.Atoi(ctx.DefaultQuery("page_size", "10"))
+
+	attachments, total, err := c.systemService.WithContext(ctx.Request.Context()).GetAttachmentList(module, relatedType, uint(relatedID), page, pageSize, middleware.CurrentDataScope(ctx))
+	if err != nil {
+		respondError(ctx, http.StatusInternalServerError, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"data":  attachments,
+		"total": total,
+	})
+}
+
+// UploadAttachment 上传附件，multipart表单字段 file 为文件，可同时指定所属记录
+func (c *SystemController) UploadAttachment(ctx *gin.Context) {
+	// 解析表单前限制请求体大小，避免超大请求写满临时目录
+	ctx.Request.Body = http.MaxBytesReader(ctx.Writer, ctx.Request.Body, service.UploadMaxSize()+multipartOverhead)
+
+	var owner service.AttachmentOwner
+	if err := ctx.ShouldBind(&owner); err != nil {
+		respondUploadFormError(ctx, err, err)
+		return
+	}
+	header, err := ctx.FormFile("file")
+	if err != nil {
+		respondUploadFormError(ctx, err, errors.New("请选择要上传的文件"))
+		return
+	}
+	file, err := header.Open()
+	if err != nil {
+		respondError(ctx, http.StatusBadRequest, err)
+		return
+	}
+	defer file.Close()
+
+	attachment, err := c.systemService.WithContext(ctx.Request.Context()).UploadAttachment(file, header.Filename, owner, middleware.CurrentUserID(ctx))
+	if err != nil {
+		respondAttachmentError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, attachment)
+}
+
+// 请求体超过大小上限时返回413，其他表单错误以 badRequest 返回400
+func respondUploadFormError(ctx *gin.Context, err, badRequest error) {
+	var tooLarge *http.MaxBytesError
+	if errors.As(err, &tooLarge) {
+		respondError(ctx, http.StatusRequestEntityTooLarge, service.ErrAttachmentTooLarge)
+		return
+	}
+	respondError(ctx, http.StatusBadRequest, badRequest)
+}
+
+// DownloadAttachment 下载附件，支持Range分段下载
+func (c *SystemController) DownloadAttachment(ctx *gin.Context) {
+	id, _ := strconv.ParseUint(ctx.Param("id"), 10, 32)
+	attachment, file, err := c.systemService.WithContext(ctx.Request.Context()).OpenAttachment(uint(id), middleware.CurrentDataScope(ctx))
+	if err != nil {
+		respondAttachmentError(ctx, err)
+		return
+	}
+	defer file.Close()
+
+	ctx.Header("Content-Type", attachment.Type)
+	ctx.Header("Content-Disposition", "attachment; filename*=UTF-8''"+url.PathEscape(attachment.Name))
+	ctx.Header("X-Content-Type-Options", "nosniff")
+	ctx.Header("ETag", `"`+attachment.Hash+`"`)
+	http.ServeContent(ctx.Writer, ctx.Request, attachment.Name, attachment.CreatedAt, file)
+}
+
+// LinkAttachment 关联附件到业务记录
+func (c *SystemController) LinkAttachment(ctx *gin.Context) {
+	id, _ := strconv.ParseUint(ctx.Param("id"), 10, 32)
+	var owner service.AttachmentOwner
+	if err := ctx.ShouldBindJSON(&owner); err != nil {
+		respondError(ctx, http.StatusBadRequest, err)
+		return
+	}
+
+	if err := c.systemService.WithContext(ctx.Request.Context()).LinkAttachment(uint(id), owner, middleware.CurrentDataScope(ctx)); err != nil {
+		respondAttachmentError(ctx, err)
+		return
+	}
+
+	ctx.Status(http.StatusNoContent)
+}
+
+// DeleteAttachment 删除附件
+func (c *SystemController) DeleteAttachment(ctx *gin.Context) {
+	id, _ := strconv.ParseUint(ctx.Param("id"), 10, 32)
+	if err := c.systemService.WithContext(ctx.Request.Context()).DeleteAttachment(uint(id), middleware.CurrentDataScope(ctx)); err != nil {
+		respondAttachmentError(ctx, err)
+		return
+	}
+
+	ctx.Status(http.StatusNoContent)
+}
+
+// CreateAttachmentUpload 创建分片上传任务
+func (c *SystemController) CreateAttachmentUpload(ctx *gin.Context) {
+	var req struct {
+		Name string `json:"name" binding:"required"`
+		Size int64  `json:"size" binding:"required"`
+	}
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		respondError(ctx, http.StatusBadRequest, err)
+		return
+	}
+
+	upload, err := c.systemService.WithContext(ctx.Request.Context()).CreateAttachmentUpload(req.Name, req.Size, middleware.CurrentUserID(ctx))
+	if err != nil {
+		respondAttachmentError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, upload)
+}
+
+// GetAttachmentUpload 获取分片上传进度
+func (c *SystemController) GetAttachmentUpload(ctx *gin.Context) {
+	status, err := c.systemService.WithContext(ctx.Request.Context()).GetAttachmentUpload(ctx.Param("upload_id"), middleware.CurrentUserID(ctx))
+	if err != nil {
+		respondAttachmentError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, status)
+}
+
+// SaveAttachmentChunk 上传一个分片，请求体为分片内容
+func (c *SystemController) SaveAttachmentChunk(ctx *gin.Context) {
+	index, err := strconv.Atoi(ctx.Param("index"))
+	if err != nil {
+		respondError(ctx, http.StatusBadRequest, service.ErrAttachmentChunkInvalid)
+		return
+	}
+
+	if err := c.systemService.WithContext(ctx.Request.Context()).SaveAttachmentChunk(ctx.Param("upload_id"), middleware.CurrentUserID(ctx), index, ctx.Request.Body); err != nil {
+		respondAttachmentError(ctx, err)
+		return
+	}
+
+	ctx.Status(http.StatusNoContent)
+}
+
+// CompleteAttachmentUpload 完成分片上传，合并为附件
+func (c *SystemController) CompleteAttachmentUpload(ctx *gin.Context) {
+	var owner service.AttachmentOwner
+	if err := ctx.ShouldBindJSON(&owner); err != nil {
+		respondError(ctx, http.StatusBadRequest, err)
+		return
+	}
+
+	attachment, err := c.systemService.WithContext(ctx.Request.Context()).CompleteAttachmentUpload(ctx.Param("upload_id"), middleware.CurrentUserID(ctx), owner)
+	if err != nil {
+		respondAttachmentError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, attachment)
+}
+
+// CancelAttachmentUpload 取消分片上传
+func (c *SystemController) CancelAttachmentUpload(ctx *gin.Context) {
+	if err := c.systemService.WithContext(ctx.Request.Context()).CancelAttachmentUpload(ctx.Param("upload_id"), middleware.CurrentUserID(ctx)); err != nil {
+		respondAttachmentError(ctx, err)
+		return
+	}
+
+	ctx.Status(http.StatusNoContent)
+}
+
+// 按错误类型返回附件接口的状态码
+func respondAttachmentError(ctx *gin.Context, err error) {
+	status := http.StatusInternalServerError
+	switch {
+	case errors.Is(err, service.ErrAttachmentTooLarge):
+		status = http.StatusRequestEntityTooLarge
+	case errors.Is(err, service.ErrAttachmentTypeForbidden):
+		status = http.StatusUnsupportedMediaType
+	case errors.Is(err, service.ErrAttachmentSizeInvalid), errors.Is(err, service.ErrAttachmentIncomplete), errors.Is(err, service.ErrAttachmentChunkInvalid):
+		status = http.StatusBadRequest
+	case errors.Is(err, service.ErrAttachmentUploadExpired), errors.Is(err, service.ErrAttachmentNotFound), errors.Is(err, service.ErrStorageObjectNotFound):
+		status = http.StatusNotFound
+	}
+	respondError(ctx, status, err)
+}
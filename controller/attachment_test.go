@@ -0,0 +1,134 @@
+package controller
+
+import (
+	"bytes"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/lemonoa/LemonOA-Go/database"
+	"github.com/lemonoa/LemonOA-Go/service"
+
+	"github.com/gin-gonic/gin"
+	"github.com/spf13/viper"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// 创建使用临时SQLite数据库和本地存储的附件接口，请求以可访问全部数据的用户1身份执行
+func newAttachmentTestRouter(t *testing.T) (*gin.Engine, *service.SystemService) {
+	t.Helper()
+	viper.Set("encryption.active_key", "k1")
+	viper.Set("encryption.keys", map[string]string{"k1": "MDEyMzQ1Njc4OWFiY2RlZjAxMjM0NTY3ODlhYmNkZWY="})
+	viper.Set("upload.save_path", t.TempDir())
+	viper.Set("upload.max_size", 1)
+	t.Cleanup(viper.Reset)
+	if err := database.InitFieldEncryption(); err != nil {
+		t.Fatal(err)
+	}
+	if err := service.InitStorage(); err != nil {
+		t.Fatal(err)
+	}
+
+	db, err := gorm.Open(sqlite.Open(filepath.Join(t.TempDir(), "test.db")), &gorm.Config{Logger: logger.Discard})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := db.AutoMigrate(database.Models()...); err != nil {
+		t.Fatal(err)
+	}
+
+	systemService := service.NewSystemService(db)
+	c := NewSystemController(systemService)
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.Use(func(ctx *gin.Context) {
+		ctx.Set("user_id", uint(1))
+		ctx.Set("data_scope", &service.DataScope{All: true, UserID: 1})
+	})
+	r.POST("/attachments", c.UploadAttachment)
+	r.GET("/attachments/:id/download", c.DownloadAttachment)
+	return r, systemService
+}
+
+func TestDownloadAttachmentRange(t *testing.T) {
+	r, systemService := newAttachmentTestRouter(t)
+	attachment, err := systemService.UploadAttachment(strings.NewReader("0123456789"), "digits.txt", service.AttachmentOwner{}, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	url := "/attachments/" + strconv.FormatUint(uint64(attachment.ID), 10) + "/download"
+
+	tests := []struct {
+		name       string
+		rangeValue string
+		wantStatus int
+		wantBody   string
+		wantRange  string
+	}{
+		{name: "full", wantStatus: http.StatusOK, wantBody: "0123456789"},
+		{name: "range", rangeValue: "bytes=2-5", wantStatus: http.StatusPartialContent, wantBody: "2345", wantRange: "bytes 2-5/10"},
+		{name: "suffix", rangeValue: "bytes=-3", wantStatus: http.StatusPartialContent, wantBody: "789", wantRange: "bytes 7-9/10"},
+		{name: "unsatisfiable", rangeValue: "bytes=20-", wantStatus: http.StatusRequestedRangeNotSatisfiable, wantRange: "bytes */10"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, url, nil)
+			if tt.rangeValue != "" {
+				req.Header.Set("Range", tt.rangeValue)
+			}
+			rec := httptest.NewRecorder()
+			r.ServeHTTP(rec, req)
+
+			if rec.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d", rec.Code, tt.wantStatus)
+			}
+			if tt.wantBody != "" && rec.Body.String() != tt.wantBody {
+				t.Errorf("body = %q, want %q", rec.Body.String(), tt.wantBody)
+			}
+			if got := rec.Header().Get("Content-Range"); got != tt.wantRange {
+				t.Errorf("Content-Range = %q, want %q", got, tt.wantRange)
+			}
+		})
+	}
+}
+
+func TestUploadAttachmentBodyLimit(t *testing.T) {
+	r, _ := newAttachmentTestRouter(t)
+
+	tests := []struct {
+		name       string
+		size       int
+		wantStatus int
+	}{
+		{name: "within limit", size: 1 << 10, wantStatus: http.StatusCreated},
+		// 超过 upload.max_size 加表单开销的请求在解析表单时被拒绝
+		{name: "request too large", size: 3 << 20, wantStatus: http.StatusRequestEntityTooLarge},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var body bytes.Buffer
+			form := multipart.NewWriter(&body)
+			file, err := form.CreateFormFile("file", "data.txt")
+			if err != nil {
+				t.Fatal(err)
+			}
+			file.Write(bytes.Repeat([]byte("a"), tt.size))
+			form.Close()
+
+			req := httptest.NewRequest(http.MethodPost, "/attachments", &body)
+			req.Header.Set("Content-Type", form.FormDataContentType())
+			rec := httptest.NewRecorder()
+			r.ServeHTTP(rec, req)
+
+			if rec.Code != tt.wantStatus {
+				t.Errorf("status = %d, want %d: %s", rec.Code, tt.wantStatus, rec.Body.String())
+			}
+		})
+	}
+}
@@ -26,7 +26,7 @@ func NewAttendanceController(attendanceService *service.AttendanceService) *Atte
 // RegisterRoutes 注册路由
 func (c *AttendanceController) RegisterRoutes(r *gin.Engine) {
 	api := r.Group("/api")
-	api.Use(middleware.JWT())
+	api.Use(middleware.JWT(), middleware.DataScope())
 
 	// 考勤规则管理
 	rules := api.Group("/attendance/rules")
@@ -46,13 +46,17 @@ func (c *AttendanceController) RegisterRoutes(r *gin.Engine) {
 		records.DELETE("/:id", middleware.RequirePermission(model.PermissionAttendanceRecordCreate), c.DeleteAttendanceRecord)
 	}
 
+	// 员工打卡，打卡人为当前用户关联的员工
+	api.POST("/attendance/check-in", middleware.RequirePermission(model.PermissionAttendanceCheckIn), middleware.RequireEmployee(), c.CheckIn)
+	api.POST("/attendance/check-out", middleware.RequirePermission(model.PermissionAttendanceCheckIn), middleware.RequireEmployee(), c.CheckOut)
+
 	// 请假管理
 	leaves := api.Group("/attendance/leaves")
 	{
 		leaves.GET("", middleware.RequirePermission(model.PermissionLeaveList), c.GetLeaveApplicationList)
-		leaves.POST("", middleware.RequirePermission(model.PermissionLeaveCreate), c.CreateLeaveApplication)
-		leaves.PUT("/:id", middleware.RequirePermission(model.PermissionLeaveCreate), c.UpdateLeaveApplication)
-		leaves.DELETE("/:id", middleware.RequirePermission(model.PermissionLeaveCreate), c.DeleteLeaveApplication)
+		leaves.POST("", middleware.RequirePermission(model.PermissionLeaveCreate), middleware.RequireEmployee(), c.CreateLeaveApplication)
+		leaves.PUT("/:id", middleware.RequirePermission(model.PermissionLeaveCreate), middleware.RequireEmployee(), c.UpdateLeaveApplication)
+		leaves.DELETE("/:id", middleware.RequirePermission(model.PermissionLeaveCreate), middleware.RequireEmployee(), c.DeleteLeaveApplication)
 		leaves.PUT("/:id/approve", middleware.RequirePermission(model.PermissionLeaveApprove), c.ApproveLeaveApplication)
 		leaves.PUT("/:id/reject", middleware.RequirePermission(model.PermissionLeaveApprove), c.RejectLeaveApplication)
 	}
@@ -61,9 +65,9 @@ func (c *AttendanceController) RegisterRoutes(r *gin.Engine) {
 	overtimes := api.Group("/attendance/overtimes")
 	{
 		overtimes.GET("", middleware.RequirePermission(model.PermissionLeaveList), c.GetOvertimeApplicationList)
-		overtimes.POST("", middleware.RequirePermission(model.PermissionLeaveCreate), c.CreateOvertimeApplication)
-		overtimes.PUT("/:id", middleware.RequirePermission(model.PermissionLeaveCreate), c.UpdateOvertimeApplication)
-		overtimes.DELETE("/:id", middleware.RequirePermission(model.PermissionLeaveCreate), c.DeleteOvertimeApplication)
+		overtimes.POST("", middleware.RequirePermission(model.PermissionLeaveCreate), middleware.RequireEmployee(), c.CreateOvertimeApplication)
+		overtimes.PUT("/:id", middleware.RequirePermission(model.PermissionLeaveCreate), middleware.RequireEmployee(), c.UpdateOvertimeApplication)
+		overtimes.DELETE("/:id", middleware.RequirePermission(model.PermissionLeaveCreate), middleware.RequireEmployee(), c.DeleteOvertimeApplication)
 		overtimes.PUT("/:id/approve", middleware.RequirePermission(model.PermissionLeaveApprove), c.ApproveOvertimeApplication)
 		overtimes.PUT("/:id/reject", middleware.RequirePermission(model.PermissionLeaveApprove), c.RejectOvertimeApplication)
 	}
@@ -72,9 +76,9 @@ func (c *AttendanceController) RegisterRoutes(r *gin.Engine) {
 	trips := api.Group("/attendance/trips")
 	{
 		trips.GET("", middleware.RequirePermission(model.PermissionLeaveList), c.GetBusinessTripApplicationList)
-		trips.POST("", middleware.RequirePermission(model.PermissionLeaveCreate), c.CreateBusinessTripApplication)
-		trips.PUT("/:id", middleware.RequirePermission(model.PermissionLeaveCreate), c.UpdateBusinessTripApplication)
-		trips.DELETE("/:id", middleware.RequirePermission(model.PermissionLeaveCreate), c.DeleteBusinessTripApplication)
+		trips.POST("", middleware.RequirePermission(model.PermissionLeaveCreate), middleware.RequireEmployee(), c.CreateBusinessTripApplication)
+		trips.PUT("/:id", middleware.RequirePermission(model.PermissionLeaveCreate), middleware.RequireEmployee(), c.UpdateBusinessTripApplication)
+		trips.DELETE("/:id", middleware.RequirePermission(model.PermissionLeaveCreate), middleware.RequireEmployee(), c.DeleteBusinessTripApplication)
 		trips.PUT("/:id/approve", middleware.RequirePermission(model.PermissionLeaveApprove), c.ApproveBusinessTripApplication)
 		trips.PUT("/:id/reject", middleware.RequirePermission(model.PermissionLeaveApprove), c.RejectBusinessTripApplication)
 	}
@@ -87,9 +91,9 @@ func (c *AttendanceController) GetAttendanceRuleList(ctx *gin.Context) {
 	page, _ := strconv.Atoi(ctx.DefaultQuery("page", "1"))
 	pageSize, _ := strconv.Atoi(ctx.DefaultQuery("page_size", "10"))
 
-	rules, total, err := c.attendanceService.GetAttendanceRuleList(status, keyword, page, pageSize)
+	rules, total, err := c.attendanceService.WithContext(ctx.Request.Context()).GetAttendanceRuleList(status, keyword, page, pageSize)
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -102,9 +106,9 @@ func (c *AttendanceController) GetAttendanceRuleList(ctx *gin.Context) {
 // GetAttendanceRuleByID 根据ID获取考勤规则
 func (c *AttendanceController) GetAttendanceRuleByID(ctx *gin.Context) {
 	id, _ := strconv.ParseUint(ctx.Param("id"), 10, 32)
-	rule, err := c.attendanceService.GetAttendanceRuleByID(uint(id))
+	rule, err := c.attendanceService.WithContext(ctx.Request.Context()).GetAttendanceRuleByID(uint(id))
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -115,15 +119,12 @@ func (c *AttendanceController) GetAttendanceRuleByID(ctx *gin.Context) {
 func (c *AttendanceController) CreateAttendanceRule(ctx *gin.Context) {
 	var rule model.AttendanceRule
 	if err := ctx.ShouldBindJSON(&rule); err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		respondError(ctx, http.StatusBadRequest, err)
 		return
 	}
 
-	// TODO: 从JWT中获取当前用户ID
-	rule.CreatedBy = uint(1)
-
-	if err := c.attendanceService.CreateAttendanceRule(&rule); err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	if err := c.attendanceService.WithContext(ctx.Request.Context()).CreateAttendanceRule(&rule); err != nil {
+		respondError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -135,13 +136,13 @@ func (c *AttendanceController) UpdateAttendanceRule(ctx *gin.Context) {
 	id, _ := strconv.ParseUint(ctx.Param("id"), 10, 32)
 	var rule model.AttendanceRule
 	if err := ctx.ShouldBindJSON(&rule); err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		respondError(ctx, http.StatusBadRequest, err)
 		return
 	}
 
 	rule.ID = uint(id)
-	if err := c.attendanceService.UpdateAttendanceRule(&rule); err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	if err := c.attendanceService.WithContext(ctx.Request.Context()).UpdateAttendanceRule(&rule); err != nil {
+		respondError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -151,8 +152,8 @@ func (c *AttendanceController) UpdateAttendanceRule(ctx *gin.Context) {
 // DeleteAttendanceRule 删除考勤规则
 func (c *AttendanceController) DeleteAttendanceRule(ctx *gin.Context) {
 	id, _ := strconv.ParseUint(ctx.Param("id"), 10, 32)
-	if err := c.attendanceService.DeleteAttendanceRule(uint(id)); err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	if err := c.attendanceService.WithContext(ctx.Request.Context()).DeleteAttendanceRule(uint(id)); err != nil {
+		respondError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -176,9 +177,9 @@ func (c *AttendanceController) GetAttendanceRecordList(ctx *gin.Context) {
 		endPtr = &endDate
 	}
 
-	records, total, err := c.attendanceService.GetAttendanceRecordList(uint(employeeID), status, startPtr, endPtr, page, pageSize)
+	records, total, err := c.attendanceService.WithContext(ctx.Request.Context()).GetAttendanceRecordList(uint(employeeID), status, startPtr, endPtr, page, pageSize, middleware.CurrentDataScope(ctx))
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -191,9 +192,9 @@ func (c *AttendanceController) GetAttendanceRecordList(ctx *gin.Context) {
 // GetAttendanceRecordByID 根据ID获取考勤记录
 func (c *AttendanceController) GetAttendanceRecordByID(ctx *gin.Context) {
 	id, _ := strconv.ParseUint(ctx.Param("id"), 10, 32)
-	record, err := c.attendanceService.GetAttendanceRecordByID(uint(id))
+	record, err := c.attendanceService.WithContext(ctx.Request.Context()).GetAttendanceRecordByID(uint(id), middleware.CurrentDataScope(ctx))
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -204,30 +205,64 @@ func (c *AttendanceController) GetAttendanceRecordByID(ctx *gin.Context) {
 func (c *AttendanceController) CreateAttendanceRecord(ctx *gin.Context) {
 	var record model.AttendanceRecord
 	if err := ctx.ShouldBindJSON(&record); err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		respondError(ctx, http.StatusBadRequest, err)
 		return
 	}
 
-	if err := c.attendanceService.CreateAttendanceRecord(&record); err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	if err := c.attendanceService.WithContext(ctx.Request.Context()).CreateAttendanceRecord(&record); err != nil {
+		respondError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 
 	ctx.JSON(http.StatusCreated, record)
 }
 
+// CheckIn 员工签到
+func (c *AttendanceController) CheckIn(ctx *gin.Context) {
+	var params struct {
+		Location string `json:"location"`
+	}
+	// 请求体可选
+	_ = ctx.ShouldBindJSON(&params)
+
+	record, err := c.attendanceService.WithContext(ctx.Request.Context()).CheckIn(middleware.CurrentEmployeeID(ctx), params.Location)
+	if err != nil {
+		respondError(ctx, http.StatusBadRequest, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, record)
+}
+
+// CheckOut 员工签退
+func (c *AttendanceController) CheckOut(ctx *gin.Context) {
+	var params struct {
+		Location string `json:"location"`
+	}
+	// 请求体可选
+	_ = ctx.ShouldBindJSON(&params)
+
+	record, err := c.attendanceService.WithContext(ctx.Request.Context()).CheckOut(middleware.CurrentEmployeeID(ctx), params.Location)
+	if err != nil {
+		respondError(ctx, http.StatusBadRequest, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, record)
+}
+
 // UpdateAttendanceRecord 更新考勤记录
 func (c *AttendanceController) UpdateAttendanceRecord(ctx *gin.Context) {
 	id, _ := strconv.ParseUint(ctx.Param("id"), 10, 32)
 	var record model.AttendanceRecord
 	if err := ctx.ShouldBindJSON(&record); err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		respondError(ctx, http.StatusBadRequest, err)
 		return
 	}
 
 	record.ID = uint(id)
-	if err := c.attendanceService.UpdateAttendanceRecord(&record); err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	if err := c.attendanceService.WithContext(ctx.Request.Context()).UpdateAttendanceRecord(&record); err != nil {
+		respondError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -237,8 +272,8 @@ func (c *AttendanceController) UpdateAttendanceRecord(ctx *gin.Context) {
 // DeleteAttendanceRecord 删除考勤记录
 func (c *AttendanceController) DeleteAttendanceRecord(ctx *gin.Context) {
 	id, _ := strconv.ParseUint(ctx.Param("id"), 10, 32)
-	if err := c.attendanceService.DeleteAttendanceRecord(uint(id)); err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	if err := c.attendanceService.WithContext(ctx.Request.Context()).DeleteAttendanceRecord(uint(id)); err != nil {
+		respondError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -262,9 +297,9 @@ func (c *AttendanceController) GetLeaveApplicationList(ctx *gin.Context) {
 		endPtr = &endDate
 	}
 
-	applications, total, err := c.attendanceService.GetLeaveApplicationList(uint(employeeID), status, startPtr, endPtr, page, pageSize)
+	applications, total, err := c.attendanceService.WithContext(ctx.Request.Context()).GetLeaveApplicationList(uint(employeeID), status, startPtr, endPtr, page, pageSize, middleware.CurrentDataScope(ctx))
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -277,9 +312,9 @@ func (c *AttendanceController) GetLeaveApplicationList(ctx *gin.Context) {
 // GetLeaveApplicationByID 根据ID获取请假申请
 func (c *AttendanceController) GetLeaveApplicationByID(ctx *gin.Context) {
 	id, _ := strconv.ParseUint(ctx.Param("id"), 10, 32)
-	application, err := c.attendanceService.GetLeaveApplicationByID(uint(id))
+	application, err := c.attendanceService.WithContext(ctx.Request.Context()).GetLeaveApplicationByID(uint(id), middleware.CurrentDataScope(ctx))
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -290,12 +325,15 @@ func (c *AttendanceController) GetLeaveApplicationByID(ctx *gin.Context) {
 func (c *AttendanceController) CreateLeaveApplication(ctx *gin.Context) {
 	var application model.LeaveApplication
 	if err := ctx.ShouldBindJSON(&application); err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		respondError(ctx, http.StatusBadRequest, err)
 		return
 	}
 
-	if err := c.attendanceService.CreateLeaveApplication(&application); err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	// 申请人为当前用户关联的员工
+	application.EmployeeID = middleware.CurrentEmployeeID(ctx)
+
+	if err := c.attendanceService.WithContext(ctx.Request.Context()).CreateLeaveApplication(&application); err != nil {
+		respondError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -307,13 +345,14 @@ func (c *AttendanceController) UpdateLeaveApplication(ctx *gin.Context) {
 	id, _ := strconv.ParseUint(ctx.Param("id"), 10, 32)
 	var application model.LeaveApplication
 	if err := ctx.ShouldBindJSON(&application); err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		respondError(ctx, http.StatusBadRequest, err)
 		return
 	}
 
 	application.ID = uint(id)
-	if err := c.attendanceService.UpdateLeaveApplication(&application); err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	application.EmployeeID = middleware.CurrentEmployeeID(ctx)
+	if err := c.attendanceService.WithContext(ctx.Request.Context()).UpdateLeaveApplication(&application); err != nil {
+		respondError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -323,8 +362,8 @@ func (c *AttendanceController) UpdateLeaveApplication(ctx *gin.Context) {
 // DeleteLeaveApplication 删除请假申请
 func (c *AttendanceController) DeleteLeaveApplication(ctx *gin.Context) {
 	id, _ := strconv.ParseUint(ctx.Param("id"), 10, 32)
-	if err := c.attendanceService.DeleteLeaveApplication(uint(id)); err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	if err := c.attendanceService.WithContext(ctx.Request.Context()).DeleteLeaveApplication(uint(id), middleware.CurrentEmployeeID(ctx)); err != nil {
+		respondError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -348,9 +387,9 @@ func (c *AttendanceController) GetOvertimeApplicationList(ctx *gin.Context) {
 		endPtr = &endDate
 	}
 
-	applications, total, err := c.attendanceService.GetOvertimeApplicationList(uint(employeeID), status, startPtr, endPtr, page, pageSize)
+	applications, total, err := c.attendanceService.WithContext(ctx.Request.Context()).GetOvertimeApplicationList(uint(employeeID), status, startPtr, endPtr, page, pageSize, middleware.CurrentDataScope(ctx))
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -363,9 +402,9 @@ func (c *AttendanceController) GetOvertimeApplicationList(ctx *gin.Context) {
 // GetOvertimeApplicationByID 根据ID获取加班申请
 func (c *AttendanceController) GetOvertimeApplicationByID(ctx *gin.Context) {
 	id, _ := strconv.ParseUint(ctx.Param("id"), 10, 32)
-	application, err := c.attendanceService.GetOvertimeApplicationByID(uint(id))
+	application, err := c.attendanceService.WithContext(ctx.Request.Context()).GetOvertimeApplicationByID(uint(id), middleware.CurrentDataScope(ctx))
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -376,12 +415,15 @@ func (c *AttendanceController) GetOvertimeApplicationByID(ctx *gin.Context) {
 func (c *AttendanceController) CreateOvertimeApplication(ctx *gin.Context) {
 	var application model.OvertimeApplication
 	if err := ctx.ShouldBindJSON(&application); err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		respondError(ctx, http.StatusBadRequest, err)
 		return
 	}
 
-	if err := c.attendanceService.CreateOvertimeApplication(&application); err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	// 申请人为当前用户关联的员工
+	application.EmployeeID = middleware.CurrentEmployeeID(ctx)
+
+	if err := c.attendanceService.WithContext(ctx.Request.Context()).CreateOvertimeApplication(&application); err != nil {
+		respondError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -393,13 +435,14 @@ func (c *AttendanceController) UpdateOvertimeApplication(ctx *gin.Context) {
 	id, _ := strconv.ParseUint(ctx.Param("id"), 10, 32)
 	var application model.OvertimeApplication
 	if err := ctx.ShouldBindJSON(&application); err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		respondError(ctx, http.StatusBadRequest, err)
 		return
 	}
 
 	application.ID = uint(id)
-	if err := c.attendanceService.UpdateOvertimeApplication(&application); err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	application.EmployeeID = middleware.CurrentEmployeeID(ctx)
+	if err := c.attendanceService.WithContext(ctx.Request.Context()).UpdateOvertimeApplication(&application); err != nil {
+		respondError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -409,8 +452,8 @@ func (c *AttendanceController) UpdateOvertimeApplication(ctx *gin.Context) {
 // DeleteOvertimeApplication 删除加班申请
 func (c *AttendanceController) DeleteOvertimeApplication(ctx *gin.Context) {
 	id, _ := strconv.ParseUint(ctx.Param("id"), 10, 32)
-	if err := c.attendanceService.DeleteOvertimeApplication(uint(id)); err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	if err := c.attendanceService.WithContext(ctx.Request.Context()).DeleteOvertimeApplication(uint(id), middleware.CurrentEmployeeID(ctx)); err != nil {
+		respondError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -434,9 +477,9 @@ func (c *AttendanceController) GetBusinessTripApplicationList(ctx *gin.Context)
 		endPtr = &endDate
 	}
 
-	applications, total, err := c.attendanceService.GetBusinessTripApplicationList(uint(employeeID), status, startPtr, endPtr, page, pageSize)
+	applications, total, err := c.attendanceService.WithContext(ctx.Request.Context()).GetBusinessTripApplicationList(uint(employeeID), status, startPtr, endPtr, page, pageSize, middleware.CurrentDataScope(ctx))
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -449,9 +492,9 @@ func (c *AttendanceController) GetBusinessTripApplicationList(ctx *gin.Context)
 // GetBusinessTripApplicationByID 根据ID获取出差申请
 func (c *AttendanceController) GetBusinessTripApplicationByID(ctx *gin.Context) {
 	id, _ := strconv.ParseUint(ctx.Param("id"), 10, 32)
-	application, err := c.attendanceService.GetBusinessTripApplicationByID(uint(id))
+	application, err := c.attendanceService.WithContext(ctx.Request.Context()).GetBusinessTripApplicationByID(uint(id), middleware.CurrentDataScope(ctx))
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -462,12 +505,15 @@ func (c *AttendanceController) GetBusinessTripApplicationByID(ctx *gin.Context)
 func (c *AttendanceController) CreateBusinessTripApplication(ctx *gin.Context) {
 	var application model.BusinessTripApplication
 	if err := ctx.ShouldBindJSON(&application); err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		respondError(ctx, http.StatusBadRequest, err)
 		return
 	}
 
-	if err := c.attendanceService.CreateBusinessTripApplication(&application); err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	// 申请人为当前用户关联的员工
+	application.EmployeeID = middleware.CurrentEmployeeID(ctx)
+
+	if err := c.attendanceService.WithContext(ctx.Request.Context()).CreateBusinessTripApplication(&application); err != nil {
+		respondError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -479,13 +525,14 @@ func (c *AttendanceController) UpdateBusinessTripApplication(ctx *gin.Context) {
 	id, _ := strconv.ParseUint(ctx.Param("id"), 10, 32)
 	var application model.BusinessTripApplication
 	if err := ctx.ShouldBindJSON(&application); err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		respondError(ctx, http.StatusBadRequest, err)
 		return
 	}
 
 	application.ID = uint(id)
-	if err := c.attendanceService.UpdateBusinessTripApplication(&application); err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	application.EmployeeID = middleware.CurrentEmployeeID(ctx)
+	if err := c.attendanceService.WithContext(ctx.Request.Context()).UpdateBusinessTripApplication(&application); err != nil {
+		respondError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -495,8 +542,8 @@ func (c *AttendanceController) UpdateBusinessTripApplication(ctx *gin.Context) {
 // DeleteBusinessTripApplication 删除出差申请
 func (c *AttendanceController) DeleteBusinessTripApplication(ctx *gin.Context) {
 	id, _ := strconv.ParseUint(ctx.Param("id"), 10, 32)
-	if err := c.attendanceService.DeleteBusinessTripApplication(uint(id)); err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	if err := c.attendanceService.WithContext(ctx.Request.Context()).DeleteBusinessTripApplication(uint(id), middleware.CurrentEmployeeID(ctx)); err != nil {
+		respondError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -506,11 +553,10 @@ func (c *AttendanceController) DeleteBusinessTripApplication(ctx *gin.Context) {
 // ApproveLeaveApplication 审批请假申请
 func (c *AttendanceController) ApproveLeaveApplication(ctx *gin.Context) {
 	id, _ := strconv.ParseUint(ctx.Param("id"), 10, 32)
-	// TODO: 从JWT中获取当前用户ID
-	approverID := uint(1)
+	approverID := middleware.CurrentUserID(ctx)
 
-	if err := c.attendanceService.ApproveLeaveApplication(uint(id), approverID); err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	if err := c.attendanceService.WithContext(ctx.Request.Context()).ApproveLeaveApplication(uint(id), approverID); err != nil {
+		respondError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -520,11 +566,10 @@ func (c *AttendanceController) ApproveLeaveApplication(ctx *gin.Context) {
 // RejectLeaveApplication 驳回请假申请
 func (c *AttendanceController) RejectLeaveApplication(ctx *gin.Context) {
 	id, _ := strconv.ParseUint(ctx.Param("id"), 10, 32)
-	// TODO: 从JWT中获取当前用户ID
-	approverID := uint(1)
+	approverID := middleware.CurrentUserID(ctx)
 
-	if err := c.attendanceService.RejectLeaveApplication(uint(id), approverID); err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	if err := c.attendanceService.WithContext(ctx.Request.Context()).RejectLeaveApplication(uint(id), approverID); err != nil {
+		respondError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -534,11 +579,10 @@ func (c *AttendanceController) RejectLeaveApplication(ctx *gin.Context) {
 // ApproveOvertimeApplication 审批加班申请
 func (c *AttendanceController) ApproveOvertimeApplication(ctx *gin.Context) {
 	id, _ := strconv.ParseUint(ctx.Param("id"), 10, 32)
-	// TODO: 从JWT中获取当前用户ID
-	approverID := uint(1)
+	approverID := middleware.CurrentUserID(ctx)
 
-	if err := c.attendanceService.ApproveOvertimeApplication(uint(id), approverID); err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	if err := c.attendanceService.WithContext(ctx.Request.Context()).ApproveOvertimeApplication(uint(id), approverID); err != nil {
+		respondError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -548,11 +592,10 @@ func (c *AttendanceController) ApproveOvertimeApplication(ctx *gin.Context) {
 // RejectOvertimeApplication 驳回加班申请
 func (c *AttendanceController) RejectOvertimeApplication(ctx *gin.Context) {
 	id, _ := strconv.ParseUint(ctx.Param("id"), 10, 32)
-	// TODO: 从JWT中获取当前用户ID
-	approverID := uint(1)
+	approverID := middleware.CurrentUserID(ctx)
 
-	if err := c.attendanceService.RejectOvertimeApplication(uint(id), approverID); err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	if err := c.attendanceService.WithContext(ctx.Request.Context()).RejectOvertimeApplication(uint(id), approverID); err != nil {
+		respondError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -562,11 +605,10 @@ func (c *AttendanceController) RejectOvertimeApplication(ctx *gin.Context) {
 // ApproveBusinessTripApplication 审批出差申请
 func (c *AttendanceController) ApproveBusinessTripApplication(ctx *gin.Context) {
 	id, _ := strconv.ParseUint(ctx.Param("id"), 10, 32)
-	// TODO: 从JWT中获取当前用户ID
-	approverID := uint(1)
+	approverID := middleware.CurrentUserID(ctx)
 
-	if err := c.attendanceService.ApproveBusinessTripApplication(uint(id), approverID); err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	if err := c.attendanceService.WithContext(ctx.Request.Context()).ApproveBusinessTripApplication(uint(id), approverID); err != nil {
+		respondError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -576,11 +618,10 @@ func (c *AttendanceController) ApproveBusinessTripApplication(ctx *gin.Context)
 // RejectBusinessTripApplication 驳回出差申请
 func (c *AttendanceController) RejectBusinessTripApplication(ctx *gin.Context) {
 	id, _ := strconv.ParseUint(ctx.Param("id"), 10, 32)
-	// TODO: 从JWT中获取当前用户ID
-	approverID := uint(1)
+	approverID := middleware.CurrentUserID(ctx)
 
-	if err := c.attendanceService.RejectBusinessTripApplication(uint(id), approverID); err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	if err := c.attendanceService.WithContext(ctx.Request.Context()).RejectBusinessTripApplication(uint(id), approverID); err != nil {
+		respondError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 
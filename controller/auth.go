@@ -1,8 +1,10 @@
 package controller
 
 import (
+	"errors"
 	"net/http"
 	"strconv"
+	"time"
 
 	"github.com/lemonoa/LemonOA-Go/model"
 	"github.com/lemonoa/LemonOA-Go/service"
@@ -28,13 +30,28 @@ func (c *AuthController) RegisterRoutes(r *gin.Engine) {
 	{
 		// 不需要认证的接口
 		api.POST("/login", c.Login)
+		api.POST("/login/2fa", c.VerifyLogin2FA)
+		api.POST("/login/2fa/setup", c.SetupLogin2FA)
+		api.POST("/login/2fa/setup/confirm", c.ConfirmLogin2FASetup)
+		api.POST("/login/password", c.ChangeExpiredPassword)
+		api.GET("/oidc/authorize", c.OIDCAuthorize)
+		api.POST("/oidc/callback", c.OIDCCallback)
+		api.POST("/refresh", c.RefreshToken)
 
 		// 需要认证的接口
 		auth := api.Use(middleware.JWT())
 		{
+			auth.POST("/logout", c.Logout)
 			auth.GET("/user-info", c.GetUserInfo)
 			auth.GET("/permissions", c.GetUserPermissions)
-			auth.POST("/change-password", c.ChangePassword)
+			auth.POST("/change-password", middleware.DenyImpersonation(), c.ChangePassword)
+			auth.POST("/2fa/setup", middleware.DenyImpersonation(), c.Setup2FA)
+			auth.POST("/2fa/enable", middleware.DenyImpersonation(), c.Enable2FA)
+			auth.POST("/2fa/disable", middleware.DenyImpersonation(), c.Disable2FA)
+			auth.POST("/2fa/recovery-codes", middleware.DenyImpersonation(), c.RegenerateRecoveryCodes)
+			auth.GET("/employee", c.GetMyEmployee)
+			auth.GET("/sessions", c.GetMySessions)
+			auth.DELETE("/sessions/:id", middleware.DenyImpersonation(), c.RevokeMySession)
 		}
 	}
 
@@ -42,30 +59,59 @@ func (c *AuthController) RegisterRoutes(r *gin.Engine) {
 	users := r.Group("/api/users").Use(middleware.JWT())
 	{
 		users.GET("", middleware.RequirePermission("system:user:list"), c.GetUserList)
-		users.POST("", middleware.RequirePermission("system:user:create"), c.CreateUser)
-		users.PUT("/:id", middleware.RequirePermission("system:user:update"), c.UpdateUser)
-		users.DELETE("/:id", middleware.RequirePermission("system:user:delete"), c.DeleteUser)
-		users.PUT("/:id/reset-password", middleware.RequirePermission("system:user:reset-password"), c.ResetPassword)
+		users.GET("/locked", middleware.RequirePermission("system:user:list"), c.GetLockedUsers)
+		users.POST("", middleware.DenyImpersonation(), middleware.RequirePermission("system:user:create"), c.CreateUser)
+		users.PUT("/:id", middleware.DenyImpersonation(), middleware.RequirePermission("system:user:update"), c.UpdateUser)
+		users.DELETE("/:id", middleware.DenyImpersonation(), middleware.RequirePermission("system:user:delete"), c.DeleteUser)
+		users.PUT("/:id/reset-password", middleware.DenyImpersonation(), middleware.RequirePermission("system:user:reset-password"), c.ResetPassword)
+		users.POST("/:id/force-logout", middleware.DenyImpersonation(), middleware.RequirePermission("system:user:force-logout"), c.ForceLogout)
+		users.PUT("/:id/unlock", middleware.DenyImpersonation(), middleware.RequirePermission("system:user:unlock"), c.UnlockUser)
+		users.POST("/:id/reset-2fa", middleware.DenyImpersonation(), middleware.RequirePermission("system:user:reset-2fa"), c.Reset2FA)
+		users.GET("/:id/roles", middleware.RequirePermission("system:user:list"), c.GetUserRoles)
+		users.PUT("/:id/roles", middleware.DenyImpersonation(), middleware.RequirePermission("system:user:update"), c.UpdateUserRoles)
+		users.GET("/:id/employee", middleware.RequirePermission("system:user:list"), c.GetUserEmployee)
+		users.PUT("/:id/employee", middleware.DenyImpersonation(), middleware.RequirePermission("system:user:update"), c.BindUserEmployee)
+		users.DELETE("/:id/employee", middleware.DenyImpersonation(), middleware.RequirePermission("system:user:update"), c.UnbindUserEmployee)
+		users.POST("/service-accounts", middleware.DenyImpersonation(), middleware.RequirePermission("system:service-account:create"), c.CreateServiceAccount)
+		users.GET("/:id/api-keys", middleware.RequirePermission("system:api-key:list"), c.GetApiKeyList)
+		users.POST("/:id/api-keys", middleware.DenyImpersonation(), middleware.RequirePermission("system:api-key:create"), c.CreateApiKey)
+		// 模拟登录仅限超级管理员，由服务层校验
+		users.POST("/:id/impersonate", middleware.DenyImpersonation(), c.Impersonate)
 	}
 
 	// 角色管理接口，需要认证和权限
 	roles := r.Group("/api/roles").Use(middleware.JWT())
 	{
 		roles.GET("", middleware.RequirePermission("system:role:list"), c.GetRoleList)
-		roles.POST("", middleware.RequirePermission("system:role:create"), c.CreateRole)
-		roles.PUT("/:id", middleware.RequirePermission("system:role:update"), c.UpdateRole)
-		roles.DELETE("/:id", middleware.RequirePermission("system:role:delete"), c.DeleteRole)
+		roles.POST("", middleware.DenyImpersonation(), middleware.RequirePermission("system:role:create"), c.CreateRole)
+		roles.PUT("/:id", middleware.DenyImpersonation(), middleware.RequirePermission("system:role:update"), c.UpdateRole)
+		roles.DELETE("/:id", middleware.DenyImpersonation(), middleware.RequirePermission("system:role:delete"), c.DeleteRole)
 		roles.GET("/:id/permissions", middleware.RequirePermission("system:role:get-permissions"), c.GetRolePermissions)
-		roles.PUT("/:id/permissions", middleware.RequirePermission("system:role:update-permissions"), c.UpdateRolePermissions)
+		roles.PUT("/:id/permissions", middleware.DenyImpersonation(), middleware.RequirePermission("system:role:update-permissions"), c.UpdateRolePermissions)
+		roles.GET("/:id/departments", middleware.RequirePermission("system:role:list"), c.GetRoleDepartments)
+		roles.PUT("/:id/departments", middleware.DenyImpersonation(), middleware.RequirePermission("system:role:update"), c.UpdateRoleDepartments)
+	}
+
+	// 在线会话管理接口，需要认证和权限
+	sessions := r.Group("/api/sessions").Use(middleware.JWT())
+	{
+		sessions.GET("", middleware.RequirePermission("system:session:list"), c.GetSessionList)
+		sessions.DELETE("/:id", middleware.DenyImpersonation(), middleware.RequirePermission("system:session:revoke"), c.RevokeSession)
+	}
+
+	// API密钥管理接口，需要认证和权限
+	apiKeys := r.Group("/api/api-keys").Use(middleware.JWT())
+	{
+		apiKeys.DELETE("/:id", middleware.DenyImpersonation(), middleware.RequirePermission("system:api-key:revoke"), c.RevokeApiKey)
 	}
 
 	// 权限管理接口，需要认证和权限
 	permissions := r.Group("/api/permissions").Use(middleware.JWT())
 	{
 		permissions.GET("", middleware.RequirePermission("system:permission:list"), c.GetPermissionList)
-		permissions.POST("", middleware.RequirePermission("system:permission:create"), c.CreatePermission)
-		permissions.PUT("/:id", middleware.RequirePermission("system:permission:update"), c.UpdatePermission)
-		permissions.DELETE("/:id", middleware.RequirePermission("system:permission:delete"), c.DeletePermission)
+		permissions.POST("", middleware.DenyImpersonation(), middleware.RequirePermission("system:permission:create"), c.CreatePermission)
+		permissions.PUT("/:id", middleware.DenyImpersonation(), middleware.RequirePermission("system:permission:update"), c.UpdatePermission)
+		permissions.DELETE("/:id", middleware.DenyImpersonation(), middleware.RequirePermission("system:permission:delete"), c.DeletePermission)
 	}
 }
 
@@ -77,29 +123,206 @@ func (c *AuthController) Login(ctx *gin.Context) {
 	}
 
 	if err := ctx.ShouldBindJSON(&params); err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		respondError(ctx, http.StatusBadRequest, err)
 		return
 	}
 
-	token, err := c.authService.Login(params.Username, params.Password, ctx.ClientIP(), ctx.Request.UserAgent())
+	result, err := c.authService.WithContext(ctx.Request.Context()).Login(params.Username, params.Password, ctx.ClientIP(), ctx.Request.UserAgent())
 	if err != nil {
-		ctx.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+		respondLoginError(ctx, err)
 		return
 	}
 
-	ctx.JSON(http.StatusOK, gin.H{
-		"token": token,
-	})
+	ctx.JSON(http.StatusOK, result)
+}
+
+// VerifyLogin2FA 登录第二步，校验两步验证码
+func (c *AuthController) VerifyLogin2FA(ctx *gin.Context) {
+	var params struct {
+		ChallengeToken string `json:"challenge_token" binding:"required"`
+		Code           string `json:"code" binding:"required"`
+	}
+
+	if err := ctx.ShouldBindJSON(&params); err != nil {
+		respondError(ctx, http.StatusBadRequest, err)
+		return
+	}
+
+	result, err := c.authService.WithContext(ctx.Request.Context()).VerifyLogin2FA(params.ChallengeToken, params.Code, ctx.ClientIP(), ctx.Request.UserAgent())
+	if err != nil {
+		respondLoginError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, result)
+}
+
+// SetupLogin2FA 登录过程中生成两步验证绑定密钥
+func (c *AuthController) SetupLogin2FA(ctx *gin.Context) {
+	var params struct {
+		ChallengeToken string `json:"challenge_token" binding:"required"`
+	}
+
+	if err := ctx.ShouldBindJSON(&params); err != nil {
+		respondError(ctx, http.StatusBadRequest, err)
+		return
+	}
+
+	setup, err := c.authService.WithContext(ctx.Request.Context()).SetupLogin2FA(params.ChallengeToken)
+	if err != nil {
+		respondLoginError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, setup)
+}
+
+// ConfirmLogin2FASetup 登录过程中确认两步验证绑定并完成登录
+func (c *AuthController) ConfirmLogin2FASetup(ctx *gin.Context) {
+	var params struct {
+		ChallengeToken string `json:"challenge_token" binding:"required"`
+		Code           string `json:"code" binding:"required"`
+	}
+
+	if err := ctx.ShouldBindJSON(&params); err != nil {
+		respondError(ctx, http.StatusBadRequest, err)
+		return
+	}
+
+	result, err := c.authService.WithContext(ctx.Request.Context()).ConfirmLogin2FASetup(params.ChallengeToken, params.Code, ctx.ClientIP(), ctx.Request.UserAgent())
+	if err != nil {
+		respondLoginError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, result)
+}
+
+// ChangeExpiredPassword 登录过程中修改已过期或被重置的密码并完成登录
+func (c *AuthController) ChangeExpiredPassword(ctx *gin.Context) {
+	var params struct {
+		ChallengeToken string `json:"challenge_token" binding:"required"`
+		NewPassword    string `json:"new_password" binding:"required"`
+	}
+
+	if err := ctx.ShouldBindJSON(&params); err != nil {
+		respondError(ctx, http.StatusBadRequest, err)
+		return
+	}
+
+	result, err := c.authService.WithContext(ctx.Request.Context()).ChangeExpiredPassword(params.ChallengeToken, params.NewPassword, ctx.ClientIP(), ctx.Request.UserAgent())
+	if err != nil {
+		// 新密码不符合密码策略时返回400，可修改后重试
+		if errors.Is(err, service.ErrInvalidPasswordChangeToken) || errors.Is(err, service.ErrInvalidChallengeToken) || errors.Is(err, service.ErrAccountLocked) {
+			respondLoginError(ctx, err)
+			return
+		}
+		respondError(ctx, http.StatusBadRequest, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, result)
+}
+
+// OIDCAuthorize 获取单点登录的IdP授权地址
+func (c *AuthController) OIDCAuthorize(ctx *gin.Context) {
+	url, err := c.authService.WithContext(ctx.Request.Context()).OIDCAuthorizeURL()
+	if err != nil {
+		if errors.Is(err, service.ErrOIDCDisabled) {
+			respondError(ctx, http.StatusNotFound, err)
+			return
+		}
+		respondLoginError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"authorization_url": url})
+}
+
+// OIDCCallback 单点登录回调，前端将IdP返回的code和state提交到此接口完成登录
+func (c *AuthController) OIDCCallback(ctx *gin.Context) {
+	var params struct {
+		Code  string `json:"code" binding:"required"`
+		State string `json:"state" binding:"required"`
+	}
+
+	if err := ctx.ShouldBindJSON(&params); err != nil {
+		respondError(ctx, http.StatusBadRequest, err)
+		return
+	}
+
+	result, err := c.authService.WithContext(ctx.Request.Context()).OIDCCallback(params.Code, params.State, ctx.ClientIP(), ctx.Request.UserAgent())
+	if err != nil {
+		if errors.Is(err, service.ErrOIDCDisabled) {
+			respondError(ctx, http.StatusNotFound, err)
+			return
+		}
+		respondLoginError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, result)
+}
+
+// 登录相关错误：锁定和限流返回429，外部认证服务不可用返回503，其余返回401
+func respondLoginError(ctx *gin.Context, err error) {
+	if errors.Is(err, service.ErrAccountLocked) || errors.Is(err, service.ErrTooManyAttempts) {
+		respondError(ctx, http.StatusTooManyRequests, err)
+		return
+	}
+	if errors.Is(err, service.ErrAuthProviderUnavailable) {
+		respondError(ctx, http.StatusServiceUnavailable, err)
+		return
+	}
+	respondError(ctx, http.StatusUnauthorized, err)
+}
+
+// RefreshToken 刷新令牌
+func (c *AuthController) RefreshToken(ctx *gin.Context) {
+	var params struct {
+		RefreshToken string `json:"refresh_token" binding:"required"`
+	}
+
+	if err := ctx.ShouldBindJSON(&params); err != nil {
+		respondError(ctx, http.StatusBadRequest, err)
+		return
+	}
+
+	tokens, err := c.authService.WithContext(ctx.Request.Context()).RefreshToken(params.RefreshToken, ctx.ClientIP(), ctx.Request.UserAgent())
+	if err != nil {
+		respondError(ctx, http.StatusUnauthorized, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, tokens)
+}
+
+// Logout 退出登录
+func (c *AuthController) Logout(ctx *gin.Context) {
+	var params struct {
+		RefreshToken string `json:"refresh_token"`
+	}
+	// 请求体可选，未提供刷新令牌时只吊销当前访问令牌
+	_ = ctx.ShouldBindJSON(&params)
+
+	jti := ctx.GetString("token_jti")
+	exp := ctx.GetTime("token_exp")
+
+	if err := c.authService.WithContext(ctx.Request.Context()).Logout(middleware.CurrentUserID(ctx), jti, exp, params.RefreshToken); err != nil {
+		respondError(ctx, http.StatusInternalServerError, err)
+		return
+	}
+
+	ctx.Status(http.StatusNoContent)
 }
 
 // GetUserInfo 获取用户信息
 func (c *AuthController) GetUserInfo(ctx *gin.Context) {
-	// 从JWT中获取userID
-	userID := uint(1) // TODO: 从JWT中获取
+	userID := middleware.CurrentUserID(ctx)
 
-	user, err := c.authService.GetUserInfo(userID)
+	user, err := c.authService.WithContext(ctx.Request.Context()).GetUserInfo(userID)
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -108,12 +331,11 @@ func (c *AuthController) GetUserInfo(ctx *gin.Context) {
 
 // GetUserPermissions 获取用户权限列表
 func (c *AuthController) GetUserPermissions(ctx *gin.Context) {
-	// 从JWT中获取userID
-	userID := uint(1) // TODO: 从JWT中获取
+	userID := middleware.CurrentUserID(ctx)
 
-	permissions, err := c.authService.GetUserPermissions(userID)
+	permissions, err := c.authService.WithContext(ctx.Request.Context()).GetUserPermissions(userID)
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -128,15 +350,107 @@ func (c *AuthController) ChangePassword(ctx *gin.Context) {
 	}
 
 	if err := ctx.ShouldBindJSON(&params); err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		respondError(ctx, http.StatusBadRequest, err)
 		return
 	}
 
-	// 从JWT中获取userID
-	userID := uint(1) // TODO: 从JWT中获取
+	userID := middleware.CurrentUserID(ctx)
 
-	if err := c.authService.ChangePassword(userID, params.OldPassword, params.NewPassword); err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	if err := c.authService.WithContext(ctx.Request.Context()).ChangePassword(userID, params.OldPassword, params.NewPassword); err != nil {
+		respondError(ctx, http.StatusInternalServerError, err)
+		return
+	}
+
+	ctx.Status(http.StatusNoContent)
+}
+
+// Setup2FA 生成两步验证绑定密钥
+func (c *AuthController) Setup2FA(ctx *gin.Context) {
+	setup, err := c.authService.WithContext(ctx.Request.Context()).Setup2FA(middleware.CurrentUserID(ctx))
+	if err != nil {
+		respondError(ctx, http.StatusBadRequest, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, setup)
+}
+
+// Enable2FA 校验验证码并开启两步验证
+func (c *AuthController) Enable2FA(ctx *gin.Context) {
+	var params struct {
+		Code string `json:"code" binding:"required"`
+	}
+
+	if err := ctx.ShouldBindJSON(&params); err != nil {
+		respondError(ctx, http.StatusBadRequest, err)
+		return
+	}
+
+	codes, err := c.authService.WithContext(ctx.Request.Context()).Enable2FA(middleware.CurrentUserID(ctx), params.Code)
+	if err != nil {
+		respondError(ctx, http.StatusBadRequest, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"recovery_codes": codes})
+}
+
+// Disable2FA 关闭两步验证
+func (c *AuthController) Disable2FA(ctx *gin.Context) {
+	var params struct {
+		Password string `json:"password" binding:"required"`
+		Code     string `json:"code" binding:"required"`
+	}
+
+	if err := ctx.ShouldBindJSON(&params); err != nil {
+		respondError(ctx, http.StatusBadRequest, err)
+		return
+	}
+
+	if err := c.authService.WithContext(ctx.Request.Context()).Disable2FA(middleware.CurrentUserID(ctx), params.Password, params.Code); err != nil {
+		respondError(ctx, http.StatusBadRequest, err)
+		return
+	}
+
+	ctx.Status(http.StatusNoContent)
+}
+
+// RegenerateRecoveryCodes 重新生成两步验证恢复码
+func (c *AuthController) RegenerateRecoveryCodes(ctx *gin.Context) {
+	var params struct {
+		Code string `json:"code" binding:"required"`
+	}
+
+	if err := ctx.ShouldBindJSON(&params); err != nil {
+		respondError(ctx, http.StatusBadRequest, err)
+		return
+	}
+
+	codes, err := c.authService.WithContext(ctx.Request.Context()).RegenerateRecoveryCodes(middleware.CurrentUserID(ctx), params.Code)
+	if err != nil {
+		respondError(ctx, http.StatusBadRequest, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"recovery_codes": codes})
+}
+
+// GetMySessions 获取当前用户已登录的设备
+func (c *AuthController) GetMySessions(ctx *gin.Context) {
+	sessions, err := c.authService.WithContext(ctx.Request.Context()).GetMySessions(middleware.CurrentUserID(ctx), middleware.CurrentSessionID(ctx))
+	if err != nil {
+		respondError(ctx, http.StatusInternalServerError, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, sessions)
+}
+
+// RevokeMySession 注销当前用户的某个登录设备
+func (c *AuthController) RevokeMySession(ctx *gin.Context) {
+	id, _ := strconv.ParseUint(ctx.Param("id"), 10, 32)
+	if err := c.authService.WithContext(ctx.Request.Context()).RevokeMySession(middleware.CurrentUserID(ctx), uint(id)); err != nil {
+		respondError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -150,12 +464,13 @@ func (c *AuthController) GetUserList(ctx *gin.Context) {
 	keyword := ctx.Query("keyword")
 	status, _ := strconv.Atoi(ctx.Query("status"))
 
-	users, total, err := c.authService.GetUserList(status, keyword, page, pageSize)
+	users, total, err := c.authService.WithContext(ctx.Request.Context()).GetUserList(status, keyword, page, pageSize)
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 
+	middleware.MaskSensitive(ctx, users)
 	ctx.JSON(http.StatusOK, gin.H{
 		"data":  users,
 		"total": total,
@@ -164,17 +479,36 @@ func (c *AuthController) GetUserList(ctx *gin.Context) {
 
 // CreateUser 创建用户
 func (c *AuthController) CreateUser(ctx *gin.Context) {
-	var user model.User
-	if err := ctx.ShouldBindJSON(&user); err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	var req struct {
+		Username           string `json:"username" binding:"required"`
+		Password           string `json:"password" binding:"required"`
+		RealName           string `json:"real_name"`
+		Avatar             string `json:"avatar"`
+		Email              string `json:"email"`
+		Mobile             string `json:"mobile"`
+		Status             int    `json:"status"`
+		EmployeeID         *uint  `json:"employee_id"`
+		MustChangePassword bool   `json:"must_change_password"`
+	}
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		respondError(ctx, http.StatusBadRequest, err)
 		return
 	}
 
-	// TODO: 从JWT中获取当前用户ID
-	user.CreatedBy = uint(1)
+	user := model.User{
+		Username:           req.Username,
+		Password:           req.Password,
+		RealName:           req.RealName,
+		Avatar:             req.Avatar,
+		Email:              req.Email,
+		Mobile:             req.Mobile,
+		Status:             req.Status,
+		EmployeeID:         req.EmployeeID,
+		MustChangePassword: req.MustChangePassword,
+	}
 
-	if err := c.authService.CreateUser(&user); err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	if err := c.authService.WithContext(ctx.Request.Context()).CreateUser(&user); err != nil {
+		respondError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -184,15 +518,28 @@ func (c *AuthController) CreateUser(ctx *gin.Context) {
 // UpdateUser 更新用户
 func (c *AuthController) UpdateUser(ctx *gin.Context) {
 	id, _ := strconv.ParseUint(ctx.Param("id"), 10, 32)
-	var user model.User
-	if err := ctx.ShouldBindJSON(&user); err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	var req struct {
+		RealName string `json:"real_name"`
+		Avatar   string `json:"avatar"`
+		Email    string `json:"email"`
+		Mobile   string `json:"mobile"`
+		Status   int    `json:"status"`
+	}
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		respondError(ctx, http.StatusBadRequest, err)
 		return
 	}
 
-	user.ID = uint(id)
-	if err := c.authService.UpdateUser(&user); err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	user := model.User{
+		ID:       uint(id),
+		RealName: req.RealName,
+		Avatar:   req.Avatar,
+		Email:    req.Email,
+		Mobile:   req.Mobile,
+		Status:   req.Status,
+	}
+	if err := c.authService.WithContext(ctx.Request.Context()).UpdateUser(&user); err != nil {
+		respondError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -202,8 +549,8 @@ func (c *AuthController) UpdateUser(ctx *gin.Context) {
 // DeleteUser 删除用户
 func (c *AuthController) DeleteUser(ctx *gin.Context) {
 	id, _ := strconv.ParseUint(ctx.Param("id"), 10, 32)
-	if err := c.authService.DeleteUser(uint(id)); err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	if err := c.authService.WithContext(ctx.Request.Context()).DeleteUser(uint(id)); err != nil {
+		respondError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -217,12 +564,276 @@ func (c *AuthController) ResetPassword(ctx *gin.Context) {
 		Password string `json:"password" binding:"required"`
 	}
 	if err := ctx.ShouldBindJSON(&req); err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		respondError(ctx, http.StatusBadRequest, err)
+		return
+	}
+
+	if err := c.authService.WithContext(ctx.Request.Context()).ResetPassword(uint(id), req.Password); err != nil {
+		respondError(ctx, http.StatusInternalServerError, err)
+		return
+	}
+
+	ctx.Status(http.StatusNoContent)
+}
+
+// ForceLogout 强制用户下线
+func (c *AuthController) ForceLogout(ctx *gin.Context) {
+	id, _ := strconv.ParseUint(ctx.Param("id"), 10, 32)
+	if err := c.authService.WithContext(ctx.Request.Context()).ForceLogout(uint(id)); err != nil {
+		respondError(ctx, http.StatusInternalServerError, err)
+		return
+	}
+
+	ctx.Status(http.StatusNoContent)
+}
+
+// Impersonate 超级管理员模拟用户登录，返回以该用户身份访问的限时令牌
+func (c *AuthController) Impersonate(ctx *gin.Context) {
+	id, _ := strconv.ParseUint(ctx.Param("id"), 10, 32)
+
+	var params struct {
+		Reason string `json:"reason" binding:"required"`
+	}
+
+	if err := ctx.ShouldBindJSON(&params); err != nil {
+		respondError(ctx, http.StatusBadRequest, err)
+		return
+	}
+
+	token, err := c.authService.WithContext(ctx.Request.Context()).Impersonate(middleware.CurrentUserID(ctx), middleware.CurrentSessionID(ctx), uint(id), params.Reason, ctx.ClientIP(), ctx.Request.UserAgent())
+	if err != nil {
+		if errors.Is(err, service.ErrImpersonationForbidden) {
+			respondError(ctx, http.StatusForbidden, err)
+			return
+		}
+		respondError(ctx, http.StatusBadRequest, err)
+		return
+	}
+
+	middleware.MaskSensitive(ctx, token.User)
+	ctx.JSON(http.StatusOK, token)
+}
+
+// GetMyEmployee 获取当前用户关联的员工
+func (c *AuthController) GetMyEmployee(ctx *gin.Context) {
+	employee, err := c.authService.WithContext(ctx.Request.Context()).GetMyEmployee(middleware.CurrentUserID(ctx))
+	if err != nil {
+		if errors.Is(err, service.ErrEmployeeNotBound) {
+			respondError(ctx, http.StatusNotFound, err)
+			return
+		}
+		respondError(ctx, http.StatusInternalServerError, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, employee)
+}
+
+// GetUserEmployee 获取用户关联的员工，未关联时返回null
+func (c *AuthController) GetUserEmployee(ctx *gin.Context) {
+	id, _ := strconv.ParseUint(ctx.Param("id"), 10, 32)
+	employee, err := c.authService.WithContext(ctx.Request.Context()).GetUserEmployee(uint(id))
+	if err != nil {
+		respondError(ctx, http.StatusInternalServerError, err)
+		return
+	}
+
+	middleware.MaskSensitive(ctx, employee)
+	ctx.JSON(http.StatusOK, employee)
+}
+
+// BindUserEmployee 关联用户和员工
+func (c *AuthController) BindUserEmployee(ctx *gin.Context) {
+	id, _ := strconv.ParseUint(ctx.Param("id"), 10, 32)
+	var params struct {
+		EmployeeID uint `json:"employee_id" binding:"required"`
+	}
+
+	if err := ctx.ShouldBindJSON(&params); err != nil {
+		respondError(ctx, http.StatusBadRequest, err)
+		return
+	}
+
+	if err := c.authService.WithContext(ctx.Request.Context()).BindUserEmployee(uint(id), params.EmployeeID); err != nil {
+		if errors.Is(err, service.ErrEmployeeAlreadyBound) {
+			respondError(ctx, http.StatusConflict, err)
+			return
+		}
+		respondError(ctx, http.StatusBadRequest, err)
+		return
+	}
+
+	ctx.Status(http.StatusNoContent)
+}
+
+// UnbindUserEmployee 解除用户和员工的关联
+func (c *AuthController) UnbindUserEmployee(ctx *gin.Context) {
+	id, _ := strconv.ParseUint(ctx.Param("id"), 10, 32)
+	if err := c.authService.WithContext(ctx.Request.Context()).UnbindUserEmployee(uint(id)); err != nil {
+		respondError(ctx, http.StatusInternalServerError, err)
+		return
+	}
+
+	ctx.Status(http.StatusNoContent)
+}
+
+// GetLockedUsers 获取锁定用户列表
+func (c *AuthController) GetLockedUsers(ctx *gin.Context) {
+	page, _ := strconv.Atoi(ctx.DefaultQuery("page", "1"))
+	pageSize, _ := strconv.Atoi(ctx.DefaultQuery("page_size", "10"))
+
+	users, total, err := c.authService.WithContext(ctx.Request.Context()).GetLockedUsers(page, pageSize)
+	if err != nil {
+		respondError(ctx, http.StatusInternalServerError, err)
+		return
+	}
+
+	middleware.MaskSensitive(ctx, users)
+	ctx.JSON(http.StatusOK, gin.H{
+		"data":  users,
+		"total": total,
+	})
+}
+
+// UnlockUser 解除账号锁定
+func (c *AuthController) UnlockUser(ctx *gin.Context) {
+	id, _ := strconv.ParseUint(ctx.Param("id"), 10, 32)
+	if err := c.authService.WithContext(ctx.Request.Context()).UnlockUser(uint(id)); err != nil {
+		respondError(ctx, http.StatusInternalServerError, err)
+		return
+	}
+
+	ctx.Status(http.StatusNoContent)
+}
+
+// Reset2FA 重置用户的两步验证
+func (c *AuthController) Reset2FA(ctx *gin.Context) {
+	id, _ := strconv.ParseUint(ctx.Param("id"), 10, 32)
+	if err := c.authService.WithContext(ctx.Request.Context()).Reset2FA(uint(id)); err != nil {
+		respondError(ctx, http.StatusInternalServerError, err)
+		return
+	}
+
+	ctx.Status(http.StatusNoContent)
+}
+
+// GetUserRoles 获取用户角色
+func (c *AuthController) GetUserRoles(ctx *gin.Context) {
+	id, _ := strconv.ParseUint(ctx.Param("id"), 10, 32)
+	roles, err := c.authService.WithContext(ctx.Request.Context()).GetUserRoles(uint(id))
+	if err != nil {
+		respondError(ctx, http.StatusInternalServerError, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, roles)
+}
+
+// UpdateUserRoles 更新用户角色
+func (c *AuthController) UpdateUserRoles(ctx *gin.Context) {
+	id, _ := strconv.ParseUint(ctx.Param("id"), 10, 32)
+	var req struct {
+		RoleIDs []uint `json:"role_ids" binding:"required"`
+	}
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		respondError(ctx, http.StatusBadRequest, err)
 		return
 	}
 
-	if err := c.authService.ResetPassword(uint(id), req.Password); err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	if err := c.authService.WithContext(ctx.Request.Context()).UpdateUserRoles(uint(id), req.RoleIDs, middleware.CurrentUserID(ctx)); err != nil {
+		respondError(ctx, http.StatusInternalServerError, err)
+		return
+	}
+
+	ctx.Status(http.StatusNoContent)
+}
+
+// GetSessionList 获取在线会话列表
+func (c *AuthController) GetSessionList(ctx *gin.Context) {
+	userID, _ := strconv.ParseUint(ctx.Query("user_id"), 10, 32)
+	includeRevoked, _ := strconv.ParseBool(ctx.Query("include_revoked"))
+	page, _ := strconv.Atoi(ctx.DefaultQuery("page", "1"))
+	pageSize, _ := strconv.Atoi(ctx.DefaultQuery("page_size", "10"))
+
+	sessions, total, err := c.authService.WithContext(ctx.Request.Context()).GetSessionList(uint(userID), includeRevoked, page, pageSize)
+	if err != nil {
+		respondError(ctx, http.StatusInternalServerError, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"data":  sessions,
+		"total": total,
+	})
+}
+
+// RevokeSession 终止会话
+func (c *AuthController) RevokeSession(ctx *gin.Context) {
+	id, _ := strconv.ParseUint(ctx.Param("id"), 10, 32)
+	if err := c.authService.WithContext(ctx.Request.Context()).RevokeSession(uint(id)); err != nil {
+		respondError(ctx, http.StatusInternalServerError, err)
+		return
+	}
+
+	ctx.Status(http.StatusNoContent)
+}
+
+// CreateServiceAccount 创建服务账号
+func (c *AuthController) CreateServiceAccount(ctx *gin.Context) {
+	var user model.User
+	if err := ctx.ShouldBindJSON(&user); err != nil {
+		respondError(ctx, http.StatusBadRequest, err)
+		return
+	}
+
+	user.CreatedBy = middleware.CurrentUserID(ctx)
+	if err := c.authService.WithContext(ctx.Request.Context()).CreateServiceAccount(&user); err != nil {
+		respondError(ctx, http.StatusInternalServerError, err)
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, user)
+}
+
+// GetApiKeyList 获取用户的API密钥列表
+func (c *AuthController) GetApiKeyList(ctx *gin.Context) {
+	id, _ := strconv.ParseUint(ctx.Param("id"), 10, 32)
+	keys, err := c.authService.WithContext(ctx.Request.Context()).GetApiKeyList(uint(id))
+	if err != nil {
+		respondError(ctx, http.StatusInternalServerError, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, keys)
+}
+
+// CreateApiKey 为服务账号创建API密钥，明文密钥只在此时返回
+func (c *AuthController) CreateApiKey(ctx *gin.Context) {
+	id, _ := strconv.ParseUint(ctx.Param("id"), 10, 32)
+	var req struct {
+		Name      string     `json:"name" binding:"required"`
+		Scopes    []string   `json:"scopes"`
+		ExpiresAt *time.Time `json:"expires_at"`
+	}
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		respondError(ctx, http.StatusBadRequest, err)
+		return
+	}
+
+	key, err := c.authService.WithContext(ctx.Request.Context()).CreateApiKey(uint(id), req.Name, req.Scopes, req.ExpiresAt, middleware.CurrentUserID(ctx))
+	if err != nil {
+		respondError(ctx, http.StatusBadRequest, err)
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, key)
+}
+
+// RevokeApiKey 吊销API密钥
+func (c *AuthController) RevokeApiKey(ctx *gin.Context) {
+	id, _ := strconv.ParseUint(ctx.Param("id"), 10, 32)
+	if err := c.authService.WithContext(ctx.Request.Context()).RevokeApiKey(uint(id)); err != nil {
+		respondError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -231,9 +842,9 @@ func (c *AuthController) ResetPassword(ctx *gin.Context) {
 
 // GetRoleList 获取角色列表
 func (c *AuthController) GetRoleList(ctx *gin.Context) {
-	roles, err := c.authService.GetRoleList()
+	roles, err := c.authService.WithContext(ctx.Request.Context()).GetRoleList()
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -244,15 +855,12 @@ func (c *AuthController) GetRoleList(ctx *gin.Context) {
 func (c *AuthController) CreateRole(ctx *gin.Context) {
 	var role model.Role
 	if err := ctx.ShouldBindJSON(&role); err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		respondError(ctx, http.StatusBadRequest, err)
 		return
 	}
 
-	// TODO: 从JWT中获取当前用户ID
-	role.CreatedBy = uint(1)
-
-	if err := c.authService.CreateRole(&role); err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	if err := c.authService.WithContext(ctx.Request.Context()).CreateRole(&role); err != nil {
+		respondError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -262,15 +870,15 @@ func (c *AuthController) CreateRole(ctx *gin.Context) {
 // UpdateRole 更新角色
 func (c *AuthController) UpdateRole(ctx *gin.Context) {
 	id, _ := strconv.ParseUint(ctx.Param("id"), 10, 32)
-	var role model.Role
-	if err := ctx.ShouldBindJSON(&role); err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	var req service.RoleUpdate
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		respondError(ctx, http.StatusBadRequest, err)
 		return
 	}
 
-	role.ID = uint(id)
-	if err := c.authService.UpdateRole(&role); err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	role, err := c.authService.WithContext(ctx.Request.Context()).UpdateRole(uint(id), req)
+	if err != nil {
+		respondError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -280,8 +888,8 @@ func (c *AuthController) UpdateRole(ctx *gin.Context) {
 // DeleteRole 删除角色
 func (c *AuthController) DeleteRole(ctx *gin.Context) {
 	id, _ := strconv.ParseUint(ctx.Param("id"), 10, 32)
-	if err := c.authService.DeleteRole(uint(id)); err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	if err := c.authService.WithContext(ctx.Request.Context()).DeleteRole(uint(id)); err != nil {
+		respondError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -291,9 +899,9 @@ func (c *AuthController) DeleteRole(ctx *gin.Context) {
 // GetRolePermissions 获取角色权限
 func (c *AuthController) GetRolePermissions(ctx *gin.Context) {
 	id, _ := strconv.ParseUint(ctx.Param("id"), 10, 32)
-	permissions, err := c.authService.GetRolePermissions(uint(id))
+	permissions, err := c.authService.WithContext(ctx.Request.Context()).GetRolePermissions(uint(id))
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -307,12 +915,43 @@ func (c *AuthController) UpdateRolePermissions(ctx *gin.Context) {
 		PermissionIDs []uint `json:"permission_ids" binding:"required"`
 	}
 	if err := ctx.ShouldBindJSON(&req); err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		respondError(ctx, http.StatusBadRequest, err)
 		return
 	}
 
-	if err := c.authService.UpdateRolePermissions(uint(id), req.PermissionIDs); err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	if err := c.authService.WithContext(ctx.Request.Context()).UpdateRolePermissions(uint(id), req.PermissionIDs); err != nil {
+		respondError(ctx, http.StatusInternalServerError, err)
+		return
+	}
+
+	ctx.Status(http.StatusNoContent)
+}
+
+// GetRoleDepartments 获取角色自定义数据权限部门
+func (c *AuthController) GetRoleDepartments(ctx *gin.Context) {
+	id, _ := strconv.ParseUint(ctx.Param("id"), 10, 32)
+	departmentIDs, err := c.authService.WithContext(ctx.Request.Context()).GetRoleDepartments(uint(id))
+	if err != nil {
+		respondError(ctx, http.StatusInternalServerError, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"department_ids": departmentIDs})
+}
+
+// UpdateRoleDepartments 更新角色自定义数据权限部门
+func (c *AuthController) UpdateRoleDepartments(ctx *gin.Context) {
+	id, _ := strconv.ParseUint(ctx.Param("id"), 10, 32)
+	var req struct {
+		DepartmentIDs []uint `json:"department_ids" binding:"required"`
+	}
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		respondError(ctx, http.StatusBadRequest, err)
+		return
+	}
+
+	if err := c.authService.WithContext(ctx.Request.Context()).UpdateRoleDepartments(uint(id), req.DepartmentIDs, middleware.CurrentUserID(ctx)); err != nil {
+		respondError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -321,9 +960,9 @@ func (c *AuthController) UpdateRolePermissions(ctx *gin.Context) {
 
 // GetPermissionList 获取权限列表
 func (c *AuthController) GetPermissionList(ctx *gin.Context) {
-	permissions, err := c.authService.GetPermissionList()
+	permissions, err := c.authService.WithContext(ctx.Request.Context()).GetPermissionList()
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -334,15 +973,12 @@ func (c *AuthController) GetPermissionList(ctx *gin.Context) {
 func (c *AuthController) CreatePermission(ctx *gin.Context) {
 	var permission model.Permission
 	if err := ctx.ShouldBindJSON(&permission); err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		respondError(ctx, http.StatusBadRequest, err)
 		return
 	}
 
-	// TODO: 从JWT中获取当前用户ID
-	permission.CreatedBy = uint(1)
-
-	if err := c.authService.CreatePermission(&permission); err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	if err := c.authService.WithContext(ctx.Request.Context()).CreatePermission(&permission); err != nil {
+		respondError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -354,13 +990,13 @@ func (c *AuthController) UpdatePermission(ctx *gin.Context) {
 	id, _ := strconv.ParseUint(ctx.Param("id"), 10, 32)
 	var permission model.Permission
 	if err := ctx.ShouldBindJSON(&permission); err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		respondError(ctx, http.StatusBadRequest, err)
 		return
 	}
 
 	permission.ID = uint(id)
-	if err := c.authService.UpdatePermission(&permission); err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	if err := c.authService.WithContext(ctx.Request.Context()).UpdatePermission(&permission); err != nil {
+		respondError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -370,8 +1006,8 @@ func (c *AuthController) UpdatePermission(ctx *gin.Context) {
 // DeletePermission 删除权限
 func (c *AuthController) DeletePermission(ctx *gin.Context) {
 	id, _ := strconv.ParseUint(ctx.Param("id"), 10, 32)
-	if err := c.authService.DeletePermission(uint(id)); err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	if err := c.authService.WithContext(ctx.Request.Context()).DeletePermission(uint(id)); err != nil {
+		respondError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -0,0 +1,100 @@
+package controller
+
+import (
+	"errors"
+	"net/http"
+	"net/url"
+	"strconv"
+
+	"github.com/lemonoa/LemonOA-Go/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// GetBackupRecordList 获取备份记录列表
+func (c *SystemController) GetBackupRecordList(ctx *gin.Context) {
+	page, _ := strconv.Atoi(ctx.DefaultQuery("page", "1"))
+	pageSize, _ := strconv.Atoi(ctx.DefaultQuery("page_size", "10"))
+
+	records, total, err := c.systemService.WithContext(ctx.Request.Context()).GetBackupRecordList(page, pageSize)
+	if err != nil {
+		respondError(ctx, http.StatusInternalServerError, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"data":  records,
+		"total": total,
+	})
+}
+
+// CreateBackupRecord 创建备份，备份在后台执行，通过备份记录的状态和进度查看结果
+func (c *SystemController) CreateBackupRecord(ctx *gin.Context) {
+	var opts service.BackupOptions
+	if err := ctx.ShouldBindJSON(&opts); err != nil {
+		respondError(ctx, http.StatusBadRequest, err)
+		return
+	}
+
+	record, err := c.systemService.WithContext(ctx.Request.Context()).StartBackup(opts)
+	if err != nil {
+		respondBackupError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusAccepted, record)
+}
+
+// DownloadBackup 下载备份文件
+func (c *SystemController) DownloadBackup(ctx *gin.Context) {
+	id, _ := strconv.ParseUint(ctx.Param("id"), 10, 32)
+	record, file, err := c.systemService.WithContext(ctx.Request.Context()).OpenBackup(uint(id))
+	if err != nil {
+		respondBackupError(ctx, err)
+		return
+	}
+	defer file.Close()
+
+	ctx.Header("Content-Type", "application/gzip")
+	ctx.Header("Content-Disposition", "attachment; filename*=UTF-8''"+url.PathEscape(record.Name))
+	http.ServeContent(ctx.Writer, ctx.Request, record.Name, record.CreatedAt, file)
+}
+
+// RestoreBackup 从备份恢复数据，完成后返回
+func (c *SystemController) RestoreBackup(ctx *gin.Context) {
+	id, _ := strconv.ParseUint(ctx.Param("id"), 10, 32)
+	if err := c.systemService.WithContext(ctx.Request.Context()).RestoreBackup(uint(id)); err != nil {
+		respondBackupError(ctx, err)
+		return
+	}
+
+	ctx.Status(http.StatusNoContent)
+}
+
+// DeleteBackupRecord 删除备份记录及备份文件
+func (c *SystemController) DeleteBackupRecord(ctx *gin.Context) {
+	id, _ := strconv.ParseUint(ctx.Param("id"), 10, 32)
+	if err := c.systemService.WithContext(ctx.Request.Context()).DeleteBackupRecord(uint(id)); err != nil {
+		respondBackupError(ctx, err)
+		return
+	}
+
+	ctx.Status(http.StatusNoContent)
+}
+
+// 按错误类型返回备份接口的状态码
+func respondBackupError(ctx *gin.Context, err error) {
+	status := http.StatusInternalServerError
+	switch {
+	case errors.Is(err, service.ErrBackupTypeInvalid):
+		status = http.StatusBadRequest
+	case errors.Is(err, service.ErrBackupNotFound):
+		status = http.StatusNotFound
+	case errors.Is(err, service.ErrBackupRunning), errors.Is(err, service.ErrBackupNotReady),
+		errors.Is(err, service.ErrBackupInUse), errors.Is(err, service.ErrBackupBaseMissing):
+		status = http.StatusConflict
+	case errors.Is(err, service.ErrBackupCorrupted), errors.Is(err, service.ErrBackupVersion):
+		status = http.StatusUnprocessableEntity
+	}
+	respondError(ctx, status, err)
+}
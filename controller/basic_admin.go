@@ -4,6 +4,7 @@ import (
 	"net/http"
 	"strconv"
 
+	"github.com/lemonoa/LemonOA-Go/middleware"
 	"github.com/lemonoa/LemonOA-Go/model"
 	"github.com/lemonoa/LemonOA-Go/service"
 
@@ -23,6 +24,7 @@ func NewBasicAdminController(basicAdminService *service.BasicAdminService) *Basi
 // RegisterRoutes 注册路由
 func (c *BasicAdminController) RegisterRoutes(r *gin.Engine) {
 	api := r.Group("/api/basic/admin")
+	api.Use(middleware.JWT())
 	{
 		// 资产分类
 		api.GET("/asset-categories", c.GetAssetCategoryList)
@@ -78,9 +80,9 @@ func (c *BasicAdminController) GetAssetCategoryList(ctx *gin.Context) {
 		}
 	}
 
-	categories, err := c.basicAdminService.GetAssetCategoryList(parentID)
+	categories, err := c.basicAdminService.WithContext(ctx.Request.Context()).GetAssetCategoryList(parentID)
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -90,9 +92,9 @@ func (c *BasicAdminController) GetAssetCategoryList(ctx *gin.Context) {
 // GetAssetCategoryByID 根据ID获取资产分类
 func (c *BasicAdminController) GetAssetCategoryByID(ctx *gin.Context) {
 	id, _ := strconv.ParseUint(ctx.Param("id"), 10, 32)
-	category, err := c.basicAdminService.GetAssetCategoryByID(uint(id))
+	category, err := c.basicAdminService.WithContext(ctx.Request.Context()).GetAssetCategoryByID(uint(id))
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -103,12 +105,12 @@ func (c *BasicAdminController) GetAssetCategoryByID(ctx *gin.Context) {
 func (c *BasicAdminController) CreateAssetCategory(ctx *gin.Context) {
 	var category model.AssetCategory
 	if err := ctx.ShouldBindJSON(&category); err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		respondError(ctx, http.StatusBadRequest, err)
 		return
 	}
 
-	if err := c.basicAdminService.CreateAssetCategory(&category); err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	if err := c.basicAdminService.WithContext(ctx.Request.Context()).CreateAssetCategory(&category); err != nil {
+		respondError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -120,13 +122,13 @@ func (c *BasicAdminController) UpdateAssetCategory(ctx *gin.Context) {
 	id, _ := strconv.ParseUint(ctx.Param("id"), 10, 32)
 	var category model.AssetCategory
 	if err := ctx.ShouldBindJSON(&category); err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		respondError(ctx, http.StatusBadRequest, err)
 		return
 	}
 
 	category.ID = uint(id)
-	if err := c.basicAdminService.UpdateAssetCategory(&category); err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	if err := c.basicAdminService.WithContext(ctx.Request.Context()).UpdateAssetCategory(&category); err != nil {
+		respondError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -136,8 +138,8 @@ func (c *BasicAdminController) UpdateAssetCategory(ctx *gin.Context) {
 // DeleteAssetCategory 删除资产分类
 func (c *BasicAdminController) DeleteAssetCategory(ctx *gin.Context) {
 	id, _ := strconv.ParseUint(ctx.Param("id"), 10, 32)
-	if err := c.basicAdminService.DeleteAssetCategory(uint(id)); err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	if err := c.basicAdminService.WithContext(ctx.Request.Context()).DeleteAssetCategory(uint(id)); err != nil {
+		respondError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -146,9 +148,9 @@ func (c *BasicAdminController) DeleteAssetCategory(ctx *gin.Context) {
 
 // GetAssetBrandList 获取资产品牌列表
 func (c *BasicAdminController) GetAssetBrandList(ctx *gin.Context) {
-	brands, err := c.basicAdminService.GetAssetBrandList()
+	brands, err := c.basicAdminService.WithContext(ctx.Request.Context()).GetAssetBrandList()
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -158,9 +160,9 @@ func (c *BasicAdminController) GetAssetBrandList(ctx *gin.Context) {
 // GetAssetBrandByID 根据ID获取资产品牌
 func (c *BasicAdminController) GetAssetBrandByID(ctx *gin.Context) {
 	id, _ := strconv.ParseUint(ctx.Param("id"), 10, 32)
-	brand, err := c.basicAdminService.GetAssetBrandByID(uint(id))
+	brand, err := c.basicAdminService.WithContext(ctx.Request.Context()).GetAssetBrandByID(uint(id))
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -171,12 +173,12 @@ func (c *BasicAdminController) GetAssetBrandByID(ctx *gin.Context) {
 func (c *BasicAdminController) CreateAssetBrand(ctx *gin.Context) {
 	var brand model.AssetBrand
 	if err := ctx.ShouldBindJSON(&brand); err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		respondError(ctx, http.StatusBadRequest, err)
 		return
 	}
 
-	if err := c.basicAdminService.CreateAssetBrand(&brand); err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	if err := c.basicAdminService.WithContext(ctx.Request.Context()).CreateAssetBrand(&brand); err != nil {
+		respondError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -188,13 +190,13 @@ func (c *BasicAdminController) UpdateAssetBrand(ctx *gin.Context) {
 	id, _ := strconv.ParseUint(ctx.Param("id"), 10, 32)
 	var brand model.AssetBrand
 	if err := ctx.ShouldBindJSON(&brand); err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		respondError(ctx, http.StatusBadRequest, err)
 		return
 	}
 
 	brand.ID = uint(id)
-	if err := c.basicAdminService.UpdateAssetBrand(&brand); err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	if err := c.basicAdminService.WithContext(ctx.Request.Context()).UpdateAssetBrand(&brand); err != nil {
+		respondError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -204,8 +206,8 @@ func (c *BasicAdminController) UpdateAssetBrand(ctx *gin.Context) {
 // DeleteAssetBrand 删除资产品牌
 func (c *BasicAdminController) DeleteAssetBrand(ctx *gin.Context) {
 	id, _ := strconv.ParseUint(ctx.Param("id"), 10, 32)
-	if err := c.basicAdminService.DeleteAssetBrand(uint(id)); err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	if err := c.basicAdminService.WithContext(ctx.Request.Context()).DeleteAssetBrand(uint(id)); err != nil {
+		respondError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -214,9 +216,9 @@ func (c *BasicAdminController) DeleteAssetBrand(ctx *gin.Context) {
 
 // GetAssetUnitList 获取资产单位列表
 func (c *BasicAdminController) GetAssetUnitList(ctx *gin.Context) {
-	units, err := c.basicAdminService.GetAssetUnitList()
+	units, err := c.basicAdminService.WithContext(ctx.Request.Context()).GetAssetUnitList()
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -226,9 +228,9 @@ func (c *BasicAdminController) GetAssetUnitList(ctx *gin.Context) {
 // GetAssetUnitByID 根据ID获取资产单位
 func (c *BasicAdminController) GetAssetUnitByID(ctx *gin.Context) {
 	id, _ := strconv.ParseUint(ctx.Param("id"), 10, 32)
-	unit, err := c.basicAdminService.GetAssetUnitByID(uint(id))
+	unit, err := c.basicAdminService.WithContext(ctx.Request.Context()).GetAssetUnitByID(uint(id))
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -239,12 +241,12 @@ func (c *BasicAdminController) GetAssetUnitByID(ctx *gin.Context) {
 func (c *BasicAdminController) CreateAssetUnit(ctx *gin.Context) {
 	var unit model.AssetUnit
 	if err := ctx.ShouldBindJSON(&unit); err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		respondError(ctx, http.StatusBadRequest, err)
 		return
 	}
 
-	if err := c.basicAdminService.CreateAssetUnit(&unit); err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	if err := c.basicAdminService.WithContext(ctx.Request.Context()).CreateAssetUnit(&unit); err != nil {
+		respondError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -256,13 +258,13 @@ func (c *BasicAdminController) UpdateAssetUnit(ctx *gin.Context) {
 	id, _ := strconv.ParseUint(ctx.Param("id"), 10, 32)
 	var unit model.AssetUnit
 	if err := ctx.ShouldBindJSON(&unit); err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		respondError(ctx, http.StatusBadRequest, err)
 		return
 	}
 
 	unit.ID = uint(id)
-	if err := c.basicAdminService.UpdateAssetUnit(&unit); err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	if err := c.basicAdminService.WithContext(ctx.Request.Context()).UpdateAssetUnit(&unit); err != nil {
+		respondError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -272,8 +274,8 @@ func (c *BasicAdminController) UpdateAssetUnit(ctx *gin.Context) {
 // DeleteAssetUnit 删除资产单位
 func (c *BasicAdminController) DeleteAssetUnit(ctx *gin.Context) {
 	id, _ := strconv.ParseUint(ctx.Param("id"), 10, 32)
-	if err := c.basicAdminService.DeleteAssetUnit(uint(id)); err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	if err := c.basicAdminService.WithContext(ctx.Request.Context()).DeleteAssetUnit(uint(id)); err != nil {
+		respondError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -282,9 +284,9 @@ func (c *BasicAdminController) DeleteAssetUnit(ctx *gin.Context) {
 
 // GetSealTypeList 获取印章类型列表
 func (c *BasicAdminController) GetSealTypeList(ctx *gin.Context) {
-	types, err := c.basicAdminService.GetSealTypeList()
+	types, err := c.basicAdminService.WithContext(ctx.Request.Context()).GetSealTypeList()
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -294,9 +296,9 @@ func (c *BasicAdminController) GetSealTypeList(ctx *gin.Context) {
 // GetSealTypeByID 根据ID获取印章类型
 func (c *BasicAdminController) GetSealTypeByID(ctx *gin.Context) {
 	id, _ := strconv.ParseUint(ctx.Param("id"), 10, 32)
-	sealType, err := c.basicAdminService.GetSealTypeByID(uint(id))
+	sealType, err := c.basicAdminService.WithContext(ctx.Request.Context()).GetSealTypeByID(uint(id))
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -307,12 +309,12 @@ func (c *BasicAdminController) GetSealTypeByID(ctx *gin.Context) {
 func (c *BasicAdminController) CreateSealType(ctx *gin.Context) {
 	var sealType model.SealType
 	if err := ctx.ShouldBindJSON(&sealType); err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		respondError(ctx, http.StatusBadRequest, err)
 		return
 	}
 
-	if err := c.basicAdminService.CreateSealType(&sealType); err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	if err := c.basicAdminService.WithContext(ctx.Request.Context()).CreateSealType(&sealType); err != nil {
+		respondError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -324,13 +326,13 @@ func (c *BasicAdminController) UpdateSealType(ctx *gin.Context) {
 	id, _ := strconv.ParseUint(ctx.Param("id"), 10, 32)
 	var sealType model.SealType
 	if err := ctx.ShouldBindJSON(&sealType); err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		respondError(ctx, http.StatusBadRequest, err)
 		return
 	}
 
 	sealType.ID = uint(id)
-	if err := c.basicAdminService.UpdateSealType(&sealType); err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	if err := c.basicAdminService.WithContext(ctx.Request.Context()).UpdateSealType(&sealType); err != nil {
+		respondError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -340,8 +342,8 @@ func (c *BasicAdminController) UpdateSealType(ctx *gin.Context) {
 // DeleteSealType 删除印章类型
 func (c *BasicAdminController) DeleteSealType(ctx *gin.Context) {
 	id, _ := strconv.ParseUint(ctx.Param("id"), 10, 32)
-	if err := c.basicAdminService.DeleteSealType(uint(id)); err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	if err := c.basicAdminService.WithContext(ctx.Request.Context()).DeleteSealType(uint(id)); err != nil {
+		respondError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -350,9 +352,9 @@ func (c *BasicAdminController) DeleteSealType(ctx *gin.Context) {
 
 // GetVehicleExpenseList 获取车辆费用列表
 func (c *BasicAdminController) GetVehicleExpenseList(ctx *gin.Context) {
-	expenses, err := c.basicAdminService.GetVehicleExpenseList()
+	expenses, err := c.basicAdminService.WithContext(ctx.Request.Context()).GetVehicleExpenseList()
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -362,9 +364,9 @@ func (c *BasicAdminController) GetVehicleExpenseList(ctx *gin.Context) {
 // GetVehicleExpenseByID 根据ID获取车辆费用
 func (c *BasicAdminController) GetVehicleExpenseByID(ctx *gin.Context) {
 	id, _ := strconv.ParseUint(ctx.Param("id"), 10, 32)
-	expense, err := c.basicAdminService.GetVehicleExpenseByID(uint(id))
+	expense, err := c.basicAdminService.WithContext(ctx.Request.Context()).GetVehicleExpenseByID(uint(id))
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -375,12 +377,12 @@ func (c *BasicAdminController) GetVehicleExpenseByID(ctx *gin.Context) {
 func (c *BasicAdminController) CreateVehicleExpense(ctx *gin.Context) {
 	var expense model.VehicleExpense
 	if err := ctx.ShouldBindJSON(&expense); err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		respondError(ctx, http.StatusBadRequest, err)
 		return
 	}
 
-	if err := c.basicAdminService.CreateVehicleExpense(&expense); err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	if err := c.basicAdminService.WithContext(ctx.Request.Context()).CreateVehicleExpense(&expense); err != nil {
+		respondError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -392,13 +394,13 @@ func (c *BasicAdminController) UpdateVehicleExpense(ctx *gin.Context) {
 	id, _ := strconv.ParseUint(ctx.Param("id"), 10, 32)
 	var expense model.VehicleExpense
 	if err := ctx.ShouldBindJSON(&expense); err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		respondError(ctx, http.StatusBadRequest, err)
 		return
 	}
 
 	expense.ID = uint(id)
-	if err := c.basicAdminService.UpdateVehicleExpense(&expense); err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	if err := c.basicAdminService.WithContext(ctx.Request.Context()).UpdateVehicleExpense(&expense); err != nil {
+		respondError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -408,8 +410,8 @@ func (c *BasicAdminController) UpdateVehicleExpense(ctx *gin.Context) {
 // DeleteVehicleExpense 删除车辆费用
 func (c *BasicAdminController) DeleteVehicleExpense(ctx *gin.Context) {
 	id, _ := strconv.ParseUint(ctx.Param("id"), 10, 32)
-	if err := c.basicAdminService.DeleteVehicleExpense(uint(id)); err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	if err := c.basicAdminService.WithContext(ctx.Request.Context()).DeleteVehicleExpense(uint(id)); err != nil {
+		respondError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -418,9 +420,9 @@ func (c *BasicAdminController) DeleteVehicleExpense(ctx *gin.Context) {
 
 // GetNoticeTypeList 获取公告类型列表
 func (c *BasicAdminController) GetNoticeTypeList(ctx *gin.Context) {
-	types, err := c.basicAdminService.GetNoticeTypeList()
+	types, err := c.basicAdminService.WithContext(ctx.Request.Context()).GetNoticeTypeList()
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -430,9 +432,9 @@ func (c *BasicAdminController) GetNoticeTypeList(ctx *gin.Context) {
 // GetNoticeTypeByID 根据ID获取公告类型
 func (c *BasicAdminController) GetNoticeTypeByID(ctx *gin.Context) {
 	id, _ := strconv.ParseUint(ctx.Param("id"), 10, 32)
-	noticeType, err := c.basicAdminService.GetNoticeTypeByID(uint(id))
+	noticeType, err := c.basicAdminService.WithContext(ctx.Request.Context()).GetNoticeTypeByID(uint(id))
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -443,12 +445,12 @@ func (c *BasicAdminController) GetNoticeTypeByID(ctx *gin.Context) {
 func (c *BasicAdminController) CreateNoticeType(ctx *gin.Context) {
 	var noticeType model.NoticeType
 	if err := ctx.ShouldBindJSON(&noticeType); err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		respondError(ctx, http.StatusBadRequest, err)
 		return
 	}
 
-	if err := c.basicAdminService.CreateNoticeType(&noticeType); err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	if err := c.basicAdminService.WithContext(ctx.Request.Context()).CreateNoticeType(&noticeType); err != nil {
+		respondError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -460,13 +462,13 @@ func (c *BasicAdminController) UpdateNoticeType(ctx *gin.Context) {
 	id, _ := strconv.ParseUint(ctx.Param("id"), 10, 32)
 	var noticeType model.NoticeType
 	if err := ctx.ShouldBindJSON(&noticeType); err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		respondError(ctx, http.StatusBadRequest, err)
 		return
 	}
 
 	noticeType.ID = uint(id)
-	if err := c.basicAdminService.UpdateNoticeType(&noticeType); err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	if err := c.basicAdminService.WithContext(ctx.Request.Context()).UpdateNoticeType(&noticeType); err != nil {
+		respondError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -476,8 +478,8 @@ func (c *BasicAdminController) UpdateNoticeType(ctx *gin.Context) {
 // DeleteNoticeType 删除公告类型
 func (c *BasicAdminController) DeleteNoticeType(ctx *gin.Context) {
 	id, _ := strconv.ParseUint(ctx.Param("id"), 10, 32)
-	if err := c.basicAdminService.DeleteNoticeType(uint(id)); err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	if err := c.basicAdminService.WithContext(ctx.Request.Context()).DeleteNoticeType(uint(id)); err != nil {
+		respondError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -4,6 +4,7 @@ import (
 	"net/http"
 	"strconv"
 
+	"github.com/lemonoa/LemonOA-Go/middleware"
 	"github.com/lemonoa/LemonOA-Go/model"
 	"github.com/lemonoa/LemonOA-Go/service"
 
@@ -23,6 +24,7 @@ func NewBasicCommonController(basicCommonService *service.BasicCommonService) *B
 // RegisterRoutes 注册路由
 func (c *BasicCommonController) RegisterRoutes(r *gin.Engine) {
 	api := r.Group("/api/basic/common")
+	api.Use(middleware.JWT())
 	{
 		// 企业主体
 		api.GET("/enterprises", c.GetEnterpriseList)
@@ -50,9 +52,9 @@ func (c *BasicCommonController) RegisterRoutes(r *gin.Engine) {
 
 // GetEnterpriseList 获取企业主体列表
 func (c *BasicCommonController) GetEnterpriseList(ctx *gin.Context) {
-	enterprises, err := c.basicCommonService.GetEnterpriseList()
+	enterprises, err := c.basicCommonService.WithContext(ctx.Request.Context()).GetEnterpriseList()
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -62,9 +64,9 @@ func (c *BasicCommonController) GetEnterpriseList(ctx *gin.Context) {
 // GetEnterpriseByID 根据ID获取企业主体
 func (c *BasicCommonController) GetEnterpriseByID(ctx *gin.Context) {
 	id, _ := strconv.ParseUint(ctx.Param("id"), 10, 32)
-	enterprise, err := c.basicCommonService.GetEnterpriseByID(uint(id))
+	enterprise, err := c.basicCommonService.WithContext(ctx.Request.Context()).GetEnterpriseByID(uint(id))
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -75,12 +77,12 @@ func (c *BasicCommonController) GetEnterpriseByID(ctx *gin.Context) {
 func (c *BasicCommonController) CreateEnterprise(ctx *gin.Context) {
 	var enterprise model.Enterprise
 	if err := ctx.ShouldBindJSON(&enterprise); err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		respondError(ctx, http.StatusBadRequest, err)
 		return
 	}
 
-	if err := c.basicCommonService.CreateEnterprise(&enterprise); err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	if err := c.basicCommonService.WithContext(ctx.Request.Context()).CreateEnterprise(&enterprise); err != nil {
+		respondError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -92,13 +94,13 @@ func (c *BasicCommonController) UpdateEnterprise(ctx *gin.Context) {
 	id, _ := strconv.ParseUint(ctx.Param("id"), 10, 32)
 	var enterprise model.Enterprise
 	if err := ctx.ShouldBindJSON(&enterprise); err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		respondError(ctx, http.StatusBadRequest, err)
 		return
 	}
 
 	enterprise.ID = uint(id)
-	if err := c.basicCommonService.UpdateEnterprise(&enterprise); err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	if err := c.basicCommonService.WithContext(ctx.Request.Context()).UpdateEnterprise(&enterprise); err != nil {
+		respondError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -108,8 +110,8 @@ func (c *BasicCommonController) UpdateEnterprise(ctx *gin.Context) {
 // DeleteEnterprise 删除企业主体
 func (c *BasicCommonController) DeleteEnterprise(ctx *gin.Context) {
 	id, _ := strconv.ParseUint(ctx.Param("id"), 10, 32)
-	if err := c.basicCommonService.DeleteEnterprise(uint(id)); err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	if err := c.basicCommonService.WithContext(ctx.Request.Context()).DeleteEnterprise(uint(id)); err != nil {
+		respondError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -126,9 +128,9 @@ func (c *BasicCommonController) GetRegionList(ctx *gin.Context) {
 		}
 	}
 
-	regions, err := c.basicCommonService.GetRegionList(parentID)
+	regions, err := c.basicCommonService.WithContext(ctx.Request.Context()).GetRegionList(parentID)
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -138,9 +140,9 @@ func (c *BasicCommonController) GetRegionList(ctx *gin.Context) {
 // GetRegionByID 根据ID获取地区
 func (c *BasicCommonController) GetRegionByID(ctx *gin.Context) {
 	id, _ := strconv.ParseUint(ctx.Param("id"), 10, 32)
-	region, err := c.basicCommonService.GetRegionByID(uint(id))
+	region, err := c.basicCommonService.WithContext(ctx.Request.Context()).GetRegionByID(uint(id))
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -151,12 +153,12 @@ func (c *BasicCommonController) GetRegionByID(ctx *gin.Context) {
 func (c *BasicCommonController) CreateRegion(ctx *gin.Context) {
 	var region model.Region
 	if err := ctx.ShouldBindJSON(&region); err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		respondError(ctx, http.StatusBadRequest, err)
 		return
 	}
 
-	if err := c.basicCommonService.CreateRegion(&region); err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	if err := c.basicCommonService.WithContext(ctx.Request.Context()).CreateRegion(&region); err != nil {
+		respondError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -168,13 +170,13 @@ func (c *BasicCommonController) UpdateRegion(ctx *gin.Context) {
 	id, _ := strconv.ParseUint(ctx.Param("id"), 10, 32)
 	var region model.Region
 	if err := ctx.ShouldBindJSON(&region); err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		respondError(ctx, http.StatusBadRequest, err)
 		return
 	}
 
 	region.ID = uint(id)
-	if err := c.basicCommonService.UpdateRegion(&region); err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	if err := c.basicCommonService.WithContext(ctx.Request.Context()).UpdateRegion(&region); err != nil {
+		respondError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -184,8 +186,8 @@ func (c *BasicCommonController) UpdateRegion(ctx *gin.Context) {
 // DeleteRegion 删除地区
 func (c *BasicCommonController) DeleteRegion(ctx *gin.Context) {
 	id, _ := strconv.ParseUint(ctx.Param("id"), 10, 32)
-	if err := c.basicCommonService.DeleteRegion(uint(id)); err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	if err := c.basicCommonService.WithContext(ctx.Request.Context()).DeleteRegion(uint(id)); err != nil {
+		respondError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -195,9 +197,9 @@ func (c *BasicCommonController) DeleteRegion(ctx *gin.Context) {
 // GetMessageTemplateList 获取消息模板列表
 func (c *BasicCommonController) GetMessageTemplateList(ctx *gin.Context) {
 	templateType, _ := strconv.Atoi(ctx.Query("type"))
-	templates, err := c.basicCommonService.GetMessageTemplateList(templateType)
+	templates, err := c.basicCommonService.WithContext(ctx.Request.Context()).GetMessageTemplateList(templateType)
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -207,9 +209,9 @@ func (c *BasicCommonController) GetMessageTemplateList(ctx *gin.Context) {
 // GetMessageTemplateByID 根据ID获取消息模板
 func (c *BasicCommonController) GetMessageTemplateByID(ctx *gin.Context) {
 	id, _ := strconv.ParseUint(ctx.Param("id"), 10, 32)
-	template, err := c.basicCommonService.GetMessageTemplateByID(uint(id))
+	template, err := c.basicCommonService.WithContext(ctx.Request.Context()).GetMessageTemplateByID(uint(id))
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -219,9 +221,9 @@ func (c *BasicCommonController) GetMessageTemplateByID(ctx *gin.Context) {
 // GetMessageTemplateByCode 根据Code获取消息模板
 func (c *BasicCommonController) GetMessageTemplateByCode(ctx *gin.Context) {
 	code := ctx.Param("code")
-	template, err := c.basicCommonService.GetMessageTemplateByCode(code)
+	template, err := c.basicCommonService.WithContext(ctx.Request.Context()).GetMessageTemplateByCode(code)
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -232,12 +234,12 @@ func (c *BasicCommonController) GetMessageTemplateByCode(ctx *gin.Context) {
 func (c *BasicCommonController) CreateMessageTemplate(ctx *gin.Context) {
 	var template model.MessageTemplate
 	if err := ctx.ShouldBindJSON(&template); err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		respondError(ctx, http.StatusBadRequest, err)
 		return
 	}
 
-	if err := c.basicCommonService.CreateMessageTemplate(&template); err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	if err := c.basicCommonService.WithContext(ctx.Request.Context()).CreateMessageTemplate(&template); err != nil {
+		respondError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -249,13 +251,13 @@ func (c *BasicCommonController) UpdateMessageTemplate(ctx *gin.Context) {
 	id, _ := strconv.ParseUint(ctx.Param("id"), 10, 32)
 	var template model.MessageTemplate
 	if err := ctx.ShouldBindJSON(&template); err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		respondError(ctx, http.StatusBadRequest, err)
 		return
 	}
 
 	template.ID = uint(id)
-	if err := c.basicCommonService.UpdateMessageTemplate(&template); err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	if err := c.basicCommonService.WithContext(ctx.Request.Context()).UpdateMessageTemplate(&template); err != nil {
+		respondError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -265,8 +267,8 @@ func (c *BasicCommonController) UpdateMessageTemplate(ctx *gin.Context) {
 // DeleteMessageTemplate 删除消息模板
 func (c *BasicCommonController) DeleteMessageTemplate(ctx *gin.Context) {
 	id, _ := strconv.ParseUint(ctx.Param("id"), 10, 32)
-	if err := c.basicCommonService.DeleteMessageTemplate(uint(id)); err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	if err := c.basicCommonService.WithContext(ctx.Request.Context()).DeleteMessageTemplate(uint(id)); err != nil {
+		respondError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -4,6 +4,7 @@ import (
 	"net/http"
 	"strconv"
 
+	"github.com/lemonoa/LemonOA-Go/middleware"
 	"github.com/lemonoa/LemonOA-Go/model"
 	"github.com/lemonoa/LemonOA-Go/service"
 
@@ -23,6 +24,7 @@ func NewBasicContractController(basicContractService *service.BasicContractServi
 // RegisterRoutes 注册路由
 func (c *BasicContractController) RegisterRoutes(r *gin.Engine) {
 	api := r.Group("/api/basic/contract")
+	api.Use(middleware.JWT())
 	{
 		// 合同分类
 		api.GET("/contract-categories", c.GetContractCategoryList)
@@ -77,9 +79,9 @@ func (c *BasicContractController) RegisterRoutes(r *gin.Engine) {
 
 // GetContractCategoryList 获取合同分类列表
 func (c *BasicContractController) GetContractCategoryList(ctx *gin.Context) {
-	categories, err := c.basicContractService.GetContractCategoryList()
+	categories, err := c.basicContractService.WithContext(ctx.Request.Context()).GetContractCategoryList()
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -89,9 +91,9 @@ func (c *BasicContractController) GetContractCategoryList(ctx *gin.Context) {
 // GetContractCategoryByID 根据ID获取合同分类
 func (c *BasicContractController) GetContractCategoryByID(ctx *gin.Context) {
 	id, _ := strconv.ParseUint(ctx.Param("id"), 10, 32)
-	category, err := c.basicContractService.GetContractCategoryByID(uint(id))
+	category, err := c.basicContractService.WithContext(ctx.Request.Context()).GetContractCategoryByID(uint(id))
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -102,12 +104,12 @@ func (c *BasicContractController) GetContractCategoryByID(ctx *gin.Context) {
 func (c *BasicContractController) CreateContractCategory(ctx *gin.Context) {
 	var category model.ContractCategory
 	if err := ctx.ShouldBindJSON(&category); err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		respondError(ctx, http.StatusBadRequest, err)
 		return
 	}
 
-	if err := c.basicContractService.CreateContractCategory(&category); err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	if err := c.basicContractService.WithContext(ctx.Request.Context()).CreateContractCategory(&category); err != nil {
+		respondError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -119,13 +121,13 @@ func (c *BasicContractController) UpdateContractCategory(ctx *gin.Context) {
 	id, _ := strconv.ParseUint(ctx.Param("id"), 10, 32)
 	var category model.ContractCategory
 	if err := ctx.ShouldBindJSON(&category); err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		respondError(ctx, http.StatusBadRequest, err)
 		return
 	}
 
 	category.ID = uint(id)
-	if err := c.basicContractService.UpdateContractCategory(&category); err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	if err := c.basicContractService.WithContext(ctx.Request.Context()).UpdateContractCategory(&category); err != nil {
+		respondError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -135,8 +137,8 @@ func (c *BasicContractController) UpdateContractCategory(ctx *gin.Context) {
 // DeleteContractCategory 删除合同分类
 func (c *BasicContractController) DeleteContractCategory(ctx *gin.Context) {
 	id, _ := strconv.ParseUint(ctx.Param("id"), 10, 32)
-	if err := c.basicContractService.DeleteContractCategory(uint(id)); err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	if err := c.basicContractService.WithContext(ctx.Request.Context()).DeleteContractCategory(uint(id)); err != nil {
+		respondError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -153,9 +155,9 @@ func (c *BasicContractController) GetProductCategoryList(ctx *gin.Context) {
 		}
 	}
 
-	categories, err := c.basicContractService.GetProductCategoryList(parentID)
+	categories, err := c.basicContractService.WithContext(ctx.Request.Context()).GetProductCategoryList(parentID)
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -165,9 +167,9 @@ func (c *BasicContractController) GetProductCategoryList(ctx *gin.Context) {
 // GetProductCategoryByID 根据ID获取产品分类
 func (c *BasicContractController) GetProductCategoryByID(ctx *gin.Context) {
 	id, _ := strconv.ParseUint(ctx.Param("id"), 10, 32)
-	category, err := c.basicContractService.GetProductCategoryByID(uint(id))
+	category, err := c.basicContractService.WithContext(ctx.Request.Context()).GetProductCategoryByID(uint(id))
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -178,12 +180,12 @@ func (c *BasicContractController) GetProductCategoryByID(ctx *gin.Context) {
 func (c *BasicContractController) CreateProductCategory(ctx *gin.Context) {
 	var category model.ProductCategory
 	if err := ctx.ShouldBindJSON(&category); err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		respondError(ctx, http.StatusBadRequest, err)
 		return
 	}
 
-	if err := c.basicContractService.CreateProductCategory(&category); err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	if err := c.basicContractService.WithContext(ctx.Request.Context()).CreateProductCategory(&category); err != nil {
+		respondError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -195,13 +197,13 @@ func (c *BasicContractController) UpdateProductCategory(ctx *gin.Context) {
 	id, _ := strconv.ParseUint(ctx.Param("id"), 10, 32)
 	var category model.ProductCategory
 	if err := ctx.ShouldBindJSON(&category); err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		respondError(ctx, http.StatusBadRequest, err)
 		return
 	}
 
 	category.ID = uint(id)
-	if err := c.basicContractService.UpdateProductCategory(&category); err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	if err := c.basicContractService.WithContext(ctx.Request.Context()).UpdateProductCategory(&category); err != nil {
+		respondError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -211,8 +213,8 @@ func (c *BasicContractController) UpdateProductCategory(ctx *gin.Context) {
 // DeleteProductCategory 删除产品分类
 func (c *BasicContractController) DeleteProductCategory(ctx *gin.Context) {
 	id, _ := strconv.ParseUint(ctx.Param("id"), 10, 32)
-	if err := c.basicContractService.DeleteProductCategory(uint(id)); err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	if err := c.basicContractService.WithContext(ctx.Request.Context()).DeleteProductCategory(uint(id)); err != nil {
+		respondError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -222,9 +224,9 @@ func (c *BasicContractController) DeleteProductCategory(ctx *gin.Context) {
 // GetProductList 获取产品列表
 func (c *BasicContractController) GetProductList(ctx *gin.Context) {
 	categoryID, _ := strconv.ParseUint(ctx.Query("category_id"), 10, 32)
-	products, err := c.basicContractService.GetProductList(uint(categoryID))
+	products, err := c.basicContractService.WithContext(ctx.Request.Context()).GetProductList(uint(categoryID))
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -234,9 +236,9 @@ func (c *BasicContractController) GetProductList(ctx *gin.Context) {
 // GetProductByID 根据ID获取产品
 func (c *BasicContractController) GetProductByID(ctx *gin.Context) {
 	id, _ := strconv.ParseUint(ctx.Param("id"), 10, 32)
-	product, err := c.basicContractService.GetProductByID(uint(id))
+	product, err := c.basicContractService.WithContext(ctx.Request.Context()).GetProductByID(uint(id))
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -247,12 +249,12 @@ func (c *BasicContractController) GetProductByID(ctx *gin.Context) {
 func (c *BasicContractController) CreateProduct(ctx *gin.Context) {
 	var product model.Product
 	if err := ctx.ShouldBindJSON(&product); err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		respondError(ctx, http.StatusBadRequest, err)
 		return
 	}
 
-	if err := c.basicContractService.CreateProduct(&product); err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	if err := c.basicContractService.WithContext(ctx.Request.Context()).CreateProduct(&product); err != nil {
+		respondError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -264,13 +266,13 @@ func (c *BasicContractController) UpdateProduct(ctx *gin.Context) {
 	id, _ := strconv.ParseUint(ctx.Param("id"), 10, 32)
 	var product model.Product
 	if err := ctx.ShouldBindJSON(&product); err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		respondError(ctx, http.StatusBadRequest, err)
 		return
 	}
 
 	product.ID = uint(id)
-	if err := c.basicContractService.UpdateProduct(&product); err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	if err := c.basicContractService.WithContext(ctx.Request.Context()).UpdateProduct(&product); err != nil {
+		respondError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -280,8 +282,8 @@ func (c *BasicContractController) UpdateProduct(ctx *gin.Context) {
 // DeleteProduct 删除产品
 func (c *BasicContractController) DeleteProduct(ctx *gin.Context) {
 	id, _ := strconv.ParseUint(ctx.Param("id"), 10, 32)
-	if err := c.basicContractService.DeleteProduct(uint(id)); err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	if err := c.basicContractService.WithContext(ctx.Request.Context()).DeleteProduct(uint(id)); err != nil {
+		respondError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -290,9 +292,9 @@ func (c *BasicContractController) DeleteProduct(ctx *gin.Context) {
 
 // GetServiceContentList 获取服务内容列表
 func (c *BasicContractController) GetServiceContentList(ctx *gin.Context) {
-	contents, err := c.basicContractService.GetServiceContentList()
+	contents, err := c.basicContractService.WithContext(ctx.Request.Context()).GetServiceContentList()
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -302,9 +304,9 @@ func (c *BasicContractController) GetServiceContentList(ctx *gin.Context) {
 // GetServiceContentByID 根据ID获取服务内容
 func (c *BasicContractController) GetServiceContentByID(ctx *gin.Context) {
 	id, _ := strconv.ParseUint(ctx.Param("id"), 10, 32)
-	content, err := c.basicContractService.GetServiceContentByID(uint(id))
+	content, err := c.basicContractService.WithContext(ctx.Request.Context()).GetServiceContentByID(uint(id))
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -315,12 +317,12 @@ func (c *BasicContractController) GetServiceContentByID(ctx *gin.Context) {
 func (c *BasicContractController) CreateServiceContent(ctx *gin.Context) {
 	var content model.ServiceContent
 	if err := ctx.ShouldBindJSON(&content); err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		respondError(ctx, http.StatusBadRequest, err)
 		return
 	}
 
-	if err := c.basicContractService.CreateServiceContent(&content); err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	if err := c.basicContractService.WithContext(ctx.Request.Context()).CreateServiceContent(&content); err != nil {
+		respondError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -332,13 +334,13 @@ func (c *BasicContractController) UpdateServiceContent(ctx *gin.Context) {
 	id, _ := strconv.ParseUint(ctx.Param("id"), 10, 32)
 	var content model.ServiceContent
 	if err := ctx.ShouldBindJSON(&content); err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		respondError(ctx, http.StatusBadRequest, err)
 		return
 	}
 
 	content.ID = uint(id)
-	if err := c.basicContractService.UpdateServiceContent(&content); err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	if err := c.basicContractService.WithContext(ctx.Request.Context()).UpdateServiceContent(&content); err != nil {
+		respondError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -348,8 +350,8 @@ func (c *BasicContractController) UpdateServiceContent(ctx *gin.Context) {
 // DeleteServiceContent 删除服务内容
 func (c *BasicContractController) DeleteServiceContent(ctx *gin.Context) {
 	id, _ := strconv.ParseUint(ctx.Param("id"), 10, 32)
-	if err := c.basicContractService.DeleteServiceContent(uint(id)); err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	if err := c.basicContractService.WithContext(ctx.Request.Context()).DeleteServiceContent(uint(id)); err != nil {
+		respondError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -358,9 +360,9 @@ func (c *BasicContractController) DeleteServiceContent(ctx *gin.Context) {
 
 // GetSupplierList 获取供应商列表
 func (c *BasicContractController) GetSupplierList(ctx *gin.Context) {
-	suppliers, err := c.basicContractService.GetSupplierList()
+	suppliers, err := c.basicContractService.WithContext(ctx.Request.Context()).GetSupplierList()
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -370,9 +372,9 @@ func (c *BasicContractController) GetSupplierList(ctx *gin.Context) {
 // GetSupplierByID 根据ID获取供应商
 func (c *BasicContractController) GetSupplierByID(ctx *gin.Context) {
 	id, _ := strconv.ParseUint(ctx.Param("id"), 10, 32)
-	supplier, err := c.basicContractService.GetSupplierByID(uint(id))
+	supplier, err := c.basicContractService.WithContext(ctx.Request.Context()).GetSupplierByID(uint(id))
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -383,12 +385,12 @@ func (c *BasicContractController) GetSupplierByID(ctx *gin.Context) {
 func (c *BasicContractController) CreateSupplier(ctx *gin.Context) {
 	var supplier model.Supplier
 	if err := ctx.ShouldBindJSON(&supplier); err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		respondError(ctx, http.StatusBadRequest, err)
 		return
 	}
 
-	if err := c.basicContractService.CreateSupplier(&supplier); err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	if err := c.basicContractService.WithContext(ctx.Request.Context()).CreateSupplier(&supplier); err != nil {
+		respondError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -400,13 +402,13 @@ func (c *BasicContractController) UpdateSupplier(ctx *gin.Context) {
 	id, _ := strconv.ParseUint(ctx.Param("id"), 10, 32)
 	var supplier model.Supplier
 	if err := ctx.ShouldBindJSON(&supplier); err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		respondError(ctx, http.StatusBadRequest, err)
 		return
 	}
 
 	supplier.ID = uint(id)
-	if err := c.basicContractService.UpdateSupplier(&supplier); err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	if err := c.basicContractService.WithContext(ctx.Request.Context()).UpdateSupplier(&supplier); err != nil {
+		respondError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -416,8 +418,8 @@ func (c *BasicContractController) UpdateSupplier(ctx *gin.Context) {
 // DeleteSupplier 删除供应商
 func (c *BasicContractController) DeleteSupplier(ctx *gin.Context) {
 	id, _ := strconv.ParseUint(ctx.Param("id"), 10, 32)
-	if err := c.basicContractService.DeleteSupplier(uint(id)); err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	if err := c.basicContractService.WithContext(ctx.Request.Context()).DeleteSupplier(uint(id)); err != nil {
+		respondError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -434,9 +436,9 @@ func (c *BasicContractController) GetPurchaseCategoryList(ctx *gin.Context) {
 		}
 	}
 
-	categories, err := c.basicContractService.GetPurchaseCategoryList(parentID)
+	categories, err := c.basicContractService.WithContext(ctx.Request.Context()).GetPurchaseCategoryList(parentID)
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -446,9 +448,9 @@ func (c *BasicContractController) GetPurchaseCategoryList(ctx *gin.Context) {
 // GetPurchaseCategoryByID 根据ID获取采购品分类
 func (c *BasicContractController) GetPurchaseCategoryByID(ctx *gin.Context) {
 	id, _ := strconv.ParseUint(ctx.Param("id"), 10, 32)
-	category, err := c.basicContractService.GetPurchaseCategoryByID(uint(id))
+	category, err := c.basicContractService.WithContext(ctx.Request.Context()).GetPurchaseCategoryByID(uint(id))
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -459,12 +461,12 @@ func (c *BasicContractController) GetPurchaseCategoryByID(ctx *gin.Context) {
 func (c *BasicContractController) CreatePurchaseCategory(ctx *gin.Context) {
 	var category model.PurchaseCategory
 	if err := ctx.ShouldBindJSON(&category); err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		respondError(ctx, http.StatusBadRequest, err)
 		return
 	}
 
-	if err := c.basicContractService.CreatePurchaseCategory(&category); err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	if err := c.basicContractService.WithContext(ctx.Request.Context()).CreatePurchaseCategory(&category); err != nil {
+		respondError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -476,13 +478,13 @@ func (c *BasicContractController) UpdatePurchaseCategory(ctx *gin.Context) {
 	id, _ := strconv.ParseUint(ctx.Param("id"), 10, 32)
 	var category model.PurchaseCategory
 	if err := ctx.ShouldBindJSON(&category); err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		respondError(ctx, http.StatusBadRequest, err)
 		return
 	}
 
 	category.ID = uint(id)
-	if err := c.basicContractService.UpdatePurchaseCategory(&category); err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	if err := c.basicContractService.WithContext(ctx.Request.Context()).UpdatePurchaseCategory(&category); err != nil {
+		respondError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -492,8 +494,8 @@ func (c *BasicContractController) UpdatePurchaseCategory(ctx *gin.Context) {
 // DeletePurchaseCategory 删除采购品分类
 func (c *BasicContractController) DeletePurchaseCategory(ctx *gin.Context) {
 	id, _ := strconv.ParseUint(ctx.Param("id"), 10, 32)
-	if err := c.basicContractService.DeletePurchaseCategory(uint(id)); err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	if err := c.basicContractService.WithContext(ctx.Request.Context()).DeletePurchaseCategory(uint(id)); err != nil {
+		respondError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -503,9 +505,9 @@ func (c *BasicContractController) DeletePurchaseCategory(ctx *gin.Context) {
 // GetPurchaseItemList 获取采购品列表
 func (c *BasicContractController) GetPurchaseItemList(ctx *gin.Context) {
 	categoryID, _ := strconv.ParseUint(ctx.Query("category_id"), 10, 32)
-	items, err := c.basicContractService.GetPurchaseItemList(uint(categoryID))
+	items, err := c.basicContractService.WithContext(ctx.Request.Context()).GetPurchaseItemList(uint(categoryID))
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -515,9 +517,9 @@ func (c *BasicContractController) GetPurchaseItemList(ctx *gin.Context) {
 // GetPurchaseItemByID 根据ID获取采购品
 func (c *BasicContractController) GetPurchaseItemByID(ctx *gin.Context) {
 	id, _ := strconv.ParseUint(ctx.Param("id"), 10, 32)
-	item, err := c.basicContractService.GetPurchaseItemByID(uint(id))
+	item, err := c.basicContractService.WithContext(ctx.Request.Context()).GetPurchaseItemByID(uint(id))
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -528,12 +530,12 @@ func (c *BasicContractController) GetPurchaseItemByID(ctx *gin.Context) {
 func (c *BasicContractController) CreatePurchaseItem(ctx *gin.Context) {
 	var item model.PurchaseItem
 	if err := ctx.ShouldBindJSON(&item); err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		respondError(ctx, http.StatusBadRequest, err)
 		return
 	}
 
-	if err := c.basicContractService.CreatePurchaseItem(&item); err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	if err := c.basicContractService.WithContext(ctx.Request.Context()).CreatePurchaseItem(&item); err != nil {
+		respondError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -545,13 +547,13 @@ func (c *BasicContractController) UpdatePurchaseItem(ctx *gin.Context) {
 	id, _ := strconv.ParseUint(ctx.Param("id"), 10, 32)
 	var item model.PurchaseItem
 	if err := ctx.ShouldBindJSON(&item); err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		respondError(ctx, http.StatusBadRequest, err)
 		return
 	}
 
 	item.ID = uint(id)
-	if err := c.basicContractService.UpdatePurchaseItem(&item); err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	if err := c.basicContractService.WithContext(ctx.Request.Context()).UpdatePurchaseItem(&item); err != nil {
+		respondError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -561,8 +563,8 @@ func (c *BasicContractController) UpdatePurchaseItem(ctx *gin.Context) {
 // DeletePurchaseItem 删除采购品
 func (c *BasicContractController) DeletePurchaseItem(ctx *gin.Context) {
 	id, _ := strconv.ParseUint(ctx.Param("id"), 10, 32)
-	if err := c.basicContractService.DeletePurchaseItem(uint(id)); err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	if err := c.basicContractService.WithContext(ctx.Request.Context()).DeletePurchaseItem(uint(id)); err != nil {
+		respondError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -4,6 +4,7 @@ import (
 	"net/http"
 	"strconv"
 
+	"github.com/lemonoa/LemonOA-Go/middleware"
 	"github.com/lemonoa/LemonOA-Go/model"
 	"github.com/lemonoa/LemonOA-Go/service"
 
@@ -23,6 +24,7 @@ func NewBasicCustomerController(basicCustomerService *service.BasicCustomerServi
 // RegisterRoutes 注册路由
 func (c *BasicCustomerController) RegisterRoutes(r *gin.Engine) {
 	api := r.Group("/api/basic/customer")
+	api.Use(middleware.JWT())
 	{
 		// 客户等级
 		api.GET("/customer-levels", c.GetCustomerLevelList)
@@ -77,9 +79,9 @@ func (c *BasicCustomerController) RegisterRoutes(r *gin.Engine) {
 
 // GetCustomerLevelList 获取客户等级列表
 func (c *BasicCustomerController) GetCustomerLevelList(ctx *gin.Context) {
-	levels, err := c.basicCustomerService.GetCustomerLevelList()
+	levels, err := c.basicCustomerService.WithContext(ctx.Request.Context()).GetCustomerLevelList()
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -89,9 +91,9 @@ func (c *BasicCustomerController) GetCustomerLevelList(ctx *gin.Context) {
 // GetCustomerLevelByID 根据ID获取客户等级
 func (c *BasicCustomerController) GetCustomerLevelByID(ctx *gin.Context) {
 	id, _ := strconv.ParseUint(ctx.Param("id"), 10, 32)
-	level, err := c.basicCustomerService.GetCustomerLevelByID(uint(id))
+	level, err := c.basicCustomerService.WithContext(ctx.Request.Context()).GetCustomerLevelByID(uint(id))
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -102,12 +104,12 @@ func (c *BasicCustomerController) GetCustomerLevelByID(ctx *gin.Context) {
 func (c *BasicCustomerController) CreateCustomerLevel(ctx *gin.Context) {
 	var level model.CustomerLevel
 	if err := ctx.ShouldBindJSON(&level); err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		respondError(ctx, http.StatusBadRequest, err)
 		return
 	}
 
-	if err := c.basicCustomerService.CreateCustomerLevel(&level); err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	if err := c.basicCustomerService.WithContext(ctx.Request.Context()).CreateCustomerLevel(&level); err != nil {
+		respondError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -119,13 +121,13 @@ func (c *BasicCustomerController) UpdateCustomerLevel(ctx *gin.Context) {
 	id, _ := strconv.ParseUint(ctx.Param("id"), 10, 32)
 	var level model.CustomerLevel
 	if err := ctx.ShouldBindJSON(&level); err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		respondError(ctx, http.StatusBadRequest, err)
 		return
 	}
 
 	level.ID = uint(id)
-	if err := c.basicCustomerService.UpdateCustomerLevel(&level); err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	if err := c.basicCustomerService.WithContext(ctx.Request.Context()).UpdateCustomerLevel(&level); err != nil {
+		respondError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -135,8 +137,8 @@ func (c *BasicCustomerController) UpdateCustomerLevel(ctx *gin.Context) {
 // DeleteCustomerLevel 删除客户等级
 func (c *BasicCustomerController) DeleteCustomerLevel(ctx *gin.Context) {
 	id, _ := strconv.ParseUint(ctx.Param("id"), 10, 32)
-	if err := c.basicCustomerService.DeleteCustomerLevel(uint(id)); err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	if err := c.basicCustomerService.WithContext(ctx.Request.Context()).DeleteCustomerLevel(uint(id)); err != nil {
+		respondError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -145,9 +147,9 @@ func (c *BasicCustomerController) DeleteCustomerLevel(ctx *gin.Context) {
 
 // GetCustomerChannelList 获取客户渠道列表
 func (c *BasicCustomerController) GetCustomerChannelList(ctx *gin.Context) {
-	channels, err := c.basicCustomerService.GetCustomerChannelList()
+	channels, err := c.basicCustomerService.WithContext(ctx.Request.Context()).GetCustomerChannelList()
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -157,9 +159,9 @@ func (c *BasicCustomerController) GetCustomerChannelList(ctx *gin.Context) {
 // GetCustomerChannelByID 根据ID获取客户渠道
 func (c *BasicCustomerController) GetCustomerChannelByID(ctx *gin.Context) {
 	id, _ := strconv.ParseUint(ctx.Param("id"), 10, 32)
-	channel, err := c.basicCustomerService.GetCustomerChannelByID(uint(id))
+	channel, err := c.basicCustomerService.WithContext(ctx.Request.Context()).GetCustomerChannelByID(uint(id))
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -170,12 +172,12 @@ func (c *BasicCustomerController) GetCustomerChannelByID(ctx *gin.Context) {
 func (c *BasicCustomerController) CreateCustomerChannel(ctx *gin.Context) {
 	var channel model.CustomerChannel
 	if err := ctx.ShouldBindJSON(&channel); err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		respondError(ctx, http.StatusBadRequest, err)
 		return
 	}
 
-	if err := c.basicCustomerService.CreateCustomerChannel(&channel); err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	if err := c.basicCustomerService.WithContext(ctx.Request.Context()).CreateCustomerChannel(&channel); err != nil {
+		respondError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -187,13 +189,13 @@ func (c *BasicCustomerController) UpdateCustomerChannel(ctx *gin.Context) {
 	id, _ := strconv.ParseUint(ctx.Param("id"), 10, 32)
 	var channel model.CustomerChannel
 	if err := ctx.ShouldBindJSON(&channel); err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		respondError(ctx, http.StatusBadRequest, err)
 		return
 	}
 
 	channel.ID = uint(id)
-	if err := c.basicCustomerService.UpdateCustomerChannel(&channel); err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	if err := c.basicCustomerService.WithContext(ctx.Request.Context()).UpdateCustomerChannel(&channel); err != nil {
+		respondError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -203,8 +205,8 @@ func (c *BasicCustomerController) UpdateCustomerChannel(ctx *gin.Context) {
 // DeleteCustomerChannel 删除客户渠道
 func (c *BasicCustomerController) DeleteCustomerChannel(ctx *gin.Context) {
 	id, _ := strconv.ParseUint(ctx.Param("id"), 10, 32)
-	if err := c.basicCustomerService.DeleteCustomerChannel(uint(id)); err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	if err := c.basicCustomerService.WithContext(ctx.Request.Context()).DeleteCustomerChannel(uint(id)); err != nil {
+		respondError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -221,9 +223,9 @@ func (c *BasicCustomerController) GetIndustryList(ctx *gin.Context) {
 		}
 	}
 
-	industries, err := c.basicCustomerService.GetIndustryList(parentID)
+	industries, err := c.basicCustomerService.WithContext(ctx.Request.Context()).GetIndustryList(parentID)
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -233,9 +235,9 @@ func (c *BasicCustomerController) GetIndustryList(ctx *gin.Context) {
 // GetIndustryByID 根据ID获取行业类型
 func (c *BasicCustomerController) GetIndustryByID(ctx *gin.Context) {
 	id, _ := strconv.ParseUint(ctx.Param("id"), 10, 32)
-	industry, err := c.basicCustomerService.GetIndustryByID(uint(id))
+	industry, err := c.basicCustomerService.WithContext(ctx.Request.Context()).GetIndustryByID(uint(id))
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -246,12 +248,12 @@ func (c *BasicCustomerController) GetIndustryByID(ctx *gin.Context) {
 func (c *BasicCustomerController) CreateIndustry(ctx *gin.Context) {
 	var industry model.Industry
 	if err := ctx.ShouldBindJSON(&industry); err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		respondError(ctx, http.StatusBadRequest, err)
 		return
 	}
 
-	if err := c.basicCustomerService.CreateIndustry(&industry); err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	if err := c.basicCustomerService.WithContext(ctx.Request.Context()).CreateIndustry(&industry); err != nil {
+		respondError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -263,13 +265,13 @@ func (c *BasicCustomerController) UpdateIndustry(ctx *gin.Context) {
 	id, _ := strconv.ParseUint(ctx.Param("id"), 10, 32)
 	var industry model.Industry
 	if err := ctx.ShouldBindJSON(&industry); err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		respondError(ctx, http.StatusBadRequest, err)
 		return
 	}
 
 	industry.ID = uint(id)
-	if err := c.basicCustomerService.UpdateIndustry(&industry); err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	if err := c.basicCustomerService.WithContext(ctx.Request.Context()).UpdateIndustry(&industry); err != nil {
+		respondError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -279,8 +281,8 @@ func (c *BasicCustomerController) UpdateIndustry(ctx *gin.Context) {
 // DeleteIndustry 删除行业类型
 func (c *BasicCustomerController) DeleteIndustry(ctx *gin.Context) {
 	id, _ := strconv.ParseUint(ctx.Param("id"), 10, 32)
-	if err := c.basicCustomerService.DeleteIndustry(uint(id)); err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	if err := c.basicCustomerService.WithContext(ctx.Request.Context()).DeleteIndustry(uint(id)); err != nil {
+		respondError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -289,9 +291,9 @@ func (c *BasicCustomerController) DeleteIndustry(ctx *gin.Context) {
 
 // GetCustomerStatusList 获取客户状态列表
 func (c *BasicCustomerController) GetCustomerStatusList(ctx *gin.Context) {
-	statuses, err := c.basicCustomerService.GetCustomerStatusList()
+	statuses, err := c.basicCustomerService.WithContext(ctx.Request.Context()).GetCustomerStatusList()
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -301,9 +303,9 @@ func (c *BasicCustomerController) GetCustomerStatusList(ctx *gin.Context) {
 // GetCustomerStatusByID 根据ID获取客户状态
 func (c *BasicCustomerController) GetCustomerStatusByID(ctx *gin.Context) {
 	id, _ := strconv.ParseUint(ctx.Param("id"), 10, 32)
-	status, err := c.basicCustomerService.GetCustomerStatusByID(uint(id))
+	status, err := c.basicCustomerService.WithContext(ctx.Request.Context()).GetCustomerStatusByID(uint(id))
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -314,12 +316,12 @@ func (c *BasicCustomerController) GetCustomerStatusByID(ctx *gin.Context) {
 func (c *BasicCustomerController) CreateCustomerStatus(ctx *gin.Context) {
 	var status model.CustomerStatus
 	if err := ctx.ShouldBindJSON(&status); err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		respondError(ctx, http.StatusBadRequest, err)
 		return
 	}
 
-	if err := c.basicCustomerService.CreateCustomerStatus(&status); err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	if err := c.basicCustomerService.WithContext(ctx.Request.Context()).CreateCustomerStatus(&status); err != nil {
+		respondError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -331,13 +333,13 @@ func (c *BasicCustomerController) UpdateCustomerStatus(ctx *gin.Context) {
 	id, _ := strconv.ParseUint(ctx.Param("id"), 10, 32)
 	var status model.CustomerStatus
 	if err := ctx.ShouldBindJSON(&status); err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		respondError(ctx, http.StatusBadRequest, err)
 		return
 	}
 
 	status.ID = uint(id)
-	if err := c.basicCustomerService.UpdateCustomerStatus(&status); err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	if err := c.basicCustomerService.WithContext(ctx.Request.Context()).UpdateCustomerStatus(&status); err != nil {
+		respondError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -347,8 +349,8 @@ func (c *BasicCustomerController) UpdateCustomerStatus(ctx *gin.Context) {
 // DeleteCustomerStatus 删除客户状态
 func (c *BasicCustomerController) DeleteCustomerStatus(ctx *gin.Context) {
 	id, _ := strconv.ParseUint(ctx.Param("id"), 10, 32)
-	if err := c.basicCustomerService.DeleteCustomerStatus(uint(id)); err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	if err := c.basicCustomerService.WithContext(ctx.Request.Context()).DeleteCustomerStatus(uint(id)); err != nil {
+		respondError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -357,9 +359,9 @@ func (c *BasicCustomerController) DeleteCustomerStatus(ctx *gin.Context) {
 
 // GetCustomerIntentionList 获取客户意向列表
 func (c *BasicCustomerController) GetCustomerIntentionList(ctx *gin.Context) {
-	intentions, err := c.basicCustomerService.GetCustomerIntentionList()
+	intentions, err := c.basicCustomerService.WithContext(ctx.Request.Context()).GetCustomerIntentionList()
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -369,9 +371,9 @@ func (c *BasicCustomerController) GetCustomerIntentionList(ctx *gin.Context) {
 // GetCustomerIntentionByID 根据ID获取客户意向
 func (c *BasicCustomerController) GetCustomerIntentionByID(ctx *gin.Context) {
 	id, _ := strconv.ParseUint(ctx.Param("id"), 10, 32)
-	intention, err := c.basicCustomerService.GetCustomerIntentionByID(uint(id))
+	intention, err := c.basicCustomerService.WithContext(ctx.Request.Context()).GetCustomerIntentionByID(uint(id))
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -382,12 +384,12 @@ func (c *BasicCustomerController) GetCustomerIntentionByID(ctx *gin.Context) {
 func (c *BasicCustomerController) CreateCustomerIntention(ctx *gin.Context) {
 	var intention model.CustomerIntention
 	if err := ctx.ShouldBindJSON(&intention); err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		respondError(ctx, http.StatusBadRequest, err)
 		return
 	}
 
-	if err := c.basicCustomerService.CreateCustomerIntention(&intention); err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	if err := c.basicCustomerService.WithContext(ctx.Request.Context()).CreateCustomerIntention(&intention); err != nil {
+		respondError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -399,13 +401,13 @@ func (c *BasicCustomerController) UpdateCustomerIntention(ctx *gin.Context) {
 	id, _ := strconv.ParseUint(ctx.Param("id"), 10, 32)
 	var intention model.CustomerIntention
 	if err := ctx.ShouldBindJSON(&intention); err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		respondError(ctx, http.StatusBadRequest, err)
 		return
 	}
 
 	intention.ID = uint(id)
-	if err := c.basicCustomerService.UpdateCustomerIntention(&intention); err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	if err := c.basicCustomerService.WithContext(ctx.Request.Context()).UpdateCustomerIntention(&intention); err != nil {
+		respondError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -415,8 +417,8 @@ func (c *BasicCustomerController) UpdateCustomerIntention(ctx *gin.Context) {
 // DeleteCustomerIntention 删除客户意向
 func (c *BasicCustomerController) DeleteCustomerIntention(ctx *gin.Context) {
 	id, _ := strconv.ParseUint(ctx.Param("id"), 10, 32)
-	if err := c.basicCustomerService.DeleteCustomerIntention(uint(id)); err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	if err := c.basicCustomerService.WithContext(ctx.Request.Context()).DeleteCustomerIntention(uint(id)); err != nil {
+		respondError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -425,9 +427,9 @@ func (c *BasicCustomerController) DeleteCustomerIntention(ctx *gin.Context) {
 
 // GetFollowUpMethodList 获取跟进方式列表
 func (c *BasicCustomerController) GetFollowUpMethodList(ctx *gin.Context) {
-	methods, err := c.basicCustomerService.GetFollowUpMethodList()
+	methods, err := c.basicCustomerService.WithContext(ctx.Request.Context()).GetFollowUpMethodList()
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -437,9 +439,9 @@ func (c *BasicCustomerController) GetFollowUpMethodList(ctx *gin.Context) {
 // GetFollowUpMethodByID 根据ID获取跟进方式
 func (c *BasicCustomerController) GetFollowUpMethodByID(ctx *gin.Context) {
 	id, _ := strconv.ParseUint(ctx.Param("id"), 10, 32)
-	method, err := c.basicCustomerService.GetFollowUpMethodByID(uint(id))
+	method, err := c.basicCustomerService.WithContext(ctx.Request.Context()).GetFollowUpMethodByID(uint(id))
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -450,12 +452,12 @@ func (c *BasicCustomerController) GetFollowUpMethodByID(ctx *gin.Context) {
 func (c *BasicCustomerController) CreateFollowUpMethod(ctx *gin.Context) {
 	var method model.FollowUpMethod
 	if err := ctx.ShouldBindJSON(&method); err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		respondError(ctx, http.StatusBadRequest, err)
 		return
 	}
 
-	if err := c.basicCustomerService.CreateFollowUpMethod(&method); err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	if err := c.basicCustomerService.WithContext(ctx.Request.Context()).CreateFollowUpMethod(&method); err != nil {
+		respondError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -467,13 +469,13 @@ func (c *BasicCustomerController) UpdateFollowUpMethod(ctx *gin.Context) {
 	id, _ := strconv.ParseUint(ctx.Param("id"), 10, 32)
 	var method model.FollowUpMethod
 	if err := ctx.ShouldBindJSON(&method); err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		respondError(ctx, http.StatusBadRequest, err)
 		return
 	}
 
 	method.ID = uint(id)
-	if err := c.basicCustomerService.UpdateFollowUpMethod(&method); err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	if err := c.basicCustomerService.WithContext(ctx.Request.Context()).UpdateFollowUpMethod(&method); err != nil {
+		respondError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -483,8 +485,8 @@ func (c *BasicCustomerController) UpdateFollowUpMethod(ctx *gin.Context) {
 // DeleteFollowUpMethod 删除跟进方式
 func (c *BasicCustomerController) DeleteFollowUpMethod(ctx *gin.Context) {
 	id, _ := strconv.ParseUint(ctx.Param("id"), 10, 32)
-	if err := c.basicCustomerService.DeleteFollowUpMethod(uint(id)); err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	if err := c.basicCustomerService.WithContext(ctx.Request.Context()).DeleteFollowUpMethod(uint(id)); err != nil {
+		respondError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -493,9 +495,9 @@ func (c *BasicCustomerController) DeleteFollowUpMethod(ctx *gin.Context) {
 
 // GetSalesStageList 获取销售阶段列表
 func (c *BasicCustomerController) GetSalesStageList(ctx *gin.Context) {
-	stages, err := c.basicCustomerService.GetSalesStageList()
+	stages, err := c.basicCustomerService.WithContext(ctx.Request.Context()).GetSalesStageList()
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -505,9 +507,9 @@ func (c *BasicCustomerController) GetSalesStageList(ctx *gin.Context) {
 // GetSalesStageByID 根据ID获取销售阶段
 func (c *BasicCustomerController) GetSalesStageByID(ctx *gin.Context) {
 	id, _ := strconv.ParseUint(ctx.Param("id"), 10, 32)
-	stage, err := c.basicCustomerService.GetSalesStageByID(uint(id))
+	stage, err := c.basicCustomerService.WithContext(ctx.Request.Context()).GetSalesStageByID(uint(id))
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -518,12 +520,12 @@ func (c *BasicCustomerController) GetSalesStageByID(ctx *gin.Context) {
 func (c *BasicCustomerController) CreateSalesStage(ctx *gin.Context) {
 	var stage model.SalesStage
 	if err := ctx.ShouldBindJSON(&stage); err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		respondError(ctx, http.StatusBadRequest, err)
 		return
 	}
 
-	if err := c.basicCustomerService.CreateSalesStage(&stage); err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	if err := c.basicCustomerService.WithContext(ctx.Request.Context()).CreateSalesStage(&stage); err != nil {
+		respondError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -535,13 +537,13 @@ func (c *BasicCustomerController) UpdateSalesStage(ctx *gin.Context) {
 	id, _ := strconv.ParseUint(ctx.Param("id"), 10, 32)
 	var stage model.SalesStage
 	if err := ctx.ShouldBindJSON(&stage); err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		respondError(ctx, http.StatusBadRequest, err)
 		return
 	}
 
 	stage.ID = uint(id)
-	if err := c.basicCustomerService.UpdateSalesStage(&stage); err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	if err := c.basicCustomerService.WithContext(ctx.Request.Context()).UpdateSalesStage(&stage); err != nil {
+		respondError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -551,8 +553,8 @@ func (c *BasicCustomerController) UpdateSalesStage(ctx *gin.Context) {
 // DeleteSalesStage 删除销售阶段
 func (c *BasicCustomerController) DeleteSalesStage(ctx *gin.Context) {
 	id, _ := strconv.ParseUint(ctx.Param("id"), 10, 32)
-	if err := c.basicCustomerService.DeleteSalesStage(uint(id)); err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	if err := c.basicCustomerService.WithContext(ctx.Request.Context()).DeleteSalesStage(uint(id)); err != nil {
+		respondError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -4,6 +4,7 @@ import (
 	"net/http"
 	"strconv"
 
+	"github.com/lemonoa/LemonOA-Go/middleware"
 	"github.com/lemonoa/LemonOA-Go/model"
 	"github.com/lemonoa/LemonOA-Go/service"
 
@@ -23,6 +24,7 @@ func NewBasicFinanceController(basicFinanceService *service.BasicFinanceService)
 // RegisterRoutes 注册路由
 func (c *BasicFinanceController) RegisterRoutes(r *gin.Engine) {
 	api := r.Group("/api/basic/finance")
+	api.Use(middleware.JWT())
 	{
 		// 费用类型
 		api.GET("/expense-types", c.GetExpenseTypeList)
@@ -43,9 +45,9 @@ func (c *BasicFinanceController) GetExpenseTypeList(ctx *gin.Context) {
 		}
 	}
 
-	types, err := c.basicFinanceService.GetExpenseTypeList(parentID)
+	types, err := c.basicFinanceService.WithContext(ctx.Request.Context()).GetExpenseTypeList(parentID)
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -55,9 +57,9 @@ func (c *BasicFinanceController) GetExpenseTypeList(ctx *gin.Context) {
 // GetExpenseTypeByID 根据ID获取费用类型
 func (c *BasicFinanceController) GetExpenseTypeByID(ctx *gin.Context) {
 	id, _ := strconv.ParseUint(ctx.Param("id"), 10, 32)
-	expenseType, err := c.basicFinanceService.GetExpenseTypeByID(uint(id))
+	expenseType, err := c.basicFinanceService.WithContext(ctx.Request.Context()).GetExpenseTypeByID(uint(id))
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -68,12 +70,12 @@ func (c *BasicFinanceController) GetExpenseTypeByID(ctx *gin.Context) {
 func (c *BasicFinanceController) CreateExpenseType(ctx *gin.Context) {
 	var expenseType model.ExpenseType
 	if err := ctx.ShouldBindJSON(&expenseType); err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		respondError(ctx, http.StatusBadRequest, err)
 		return
 	}
 
-	if err := c.basicFinanceService.CreateExpenseType(&expenseType); err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	if err := c.basicFinanceService.WithContext(ctx.Request.Context()).CreateExpenseType(&expenseType); err != nil {
+		respondError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -85,13 +87,13 @@ func (c *BasicFinanceController) UpdateExpenseType(ctx *gin.Context) {
 	id, _ := strconv.ParseUint(ctx.Param("id"), 10, 32)
 	var expenseType model.ExpenseType
 	if err := ctx.ShouldBindJSON(&expenseType); err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		respondError(ctx, http.StatusBadRequest, err)
 		return
 	}
 
 	expenseType.ID = uint(id)
-	if err := c.basicFinanceService.UpdateExpenseType(&expenseType); err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	if err := c.basicFinanceService.WithContext(ctx.Request.Context()).UpdateExpenseType(&expenseType); err != nil {
+		respondError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -101,8 +103,8 @@ func (c *BasicFinanceController) UpdateExpenseType(ctx *gin.Context) {
 // DeleteExpenseType 删除费用类型
 func (c *BasicFinanceController) DeleteExpenseType(ctx *gin.Context) {
 	id, _ := strconv.ParseUint(ctx.Param("id"), 10, 32)
-	if err := c.basicFinanceService.DeleteExpenseType(uint(id)); err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	if err := c.basicFinanceService.WithContext(ctx.Request.Context()).DeleteExpenseType(uint(id)); err != nil {
+		respondError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 
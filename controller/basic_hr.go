@@ -4,6 +4,7 @@ import (
 	"net/http"
 	"strconv"
 
+	"github.com/lemonoa/LemonOA-Go/middleware"
 	"github.com/lemonoa/LemonOA-Go/model"
 	"github.com/lemonoa/LemonOA-Go/service"
 
@@ -23,6 +24,7 @@ func NewBasicHRController(basicHRService *service.BasicHRService) *BasicHRContro
 // RegisterRoutes 注册路由
 func (c *BasicHRController) RegisterRoutes(r *gin.Engine) {
 	api := r.Group("/api/basic/hr")
+	api.Use(middleware.JWT())
 	{
 		// 奖惩项目
 		api.GET("/reward-punishments", c.GetRewardPunishmentList)
@@ -51,9 +53,9 @@ func (c *BasicHRController) RegisterRoutes(r *gin.Engine) {
 // GetRewardPunishmentList 获取奖惩项目列表
 func (c *BasicHRController) GetRewardPunishmentList(ctx *gin.Context) {
 	rewardType, _ := strconv.Atoi(ctx.Query("type"))
-	items, err := c.basicHRService.GetRewardPunishmentList(rewardType)
+	items, err := c.basicHRService.WithContext(ctx.Request.Context()).GetRewardPunishmentList(rewardType)
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -63,9 +65,9 @@ func (c *BasicHRController) GetRewardPunishmentList(ctx *gin.Context) {
 // GetRewardPunishmentByID 根据ID获取奖惩项目
 func (c *BasicHRController) GetRewardPunishmentByID(ctx *gin.Context) {
 	id, _ := strconv.ParseUint(ctx.Param("id"), 10, 32)
-	item, err := c.basicHRService.GetRewardPunishmentByID(uint(id))
+	item, err := c.basicHRService.WithContext(ctx.Request.Context()).GetRewardPunishmentByID(uint(id))
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -76,12 +78,12 @@ func (c *BasicHRController) GetRewardPunishmentByID(ctx *gin.Context) {
 func (c *BasicHRController) CreateRewardPunishment(ctx *gin.Context) {
 	var item model.RewardPunishment
 	if err := ctx.ShouldBindJSON(&item); err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		respondError(ctx, http.StatusBadRequest, err)
 		return
 	}
 
-	if err := c.basicHRService.CreateRewardPunishment(&item); err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	if err := c.basicHRService.WithContext(ctx.Request.Context()).CreateRewardPunishment(&item); err != nil {
+		respondError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -93,13 +95,13 @@ func (c *BasicHRController) UpdateRewardPunishment(ctx *gin.Context) {
 	id, _ := strconv.ParseUint(ctx.Param("id"), 10, 32)
 	var item model.RewardPunishment
 	if err := ctx.ShouldBindJSON(&item); err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		respondError(ctx, http.StatusBadRequest, err)
 		return
 	}
 
 	item.ID = uint(id)
-	if err := c.basicHRService.UpdateRewardPunishment(&item); err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	if err := c.basicHRService.WithContext(ctx.Request.Context()).UpdateRewardPunishment(&item); err != nil {
+		respondError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -109,8 +111,8 @@ func (c *BasicHRController) UpdateRewardPunishment(ctx *gin.Context) {
 // DeleteRewardPunishment 删除奖惩项目
 func (c *BasicHRController) DeleteRewardPunishment(ctx *gin.Context) {
 	id, _ := strconv.ParseUint(ctx.Param("id"), 10, 32)
-	if err := c.basicHRService.DeleteRewardPunishment(uint(id)); err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	if err := c.basicHRService.WithContext(ctx.Request.Context()).DeleteRewardPunishment(uint(id)); err != nil {
+		respondError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -120,9 +122,9 @@ func (c *BasicHRController) DeleteRewardPunishment(ctx *gin.Context) {
 // GetCareProjectList 获取关怀项目列表
 func (c *BasicHRController) GetCareProjectList(ctx *gin.Context) {
 	careType, _ := strconv.Atoi(ctx.Query("type"))
-	items, err := c.basicHRService.GetCareProjectList(careType)
+	items, err := c.basicHRService.WithContext(ctx.Request.Context()).GetCareProjectList(careType)
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -132,9 +134,9 @@ func (c *BasicHRController) GetCareProjectList(ctx *gin.Context) {
 // GetCareProjectByID 根据ID获取关怀项目
 func (c *BasicHRController) GetCareProjectByID(ctx *gin.Context) {
 	id, _ := strconv.ParseUint(ctx.Param("id"), 10, 32)
-	item, err := c.basicHRService.GetCareProjectByID(uint(id))
+	item, err := c.basicHRService.WithContext(ctx.Request.Context()).GetCareProjectByID(uint(id))
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -145,12 +147,12 @@ func (c *BasicHRController) GetCareProjectByID(ctx *gin.Context) {
 func (c *BasicHRController) CreateCareProject(ctx *gin.Context) {
 	var item model.CareProject
 	if err := ctx.ShouldBindJSON(&item); err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		respondError(ctx, http.StatusBadRequest, err)
 		return
 	}
 
-	if err := c.basicHRService.CreateCareProject(&item); err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	if err := c.basicHRService.WithContext(ctx.Request.Context()).CreateCareProject(&item); err != nil {
+		respondError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -162,13 +164,13 @@ func (c *BasicHRController) UpdateCareProject(ctx *gin.Context) {
 	id, _ := strconv.ParseUint(ctx.Param("id"), 10, 32)
 	var item model.CareProject
 	if err := ctx.ShouldBindJSON(&item); err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		respondError(ctx, http.StatusBadRequest, err)
 		return
 	}
 
 	item.ID = uint(id)
-	if err := c.basicHRService.UpdateCareProject(&item); err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	if err := c.basicHRService.WithContext(ctx.Request.Context()).UpdateCareProject(&item); err != nil {
+		respondError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -178,8 +180,8 @@ func (c *BasicHRController) UpdateCareProject(ctx *gin.Context) {
 // DeleteCareProject 删除关怀项目
 func (c *BasicHRController) DeleteCareProject(ctx *gin.Context) {
 	id, _ := strconv.ParseUint(ctx.Param("id"), 10, 32)
-	if err := c.basicHRService.DeleteCareProject(uint(id)); err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	if err := c.basicHRService.WithContext(ctx.Request.Context()).DeleteCareProject(uint(id)); err != nil {
+		respondError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -189,9 +191,9 @@ func (c *BasicHRController) DeleteCareProject(ctx *gin.Context) {
 // GetCommonDataList 获取常规数据列表
 func (c *BasicHRController) GetCommonDataList(ctx *gin.Context) {
 	dataType := ctx.Query("type")
-	items, err := c.basicHRService.GetCommonDataList(dataType)
+	items, err := c.basicHRService.WithContext(ctx.Request.Context()).GetCommonDataList(dataType)
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -201,9 +203,9 @@ func (c *BasicHRController) GetCommonDataList(ctx *gin.Context) {
 // GetCommonDataByID 根据ID获取常规数据
 func (c *BasicHRController) GetCommonDataByID(ctx *gin.Context) {
 	id, _ := strconv.ParseUint(ctx.Param("id"), 10, 32)
-	item, err := c.basicHRService.GetCommonDataByID(uint(id))
+	item, err := c.basicHRService.WithContext(ctx.Request.Context()).GetCommonDataByID(uint(id))
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -213,9 +215,9 @@ func (c *BasicHRController) GetCommonDataByID(ctx *gin.Context) {
 // GetCommonDataByCode 根据Code获取常规数据
 func (c *BasicHRController) GetCommonDataByCode(ctx *gin.Context) {
 	code := ctx.Param("code")
-	item, err := c.basicHRService.GetCommonDataByCode(code)
+	item, err := c.basicHRService.WithContext(ctx.Request.Context()).GetCommonDataByCode(code)
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -226,12 +228,12 @@ func (c *BasicHRController) GetCommonDataByCode(ctx *gin.Context) {
 func (c *BasicHRController) CreateCommonData(ctx *gin.Context) {
 	var item model.CommonData
 	if err := ctx.ShouldBindJSON(&item); err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		respondError(ctx, http.StatusBadRequest, err)
 		return
 	}
 
-	if err := c.basicHRService.CreateCommonData(&item); err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	if err := c.basicHRService.WithContext(ctx.Request.Context()).CreateCommonData(&item); err != nil {
+		respondError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -243,13 +245,13 @@ func (c *BasicHRController) UpdateCommonData(ctx *gin.Context) {
 	id, _ := strconv.ParseUint(ctx.Param("id"), 10, 32)
 	var item model.CommonData
 	if err := ctx.ShouldBindJSON(&item); err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		respondError(ctx, http.StatusBadRequest, err)
 		return
 	}
 
 	item.ID = uint(id)
-	if err := c.basicHRService.UpdateCommonData(&item); err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	if err := c.basicHRService.WithContext(ctx.Request.Context()).UpdateCommonData(&item); err != nil {
+		respondError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -259,8 +261,8 @@ func (c *BasicHRController) UpdateCommonData(ctx *gin.Context) {
 // DeleteCommonData 删除常规数据
 func (c *BasicHRController) DeleteCommonData(ctx *gin.Context) {
 	id, _ := strconv.ParseUint(ctx.Param("id"), 10, 32)
-	if err := c.basicHRService.DeleteCommonData(uint(id)); err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	if err := c.basicHRService.WithContext(ctx.Request.Context()).DeleteCommonData(uint(id)); err != nil {
+		respondError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -4,6 +4,7 @@ import (
 	"net/http"
 	"strconv"
 
+	"github.com/lemonoa/LemonOA-Go/middleware"
 	"github.com/lemonoa/LemonOA-Go/model"
 	"github.com/lemonoa/LemonOA-Go/service"
 
@@ -23,6 +24,7 @@ func NewBasicProjectController(basicProjectService *service.BasicProjectService)
 // RegisterRoutes 注册路由
 func (c *BasicProjectController) RegisterRoutes(r *gin.Engine) {
 	api := r.Group("/api/basic/project")
+	api.Use(middleware.JWT())
 	{
 		// 项目阶段
 		api.GET("/project-stages", c.GetProjectStageList)
@@ -49,9 +51,9 @@ func (c *BasicProjectController) RegisterRoutes(r *gin.Engine) {
 
 // GetProjectStageList 获取项目阶段列表
 func (c *BasicProjectController) GetProjectStageList(ctx *gin.Context) {
-	stages, err := c.basicProjectService.GetProjectStageList()
+	stages, err := c.basicProjectService.WithContext(ctx.Request.Context()).GetProjectStageList()
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -61,9 +63,9 @@ func (c *BasicProjectController) GetProjectStageList(ctx *gin.Context) {
 // GetProjectStageByID 根据ID获取项目阶段
 func (c *BasicProjectController) GetProjectStageByID(ctx *gin.Context) {
 	id, _ := strconv.ParseUint(ctx.Param("id"), 10, 32)
-	stage, err := c.basicProjectService.GetProjectStageByID(uint(id))
+	stage, err := c.basicProjectService.WithContext(ctx.Request.Context()).GetProjectStageByID(uint(id))
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -74,12 +76,12 @@ func (c *BasicProjectController) GetProjectStageByID(ctx *gin.Context) {
 func (c *BasicProjectController) CreateProjectStage(ctx *gin.Context) {
 	var stage model.ProjectStage
 	if err := ctx.ShouldBindJSON(&stage); err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		respondError(ctx, http.StatusBadRequest, err)
 		return
 	}
 
-	if err := c.basicProjectService.CreateProjectStage(&stage); err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	if err := c.basicProjectService.WithContext(ctx.Request.Context()).CreateProjectStage(&stage); err != nil {
+		respondError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -91,13 +93,13 @@ func (c *BasicProjectController) UpdateProjectStage(ctx *gin.Context) {
 	id, _ := strconv.ParseUint(ctx.Param("id"), 10, 32)
 	var stage model.ProjectStage
 	if err := ctx.ShouldBindJSON(&stage); err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		respondError(ctx, http.StatusBadRequest, err)
 		return
 	}
 
 	stage.ID = uint(id)
-	if err := c.basicProjectService.UpdateProjectStage(&stage); err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	if err := c.basicProjectService.WithContext(ctx.Request.Context()).UpdateProjectStage(&stage); err != nil {
+		respondError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -107,8 +109,8 @@ func (c *BasicProjectController) UpdateProjectStage(ctx *gin.Context) {
 // DeleteProjectStage 删除项目阶段
 func (c *BasicProjectController) DeleteProjectStage(ctx *gin.Context) {
 	id, _ := strconv.ParseUint(ctx.Param("id"), 10, 32)
-	if err := c.basicProjectService.DeleteProjectStage(uint(id)); err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	if err := c.basicProjectService.WithContext(ctx.Request.Context()).DeleteProjectStage(uint(id)); err != nil {
+		respondError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -117,9 +119,9 @@ func (c *BasicProjectController) DeleteProjectStage(ctx *gin.Context) {
 
 // GetProjectCategoryList 获取项目分类列表
 func (c *BasicProjectController) GetProjectCategoryList(ctx *gin.Context) {
-	categories, err := c.basicProjectService.GetProjectCategoryList()
+	categories, err := c.basicProjectService.WithContext(ctx.Request.Context()).GetProjectCategoryList()
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -129,9 +131,9 @@ func (c *BasicProjectController) GetProjectCategoryList(ctx *gin.Context) {
 // GetProjectCategoryByID 根据ID获取项目分类
 func (c *BasicProjectController) GetProjectCategoryByID(ctx *gin.Context) {
 	id, _ := strconv.ParseUint(ctx.Param("id"), 10, 32)
-	category, err := c.basicProjectService.GetProjectCategoryByID(uint(id))
+	category, err := c.basicProjectService.WithContext(ctx.Request.Context()).GetProjectCategoryByID(uint(id))
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -142,12 +144,12 @@ func (c *BasicProjectController) GetProjectCategoryByID(ctx *gin.Context) {
 func (c *BasicProjectController) CreateProjectCategory(ctx *gin.Context) {
 	var category model.ProjectCategory
 	if err := ctx.ShouldBindJSON(&category); err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		respondError(ctx, http.StatusBadRequest, err)
 		return
 	}
 
-	if err := c.basicProjectService.CreateProjectCategory(&category); err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	if err := c.basicProjectService.WithContext(ctx.Request.Context()).CreateProjectCategory(&category); err != nil {
+		respondError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -159,13 +161,13 @@ func (c *BasicProjectController) UpdateProjectCategory(ctx *gin.Context) {
 	id, _ := strconv.ParseUint(ctx.Param("id"), 10, 32)
 	var category model.ProjectCategory
 	if err := ctx.ShouldBindJSON(&category); err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		respondError(ctx, http.StatusBadRequest, err)
 		return
 	}
 
 	category.ID = uint(id)
-	if err := c.basicProjectService.UpdateProjectCategory(&category); err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	if err := c.basicProjectService.WithContext(ctx.Request.Context()).UpdateProjectCategory(&category); err != nil {
+		respondError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -175,8 +177,8 @@ func (c *BasicProjectController) UpdateProjectCategory(ctx *gin.Context) {
 // DeleteProjectCategory 删除项目分类
 func (c *BasicProjectController) DeleteProjectCategory(ctx *gin.Context) {
 	id, _ := strconv.ParseUint(ctx.Param("id"), 10, 32)
-	if err := c.basicProjectService.DeleteProjectCategory(uint(id)); err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	if err := c.basicProjectService.WithContext(ctx.Request.Context()).DeleteProjectCategory(uint(id)); err != nil {
+		respondError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -185,9 +187,9 @@ func (c *BasicProjectController) DeleteProjectCategory(ctx *gin.Context) {
 
 // GetWorkTypeList 获取工作类型列表
 func (c *BasicProjectController) GetWorkTypeList(ctx *gin.Context) {
-	types, err := c.basicProjectService.GetWorkTypeList()
+	types, err := c.basicProjectService.WithContext(ctx.Request.Context()).GetWorkTypeList()
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -197,9 +199,9 @@ func (c *BasicProjectController) GetWorkTypeList(ctx *gin.Context) {
 // GetWorkTypeByID 根据ID获取工作类型
 func (c *BasicProjectController) GetWorkTypeByID(ctx *gin.Context) {
 	id, _ := strconv.ParseUint(ctx.Param("id"), 10, 32)
-	workType, err := c.basicProjectService.GetWorkTypeByID(uint(id))
+	workType, err := c.basicProjectService.WithContext(ctx.Request.Context()).GetWorkTypeByID(uint(id))
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -210,12 +212,12 @@ func (c *BasicProjectController) GetWorkTypeByID(ctx *gin.Context) {
 func (c *BasicProjectController) CreateWorkType(ctx *gin.Context) {
 	var workType model.WorkType
 	if err := ctx.ShouldBindJSON(&workType); err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		respondError(ctx, http.StatusBadRequest, err)
 		return
 	}
 
-	if err := c.basicProjectService.CreateWorkType(&workType); err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	if err := c.basicProjectService.WithContext(ctx.Request.Context()).CreateWorkType(&workType); err != nil {
+		respondError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -227,13 +229,13 @@ func (c *BasicProjectController) UpdateWorkType(ctx *gin.Context) {
 	id, _ := strconv.ParseUint(ctx.Param("id"), 10, 32)
 	var workType model.WorkType
 	if err := ctx.ShouldBindJSON(&workType); err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		respondError(ctx, http.StatusBadRequest, err)
 		return
 	}
 
 	workType.ID = uint(id)
-	if err := c.basicProjectService.UpdateWorkType(&workType); err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	if err := c.basicProjectService.WithContext(ctx.Request.Context()).UpdateWorkType(&workType); err != nil {
+		respondError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -243,8 +245,8 @@ func (c *BasicProjectController) UpdateWorkType(ctx *gin.Context) {
 // DeleteWorkType 删除工作类型
 func (c *BasicProjectController) DeleteWorkType(ctx *gin.Context) {
 	id, _ := strconv.ParseUint(ctx.Param("id"), 10, 32)
-	if err := c.basicProjectService.DeleteWorkType(uint(id)); err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	if err := c.basicProjectService.WithContext(ctx.Request.Context()).DeleteWorkType(uint(id)); err != nil {
+		respondError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 
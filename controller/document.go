@@ -25,7 +25,7 @@ func NewDocumentController(documentService *service.DocumentService) *DocumentCo
 // RegisterRoutes 注册路由
 func (c *DocumentController) RegisterRoutes(r *gin.Engine) {
 	api := r.Group("/api")
-	api.Use(middleware.JWT())
+	api.Use(middleware.JWT(), middleware.DataScope())
 
 	// 文档管理
 	docs := api.Group("/documents")
@@ -54,9 +54,9 @@ func (c *DocumentController) GetDocumentList(ctx *gin.Context) {
 	page, _ := strconv.Atoi(ctx.DefaultQuery("page", "1"))
 	pageSize, _ := strconv.Atoi(ctx.DefaultQuery("page_size", "10"))
 
-	documents, total, err := c.documentService.GetDocumentList(uint(typeID), status, keyword, page, pageSize)
+	documents, total, err := c.documentService.WithContext(ctx.Request.Context()).GetDocumentList(uint(typeID), status, keyword, page, pageSize, middleware.CurrentDataScope(ctx))
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -69,9 +69,9 @@ func (c *DocumentController) GetDocumentList(ctx *gin.Context) {
 // GetDocumentByID 根据ID获取公文
 func (c *DocumentController) GetDocumentByID(ctx *gin.Context) {
 	id, _ := strconv.ParseUint(ctx.Param("id"), 10, 32)
-	document, err := c.documentService.GetDocumentByID(uint(id))
+	document, err := c.documentService.WithContext(ctx.Request.Context()).GetDocumentByID(uint(id), middleware.CurrentDataScope(ctx))
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -82,15 +82,12 @@ func (c *DocumentController) GetDocumentByID(ctx *gin.Context) {
 func (c *DocumentController) CreateDocument(ctx *gin.Context) {
 	var document model.Document
 	if err := ctx.ShouldBindJSON(&document); err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		respondError(ctx, http.StatusBadRequest, err)
 		return
 	}
 
-	// TODO: 从JWT中获取当前用户ID
-	document.CreatedBy = uint(1)
-
-	if err := c.documentService.CreateDocument(&document); err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	if err := c.documentService.WithContext(ctx.Request.Context()).CreateDocument(&document); err != nil {
+		respondError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -102,13 +99,13 @@ func (c *DocumentController) UpdateDocument(ctx *gin.Context) {
 	id, _ := strconv.ParseUint(ctx.Param("id"), 10, 32)
 	var document model.Document
 	if err := ctx.ShouldBindJSON(&document); err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		respondError(ctx, http.StatusBadRequest, err)
 		return
 	}
 
 	document.ID = uint(id)
-	if err := c.documentService.UpdateDocument(&document); err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	if err := c.documentService.WithContext(ctx.Request.Context()).UpdateDocument(&document); err != nil {
+		respondError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -118,8 +115,8 @@ func (c *DocumentController) UpdateDocument(ctx *gin.Context) {
 // DeleteDocument 删除公文
 func (c *DocumentController) DeleteDocument(ctx *gin.Context) {
 	id, _ := strconv.ParseUint(ctx.Param("id"), 10, 32)
-	if err := c.documentService.DeleteDocument(uint(id)); err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	if err := c.documentService.WithContext(ctx.Request.Context()).DeleteDocument(uint(id)); err != nil {
+		respondError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -131,12 +128,12 @@ func (c *DocumentController) SubmitDocument(ctx *gin.Context) {
 	id, _ := strconv.ParseUint(ctx.Param("id"), 10, 32)
 	var approvers []model.DocumentApproval
 	if err := ctx.ShouldBindJSON(&approvers); err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		respondError(ctx, http.StatusBadRequest, err)
 		return
 	}
 
-	if err := c.documentService.SubmitDocument(uint(id), approvers); err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	if err := c.documentService.WithContext(ctx.Request.Context()).SubmitDocument(uint(id), approvers); err != nil {
+		respondError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -150,15 +147,14 @@ func (c *DocumentController) ApproveDocument(ctx *gin.Context) {
 		Comment string `json:"comment"`
 	}
 	if err := ctx.ShouldBindJSON(&data); err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		respondError(ctx, http.StatusBadRequest, err)
 		return
 	}
 
-	// TODO: 从JWT中获取当前用户ID
-	approverID := uint(1)
+	approverID := middleware.CurrentUserID(ctx)
 
-	if err := c.documentService.ApproveDocument(uint(id), approverID, data.Comment); err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	if err := c.documentService.WithContext(ctx.Request.Context()).ApproveDocument(uint(id), approverID, data.Comment); err != nil {
+		respondError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -172,15 +168,14 @@ func (c *DocumentController) RejectDocument(ctx *gin.Context) {
 		Comment string `json:"comment"`
 	}
 	if err := ctx.ShouldBindJSON(&data); err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		respondError(ctx, http.StatusBadRequest, err)
 		return
 	}
 
-	// TODO: 从JWT中获取当前用户ID
-	approverID := uint(1)
+	approverID := middleware.CurrentUserID(ctx)
 
-	if err := c.documentService.RejectDocument(uint(id), approverID, data.Comment); err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	if err := c.documentService.WithContext(ctx.Request.Context()).RejectDocument(uint(id), approverID, data.Comment); err != nil {
+		respondError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -191,17 +186,12 @@ func (c *DocumentController) RejectDocument(ctx *gin.Context) {
 func (c *DocumentController) DistributeDocument(ctx *gin.Context) {
 	var distributions []model.DocumentDistribution
 	if err := ctx.ShouldBindJSON(&distributions); err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		respondError(ctx, http.StatusBadRequest, err)
 		return
 	}
 
-	// TODO: 从JWT中获取当前用户ID
-	for i := range distributions {
-		distributions[i].CreatedBy = uint(1)
-	}
-
-	if err := c.documentService.DistributeDocument(distributions); err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	if err := c.documentService.WithContext(ctx.Request.Context()).DistributeDocument(distributions); err != nil {
+		respondError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -211,11 +201,10 @@ func (c *DocumentController) DistributeDocument(ctx *gin.Context) {
 // ReadDocument 阅读公文
 func (c *DocumentController) ReadDocument(ctx *gin.Context) {
 	id, _ := strconv.ParseUint(ctx.Param("id"), 10, 32)
-	// TODO: 从JWT中获取当前用户ID
-	receiverID := uint(1)
+	receiverID := middleware.CurrentUserID(ctx)
 
-	if err := c.documentService.ReadDocument(uint(id), receiverID); err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	if err := c.documentService.WithContext(ctx.Request.Context()).ReadDocument(uint(id), receiverID); err != nil {
+		respondError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -226,15 +215,12 @@ func (c *DocumentController) ReadDocument(ctx *gin.Context) {
 func (c *DocumentController) ArchiveDocument(ctx *gin.Context) {
 	var archive model.DocumentArchive
 	if err := ctx.ShouldBindJSON(&archive); err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		respondError(ctx, http.StatusBadRequest, err)
 		return
 	}
 
-	// TODO: 从JWT中获取当前用户ID
-	archive.CreatedBy = uint(1)
-
-	if err := c.documentService.ArchiveDocument(&archive); err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	if err := c.documentService.WithContext(ctx.Request.Context()).ArchiveDocument(&archive); err != nil {
+		respondError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -245,15 +231,12 @@ func (c *DocumentController) ArchiveDocument(ctx *gin.Context) {
 func (c *DocumentController) BorrowDocument(ctx *gin.Context) {
 	var borrow model.DocumentBorrow
 	if err := ctx.ShouldBindJSON(&borrow); err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		respondError(ctx, http.StatusBadRequest, err)
 		return
 	}
 
-	// TODO: 从JWT中获取当前用户ID
-	borrow.CreatedBy = uint(1)
-
-	if err := c.documentService.BorrowDocument(&borrow); err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	if err := c.documentService.WithContext(ctx.Request.Context()).BorrowDocument(&borrow); err != nil {
+		respondError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -263,8 +246,8 @@ func (c *DocumentController) BorrowDocument(ctx *gin.Context) {
 // ReturnDocument 归还公文
 func (c *DocumentController) ReturnDocument(ctx *gin.Context) {
 	id, _ := strconv.ParseUint(ctx.Param("id"), 10, 32)
-	if err := c.documentService.ReturnDocument(uint(id)); err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	if err := c.documentService.WithContext(ctx.Request.Context()).ReturnDocument(uint(id)); err != nil {
+		respondError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -274,8 +257,8 @@ func (c *DocumentController) ReturnDocument(ctx *gin.Context) {
 // DestroyDocument 销毁公文
 func (c *DocumentController) DestroyDocument(ctx *gin.Context) {
 	id, _ := strconv.ParseUint(ctx.Param("id"), 10, 32)
-	if err := c.documentService.DestroyDocument(uint(id)); err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	if err := c.documentService.WithContext(ctx.Request.Context()).DestroyDocument(uint(id)); err != nil {
+		respondError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 
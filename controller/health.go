@@ -0,0 +1,53 @@
+package controller
+
+import (
+	"context"
+	"net/http"
+	"sync/atomic"
+	"time"
+
+	"github.com/lemonoa/LemonOA-Go/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+type HealthController struct {
+	draining atomic.Bool
+}
+
+func NewHealthController() *HealthController {
+	return &HealthController{}
+}
+
+// RegisterRoutes 注册路由，供负载均衡和容器编排探测，不需要认证
+func (c *HealthController) RegisterRoutes(r *gin.Engine) {
+	r.GET("/healthz", c.Healthz)
+	r.GET("/readyz", c.Readyz)
+}
+
+// Drain 服务开始退出，就绪检查返回503，使负载均衡不再分发新请求
+func (c *HealthController) Drain() {
+	c.draining.Store(true)
+}
+
+// Healthz 存活检查，进程能处理请求即返回200
+func (c *HealthController) Healthz(ctx *gin.Context) {
+	ctx.JSON(http.StatusOK, gin.H{"status": "ok"})
+}
+
+// Readyz 就绪检查，数据库以及已启用的Redis和附件存储均可用时返回200
+func (c *HealthController) Readyz(ctx *gin.Context) {
+	if c.draining.Load() {
+		ctx.JSON(http.StatusServiceUnavailable, gin.H{"status": "draining"})
+		return
+	}
+
+	checkCtx, cancel := context.WithTimeout(ctx.Request.Context(), 3*time.Second)
+	defer cancel()
+	checks, ready := service.CheckReadiness(checkCtx)
+	if !ready {
+		ctx.JSON(http.StatusServiceUnavailable, gin.H{"status": "unavailable", "checks": checks})
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{"status": "ok", "checks": checks})
+}
@@ -7,6 +7,8 @@ import (
 	"github.com/lemonoa/LemonOA-Go/model"
 	"github.com/lemonoa/LemonOA-Go/service"
 
+	"github.com/lemonoa/LemonOA-Go/middleware"
+
 	"github.com/gin-gonic/gin"
 )
 
@@ -23,6 +25,7 @@ func NewHRController(hrService *service.HRService) *HRController {
 // RegisterRoutes 注册路由
 func (c *HRController) RegisterRoutes(r *gin.Engine) {
 	api := r.Group("/api/hr")
+	api.Use(middleware.JWT(), middleware.DataScope())
 	{
 		// 岗位职称管理
 		api.GET("/positions", c.GetPositionList)
@@ -91,9 +94,9 @@ func (c *HRController) RegisterRoutes(r *gin.Engine) {
 
 // GetPositionList 获取岗位职称列表
 func (c *HRController) GetPositionList(ctx *gin.Context) {
-	positions, err := c.hrService.GetPositionList()
+	positions, err := c.hrService.WithContext(ctx.Request.Context()).GetPositionList()
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -103,9 +106,9 @@ func (c *HRController) GetPositionList(ctx *gin.Context) {
 // GetPositionByID 根据ID获取岗位职称
 func (c *HRController) GetPositionByID(ctx *gin.Context) {
 	id, _ := strconv.ParseUint(ctx.Param("id"), 10, 32)
-	position, err := c.hrService.GetPositionByID(uint(id))
+	position, err := c.hrService.WithContext(ctx.Request.Context()).GetPositionByID(uint(id))
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -116,12 +119,12 @@ func (c *HRController) GetPositionByID(ctx *gin.Context) {
 func (c *HRController) CreatePosition(ctx *gin.Context) {
 	var position model.Position
 	if err := ctx.ShouldBindJSON(&position); err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		respondError(ctx, http.StatusBadRequest, err)
 		return
 	}
 
-	if err := c.hrService.CreatePosition(&position); err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	if err := c.hrService.WithContext(ctx.Request.Context()).CreatePosition(&position); err != nil {
+		respondError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -133,13 +136,13 @@ func (c *HRController) UpdatePosition(ctx *gin.Context) {
 	id, _ := strconv.ParseUint(ctx.Param("id"), 10, 32)
 	var position model.Position
 	if err := ctx.ShouldBindJSON(&position); err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		respondError(ctx, http.StatusBadRequest, err)
 		return
 	}
 
 	position.ID = uint(id)
-	if err := c.hrService.UpdatePosition(&position); err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	if err := c.hrService.WithContext(ctx.Request.Context()).UpdatePosition(&position); err != nil {
+		respondError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -149,8 +152,8 @@ func (c *HRController) UpdatePosition(ctx *gin.Context) {
 // DeletePosition 删除岗位职称
 func (c *HRController) DeletePosition(ctx *gin.Context) {
 	id, _ := strconv.ParseUint(ctx.Param("id"), 10, 32)
-	if err := c.hrService.DeletePosition(uint(id)); err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	if err := c.hrService.WithContext(ctx.Request.Context()).DeletePosition(uint(id)); err != nil {
+		respondError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -163,12 +166,13 @@ func (c *HRController) GetEmployeeArchiveList(ctx *gin.Context) {
 	page, _ := strconv.Atoi(ctx.DefaultQuery("page", "1"))
 	pageSize, _ := strconv.Atoi(ctx.DefaultQuery("page_size", "10"))
 
-	archives, total, err := c.hrService.GetEmployeeArchiveList(uint(departmentID), page, pageSize)
+	archives, total, err := c.hrService.WithContext(ctx.Request.Context()).GetEmployeeArchiveList(uint(departmentID), page, pageSize, middleware.CurrentDataScope(ctx))
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 
+	middleware.MaskSensitive(ctx, archives)
 	ctx.JSON(http.StatusOK, gin.H{
 		"data":  archives,
 		"total": total,
@@ -178,12 +182,13 @@ func (c *HRController) GetEmployeeArchiveList(ctx *gin.Context) {
 // GetEmployeeArchiveByEmployeeID 根据员工ID获取档案
 func (c *HRController) GetEmployeeArchiveByEmployeeID(ctx *gin.Context) {
 	employeeID, _ := strconv.ParseUint(ctx.Param("employee_id"), 10, 32)
-	archive, err := c.hrService.GetEmployeeArchiveByEmployeeID(uint(employeeID))
+	archive, err := c.hrService.WithContext(ctx.Request.Context()).GetEmployeeArchiveByEmployeeID(uint(employeeID), middleware.CurrentDataScope(ctx))
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 
+	middleware.MaskSensitive(ctx, archive)
 	ctx.JSON(http.StatusOK, archive)
 }
 
@@ -191,12 +196,12 @@ func (c *HRController) GetEmployeeArchiveByEmployeeID(ctx *gin.Context) {
 func (c *HRController) CreateEmployeeArchive(ctx *gin.Context) {
 	var archive model.EmployeeArchive
 	if err := ctx.ShouldBindJSON(&archive); err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		respondError(ctx, http.StatusBadRequest, err)
 		return
 	}
 
-	if err := c.hrService.CreateEmployeeArchive(&archive); err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	if err := c.hrService.WithContext(ctx.Request.Context()).CreateEmployeeArchive(&archive); err != nil {
+		respondError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -208,13 +213,13 @@ func (c *HRController) UpdateEmployeeArchive(ctx *gin.Context) {
 	id, _ := strconv.ParseUint(ctx.Param("id"), 10, 32)
 	var archive model.EmployeeArchive
 	if err := ctx.ShouldBindJSON(&archive); err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		respondError(ctx, http.StatusBadRequest, err)
 		return
 	}
 
 	archive.ID = uint(id)
-	if err := c.hrService.UpdateEmployeeArchive(&archive); err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	if err := c.hrService.WithContext(ctx.Request.Context()).UpdateEmployeeArchive(&archive); err != nil {
+		respondError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -224,8 +229,8 @@ func (c *HRController) UpdateEmployeeArchive(ctx *gin.Context) {
 // DeleteEmployeeArchive 删除员工档案
 func (c *HRController) DeleteEmployeeArchive(ctx *gin.Context) {
 	id, _ := strconv.ParseUint(ctx.Param("id"), 10, 32)
-	if err := c.hrService.DeleteEmployeeArchive(uint(id)); err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	if err := c.hrService.WithContext(ctx.Request.Context()).DeleteEmployeeArchive(uint(id)); err != nil {
+		respondError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -238,9 +243,9 @@ func (c *HRController) GetRewardPunishmentRecordList(ctx *gin.Context) {
 	page, _ := strconv.Atoi(ctx.DefaultQuery("page", "1"))
 	pageSize, _ := strconv.Atoi(ctx.DefaultQuery("page_size", "10"))
 
-	records, total, err := c.hrService.GetRewardPunishmentRecordList(uint(employeeID), page, pageSize)
+	records, total, err := c.hrService.WithContext(ctx.Request.Context()).GetRewardPunishmentRecordList(uint(employeeID), page, pageSize, middleware.CurrentDataScope(ctx))
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -253,9 +258,9 @@ func (c *HRController) GetRewardPunishmentRecordList(ctx *gin.Context) {
 // GetRewardPunishmentRecordByID 根据ID获取奖惩记录
 func (c *HRController) GetRewardPunishmentRecordByID(ctx *gin.Context) {
 	id, _ := strconv.ParseUint(ctx.Param("id"), 10, 32)
-	record, err := c.hrService.GetRewardPunishmentRecordByID(uint(id))
+	record, err := c.hrService.WithContext(ctx.Request.Context()).GetRewardPunishmentRecordByID(uint(id), middleware.CurrentDataScope(ctx))
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -266,12 +271,12 @@ func (c *HRController) GetRewardPunishmentRecordByID(ctx *gin.Context) {
 func (c *HRController) CreateRewardPunishmentRecord(ctx *gin.Context) {
 	var record model.RewardPunishmentRecord
 	if err := ctx.ShouldBindJSON(&record); err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		respondError(ctx, http.StatusBadRequest, err)
 		return
 	}
 
-	if err := c.hrService.CreateRewardPunishmentRecord(&record); err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	if err := c.hrService.WithContext(ctx.Request.Context()).CreateRewardPunishmentRecord(&record); err != nil {
+		respondError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -283,13 +288,13 @@ func (c *HRController) UpdateRewardPunishmentRecord(ctx *gin.Context) {
 	id, _ := strconv.ParseUint(ctx.Param("id"), 10, 32)
 	var record model.RewardPunishmentRecord
 	if err := ctx.ShouldBindJSON(&record); err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		respondError(ctx, http.StatusBadRequest, err)
 		return
 	}
 
 	record.ID = uint(id)
-	if err := c.hrService.UpdateRewardPunishmentRecord(&record); err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	if err := c.hrService.WithContext(ctx.Request.Context()).UpdateRewardPunishmentRecord(&record); err != nil {
+		respondError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -299,8 +304,8 @@ func (c *HRController) UpdateRewardPunishmentRecord(ctx *gin.Context) {
 // DeleteRewardPunishmentRecord 删除奖惩记录
 func (c *HRController) DeleteRewardPunishmentRecord(ctx *gin.Context) {
 	id, _ := strconv.ParseUint(ctx.Param("id"), 10, 32)
-	if err := c.hrService.DeleteRewardPunishmentRecord(uint(id)); err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	if err := c.hrService.WithContext(ctx.Request.Context()).DeleteRewardPunishmentRecord(uint(id)); err != nil {
+		respondError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -313,9 +318,9 @@ func (c *HRController) GetCareRecordList(ctx *gin.Context) {
 	page, _ := strconv.Atoi(ctx.DefaultQuery("page", "1"))
 	pageSize, _ := strconv.Atoi(ctx.DefaultQuery("page_size", "10"))
 
-	records, total, err := c.hrService.GetCareRecordList(uint(employeeID), page, pageSize)
+	records, total, err := c.hrService.WithContext(ctx.Request.Context()).GetCareRecordList(uint(employeeID), page, pageSize, middleware.CurrentDataScope(ctx))
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -328,9 +333,9 @@ func (c *HRController) GetCareRecordList(ctx *gin.Context) {
 // GetCareRecordByID 根据ID获取关怀记录
 func (c *HRController) GetCareRecordByID(ctx *gin.Context) {
 	id, _ := strconv.ParseUint(ctx.Param("id"), 10, 32)
-	record, err := c.hrService.GetCareRecordByID(uint(id))
+	record, err := c.hrService.WithContext(ctx.Request.Context()).GetCareRecordByID(uint(id), middleware.CurrentDataScope(ctx))
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -341,12 +346,12 @@ func (c *HRController) GetCareRecordByID(ctx *gin.Context) {
 func (c *HRController) CreateCareRecord(ctx *gin.Context) {
 	var record model.CareRecord
 	if err := ctx.ShouldBindJSON(&record); err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		respondError(ctx, http.StatusBadRequest, err)
 		return
 	}
 
-	if err := c.hrService.CreateCareRecord(&record); err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	if err := c.hrService.WithContext(ctx.Request.Context()).CreateCareRecord(&record); err != nil {
+		respondError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -358,13 +363,13 @@ func (c *HRController) UpdateCareRecord(ctx *gin.Context) {
 	id, _ := strconv.ParseUint(ctx.Param("id"), 10, 32)
 	var record model.CareRecord
 	if err := ctx.ShouldBindJSON(&record); err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		respondError(ctx, http.StatusBadRequest, err)
 		return
 	}
 
 	record.ID = uint(id)
-	if err := c.hrService.UpdateCareRecord(&record); err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	if err := c.hrService.WithContext(ctx.Request.Context()).UpdateCareRecord(&record); err != nil {
+		respondError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -374,8 +379,8 @@ func (c *HRController) UpdateCareRecord(ctx *gin.Context) {
 // DeleteCareRecord 删除关怀记录
 func (c *HRController) DeleteCareRecord(ctx *gin.Context) {
 	id, _ := strconv.ParseUint(ctx.Param("id"), 10, 32)
-	if err := c.hrService.DeleteCareRecord(uint(id)); err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	if err := c.hrService.WithContext(ctx.Request.Context()).DeleteCareRecord(uint(id)); err != nil {
+		respondError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -389,9 +394,9 @@ func (c *HRController) GetTransferList(ctx *gin.Context) {
 	page, _ := strconv.Atoi(ctx.DefaultQuery("page", "1"))
 	pageSize, _ := strconv.Atoi(ctx.DefaultQuery("page_size", "10"))
 
-	transfers, total, err := c.hrService.GetTransferList(uint(employeeID), status, page, pageSize)
+	transfers, total, err := c.hrService.WithContext(ctx.Request.Context()).GetTransferList(uint(employeeID), status, page, pageSize, middleware.CurrentDataScope(ctx))
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -404,9 +409,9 @@ func (c *HRController) GetTransferList(ctx *gin.Context) {
 // GetTransferByID 根据ID获取人事调动
 func (c *HRController) GetTransferByID(ctx *gin.Context) {
 	id, _ := strconv.ParseUint(ctx.Param("id"), 10, 32)
-	transfer, err := c.hrService.GetTransferByID(uint(id))
+	transfer, err := c.hrService.WithContext(ctx.Request.Context()).GetTransferByID(uint(id), middleware.CurrentDataScope(ctx))
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -417,12 +422,12 @@ func (c *HRController) GetTransferByID(ctx *gin.Context) {
 func (c *HRController) CreateTransfer(ctx *gin.Context) {
 	var transfer model.Transfer
 	if err := ctx.ShouldBindJSON(&transfer); err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		respondError(ctx, http.StatusBadRequest, err)
 		return
 	}
 
-	if err := c.hrService.CreateTransfer(&transfer); err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	if err := c.hrService.WithContext(ctx.Request.Context()).CreateTransfer(&transfer); err != nil {
+		respondError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -434,13 +439,13 @@ func (c *HRController) UpdateTransfer(ctx *gin.Context) {
 	id, _ := strconv.ParseUint(ctx.Param("id"), 10, 32)
 	var transfer model.Transfer
 	if err := ctx.ShouldBindJSON(&transfer); err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		respondError(ctx, http.StatusBadRequest, err)
 		return
 	}
 
 	transfer.ID = uint(id)
-	if err := c.hrService.UpdateTransfer(&transfer); err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	if err := c.hrService.WithContext(ctx.Request.Context()).UpdateTransfer(&transfer); err != nil {
+		respondError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -450,8 +455,8 @@ func (c *HRController) UpdateTransfer(ctx *gin.Context) {
 // DeleteTransfer 删除人事调动
 func (c *HRController) DeleteTransfer(ctx *gin.Context) {
 	id, _ := strconv.ParseUint(ctx.Param("id"), 10, 32)
-	if err := c.hrService.DeleteTransfer(uint(id)); err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	if err := c.hrService.WithContext(ctx.Request.Context()).DeleteTransfer(uint(id)); err != nil {
+		respondError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -461,8 +466,8 @@ func (c *HRController) DeleteTransfer(ctx *gin.Context) {
 // ApproveTransfer 审批通过人事调动
 func (c *HRController) ApproveTransfer(ctx *gin.Context) {
 	id, _ := strconv.ParseUint(ctx.Param("id"), 10, 32)
-	if err := c.hrService.ApproveTransfer(uint(id)); err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	if err := c.hrService.WithContext(ctx.Request.Context()).ApproveTransfer(uint(id)); err != nil {
+		respondError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -472,8 +477,8 @@ func (c *HRController) ApproveTransfer(ctx *gin.Context) {
 // RejectTransfer 审批驳回人事调动
 func (c *HRController) RejectTransfer(ctx *gin.Context) {
 	id, _ := strconv.ParseUint(ctx.Param("id"), 10, 32)
-	if err := c.hrService.RejectTransfer(uint(id)); err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	if err := c.hrService.WithContext(ctx.Request.Context()).RejectTransfer(uint(id)); err != nil {
+		respondError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -487,9 +492,9 @@ func (c *HRController) GetResignationList(ctx *gin.Context) {
 	page, _ := strconv.Atoi(ctx.DefaultQuery("page", "1"))
 	pageSize, _ := strconv.Atoi(ctx.DefaultQuery("page_size", "10"))
 
-	resignations, total, err := c.hrService.GetResignationList(uint(employeeID), status, page, pageSize)
+	resignations, total, err := c.hrService.WithContext(ctx.Request.Context()).GetResignationList(uint(employeeID), status, page, pageSize, middleware.CurrentDataScope(ctx))
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -502,9 +507,9 @@ func (c *HRController) GetResignationList(ctx *gin.Context) {
 // GetResignationByID 根据ID获取离职档案
 func (c *HRController) GetResignationByID(ctx *gin.Context) {
 	id, _ := strconv.ParseUint(ctx.Param("id"), 10, 32)
-	resignation, err := c.hrService.GetResignationByID(uint(id))
+	resignation, err := c.hrService.WithContext(ctx.Request.Context()).GetResignationByID(uint(id), middleware.CurrentDataScope(ctx))
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -515,12 +520,12 @@ func (c *HRController) GetResignationByID(ctx *gin.Context) {
 func (c *HRController) CreateResignation(ctx *gin.Context) {
 	var resignation model.Resignation
 	if err := ctx.ShouldBindJSON(&resignation); err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		respondError(ctx, http.StatusBadRequest, err)
 		return
 	}
 
-	if err := c.hrService.CreateResignation(&resignation); err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	if err := c.hrService.WithContext(ctx.Request.Context()).CreateResignation(&resignation); err != nil {
+		respondError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -532,13 +537,13 @@ func (c *HRController) UpdateResignation(ctx *gin.Context) {
 	id, _ := strconv.ParseUint(ctx.Param("id"), 10, 32)
 	var resignation model.Resignation
 	if err := ctx.ShouldBindJSON(&resignation); err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		respondError(ctx, http.StatusBadRequest, err)
 		return
 	}
 
 	resignation.ID = uint(id)
-	if err := c.hrService.UpdateResignation(&resignation); err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	if err := c.hrService.WithContext(ctx.Request.Context()).UpdateResignation(&resignation); err != nil {
+		respondError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -548,8 +553,8 @@ func (c *HRController) UpdateResignation(ctx *gin.Context) {
 // DeleteResignation 删除离职档案
 func (c *HRController) DeleteResignation(ctx *gin.Context) {
 	id, _ := strconv.ParseUint(ctx.Param("id"), 10, 32)
-	if err := c.hrService.DeleteResignation(uint(id)); err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	if err := c.hrService.WithContext(ctx.Request.Context()).DeleteResignation(uint(id)); err != nil {
+		respondError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -559,8 +564,8 @@ func (c *HRController) DeleteResignation(ctx *gin.Context) {
 // ApproveResignation 审批通过离职档案
 func (c *HRController) ApproveResignation(ctx *gin.Context) {
 	id, _ := strconv.ParseUint(ctx.Param("id"), 10, 32)
-	if err := c.hrService.ApproveResignation(uint(id)); err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	if err := c.hrService.WithContext(ctx.Request.Context()).ApproveResignation(uint(id)); err != nil {
+		respondError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -570,8 +575,8 @@ func (c *HRController) ApproveResignation(ctx *gin.Context) {
 // RejectResignation 审批驳回离职档案
 func (c *HRController) RejectResignation(ctx *gin.Context) {
 	id, _ := strconv.ParseUint(ctx.Param("id"), 10, 32)
-	if err := c.hrService.RejectResignation(uint(id)); err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	if err := c.hrService.WithContext(ctx.Request.Context()).RejectResignation(uint(id)); err != nil {
+		respondError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -585,9 +590,9 @@ func (c *HRController) GetContractList(ctx *gin.Context) {
 	page, _ := strconv.Atoi(ctx.DefaultQuery("page", "1"))
 	pageSize, _ := strconv.Atoi(ctx.DefaultQuery("page_size", "10"))
 
-	contracts, total, err := c.hrService.GetContractList(uint(employeeID), status, page, pageSize)
+	contracts, total, err := c.hrService.WithContext(ctx.Request.Context()).GetContractList(uint(employeeID), status, page, pageSize, middleware.CurrentDataScope(ctx))
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -600,9 +605,9 @@ func (c *HRController) GetContractList(ctx *gin.Context) {
 // GetContractByID 根据ID获取员工合同
 func (c *HRController) GetContractByID(ctx *gin.Context) {
 	id, _ := strconv.ParseUint(ctx.Param("id"), 10, 32)
-	contract, err := c.hrService.GetContractByID(uint(id))
+	contract, err := c.hrService.WithContext(ctx.Request.Context()).GetContractByID(uint(id), middleware.CurrentDataScope(ctx))
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -613,12 +618,12 @@ func (c *HRController) GetContractByID(ctx *gin.Context) {
 func (c *HRController) CreateContract(ctx *gin.Context) {
 	var contract model.Contract
 	if err := ctx.ShouldBindJSON(&contract); err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		respondError(ctx, http.StatusBadRequest, err)
 		return
 	}
 
-	if err := c.hrService.CreateContract(&contract); err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	if err := c.hrService.WithContext(ctx.Request.Context()).CreateContract(&contract); err != nil {
+		respondError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -630,13 +635,13 @@ func (c *HRController) UpdateContract(ctx *gin.Context) {
 	id, _ := strconv.ParseUint(ctx.Param("id"), 10, 32)
 	var contract model.Contract
 	if err := ctx.ShouldBindJSON(&contract); err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		respondError(ctx, http.StatusBadRequest, err)
 		return
 	}
 
 	contract.ID = uint(id)
-	if err := c.hrService.UpdateContract(&contract); err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	if err := c.hrService.WithContext(ctx.Request.Context()).UpdateContract(&contract); err != nil {
+		respondError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -646,8 +651,8 @@ func (c *HRController) UpdateContract(ctx *gin.Context) {
 // DeleteContract 删除员工合同
 func (c *HRController) DeleteContract(ctx *gin.Context) {
 	id, _ := strconv.ParseUint(ctx.Param("id"), 10, 32)
-	if err := c.hrService.DeleteContract(uint(id)); err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	if err := c.hrService.WithContext(ctx.Request.Context()).DeleteContract(uint(id)); err != nil {
+		respondError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -657,8 +662,8 @@ func (c *HRController) DeleteContract(ctx *gin.Context) {
 // TerminateContract 终止员工合同
 func (c *HRController) TerminateContract(ctx *gin.Context) {
 	id, _ := strconv.ParseUint(ctx.Param("id"), 10, 32)
-	if err := c.hrService.TerminateContract(uint(id)); err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	if err := c.hrService.WithContext(ctx.Request.Context()).TerminateContract(uint(id)); err != nil {
+		respondError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -672,9 +677,9 @@ func (c *HRController) GetProbationList(ctx *gin.Context) {
 	page, _ := strconv.Atoi(ctx.DefaultQuery("page", "1"))
 	pageSize, _ := strconv.Atoi(ctx.DefaultQuery("page_size", "10"))
 
-	probations, total, err := c.hrService.GetProbationList(uint(employeeID), status, page, pageSize)
+	probations, total, err := c.hrService.WithContext(ctx.Request.Context()).GetProbationList(uint(employeeID), status, page, pageSize, middleware.CurrentDataScope(ctx))
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -687,9 +692,9 @@ func (c *HRController) GetProbationList(ctx *gin.Context) {
 // GetProbationByID 根据ID获取转正
 func (c *HRController) GetProbationByID(ctx *gin.Context) {
 	id, _ := strconv.ParseUint(ctx.Param("id"), 10, 32)
-	probation, err := c.hrService.GetProbationByID(uint(id))
+	probation, err := c.hrService.WithContext(ctx.Request.Context()).GetProbationByID(uint(id), middleware.CurrentDataScope(ctx))
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -700,12 +705,12 @@ func (c *HRController) GetProbationByID(ctx *gin.Context) {
 func (c *HRController) CreateProbation(ctx *gin.Context) {
 	var probation model.Probation
 	if err := ctx.ShouldBindJSON(&probation); err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		respondError(ctx, http.StatusBadRequest, err)
 		return
 	}
 
-	if err := c.hrService.CreateProbation(&probation); err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	if err := c.hrService.WithContext(ctx.Request.Context()).CreateProbation(&probation); err != nil {
+		respondError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -717,13 +722,13 @@ func (c *HRController) UpdateProbation(ctx *gin.Context) {
 	id, _ := strconv.ParseUint(ctx.Param("id"), 10, 32)
 	var probation model.Probation
 	if err := ctx.ShouldBindJSON(&probation); err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		respondError(ctx, http.StatusBadRequest, err)
 		return
 	}
 
 	probation.ID = uint(id)
-	if err := c.hrService.UpdateProbation(&probation); err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	if err := c.hrService.WithContext(ctx.Request.Context()).UpdateProbation(&probation); err != nil {
+		respondError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -733,8 +738,8 @@ func (c *HRController) UpdateProbation(ctx *gin.Context) {
 // DeleteProbation 删除转正
 func (c *HRController) DeleteProbation(ctx *gin.Context) {
 	id, _ := strconv.ParseUint(ctx.Param("id"), 10, 32)
-	if err := c.hrService.DeleteProbation(uint(id)); err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	if err := c.hrService.WithContext(ctx.Request.Context()).DeleteProbation(uint(id)); err != nil {
+		respondError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -744,8 +749,8 @@ func (c *HRController) DeleteProbation(ctx *gin.Context) {
 // ApproveProbation 审批通过转正
 func (c *HRController) ApproveProbation(ctx *gin.Context) {
 	id, _ := strconv.ParseUint(ctx.Param("id"), 10, 32)
-	if err := c.hrService.ApproveProbation(uint(id)); err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	if err := c.hrService.WithContext(ctx.Request.Context()).ApproveProbation(uint(id)); err != nil {
+		respondError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -755,8 +760,8 @@ func (c *HRController) ApproveProbation(ctx *gin.Context) {
 // RejectProbation 审批驳回转正
 func (c *HRController) RejectProbation(ctx *gin.Context) {
 	id, _ := strconv.ParseUint(ctx.Param("id"), 10, 32)
-	if err := c.hrService.RejectProbation(uint(id)); err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	if err := c.hrService.WithContext(ctx.Request.Context()).RejectProbation(uint(id)); err != nil {
+		respondError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 
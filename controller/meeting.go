@@ -40,14 +40,14 @@ func (c *MeetingController) RegisterRoutes(r *gin.Engine) {
 	reservations := api.Group("/meeting/reservations")
 	{
 		reservations.GET("", middleware.RequirePermission(model.PermissionMeetingReserveList), c.GetMeetingReservationList)
-		reservations.POST("", middleware.RequirePermission(model.PermissionMeetingReserveCreate), c.CreateMeetingReservation)
-		reservations.PUT("/:id", middleware.RequirePermission(model.PermissionMeetingReserveCreate), c.UpdateMeetingReservation)
-		reservations.DELETE("/:id", middleware.RequirePermission(model.PermissionMeetingReserveCreate), c.DeleteMeetingReservation)
+		reservations.POST("", middleware.RequirePermission(model.PermissionMeetingReserveCreate), middleware.RequireEmployee(), c.CreateMeetingReservation)
+		reservations.PUT("/:id", middleware.RequirePermission(model.PermissionMeetingReserveCreate), middleware.RequireEmployee(), c.UpdateMeetingReservation)
+		reservations.DELETE("/:id", middleware.RequirePermission(model.PermissionMeetingReserveCreate), middleware.RequireEmployee(), c.DeleteMeetingReservation)
 		reservations.PUT("/:id/approve", middleware.RequirePermission(model.PermissionMeetingReserveApprove), c.ApproveMeetingReservation)
 		reservations.PUT("/:id/reject", middleware.RequirePermission(model.PermissionMeetingReserveApprove), c.RejectMeetingReservation)
-		reservations.PUT("/:id/cancel", middleware.RequirePermission(model.PermissionMeetingReserveCreate), c.CancelMeetingReservation)
-		reservations.PUT("/:id/check-in", middleware.RequirePermission(model.PermissionMeetingReserveCreate), c.CheckInMeeting)
-		reservations.PUT("/:id/check-out", middleware.RequirePermission(model.PermissionMeetingReserveCreate), c.CheckOutMeeting)
+		reservations.PUT("/:id/cancel", middleware.RequirePermission(model.PermissionMeetingReserveCreate), middleware.RequireEmployee(), c.CancelMeetingReservation)
+		reservations.PUT("/:id/check-in", middleware.RequirePermission(model.PermissionMeetingReserveCreate), middleware.RequireEmployee(), c.CheckInMeeting)
+		reservations.PUT("/:id/check-out", middleware.RequirePermission(model.PermissionMeetingReserveCreate), middleware.RequireEmployee(), c.CheckOutMeeting)
 	}
 
 	// 会议纪要管理
@@ -77,9 +77,9 @@ func (c *MeetingController) GetMeetingRoomList(ctx *gin.Context) {
 	page, _ := strconv.Atoi(ctx.DefaultQuery("page", "1"))
 	pageSize, _ := strconv.Atoi(ctx.DefaultQuery("page_size", "10"))
 
-	rooms, total, err := c.meetingService.GetMeetingRoomList(status, keyword, page, pageSize)
+	rooms, total, err := c.meetingService.WithContext(ctx.Request.Context()).GetMeetingRoomList(status, keyword, page, pageSize)
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -92,9 +92,9 @@ func (c *MeetingController) GetMeetingRoomList(ctx *gin.Context) {
 // GetMeetingRoomByID 根据ID获取会议室
 func (c *MeetingController) GetMeetingRoomByID(ctx *gin.Context) {
 	id, _ := strconv.ParseUint(ctx.Param("id"), 10, 32)
-	room, err := c.meetingService.GetMeetingRoomByID(uint(id))
+	room, err := c.meetingService.WithContext(ctx.Request.Context()).GetMeetingRoomByID(uint(id))
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -105,15 +105,12 @@ func (c *MeetingController) GetMeetingRoomByID(ctx *gin.Context) {
 func (c *MeetingController) CreateMeetingRoom(ctx *gin.Context) {
 	var room model.MeetingRoom
 	if err := ctx.ShouldBindJSON(&room); err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		respondError(ctx, http.StatusBadRequest, err)
 		return
 	}
 
-	// TODO: 从JWT中获取当前用户ID
-	room.CreatedBy = uint(1)
-
-	if err := c.meetingService.CreateMeetingRoom(&room); err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	if err := c.meetingService.WithContext(ctx.Request.Context()).CreateMeetingRoom(&room); err != nil {
+		respondError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -125,13 +122,13 @@ func (c *MeetingController) UpdateMeetingRoom(ctx *gin.Context) {
 	id, _ := strconv.ParseUint(ctx.Param("id"), 10, 32)
 	var room model.MeetingRoom
 	if err := ctx.ShouldBindJSON(&room); err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		respondError(ctx, http.StatusBadRequest, err)
 		return
 	}
 
 	room.ID = uint(id)
-	if err := c.meetingService.UpdateMeetingRoom(&room); err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	if err := c.meetingService.WithContext(ctx.Request.Context()).UpdateMeetingRoom(&room); err != nil {
+		respondError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -141,8 +138,8 @@ func (c *MeetingController) UpdateMeetingRoom(ctx *gin.Context) {
 // DeleteMeetingRoom 删除会议室
 func (c *MeetingController) DeleteMeetingRoom(ctx *gin.Context) {
 	id, _ := strconv.ParseUint(ctx.Param("id"), 10, 32)
-	if err := c.meetingService.DeleteMeetingRoom(uint(id)); err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	if err := c.meetingService.WithContext(ctx.Request.Context()).DeleteMeetingRoom(uint(id)); err != nil {
+		respondError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -157,9 +154,9 @@ func (c *MeetingController) GetMeetingReservationList(ctx *gin.Context) {
 	page, _ := strconv.Atoi(ctx.DefaultQuery("page", "1"))
 	pageSize, _ := strconv.Atoi(ctx.DefaultQuery("page_size", "10"))
 
-	reservations, total, err := c.meetingService.GetMeetingReservationList(uint(roomID), uint(userID), status, page, pageSize)
+	reservations, total, err := c.meetingService.WithContext(ctx.Request.Context()).GetMeetingReservationList(uint(roomID), uint(userID), status, page, pageSize)
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -172,9 +169,9 @@ func (c *MeetingController) GetMeetingReservationList(ctx *gin.Context) {
 // GetMeetingReservationByID 根据ID获取会议室预约
 func (c *MeetingController) GetMeetingReservationByID(ctx *gin.Context) {
 	id, _ := strconv.ParseUint(ctx.Param("id"), 10, 32)
-	reservation, err := c.meetingService.GetMeetingReservationByID(uint(id))
+	reservation, err := c.meetingService.WithContext(ctx.Request.Context()).GetMeetingReservationByID(uint(id))
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -185,12 +182,17 @@ func (c *MeetingController) GetMeetingReservationByID(ctx *gin.Context) {
 func (c *MeetingController) CreateMeetingReservation(ctx *gin.Context) {
 	var reservation model.MeetingReservation
 	if err := ctx.ShouldBindJSON(&reservation); err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		respondError(ctx, http.StatusBadRequest, err)
 		return
 	}
 
-	if err := c.meetingService.CreateMeetingReservation(&reservation); err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	// 预约人及预约部门为当前用户关联的员工及其部门
+	employee := middleware.CurrentEmployee(ctx)
+	reservation.UserID = employee.ID
+	reservation.DepartmentID = employee.DepartmentID
+
+	if err := c.meetingService.WithContext(ctx.Request.Context()).CreateMeetingReservation(&reservation); err != nil {
+		respondError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -202,13 +204,16 @@ func (c *MeetingController) UpdateMeetingReservation(ctx *gin.Context) {
 	id, _ := strconv.ParseUint(ctx.Param("id"), 10, 32)
 	var reservation model.MeetingReservation
 	if err := ctx.ShouldBindJSON(&reservation); err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		respondError(ctx, http.StatusBadRequest, err)
 		return
 	}
 
+	employee := middleware.CurrentEmployee(ctx)
 	reservation.ID = uint(id)
-	if err := c.meetingService.UpdateMeetingReservation(&reservation); err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	reservation.UserID = employee.ID
+	reservation.DepartmentID = employee.DepartmentID
+	if err := c.meetingService.WithContext(ctx.Request.Context()).UpdateMeetingReservation(&reservation); err != nil {
+		respondError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -218,8 +223,8 @@ func (c *MeetingController) UpdateMeetingReservation(ctx *gin.Context) {
 // DeleteMeetingReservation 删除会议室预约
 func (c *MeetingController) DeleteMeetingReservation(ctx *gin.Context) {
 	id, _ := strconv.ParseUint(ctx.Param("id"), 10, 32)
-	if err := c.meetingService.DeleteMeetingReservation(uint(id)); err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	if err := c.meetingService.WithContext(ctx.Request.Context()).DeleteMeetingReservation(uint(id), middleware.CurrentEmployeeID(ctx)); err != nil {
+		respondError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -229,11 +234,10 @@ func (c *MeetingController) DeleteMeetingReservation(ctx *gin.Context) {
 // ApproveMeetingReservation 审批通过会议室预约
 func (c *MeetingController) ApproveMeetingReservation(ctx *gin.Context) {
 	id, _ := strconv.ParseUint(ctx.Param("id"), 10, 32)
-	// TODO: 从JWT中获取当前用户ID
-	approverID := uint(1)
+	approverID := middleware.CurrentUserID(ctx)
 
-	if err := c.meetingService.ApproveMeetingReservation(uint(id), approverID); err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	if err := c.meetingService.WithContext(ctx.Request.Context()).ApproveMeetingReservation(uint(id), approverID); err != nil {
+		respondError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -243,11 +247,10 @@ func (c *MeetingController) ApproveMeetingReservation(ctx *gin.Context) {
 // RejectMeetingReservation 审批驳回会议室预约
 func (c *MeetingController) RejectMeetingReservation(ctx *gin.Context) {
 	id, _ := strconv.ParseUint(ctx.Param("id"), 10, 32)
-	// TODO: 从JWT中获取当前用户ID
-	approverID := uint(1)
+	approverID := middleware.CurrentUserID(ctx)
 
-	if err := c.meetingService.RejectMeetingReservation(uint(id), approverID); err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	if err := c.meetingService.WithContext(ctx.Request.Context()).RejectMeetingReservation(uint(id), approverID); err != nil {
+		respondError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -261,12 +264,12 @@ func (c *MeetingController) CancelMeetingReservation(ctx *gin.Context) {
 		Reason string `json:"reason"`
 	}
 	if err := ctx.ShouldBindJSON(&data); err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		respondError(ctx, http.StatusBadRequest, err)
 		return
 	}
 
-	if err := c.meetingService.CancelMeetingReservation(uint(id), data.Reason); err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	if err := c.meetingService.WithContext(ctx.Request.Context()).CancelMeetingReservation(uint(id), middleware.CurrentEmployeeID(ctx), data.Reason); err != nil {
+		respondError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -276,8 +279,8 @@ func (c *MeetingController) CancelMeetingReservation(ctx *gin.Context) {
 // CheckInMeeting 会议签到
 func (c *MeetingController) CheckInMeeting(ctx *gin.Context) {
 	id, _ := strconv.ParseUint(ctx.Param("id"), 10, 32)
-	if err := c.meetingService.CheckInMeeting(uint(id)); err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	if err := c.meetingService.WithContext(ctx.Request.Context()).CheckInMeeting(uint(id), middleware.CurrentEmployeeID(ctx)); err != nil {
+		respondError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -287,8 +290,8 @@ func (c *MeetingController) CheckInMeeting(ctx *gin.Context) {
 // CheckOutMeeting 会议签退
 func (c *MeetingController) CheckOutMeeting(ctx *gin.Context) {
 	id, _ := strconv.ParseUint(ctx.Param("id"), 10, 32)
-	if err := c.meetingService.CheckOutMeeting(uint(id)); err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	if err := c.meetingService.WithContext(ctx.Request.Context()).CheckOutMeeting(uint(id), middleware.CurrentEmployeeID(ctx)); err != nil {
+		respondError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -301,9 +304,9 @@ func (c *MeetingController) GetMeetingMinutesList(ctx *gin.Context) {
 	page, _ := strconv.Atoi(ctx.DefaultQuery("page", "1"))
 	pageSize, _ := strconv.Atoi(ctx.DefaultQuery("page_size", "10"))
 
-	minutes, total, err := c.meetingService.GetMeetingMinutesList(uint(reservationID), page, pageSize)
+	minutes, total, err := c.meetingService.WithContext(ctx.Request.Context()).GetMeetingMinutesList(uint(reservationID), page, pageSize)
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -316,9 +319,9 @@ func (c *MeetingController) GetMeetingMinutesList(ctx *gin.Context) {
 // GetMeetingMinutesByID 根据ID获取会议纪要
 func (c *MeetingController) GetMeetingMinutesByID(ctx *gin.Context) {
 	id, _ := strconv.ParseUint(ctx.Param("id"), 10, 32)
-	minutes, err := c.meetingService.GetMeetingMinutesByID(uint(id))
+	minutes, err := c.meetingService.WithContext(ctx.Request.Context()).GetMeetingMinutesByID(uint(id))
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -329,15 +332,12 @@ func (c *MeetingController) GetMeetingMinutesByID(ctx *gin.Context) {
 func (c *MeetingController) CreateMeetingMinutes(ctx *gin.Context) {
 	var minutes model.MeetingMinutes
 	if err := ctx.ShouldBindJSON(&minutes); err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		respondError(ctx, http.StatusBadRequest, err)
 		return
 	}
 
-	// TODO: 从JWT中获取当前用户ID
-	minutes.CreatedBy = uint(1)
-
-	if err := c.meetingService.CreateMeetingMinutes(&minutes); err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	if err := c.meetingService.WithContext(ctx.Request.Context()).CreateMeetingMinutes(&minutes); err != nil {
+		respondError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -349,13 +349,13 @@ func (c *MeetingController) UpdateMeetingMinutes(ctx *gin.Context) {
 	id, _ := strconv.ParseUint(ctx.Param("id"), 10, 32)
 	var minutes model.MeetingMinutes
 	if err := ctx.ShouldBindJSON(&minutes); err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		respondError(ctx, http.StatusBadRequest, err)
 		return
 	}
 
 	minutes.ID = uint(id)
-	if err := c.meetingService.UpdateMeetingMinutes(&minutes); err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	if err := c.meetingService.WithContext(ctx.Request.Context()).UpdateMeetingMinutes(&minutes); err != nil {
+		respondError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -365,8 +365,8 @@ func (c *MeetingController) UpdateMeetingMinutes(ctx *gin.Context) {
 // DeleteMeetingMinutes 删除会议纪要
 func (c *MeetingController) DeleteMeetingMinutes(ctx *gin.Context) {
 	id, _ := strconv.ParseUint(ctx.Param("id"), 10, 32)
-	if err := c.meetingService.DeleteMeetingMinutes(uint(id)); err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	if err := c.meetingService.WithContext(ctx.Request.Context()).DeleteMeetingMinutes(uint(id)); err != nil {
+		respondError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -380,9 +380,9 @@ func (c *MeetingController) GetMeetingRoomMaintenanceList(ctx *gin.Context) {
 	page, _ := strconv.Atoi(ctx.DefaultQuery("page", "1"))
 	pageSize, _ := strconv.Atoi(ctx.DefaultQuery("page_size", "10"))
 
-	maintenances, total, err := c.meetingService.GetMeetingRoomMaintenanceList(uint(roomID), status, page, pageSize)
+	maintenances, total, err := c.meetingService.WithContext(ctx.Request.Context()).GetMeetingRoomMaintenanceList(uint(roomID), status, page, pageSize)
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -395,9 +395,9 @@ func (c *MeetingController) GetMeetingRoomMaintenanceList(ctx *gin.Context) {
 // GetMeetingRoomMaintenanceByID 根据ID获取会议室维护记录
 func (c *MeetingController) GetMeetingRoomMaintenanceByID(ctx *gin.Context) {
 	id, _ := strconv.ParseUint(ctx.Param("id"), 10, 32)
-	maintenance, err := c.meetingService.GetMeetingRoomMaintenanceByID(uint(id))
+	maintenance, err := c.meetingService.WithContext(ctx.Request.Context()).GetMeetingRoomMaintenanceByID(uint(id))
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -408,15 +408,12 @@ func (c *MeetingController) GetMeetingRoomMaintenanceByID(ctx *gin.Context) {
 func (c *MeetingController) CreateMeetingRoomMaintenance(ctx *gin.Context) {
 	var maintenance model.MeetingRoomMaintenance
 	if err := ctx.ShouldBindJSON(&maintenance); err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		respondError(ctx, http.StatusBadRequest, err)
 		return
 	}
 
-	// TODO: 从JWT中获取当前用户ID
-	maintenance.CreatedBy = uint(1)
-
-	if err := c.meetingService.CreateMeetingRoomMaintenance(&maintenance); err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	if err := c.meetingService.WithContext(ctx.Request.Context()).CreateMeetingRoomMaintenance(&maintenance); err != nil {
+		respondError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -428,13 +425,13 @@ func (c *MeetingController) UpdateMeetingRoomMaintenance(ctx *gin.Context) {
 	id, _ := strconv.ParseUint(ctx.Param("id"), 10, 32)
 	var maintenance model.MeetingRoomMaintenance
 	if err := ctx.ShouldBindJSON(&maintenance); err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		respondError(ctx, http.StatusBadRequest, err)
 		return
 	}
 
 	maintenance.ID = uint(id)
-	if err := c.meetingService.UpdateMeetingRoomMaintenance(&maintenance); err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	if err := c.meetingService.WithContext(ctx.Request.Context()).UpdateMeetingRoomMaintenance(&maintenance); err != nil {
+		respondError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -444,8 +441,8 @@ func (c *MeetingController) UpdateMeetingRoomMaintenance(ctx *gin.Context) {
 // DeleteMeetingRoomMaintenance 删除会议室维护记录
 func (c *MeetingController) DeleteMeetingRoomMaintenance(ctx *gin.Context) {
 	id, _ := strconv.ParseUint(ctx.Param("id"), 10, 32)
-	if err := c.meetingService.DeleteMeetingRoomMaintenance(uint(id)); err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	if err := c.meetingService.WithContext(ctx.Request.Context()).DeleteMeetingRoomMaintenance(uint(id)); err != nil {
+		respondError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -455,8 +452,8 @@ func (c *MeetingController) DeleteMeetingRoomMaintenance(ctx *gin.Context) {
 // CompleteMeetingRoomMaintenance 完成会议室维护
 func (c *MeetingController) CompleteMeetingRoomMaintenance(ctx *gin.Context) {
 	id, _ := strconv.ParseUint(ctx.Param("id"), 10, 32)
-	if err := c.meetingService.CompleteMeetingRoomMaintenance(uint(id)); err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	if err := c.meetingService.WithContext(ctx.Request.Context()).CompleteMeetingRoomMaintenance(uint(id)); err != nil {
+		respondError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -4,6 +4,7 @@ import (
 	"net/http"
 	"strconv"
 
+	"github.com/lemonoa/LemonOA-Go/middleware"
 	"github.com/lemonoa/LemonOA-Go/model"
 	"github.com/lemonoa/LemonOA-Go/service"
 
@@ -23,6 +24,7 @@ func NewNoticeController(noticeService *service.NoticeService) *NoticeController
 // RegisterRoutes 注册路由
 func (c *NoticeController) RegisterRoutes(r *gin.Engine) {
 	api := r.Group("/api/notices")
+	api.Use(middleware.JWT())
 	{
 		// 公告管理
 		api.GET("", c.GetNoticeList)
@@ -48,9 +50,9 @@ func (c *NoticeController) GetNoticeList(ctx *gin.Context) {
 	page, _ := strconv.Atoi(ctx.DefaultQuery("page", "1"))
 	pageSize, _ := strconv.Atoi(ctx.DefaultQuery("page_size", "10"))
 
-	notices, total, err := c.noticeService.GetNoticeList(uint(typeID), status, keyword, page, pageSize)
+	notices, total, err := c.noticeService.WithContext(ctx.Request.Context()).GetNoticeList(uint(typeID), status, keyword, page, pageSize)
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -63,9 +65,9 @@ func (c *NoticeController) GetNoticeList(ctx *gin.Context) {
 // GetNoticeByID 根据ID获取公告
 func (c *NoticeController) GetNoticeByID(ctx *gin.Context) {
 	id, _ := strconv.ParseUint(ctx.Param("id"), 10, 32)
-	notice, err := c.noticeService.GetNoticeByID(uint(id))
+	notice, err := c.noticeService.WithContext(ctx.Request.Context()).GetNoticeByID(uint(id))
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -76,15 +78,12 @@ func (c *NoticeController) GetNoticeByID(ctx *gin.Context) {
 func (c *NoticeController) CreateNotice(ctx *gin.Context) {
 	var notice model.Notice
 	if err := ctx.ShouldBindJSON(&notice); err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		respondError(ctx, http.StatusBadRequest, err)
 		return
 	}
 
-	// TODO: 从JWT中获取当前用户ID
-	notice.CreatedBy = uint(1)
-
-	if err := c.noticeService.CreateNotice(&notice); err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	if err := c.noticeService.WithContext(ctx.Request.Context()).CreateNotice(&notice); err != nil {
+		respondError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -96,13 +95,13 @@ func (c *NoticeController) UpdateNotice(ctx *gin.Context) {
 	id, _ := strconv.ParseUint(ctx.Param("id"), 10, 32)
 	var notice model.Notice
 	if err := ctx.ShouldBindJSON(&notice); err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		respondError(ctx, http.StatusBadRequest, err)
 		return
 	}
 
 	notice.ID = uint(id)
-	if err := c.noticeService.UpdateNotice(&notice); err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	if err := c.noticeService.WithContext(ctx.Request.Context()).UpdateNotice(&notice); err != nil {
+		respondError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -112,8 +111,8 @@ func (c *NoticeController) UpdateNotice(ctx *gin.Context) {
 // DeleteNotice 删除公告
 func (c *NoticeController) DeleteNotice(ctx *gin.Context) {
 	id, _ := strconv.ParseUint(ctx.Param("id"), 10, 32)
-	if err := c.noticeService.DeleteNotice(uint(id)); err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	if err := c.noticeService.WithContext(ctx.Request.Context()).DeleteNotice(uint(id)); err != nil {
+		respondError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -123,8 +122,8 @@ func (c *NoticeController) DeleteNotice(ctx *gin.Context) {
 // PublishNotice 发布公告
 func (c *NoticeController) PublishNotice(ctx *gin.Context) {
 	id, _ := strconv.ParseUint(ctx.Param("id"), 10, 32)
-	if err := c.noticeService.PublishNotice(uint(id)); err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	if err := c.noticeService.WithContext(ctx.Request.Context()).PublishNotice(uint(id)); err != nil {
+		respondError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -134,8 +133,8 @@ func (c *NoticeController) PublishNotice(ctx *gin.Context) {
 // RecallNotice 撤回公告
 func (c *NoticeController) RecallNotice(ctx *gin.Context) {
 	id, _ := strconv.ParseUint(ctx.Param("id"), 10, 32)
-	if err := c.noticeService.RecallNotice(uint(id)); err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	if err := c.noticeService.WithContext(ctx.Request.Context()).RecallNotice(uint(id)); err != nil {
+		respondError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -148,9 +147,9 @@ func (c *NoticeController) GetNoticeReadList(ctx *gin.Context) {
 	page, _ := strconv.Atoi(ctx.DefaultQuery("page", "1"))
 	pageSize, _ := strconv.Atoi(ctx.DefaultQuery("page_size", "10"))
 
-	reads, total, err := c.noticeService.GetNoticeReadList(uint(id), page, pageSize)
+	reads, total, err := c.noticeService.WithContext(ctx.Request.Context()).GetNoticeReadList(uint(id), page, pageSize)
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -163,11 +162,10 @@ func (c *NoticeController) GetNoticeReadList(ctx *gin.Context) {
 // ReadNotice 阅读公告
 func (c *NoticeController) ReadNotice(ctx *gin.Context) {
 	id, _ := strconv.ParseUint(ctx.Param("id"), 10, 32)
-	// TODO: 从JWT中获取当前用户ID
-	userID := uint(1)
+	userID := middleware.CurrentUserID(ctx)
 
-	if err := c.noticeService.ReadNotice(uint(id), userID); err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	if err := c.noticeService.WithContext(ctx.Request.Context()).ReadNotice(uint(id), userID); err != nil {
+		respondError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -176,12 +174,11 @@ func (c *NoticeController) ReadNotice(ctx *gin.Context) {
 
 // GetUnreadNoticeCount 获取未读公告数量
 func (c *NoticeController) GetUnreadNoticeCount(ctx *gin.Context) {
-	// TODO: 从JWT中获取当前用户ID
-	userID := uint(1)
+	userID := middleware.CurrentUserID(ctx)
 
-	count, err := c.noticeService.GetUnreadNoticeCount(userID)
+	count, err := c.noticeService.WithContext(ctx.Request.Context()).GetUnreadNoticeCount(userID)
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -4,6 +4,7 @@ import (
 	"net/http"
 	"strconv"
 
+	"github.com/lemonoa/LemonOA-Go/middleware"
 	"github.com/lemonoa/LemonOA-Go/model"
 	"github.com/lemonoa/LemonOA-Go/service"
 
@@ -23,6 +24,7 @@ func NewNotificationController(notificationService *service.NotificationService)
 // RegisterRoutes 注册路由
 func (c *NotificationController) RegisterRoutes(r *gin.Engine) {
 	api := r.Group("/api/notifications")
+	api.Use(middleware.JWT())
 	{
 		api.GET("", c.GetNotificationList)
 		api.GET("/unread-count", c.GetUnreadCount)
@@ -35,14 +37,13 @@ func (c *NotificationController) RegisterRoutes(r *gin.Engine) {
 
 // GetNotificationList 获取消息列表
 func (c *NotificationController) GetNotificationList(ctx *gin.Context) {
-	// TODO: 从JWT中获取userID
-	userID := uint(1)
+	userID := middleware.CurrentUserID(ctx)
 	page, _ := strconv.Atoi(ctx.DefaultQuery("page", "1"))
 	pageSize, _ := strconv.Atoi(ctx.DefaultQuery("page_size", "10"))
 
-	notifications, total, err := c.notificationService.GetNotificationList(userID, page, pageSize)
+	notifications, total, err := c.notificationService.WithContext(ctx.Request.Context()).GetNotificationList(userID, page, pageSize)
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -54,12 +55,11 @@ func (c *NotificationController) GetNotificationList(ctx *gin.Context) {
 
 // GetUnreadCount 获取未读消息数量
 func (c *NotificationController) GetUnreadCount(ctx *gin.Context) {
-	// TODO: 从JWT中获取userID
-	userID := uint(1)
+	userID := middleware.CurrentUserID(ctx)
 
-	count, err := c.notificationService.GetUnreadCount(userID)
+	count, err := c.notificationService.WithContext(ctx.Request.Context()).GetUnreadCount(userID)
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -70,12 +70,12 @@ func (c *NotificationController) GetUnreadCount(ctx *gin.Context) {
 func (c *NotificationController) CreateNotification(ctx *gin.Context) {
 	var notification model.Notification
 	if err := ctx.ShouldBindJSON(&notification); err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		respondError(ctx, http.StatusBadRequest, err)
 		return
 	}
 
-	if err := c.notificationService.CreateNotification(&notification); err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	if err := c.notificationService.WithContext(ctx.Request.Context()).CreateNotification(&notification); err != nil {
+		respondError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -85,11 +85,10 @@ func (c *NotificationController) CreateNotification(ctx *gin.Context) {
 // MarkAsRead 标记消息为已读
 func (c *NotificationController) MarkAsRead(ctx *gin.Context) {
 	id, _ := strconv.ParseUint(ctx.Param("id"), 10, 32)
-	// TODO: 从JWT中获取userID
-	userID := uint(1)
+	userID := middleware.CurrentUserID(ctx)
 
-	if err := c.notificationService.MarkAsRead(uint(id), userID); err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	if err := c.notificationService.WithContext(ctx.Request.Context()).MarkAsRead(uint(id), userID); err != nil {
+		respondError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -98,11 +97,10 @@ func (c *NotificationController) MarkAsRead(ctx *gin.Context) {
 
 // MarkAllAsRead 标记所有消息为已读
 func (c *NotificationController) MarkAllAsRead(ctx *gin.Context) {
-	// TODO: 从JWT中获取userID
-	userID := uint(1)
+	userID := middleware.CurrentUserID(ctx)
 
-	if err := c.notificationService.MarkAllAsRead(userID); err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	if err := c.notificationService.WithContext(ctx.Request.Context()).MarkAllAsRead(userID); err != nil {
+		respondError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -112,11 +110,10 @@ func (c *NotificationController) MarkAllAsRead(ctx *gin.Context) {
 // DeleteNotification 删除消息
 func (c *NotificationController) DeleteNotification(ctx *gin.Context) {
 	id, _ := strconv.ParseUint(ctx.Param("id"), 10, 32)
-	// TODO: 从JWT中获取userID
-	userID := uint(1)
+	userID := middleware.CurrentUserID(ctx)
 
-	if err := c.notificationService.DeleteNotification(uint(id), userID); err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	if err := c.notificationService.WithContext(ctx.Request.Context()).DeleteNotification(uint(id), userID); err != nil {
+		respondError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -0,0 +1,13 @@
+package controller
+
+import (
+	"github.com/lemonoa/LemonOA-Go/middleware"
+
+	"github.com/gin-gonic/gin"
+)
+
+// 返回错误响应，错误同时记录到请求上下文中由访问日志输出；响应中带有请求ID，便于按日志排查
+func respondError(ctx *gin.Context, status int, err error) {
+	_ = ctx.Error(err)
+	ctx.JSON(status, gin.H{"error": err.Error(), "request_id": middleware.CurrentRequestID(ctx)})
+}
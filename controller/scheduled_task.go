@@ -0,0 +1,140 @@
+package controller
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/lemonoa/LemonOA-Go/middleware"
+	"github.com/lemonoa/LemonOA-Go/model"
+	"github.com/lemonoa/LemonOA-Go/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// GetScheduledJobList 获取可供定时任务运行的任务
+func (c *SystemController) GetScheduledJobList(ctx *gin.Context) {
+	ctx.JSON(http.StatusOK, service.RegisteredJobs())
+}
+
+// GetScheduledTaskList 获取定时任务列表
+func (c *SystemController) GetScheduledTaskList(ctx *gin.Context) {
+	tasks, err := c.systemService.WithContext(ctx.Request.Context()).GetScheduledTaskList()
+	if err != nil {
+		respondError(ctx, http.StatusInternalServerError, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, tasks)
+}
+
+// CreateScheduledTask 创建定时任务
+func (c *SystemController) CreateScheduledTask(ctx *gin.Context) {
+	var task model.ScheduledTask
+	if err := ctx.ShouldBindJSON(&task); err != nil {
+		respondError(ctx, http.StatusBadRequest, err)
+		return
+	}
+
+	if err := c.systemService.WithContext(ctx.Request.Context()).CreateScheduledTask(&task); err != nil {
+		respondTaskError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, task)
+}
+
+// UpdateScheduledTask 更新定时任务
+func (c *SystemController) UpdateScheduledTask(ctx *gin.Context) {
+	id, _ := strconv.ParseUint(ctx.Param("id"), 10, 32)
+	var task model.ScheduledTask
+	if err := ctx.ShouldBindJSON(&task); err != nil {
+		respondError(ctx, http.StatusBadRequest, err)
+		return
+	}
+
+	task.ID = uint(id)
+	if err := c.systemService.WithContext(ctx.Request.Context()).UpdateScheduledTask(&task); err != nil {
+		respondTaskError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, task)
+}
+
+// DeleteScheduledTask 删除定时任务
+func (c *SystemController) DeleteScheduledTask(ctx *gin.Context) {
+	id, _ := strconv.ParseUint(ctx.Param("id"), 10, 32)
+	if err := c.systemService.WithContext(ctx.Request.Context()).DeleteScheduledTask(uint(id)); err != nil {
+		respondError(ctx, http.StatusInternalServerError, err)
+		return
+	}
+
+	ctx.Status(http.StatusNoContent)
+}
+
+// UpdateTaskStatus 更新任务状态
+func (c *SystemController) UpdateTaskStatus(ctx *gin.Context) {
+	id, _ := strconv.ParseUint(ctx.Param("id"), 10, 32)
+	var req struct {
+		Status int `json:"status" binding:"required"`
+	}
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		respondError(ctx, http.StatusBadRequest, err)
+		return
+	}
+
+	if err := c.systemService.WithContext(ctx.Request.Context()).UpdateTaskStatus(uint(id), req.Status); err != nil {
+		respondTaskError(ctx, err)
+		return
+	}
+
+	ctx.Status(http.StatusNoContent)
+}
+
+// RunScheduledTask 立即运行定时任务，任务在后台执行
+func (c *SystemController) RunScheduledTask(ctx *gin.Context) {
+	id, _ := strconv.ParseUint(ctx.Param("id"), 10, 32)
+	run, err := c.systemService.WithContext(ctx.Request.Context()).RunScheduledTask(uint(id), middleware.CurrentUserID(ctx))
+	if err != nil {
+		respondTaskError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusAccepted, run)
+}
+
+// GetScheduledTaskRunList 获取定时任务运行记录
+func (c *SystemController) GetScheduledTaskRunList(ctx *gin.Context) {
+	id, _ := strconv.ParseUint(ctx.Param("id"), 10, 32)
+	status, _ := strconv.Atoi(ctx.Query("status"))
+	page, _ := strconv.Atoi(ctx.DefaultQuery("page", "1"))
+	pageSize, _ := strconv.Atoi(ctx.DefaultQuery("page_size", "10"))
+
+	runs, total, err := c.systemService.WithContext(ctx.Request.Context()).GetScheduledTaskRunList(uint(id), status, page, pageSize)
+	if err != nil {
+		respondError(ctx, http.StatusInternalServerError, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"data":  runs,
+		"total": total,
+	})
+}
+
+// 按错误类型返回定时任务接口的状态码
+func respondTaskError(ctx *gin.Context, err error) {
+	status := http.StatusInternalServerError
+	switch {
+	case errors.Is(err, service.ErrTaskCronInvalid), errors.Is(err, service.ErrTaskJobNotFound), errors.Is(err, service.ErrTaskStatusInvalid):
+		status = http.StatusBadRequest
+	case errors.Is(err, service.ErrTaskNotFound):
+		status = http.StatusNotFound
+	case errors.Is(err, service.ErrTaskRunning):
+		status = http.StatusConflict
+	case errors.Is(err, service.ErrSchedulerStopped):
+		status = http.StatusServiceUnavailable
+	}
+	respondError(ctx, status, err)
+}
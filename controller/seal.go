@@ -7,6 +7,8 @@ import (
 	"github.com/lemonoa/LemonOA-Go/model"
 	"github.com/lemonoa/LemonOA-Go/service"
 
+	"github.com/lemonoa/LemonOA-Go/middleware"
+
 	"github.com/gin-gonic/gin"
 )
 
@@ -23,6 +25,7 @@ func NewSealController(sealService *service.SealService) *SealController {
 // RegisterRoutes 注册路由
 func (c *SealController) RegisterRoutes(r *gin.Engine) {
 	api := r.Group("/api/seals")
+	api.Use(middleware.JWT(), middleware.DataScope())
 	{
 		// 印章管理
 		api.GET("", c.GetSealList)
@@ -59,9 +62,9 @@ func (c *SealController) GetSealList(ctx *gin.Context) {
 	page, _ := strconv.Atoi(ctx.DefaultQuery("page", "1"))
 	pageSize, _ := strconv.Atoi(ctx.DefaultQuery("page_size", "10"))
 
-	seals, total, err := c.sealService.GetSealList(uint(typeID), status, keyword, page, pageSize)
+	seals, total, err := c.sealService.WithContext(ctx.Request.Context()).GetSealList(uint(typeID), status, keyword, page, pageSize, middleware.CurrentDataScope(ctx))
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -74,9 +77,9 @@ func (c *SealController) GetSealList(ctx *gin.Context) {
 // GetSealByID 根据ID获取印章
 func (c *SealController) GetSealByID(ctx *gin.Context) {
 	id, _ := strconv.ParseUint(ctx.Param("id"), 10, 32)
-	seal, err := c.sealService.GetSealByID(uint(id))
+	seal, err := c.sealService.WithContext(ctx.Request.Context()).GetSealByID(uint(id), middleware.CurrentDataScope(ctx))
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -87,15 +90,12 @@ func (c *SealController) GetSealByID(ctx *gin.Context) {
 func (c *SealController) CreateSeal(ctx *gin.Context) {
 	var seal model.Seal
 	if err := ctx.ShouldBindJSON(&seal); err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		respondError(ctx, http.StatusBadRequest, err)
 		return
 	}
 
-	// TODO: 从JWT中获取当前用户ID
-	seal.CreatedBy = uint(1)
-
-	if err := c.sealService.CreateSeal(&seal); err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	if err := c.sealService.WithContext(ctx.Request.Context()).CreateSeal(&seal); err != nil {
+		respondError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -107,13 +107,13 @@ func (c *SealController) UpdateSeal(ctx *gin.Context) {
 	id, _ := strconv.ParseUint(ctx.Param("id"), 10, 32)
 	var seal model.Seal
 	if err := ctx.ShouldBindJSON(&seal); err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		respondError(ctx, http.StatusBadRequest, err)
 		return
 	}
 
 	seal.ID = uint(id)
-	if err := c.sealService.UpdateSeal(&seal); err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	if err := c.sealService.WithContext(ctx.Request.Context()).UpdateSeal(&seal); err != nil {
+		respondError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -123,8 +123,8 @@ func (c *SealController) UpdateSeal(ctx *gin.Context) {
 // DeleteSeal 删除印章
 func (c *SealController) DeleteSeal(ctx *gin.Context) {
 	id, _ := strconv.ParseUint(ctx.Param("id"), 10, 32)
-	if err := c.sealService.DeleteSeal(uint(id)); err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	if err := c.sealService.WithContext(ctx.Request.Context()).DeleteSeal(uint(id)); err != nil {
+		respondError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -139,9 +139,9 @@ func (c *SealController) GetSealApplicationList(ctx *gin.Context) {
 	page, _ := strconv.Atoi(ctx.DefaultQuery("page", "1"))
 	pageSize, _ := strconv.Atoi(ctx.DefaultQuery("page_size", "10"))
 
-	applications, total, err := c.sealService.GetSealApplicationList(uint(sealID), uint(userID), status, page, pageSize)
+	applications, total, err := c.sealService.WithContext(ctx.Request.Context()).GetSealApplicationList(uint(sealID), uint(userID), status, page, pageSize, middleware.CurrentDataScope(ctx))
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -154,9 +154,9 @@ func (c *SealController) GetSealApplicationList(ctx *gin.Context) {
 // GetSealApplicationByID 根据ID获取用印申请
 func (c *SealController) GetSealApplicationByID(ctx *gin.Context) {
 	id, _ := strconv.ParseUint(ctx.Param("id"), 10, 32)
-	application, err := c.sealService.GetSealApplicationByID(uint(id))
+	application, err := c.sealService.WithContext(ctx.Request.Context()).GetSealApplicationByID(uint(id), middleware.CurrentDataScope(ctx))
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -167,12 +167,12 @@ func (c *SealController) GetSealApplicationByID(ctx *gin.Context) {
 func (c *SealController) CreateSealApplication(ctx *gin.Context) {
 	var application model.SealApplication
 	if err := ctx.ShouldBindJSON(&application); err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		respondError(ctx, http.StatusBadRequest, err)
 		return
 	}
 
-	if err := c.sealService.CreateSealApplication(&application); err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	if err := c.sealService.WithContext(ctx.Request.Context()).CreateSealApplication(&application); err != nil {
+		respondError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -184,13 +184,13 @@ func (c *SealController) UpdateSealApplication(ctx *gin.Context) {
 	id, _ := strconv.ParseUint(ctx.Param("id"), 10, 32)
 	var application model.SealApplication
 	if err := ctx.ShouldBindJSON(&application); err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		respondError(ctx, http.StatusBadRequest, err)
 		return
 	}
 
 	application.ID = uint(id)
-	if err := c.sealService.UpdateSealApplication(&application); err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	if err := c.sealService.WithContext(ctx.Request.Context()).UpdateSealApplication(&application); err != nil {
+		respondError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -200,8 +200,8 @@ func (c *SealController) UpdateSealApplication(ctx *gin.Context) {
 // DeleteSealApplication 删除用印申请
 func (c *SealController) DeleteSealApplication(ctx *gin.Context) {
 	id, _ := strconv.ParseUint(ctx.Param("id"), 10, 32)
-	if err := c.sealService.DeleteSealApplication(uint(id)); err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	if err := c.sealService.WithContext(ctx.Request.Context()).DeleteSealApplication(uint(id)); err != nil {
+		respondError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -211,8 +211,8 @@ func (c *SealController) DeleteSealApplication(ctx *gin.Context) {
 // ApproveSealApplication 审批通过用印申请
 func (c *SealController) ApproveSealApplication(ctx *gin.Context) {
 	id, _ := strconv.ParseUint(ctx.Param("id"), 10, 32)
-	if err := c.sealService.ApproveSealApplication(uint(id)); err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	if err := c.sealService.WithContext(ctx.Request.Context()).ApproveSealApplication(uint(id)); err != nil {
+		respondError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -222,8 +222,8 @@ func (c *SealController) ApproveSealApplication(ctx *gin.Context) {
 // RejectSealApplication 审批驳回用印申请
 func (c *SealController) RejectSealApplication(ctx *gin.Context) {
 	id, _ := strconv.ParseUint(ctx.Param("id"), 10, 32)
-	if err := c.sealService.RejectSealApplication(uint(id)); err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	if err := c.sealService.WithContext(ctx.Request.Context()).RejectSealApplication(uint(id)); err != nil {
+		respondError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -233,8 +233,8 @@ func (c *SealController) RejectSealApplication(ctx *gin.Context) {
 // CancelSealApplication 取消用印申请
 func (c *SealController) CancelSealApplication(ctx *gin.Context) {
 	id, _ := strconv.ParseUint(ctx.Param("id"), 10, 32)
-	if err := c.sealService.CancelSealApplication(uint(id)); err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	if err := c.sealService.WithContext(ctx.Request.Context()).CancelSealApplication(uint(id)); err != nil {
+		respondError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -248,9 +248,9 @@ func (c *SealController) GetSealRecordList(ctx *gin.Context) {
 	page, _ := strconv.Atoi(ctx.DefaultQuery("page", "1"))
 	pageSize, _ := strconv.Atoi(ctx.DefaultQuery("page_size", "10"))
 
-	records, total, err := c.sealService.GetSealRecordList(uint(applicationID), status, page, pageSize)
+	records, total, err := c.sealService.WithContext(ctx.Request.Context()).GetSealRecordList(uint(applicationID), status, page, pageSize, middleware.CurrentDataScope(ctx))
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -263,9 +263,9 @@ func (c *SealController) GetSealRecordList(ctx *gin.Context) {
 // GetSealRecordByID 根据ID获取用印记录
 func (c *SealController) GetSealRecordByID(ctx *gin.Context) {
 	id, _ := strconv.ParseUint(ctx.Param("id"), 10, 32)
-	record, err := c.sealService.GetSealRecordByID(uint(id))
+	record, err := c.sealService.WithContext(ctx.Request.Context()).GetSealRecordByID(uint(id), middleware.CurrentDataScope(ctx))
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -276,15 +276,12 @@ func (c *SealController) GetSealRecordByID(ctx *gin.Context) {
 func (c *SealController) CreateSealRecord(ctx *gin.Context) {
 	var record model.SealRecord
 	if err := ctx.ShouldBindJSON(&record); err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		respondError(ctx, http.StatusBadRequest, err)
 		return
 	}
 
-	// TODO: 从JWT中获取当前用户ID
-	record.CreatedBy = uint(1)
-
-	if err := c.sealService.CreateSealRecord(&record); err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	if err := c.sealService.WithContext(ctx.Request.Context()).CreateSealRecord(&record); err != nil {
+		respondError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -296,13 +293,13 @@ func (c *SealController) UpdateSealRecord(ctx *gin.Context) {
 	id, _ := strconv.ParseUint(ctx.Param("id"), 10, 32)
 	var record model.SealRecord
 	if err := ctx.ShouldBindJSON(&record); err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		respondError(ctx, http.StatusBadRequest, err)
 		return
 	}
 
 	record.ID = uint(id)
-	if err := c.sealService.UpdateSealRecord(&record); err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	if err := c.sealService.WithContext(ctx.Request.Context()).UpdateSealRecord(&record); err != nil {
+		respondError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -312,8 +309,8 @@ func (c *SealController) UpdateSealRecord(ctx *gin.Context) {
 // DeleteSealRecord 删除用印记录
 func (c *SealController) DeleteSealRecord(ctx *gin.Context) {
 	id, _ := strconv.ParseUint(ctx.Param("id"), 10, 32)
-	if err := c.sealService.DeleteSealRecord(uint(id)); err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	if err := c.sealService.WithContext(ctx.Request.Context()).DeleteSealRecord(uint(id)); err != nil {
+		respondError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -323,8 +320,8 @@ func (c *SealController) DeleteSealRecord(ctx *gin.Context) {
 // ReturnSeal 归还印章
 func (c *SealController) ReturnSeal(ctx *gin.Context) {
 	id, _ := strconv.ParseUint(ctx.Param("id"), 10, 32)
-	if err := c.sealService.ReturnSeal(uint(id)); err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	if err := c.sealService.WithContext(ctx.Request.Context()).ReturnSeal(uint(id)); err != nil {
+		respondError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -1,9 +1,13 @@
 package controller
 
 import (
+	"fmt"
 	"net/http"
 	"strconv"
+	"time"
 
+	"github.com/lemonoa/LemonOA-Go/logging"
+	"github.com/lemonoa/LemonOA-Go/middleware"
 	"github.com/lemonoa/LemonOA-Go/model"
 	"github.com/lemonoa/LemonOA-Go/service"
 
@@ -23,64 +27,82 @@ func NewSystemController(systemService *service.SystemService) *SystemController
 // RegisterRoutes 注册路由
 func (c *SystemController) RegisterRoutes(r *gin.Engine) {
 	api := r.Group("/api/system")
+	api.Use(middleware.JWT())
 	{
 		// 系统配置
 		api.GET("/configs", c.GetSystemConfigList)
 		api.GET("/configs/:key", c.GetSystemConfigByKey)
-		api.PUT("/configs/:id", c.UpdateSystemConfig)
+		api.PUT("/configs/:id", middleware.DenyImpersonation(), middleware.RequirePermission(model.PermissionConfigUpdate), c.UpdateSystemConfig)
 
 		// 功能模块
 		api.GET("/modules", c.GetModuleList)
-		api.POST("/modules", c.CreateModule)
-		api.PUT("/modules/:id", c.UpdateModule)
-		api.DELETE("/modules/:id", c.DeleteModule)
+		api.POST("/modules", middleware.DenyImpersonation(), middleware.RequirePermission(model.PermissionModuleCreate), c.CreateModule)
+		api.PUT("/modules/:id", middleware.DenyImpersonation(), middleware.RequirePermission(model.PermissionModuleUpdate), c.UpdateModule)
+		api.DELETE("/modules/:id", middleware.DenyImpersonation(), middleware.RequirePermission(model.PermissionModuleDelete), c.DeleteModule)
 
 		// 模块配置
 		api.GET("/module-configs", c.GetModuleConfigList)
-		api.PUT("/module-configs/:id", c.UpdateModuleConfig)
-
-		// 功能节点
-		api.GET("/function-nodes", c.GetFunctionNodeList)
-		api.POST("/function-nodes", c.CreateFunctionNode)
-		api.PUT("/function-nodes/:id", c.UpdateFunctionNode)
-		api.DELETE("/function-nodes/:id", c.DeleteFunctionNode)
-
-		// 角色管理
-		api.GET("/roles", c.GetRoleList)
-		api.POST("/roles", c.CreateRole)
-		api.PUT("/roles/:id", c.UpdateRole)
-		api.DELETE("/roles/:id", c.DeleteRole)
-		api.GET("/roles/:id/functions", c.GetRoleFunctions)
-		api.PUT("/roles/:id/functions", c.UpdateRoleFunctions)
+		api.PUT("/module-configs/:id", middleware.DenyImpersonation(), middleware.RequirePermission(model.PermissionModuleConfigUpdate), c.UpdateModuleConfig)
+
+		// 功能节点，与 /api/permissions 使用相同的权限
+		api.GET("/function-nodes", middleware.RequirePermission(model.PermissionPermList), c.GetFunctionNodeList)
+		api.POST("/function-nodes", middleware.DenyImpersonation(), middleware.RequirePermission(model.PermissionPermCreate), c.CreateFunctionNode)
+		api.PUT("/function-nodes/:id", middleware.DenyImpersonation(), middleware.RequirePermission(model.PermissionPermUpdate), c.UpdateFunctionNode)
+		api.DELETE("/function-nodes/:id", middleware.DenyImpersonation(), middleware.RequirePermission(model.PermissionPermDelete), c.DeleteFunctionNode)
+
+		// 角色管理，与 /api/roles 使用相同的权限
+		api.GET("/roles", middleware.RequirePermission(model.PermissionRoleList), c.GetRoleList)
+		api.POST("/roles", middleware.DenyImpersonation(), middleware.RequirePermission(model.PermissionRoleCreate), c.CreateRole)
+		api.PUT("/roles/:id", middleware.DenyImpersonation(), middleware.RequirePermission(model.PermissionRoleUpdate), c.UpdateRole)
+		api.DELETE("/roles/:id", middleware.DenyImpersonation(), middleware.RequirePermission(model.PermissionRoleDelete), c.DeleteRole)
+		api.GET("/roles/:id/functions", middleware.RequirePermission(model.PermissionRoleGetPerms), c.GetRoleFunctions)
+		api.PUT("/roles/:id/functions", middleware.DenyImpersonation(), middleware.RequirePermission(model.PermissionRoleUpdatePerms), c.UpdateRoleFunctions)
 
 		// 操作日志
-		api.GET("/operation-logs", c.GetOperationLogList)
-
-		// 附件管理
-		api.GET("/attachments", c.GetAttachmentList)
-		api.POST("/attachments", c.CreateAttachment)
-		api.DELETE("/attachments/:id", c.DeleteAttachment)
+		api.GET("/operation-logs", middleware.RequirePermission(model.PermissionOperationLogList), c.GetOperationLogList)
+		api.GET("/operation-logs/export", middleware.RequirePermission(model.PermissionOperationLogExport), c.ExportOperationLogs)
+
+		// 敏感字段加密
+		api.POST("/encryption/reencrypt", middleware.DenyImpersonation(), middleware.RequirePermission(model.PermissionEncryptionKeyRotate), c.ReencryptFields)
+
+		// 附件管理，只能访问数据权限范围内的用户上传的附件
+		api.GET("/attachments", middleware.RequirePermission(model.PermissionAttachmentList), middleware.DataScope(), c.GetAttachmentList)
+		api.POST("/attachments", middleware.RequirePermission(model.PermissionAttachmentUpload), c.UploadAttachment)
+		api.GET("/attachments/:id/download", middleware.RequirePermission(model.PermissionAttachmentDownload), middleware.DataScope(), c.DownloadAttachment)
+		api.PUT("/attachments/:id/related", middleware.RequirePermission(model.PermissionAttachmentUpdate), middleware.DataScope(), c.LinkAttachment)
+		api.DELETE("/attachments/:id", middleware.RequirePermission(model.PermissionAttachmentDelete), middleware.DataScope(), c.DeleteAttachment)
+
+		// 分片上传，支持断点续传，上传任务只有创建人可以访问
+		api.POST("/attachments/uploads", middleware.RequirePermission(model.PermissionAttachmentUpload), c.CreateAttachmentUpload)
+		api.GET("/attachments/uploads/:upload_id", middleware.RequirePermission(model.PermissionAttachmentUpload), c.GetAttachmentUpload)
+		api.PUT("/attachments/uploads/:upload_id/chunks/:index", middleware.RequirePermission(model.PermissionAttachmentUpload), c.SaveAttachmentChunk)
+		api.POST("/attachments/uploads/:upload_id/complete", middleware.RequirePermission(model.PermissionAttachmentUpload), c.CompleteAttachmentUpload)
+		api.DELETE("/attachments/uploads/:upload_id", middleware.RequirePermission(model.PermissionAttachmentUpload), c.CancelAttachmentUpload)
 
 		// 备份管理
-		api.GET("/backup-records", c.GetBackupRecordList)
-		api.POST("/backup-records", c.CreateBackupRecord)
-		api.PUT("/backup-records/:id", c.UpdateBackupRecord)
-		api.DELETE("/backup-records/:id", c.DeleteBackupRecord)
+		api.GET("/backup-records", middleware.RequirePermission(model.PermissionBackupList), c.GetBackupRecordList)
+		api.POST("/backup-records", middleware.DenyImpersonation(), middleware.RequirePermission(model.PermissionBackupCreate), c.CreateBackupRecord)
+		api.GET("/backup-records/:id/download", middleware.DenyImpersonation(), middleware.RequirePermission(model.PermissionBackupDownload), c.DownloadBackup)
+		api.POST("/backup-records/:id/restore", middleware.DenyImpersonation(), middleware.RequirePermission(model.PermissionBackupRestore), c.RestoreBackup)
+		api.DELETE("/backup-records/:id", middleware.DenyImpersonation(), middleware.RequirePermission(model.PermissionBackupDelete), c.DeleteBackupRecord)
 
 		// 定时任务
-		api.GET("/scheduled-tasks", c.GetScheduledTaskList)
-		api.POST("/scheduled-tasks", c.CreateScheduledTask)
-		api.PUT("/scheduled-tasks/:id", c.UpdateScheduledTask)
-		api.DELETE("/scheduled-tasks/:id", c.DeleteScheduledTask)
-		api.PUT("/scheduled-tasks/:id/status", c.UpdateTaskStatus)
+		api.GET("/scheduled-jobs", middleware.RequirePermission(model.PermissionTaskList), c.GetScheduledJobList)
+		api.GET("/scheduled-tasks", middleware.RequirePermission(model.PermissionTaskList), c.GetScheduledTaskList)
+		api.POST("/scheduled-tasks", middleware.DenyImpersonation(), middleware.RequirePermission(model.PermissionTaskCreate), c.CreateScheduledTask)
+		api.PUT("/scheduled-tasks/:id", middleware.DenyImpersonation(), middleware.RequirePermission(model.PermissionTaskUpdate), c.UpdateScheduledTask)
+		api.DELETE("/scheduled-tasks/:id", middleware.DenyImpersonation(), middleware.RequirePermission(model.PermissionTaskDelete), c.DeleteScheduledTask)
+		api.PUT("/scheduled-tasks/:id/status", middleware.DenyImpersonation(), middleware.RequirePermission(model.PermissionTaskUpdate), c.UpdateTaskStatus)
+		api.POST("/scheduled-tasks/:id/run", middleware.DenyImpersonation(), middleware.RequirePermission(model.PermissionTaskRun), c.RunScheduledTask)
+		api.GET("/scheduled-tasks/:id/runs", middleware.RequirePermission(model.PermissionTaskList), c.GetScheduledTaskRunList)
 	}
 }
 
 // GetSystemConfigList 获取系统配置列表
 func (c *SystemController) GetSystemConfigList(ctx *gin.Context) {
-	configs, err := c.systemService.GetSystemConfigList()
+	configs, err := c.systemService.WithContext(ctx.Request.Context()).GetSystemConfigList()
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -90,9 +112,9 @@ func (c *SystemController) GetSystemConfigList(ctx *gin.Context) {
 // GetSystemConfigByKey 根据Key获取系统配置
 func (c *SystemController) GetSystemConfigByKey(ctx *gin.Context) {
 	key := ctx.Param("key")
-	config, err := c.systemService.GetSystemConfigByKey(key)
+	config, err := c.systemService.WithContext(ctx.Request.Context()).GetSystemConfigByKey(key)
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -104,13 +126,13 @@ func (c *SystemController) UpdateSystemConfig(ctx *gin.Context) {
 	id, _ := strconv.ParseUint(ctx.Param("id"), 10, 32)
 	var config model.SystemConfig
 	if err := ctx.ShouldBindJSON(&config); err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		respondError(ctx, http.StatusBadRequest, err)
 		return
 	}
 
 	config.ID = uint(id)
-	if err := c.systemService.UpdateSystemConfig(&config); err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	if err := c.systemService.WithContext(ctx.Request.Context()).UpdateSystemConfig(&config); err != nil {
+		respondError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -119,9 +141,9 @@ func (c *SystemController) UpdateSystemConfig(ctx *gin.Context) {
 
 // GetModuleList 获取功能模块列表
 func (c *SystemController) GetModuleList(ctx *gin.Context) {
-	modules, err := c.systemService.GetModuleList()
+	modules, err := c.systemService.WithContext(ctx.Request.Context()).GetModuleList()
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -132,12 +154,12 @@ func (c *SystemController) GetModuleList(ctx *gin.Context) {
 func (c *SystemController) CreateModule(ctx *gin.Context) {
 	var module model.Module
 	if err := ctx.ShouldBindJSON(&module); err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		respondError(ctx, http.StatusBadRequest, err)
 		return
 	}
 
-	if err := c.systemService.CreateModule(&module); err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	if err := c.systemService.WithContext(ctx.Request.Context()).CreateModule(&module); err != nil {
+		respondError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -149,13 +171,13 @@ func (c *SystemController) UpdateModule(ctx *gin.Context) {
 	id, _ := strconv.ParseUint(ctx.Param("id"), 10, 32)
 	var module model.Module
 	if err := ctx.ShouldBindJSON(&module); err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		respondError(ctx, http.StatusBadRequest, err)
 		return
 	}
 
 	module.ID = uint(id)
-	if err := c.systemService.UpdateModule(&module); err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	if err := c.systemService.WithContext(ctx.Request.Context()).UpdateModule(&module); err != nil {
+		respondError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -165,8 +187,8 @@ func (c *SystemController) UpdateModule(ctx *gin.Context) {
 // DeleteModule 删除功能模块
 func (c *SystemController) DeleteModule(ctx *gin.Context) {
 	id, _ := strconv.ParseUint(ctx.Param("id"), 10, 32)
-	if err := c.systemService.DeleteModule(uint(id)); err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	if err := c.systemService.WithContext(ctx.Request.Context()).DeleteModule(uint(id)); err != nil {
+		respondError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -176,9 +198,9 @@ func (c *SystemController) DeleteModule(ctx *gin.Context) {
 // GetModuleConfigList 获取模块配置列表
 func (c *SystemController) GetModuleConfigList(ctx *gin.Context) {
 	moduleID, _ := strconv.ParseUint(ctx.Query("module_id"), 10, 32)
-	configs, err := c.systemService.GetModuleConfigList(uint(moduleID))
+	configs, err := c.systemService.WithContext(ctx.Request.Context()).GetModuleConfigList(uint(moduleID))
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -190,13 +212,13 @@ func (c *SystemController) UpdateModuleConfig(ctx *gin.Context) {
 	id, _ := strconv.ParseUint(ctx.Param("id"), 10, 32)
 	var config model.ModuleConfig
 	if err := ctx.ShouldBindJSON(&config); err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		respondError(ctx, http.StatusBadRequest, err)
 		return
 	}
 
 	config.ID = uint(id)
-	if err := c.systemService.UpdateModuleConfig(&config); err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	if err := c.systemService.WithContext(ctx.Request.Context()).UpdateModuleConfig(&config); err != nil {
+		respondError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -206,9 +228,9 @@ func (c *SystemController) UpdateModuleConfig(ctx *gin.Context) {
 // GetFunctionNodeList 获取功能节点列表
 func (c *SystemController) GetFunctionNodeList(ctx *gin.Context) {
 	moduleID, _ := strconv.ParseUint(ctx.Query("module_id"), 10, 32)
-	nodes, err := c.systemService.GetFunctionNodeList(uint(moduleID))
+	nodes, err := c.systemService.WithContext(ctx.Request.Context()).GetFunctionNodeList(uint(moduleID))
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -219,12 +241,12 @@ func (c *SystemController) GetFunctionNodeList(ctx *gin.Context) {
 func (c *SystemController) CreateFunctionNode(ctx *gin.Context) {
 	var node model.FunctionNode
 	if err := ctx.ShouldBindJSON(&node); err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		respondError(ctx, http.StatusBadRequest, err)
 		return
 	}
 
-	if err := c.systemService.CreateFunctionNode(&node); err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	if err := c.systemService.WithContext(ctx.Request.Context()).CreateFunctionNode(&node); err != nil {
+		respondError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -236,13 +258,13 @@ func (c *SystemController) UpdateFunctionNode(ctx *gin.Context) {
 	id, _ := strconv.ParseUint(ctx.Param("id"), 10, 32)
 	var node model.FunctionNode
 	if err := ctx.ShouldBindJSON(&node); err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		respondError(ctx, http.StatusBadRequest, err)
 		return
 	}
 
 	node.ID = uint(id)
-	if err := c.systemService.UpdateFunctionNode(&node); err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	if err := c.systemService.WithContext(ctx.Request.Context()).UpdateFunctionNode(&node); err != nil {
+		respondError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -252,8 +274,8 @@ func (c *SystemController) UpdateFunctionNode(ctx *gin.Context) {
 // DeleteFunctionNode 删除功能节点
 func (c *SystemController) DeleteFunctionNode(ctx *gin.Context) {
 	id, _ := strconv.ParseUint(ctx.Param("id"), 10, 32)
-	if err := c.systemService.DeleteFunctionNode(uint(id)); err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	if err := c.systemService.WithContext(ctx.Request.Context()).DeleteFunctionNode(uint(id)); err != nil {
+		respondError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -262,9 +284,9 @@ func (c *SystemController) DeleteFunctionNode(ctx *gin.Context) {
 
 // GetRoleList 获取角色列表
 func (c *SystemController) GetRoleList(ctx *gin.Context) {
-	roles, err := c.systemService.GetRoleList()
+	roles, err := c.systemService.WithContext(ctx.Request.Context()).GetRoleList()
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -275,12 +297,12 @@ func (c *SystemController) GetRoleList(ctx *gin.Context) {
 func (c *SystemController) CreateRole(ctx *gin.Context) {
 	var role model.Role
 	if err := ctx.ShouldBindJSON(&role); err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		respondError(ctx, http.StatusBadRequest, err)
 		return
 	}
 
-	if err := c.systemService.CreateRole(&role); err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	if err := c.systemService.WithContext(ctx.Request.Context()).CreateRole(&role); err != nil {
+		respondError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -290,15 +312,15 @@ func (c *SystemController) CreateRole(ctx *gin.Context) {
 // UpdateRole 更新角色
 func (c *SystemController) UpdateRole(ctx *gin.Context) {
 	id, _ := strconv.ParseUint(ctx.Param("id"), 10, 32)
-	var role model.Role
-	if err := ctx.ShouldBindJSON(&role); err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	var req service.RoleUpdate
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		respondError(ctx, http.StatusBadRequest, err)
 		return
 	}
 
-	role.ID = uint(id)
-	if err := c.systemService.UpdateRole(&role); err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	role, err := c.systemService.WithContext(ctx.Request.Context()).UpdateRole(uint(id), req)
+	if err != nil {
+		respondError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -308,8 +330,8 @@ func (c *SystemController) UpdateRole(ctx *gin.Context) {
 // DeleteRole 删除角色
 func (c *SystemController) DeleteRole(ctx *gin.Context) {
 	id, _ := strconv.ParseUint(ctx.Param("id"), 10, 32)
-	if err := c.systemService.DeleteRole(uint(id)); err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	if err := c.systemService.WithContext(ctx.Request.Context()).DeleteRole(uint(id)); err != nil {
+		respondError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -319,9 +341,9 @@ func (c *SystemController) DeleteRole(ctx *gin.Context) {
 // GetRoleFunctions 获取角色的功能权限
 func (c *SystemController) GetRoleFunctions(ctx *gin.Context) {
 	id, _ := strconv.ParseUint(ctx.Param("id"), 10, 32)
-	nodes, err := c.systemService.GetRoleFunctions(uint(id))
+	nodes, err := c.systemService.WithContext(ctx.Request.Context()).GetRoleFunctions(uint(id))
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -335,12 +357,12 @@ func (c *SystemController) UpdateRoleFunctions(ctx *gin.Context) {
 		FunctionNodeIDs []uint `json:"function_node_ids" binding:"required"`
 	}
 	if err := ctx.ShouldBindJSON(&req); err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		respondError(ctx, http.StatusBadRequest, err)
 		return
 	}
 
-	if err := c.systemService.UpdateRoleFunctions(uint(id), req.FunctionNodeIDs); err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	if err := c.systemService.WithContext(ctx.Request.Context()).UpdateRoleFunctions(uint(id), req.FunctionNodeIDs); err != nil {
+		respondError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -349,14 +371,13 @@ func (c *SystemController) UpdateRoleFunctions(ctx *gin.Context) {
 
 // GetOperationLogList 获取操作日志列表
 func (c *SystemController) GetOperationLogList(ctx *gin.Context) {
-	userID, _ := strconv.ParseUint(ctx.Query("user_id"), 10, 32)
-	module := ctx.Query("module")
+	filter := operationLogFilter(ctx)
 	page, _ := strconv.Atoi(ctx.DefaultQuery("page", "1"))
 	pageSize, _ := strconv.Atoi(ctx.DefaultQuery("page_size", "10"))
 
-	logs, total, err := c.systemService.GetOperationLogList(uint(userID), module, page, pageSize)
+	logs, total, err := c.systemService.WithContext(ctx.Request.Context()).GetOperationLogList(filter, page, pageSize)
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -366,188 +387,56 @@ func (c *SystemController) GetOperationLogList(ctx *gin.Context) {
 	})
 }
 
-// GetAttachmentList 获取附件列表
-func (c *SystemController) GetAttachmentList(ctx *gin.Context) {
-	module := ctx.Query("module")
-	relatedID, _ := strconv.ParseUint(ctx.Query("related_id"), 10, 32)
-	page, _ := strconv.Atoi(ctx.DefaultQuery("page", "1"))
-	pageSize, _ := strconv.Atoi(ctx.DefaultQuery("page_size", "10"))
+// ExportOperationLogs 按查询条件导出操作日志CSV
+func (c *SystemController) ExportOperationLogs(ctx *gin.Context) {
+	filter := operationLogFilter(ctx)
 
-	attachments, total, err := c.systemService.GetAttachmentList(module, uint(relatedID), page, pageSize)
-	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
-		return
-	}
+	filename := fmt.Sprintf("operation_logs_%s.csv", time.Now().Format("20060102150405"))
+	ctx.Header("Content-Type", "text/csv; charset=utf-8")
+	ctx.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%q", filename))
+	ctx.Status(http.StatusOK)
 
-	ctx.JSON(http.StatusOK, gin.H{
-		"data":  attachments,
-		"total": total,
-	})
-}
-
-// CreateAttachment 创建附件
-func (c *SystemController) CreateAttachment(ctx *gin.Context) {
-	var attachment model.Attachment
-	if err := ctx.ShouldBindJSON(&attachment); err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
-		return
-	}
-
-	// TODO: 从JWT中获取userID
-	attachment.UploadedBy = uint(1)
-
-	if err := c.systemService.CreateAttachment(&attachment); err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
-		return
+	// 已开始输出文件内容，出错时只能中断
+	if err := c.systemService.WithContext(ctx.Request.Context()).ExportOperationLogs(filter, ctx.Writer); err != nil {
+		reqCtx := ctx.Request.Context()
+		logging.FromContext(reqCtx).ErrorContext(reqCtx, "导出操作日志失败", "error", err)
+		_ = ctx.Error(err)
+		ctx.Abort()
 	}
-
-	ctx.JSON(http.StatusCreated, attachment)
 }
 
-// DeleteAttachment 删除附件
-func (c *SystemController) DeleteAttachment(ctx *gin.Context) {
-	id, _ := strconv.ParseUint(ctx.Param("id"), 10, 32)
-	if err := c.systemService.DeleteAttachment(uint(id)); err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
-		return
-	}
-
-	ctx.Status(http.StatusNoContent)
-}
-
-// GetBackupRecordList 获取备份记录列表
-func (c *SystemController) GetBackupRecordList(ctx *gin.Context) {
-	page, _ := strconv.Atoi(ctx.DefaultQuery("page", "1"))
-	pageSize, _ := strconv.Atoi(ctx.DefaultQuery("page_size", "10"))
+// 从查询参数解析操作日志查询条件，结束日期包含当天
+func operationLogFilter(ctx *gin.Context) service.OperationLogFilter {
+	userID, _ := strconv.ParseUint(ctx.Query("user_id"), 10, 32)
+	failed, _ := strconv.ParseBool(ctx.Query("failed"))
+	startDate, _ := time.ParseInLocation("2006-01-02", ctx.Query("start_date"), time.Local)
+	endDate, _ := time.ParseInLocation("2006-01-02", ctx.Query("end_date"), time.Local)
 
-	records, total, err := c.systemService.GetBackupRecordList(page, pageSize)
-	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
-		return
+	filter := service.OperationLogFilter{
+		UserID:  uint(userID),
+		Module:  ctx.Query("module"),
+		Action:  ctx.Query("action"),
+		Method:  ctx.Query("method"),
+		Keyword: ctx.Query("keyword"),
+		Failed:  failed,
 	}
-
-	ctx.JSON(http.StatusOK, gin.H{
-		"data":  records,
-		"total": total,
-	})
-}
-
-// CreateBackupRecord 创建备份记录
-func (c *SystemController) CreateBackupRecord(ctx *gin.Context) {
-	var record model.BackupRecord
-	if err := ctx.ShouldBindJSON(&record); err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
-		return
+	if !startDate.IsZero() {
+		filter.StartTime = &startDate
 	}
-
-	if err := c.systemService.CreateBackupRecord(&record); err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
-		return
+	if !endDate.IsZero() {
+		end := endDate.AddDate(0, 0, 1)
+		filter.EndTime = &end
 	}
-
-	ctx.JSON(http.StatusCreated, record)
+	return filter
 }
 
-// UpdateBackupRecord 更新备份记录
-func (c *SystemController) UpdateBackupRecord(ctx *gin.Context) {
-	id, _ := strconv.ParseUint(ctx.Param("id"), 10, 32)
-	var record model.BackupRecord
-	if err := ctx.ShouldBindJSON(&record); err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
-		return
-	}
-
-	record.ID = uint(id)
-	if err := c.systemService.UpdateBackupRecord(&record); err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
-		return
-	}
-
-	ctx.JSON(http.StatusOK, record)
-}
-
-// DeleteBackupRecord 删除备份记录
-func (c *SystemController) DeleteBackupRecord(ctx *gin.Context) {
-	id, _ := strconv.ParseUint(ctx.Param("id"), 10, 32)
-	if err := c.systemService.DeleteBackupRecord(uint(id)); err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
-		return
-	}
-
-	ctx.Status(http.StatusNoContent)
-}
-
-// GetScheduledTaskList 获取定时任务列表
-func (c *SystemController) GetScheduledTaskList(ctx *gin.Context) {
-	tasks, err := c.systemService.GetScheduledTaskList()
+// ReencryptFields 使用当前密钥重新加密敏感字段
+func (c *SystemController) ReencryptFields(ctx *gin.Context) {
+	count, err := c.systemService.WithContext(ctx.Request.Context()).ReencryptFields()
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
-		return
-	}
-
-	ctx.JSON(http.StatusOK, tasks)
-}
-
-// CreateScheduledTask 创建定时任务
-func (c *SystemController) CreateScheduledTask(ctx *gin.Context) {
-	var task model.ScheduledTask
-	if err := ctx.ShouldBindJSON(&task); err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
-		return
-	}
-
-	if err := c.systemService.CreateScheduledTask(&task); err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
-		return
-	}
-
-	ctx.JSON(http.StatusCreated, task)
-}
-
-// UpdateScheduledTask 更新定时任务
-func (c *SystemController) UpdateScheduledTask(ctx *gin.Context) {
-	id, _ := strconv.ParseUint(ctx.Param("id"), 10, 32)
-	var task model.ScheduledTask
-	if err := ctx.ShouldBindJSON(&task); err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
-		return
-	}
-
-	task.ID = uint(id)
-	if err := c.systemService.UpdateScheduledTask(&task); err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
-		return
-	}
-
-	ctx.JSON(http.StatusOK, task)
-}
-
-// DeleteScheduledTask 删除定时任务
-func (c *SystemController) DeleteScheduledTask(ctx *gin.Context) {
-	id, _ := strconv.ParseUint(ctx.Param("id"), 10, 32)
-	if err := c.systemService.DeleteScheduledTask(uint(id)); err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
-		return
-	}
-
-	ctx.Status(http.StatusNoContent)
-}
-
-// UpdateTaskStatus 更新任务状态
-func (c *SystemController) UpdateTaskStatus(ctx *gin.Context) {
-	id, _ := strconv.ParseUint(ctx.Param("id"), 10, 32)
-	var req struct {
-		Status int `json:"status" binding:"required"`
-	}
-	if err := ctx.ShouldBindJSON(&req); err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
-		return
-	}
-
-	if err := c.systemService.UpdateTaskStatus(uint(id), req.Status); err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 
-	ctx.Status(http.StatusNoContent)
+	ctx.JSON(http.StatusOK, gin.H{"count": count})
 }
@@ -4,6 +4,7 @@ import (
 	"net/http"
 	"strconv"
 
+	"github.com/lemonoa/LemonOA-Go/middleware"
 	"github.com/lemonoa/LemonOA-Go/model"
 	"github.com/lemonoa/LemonOA-Go/service"
 
@@ -23,6 +24,7 @@ func NewTodoController(todoService *service.TodoService) *TodoController {
 // RegisterRoutes 注册路由
 func (c *TodoController) RegisterRoutes(r *gin.Engine) {
 	api := r.Group("/api/todos")
+	api.Use(middleware.JWT())
 	{
 		api.GET("", c.GetTodoList)
 		api.POST("", c.CreateTodo)
@@ -35,15 +37,14 @@ func (c *TodoController) RegisterRoutes(r *gin.Engine) {
 
 // GetTodoList 获取待办事项列表
 func (c *TodoController) GetTodoList(ctx *gin.Context) {
-	// TODO: 从JWT中获取userID
-	userID := uint(1)
+	userID := middleware.CurrentUserID(ctx)
 	status, _ := strconv.Atoi(ctx.Query("status"))
 	page, _ := strconv.Atoi(ctx.DefaultQuery("page", "1"))
 	pageSize, _ := strconv.Atoi(ctx.DefaultQuery("page_size", "10"))
 
-	todos, total, err := c.todoService.GetTodoList(userID, status, page, pageSize)
+	todos, total, err := c.todoService.WithContext(ctx.Request.Context()).GetTodoList(userID, status, page, pageSize)
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -57,15 +58,14 @@ func (c *TodoController) GetTodoList(ctx *gin.Context) {
 func (c *TodoController) CreateTodo(ctx *gin.Context) {
 	var todo model.Todo
 	if err := ctx.ShouldBindJSON(&todo); err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		respondError(ctx, http.StatusBadRequest, err)
 		return
 	}
 
-	// TODO: 从JWT中获取userID
-	todo.UserID = uint(1)
+	todo.UserID = middleware.CurrentUserID(ctx)
 
-	if err := c.todoService.CreateTodo(&todo); err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	if err := c.todoService.WithContext(ctx.Request.Context()).CreateTodo(&todo); err != nil {
+		respondError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -77,16 +77,15 @@ func (c *TodoController) UpdateTodo(ctx *gin.Context) {
 	id, _ := strconv.ParseUint(ctx.Param("id"), 10, 32)
 	var todo model.Todo
 	if err := ctx.ShouldBindJSON(&todo); err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		respondError(ctx, http.StatusBadRequest, err)
 		return
 	}
 
 	todo.ID = uint(id)
-	// TODO: 从JWT中获取userID
-	todo.UserID = uint(1)
+	todo.UserID = middleware.CurrentUserID(ctx)
 
-	if err := c.todoService.UpdateTodo(&todo); err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	if err := c.todoService.WithContext(ctx.Request.Context()).UpdateTodo(&todo); err != nil {
+		respondError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -96,11 +95,10 @@ func (c *TodoController) UpdateTodo(ctx *gin.Context) {
 // DeleteTodo 删除待办事项
 func (c *TodoController) DeleteTodo(ctx *gin.Context) {
 	id, _ := strconv.ParseUint(ctx.Param("id"), 10, 32)
-	// TODO: 从JWT中获取userID
-	userID := uint(1)
+	userID := middleware.CurrentUserID(ctx)
 
-	if err := c.todoService.DeleteTodo(uint(id), userID); err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	if err := c.todoService.WithContext(ctx.Request.Context()).DeleteTodo(uint(id), userID); err != nil {
+		respondError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -110,11 +108,10 @@ func (c *TodoController) DeleteTodo(ctx *gin.Context) {
 // MarkAsCompleted 标记待办事项为已完成
 func (c *TodoController) MarkAsCompleted(ctx *gin.Context) {
 	id, _ := strconv.ParseUint(ctx.Param("id"), 10, 32)
-	// TODO: 从JWT中获取userID
-	userID := uint(1)
+	userID := middleware.CurrentUserID(ctx)
 
-	if err := c.todoService.MarkAsCompleted(uint(id), userID); err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	if err := c.todoService.WithContext(ctx.Request.Context()).MarkAsCompleted(uint(id), userID); err != nil {
+		respondError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -124,11 +121,10 @@ func (c *TodoController) MarkAsCompleted(ctx *gin.Context) {
 // MarkAsUncompleted 标记待办事项为未完成
 func (c *TodoController) MarkAsUncompleted(ctx *gin.Context) {
 	id, _ := strconv.ParseUint(ctx.Param("id"), 10, 32)
-	// TODO: 从JWT中获取userID
-	userID := uint(1)
+	userID := middleware.CurrentUserID(ctx)
 
-	if err := c.todoService.MarkAsUncompleted(uint(id), userID); err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	if err := c.todoService.WithContext(ctx.Request.Context()).MarkAsUncompleted(uint(id), userID); err != nil {
+		respondError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -7,6 +7,8 @@ import (
 	"github.com/lemonoa/LemonOA-Go/model"
 	"github.com/lemonoa/LemonOA-Go/service"
 
+	"github.com/lemonoa/LemonOA-Go/middleware"
+
 	"github.com/gin-gonic/gin"
 )
 
@@ -23,6 +25,7 @@ func NewVehicleController(vehicleService *service.VehicleService) *VehicleContro
 // RegisterRoutes 注册路由
 func (c *VehicleController) RegisterRoutes(r *gin.Engine) {
 	api := r.Group("/api/vehicles")
+	api.Use(middleware.JWT(), middleware.DataScope())
 	{
 		// 车辆管理
 		api.GET("", c.GetVehicleList)
@@ -102,9 +105,9 @@ func (c *VehicleController) GetVehicleList(ctx *gin.Context) {
 	page, _ := strconv.Atoi(ctx.DefaultQuery("page", "1"))
 	pageSize, _ := strconv.Atoi(ctx.DefaultQuery("page_size", "10"))
 
-	vehicles, total, err := c.vehicleService.GetVehicleList(status, keyword, page, pageSize)
+	vehicles, total, err := c.vehicleService.WithContext(ctx.Request.Context()).GetVehicleList(status, keyword, page, pageSize, middleware.CurrentDataScope(ctx))
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -117,9 +120,9 @@ func (c *VehicleController) GetVehicleList(ctx *gin.Context) {
 // GetVehicleByID 根据ID获取车辆
 func (c *VehicleController) GetVehicleByID(ctx *gin.Context) {
 	id, _ := strconv.ParseUint(ctx.Param("id"), 10, 32)
-	vehicle, err := c.vehicleService.GetVehicleByID(uint(id))
+	vehicle, err := c.vehicleService.WithContext(ctx.Request.Context()).GetVehicleByID(uint(id), middleware.CurrentDataScope(ctx))
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -130,15 +133,12 @@ func (c *VehicleController) GetVehicleByID(ctx *gin.Context) {
 func (c *VehicleController) CreateVehicle(ctx *gin.Context) {
 	var vehicle model.Vehicle
 	if err := ctx.ShouldBindJSON(&vehicle); err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		respondError(ctx, http.StatusBadRequest, err)
 		return
 	}
 
-	// TODO: 从JWT中获取当前用户ID
-	vehicle.CreatedBy = uint(1)
-
-	if err := c.vehicleService.CreateVehicle(&vehicle); err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	if err := c.vehicleService.WithContext(ctx.Request.Context()).CreateVehicle(&vehicle); err != nil {
+		respondError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -150,13 +150,13 @@ func (c *VehicleController) UpdateVehicle(ctx *gin.Context) {
 	id, _ := strconv.ParseUint(ctx.Param("id"), 10, 32)
 	var vehicle model.Vehicle
 	if err := ctx.ShouldBindJSON(&vehicle); err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		respondError(ctx, http.StatusBadRequest, err)
 		return
 	}
 
 	vehicle.ID = uint(id)
-	if err := c.vehicleService.UpdateVehicle(&vehicle); err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	if err := c.vehicleService.WithContext(ctx.Request.Context()).UpdateVehicle(&vehicle); err != nil {
+		respondError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -166,8 +166,8 @@ func (c *VehicleController) UpdateVehicle(ctx *gin.Context) {
 // DeleteVehicle 删除车辆
 func (c *VehicleController) DeleteVehicle(ctx *gin.Context) {
 	id, _ := strconv.ParseUint(ctx.Param("id"), 10, 32)
-	if err := c.vehicleService.DeleteVehicle(uint(id)); err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	if err := c.vehicleService.WithContext(ctx.Request.Context()).DeleteVehicle(uint(id)); err != nil {
+		respondError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -181,9 +181,9 @@ func (c *VehicleController) GetVehicleRepairList(ctx *gin.Context) {
 	page, _ := strconv.Atoi(ctx.DefaultQuery("page", "1"))
 	pageSize, _ := strconv.Atoi(ctx.DefaultQuery("page_size", "10"))
 
-	repairs, total, err := c.vehicleService.GetVehicleRepairList(uint(vehicleID), status, page, pageSize)
+	repairs, total, err := c.vehicleService.WithContext(ctx.Request.Context()).GetVehicleRepairList(uint(vehicleID), status, page, pageSize)
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -196,9 +196,9 @@ func (c *VehicleController) GetVehicleRepairList(ctx *gin.Context) {
 // GetVehicleRepairByID 根据ID获取车辆维修记录
 func (c *VehicleController) GetVehicleRepairByID(ctx *gin.Context) {
 	id, _ := strconv.ParseUint(ctx.Param("id"), 10, 32)
-	repair, err := c.vehicleService.GetVehicleRepairByID(uint(id))
+	repair, err := c.vehicleService.WithContext(ctx.Request.Context()).GetVehicleRepairByID(uint(id))
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -209,15 +209,12 @@ func (c *VehicleController) GetVehicleRepairByID(ctx *gin.Context) {
 func (c *VehicleController) CreateVehicleRepair(ctx *gin.Context) {
 	var repair model.VehicleRepair
 	if err := ctx.ShouldBindJSON(&repair); err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		respondError(ctx, http.StatusBadRequest, err)
 		return
 	}
 
-	// TODO: 从JWT中获取当前用户ID
-	repair.CreatedBy = uint(1)
-
-	if err := c.vehicleService.CreateVehicleRepair(&repair); err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	if err := c.vehicleService.WithContext(ctx.Request.Context()).CreateVehicleRepair(&repair); err != nil {
+		respondError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -229,13 +226,13 @@ func (c *VehicleController) UpdateVehicleRepair(ctx *gin.Context) {
 	id, _ := strconv.ParseUint(ctx.Param("id"), 10, 32)
 	var repair model.VehicleRepair
 	if err := ctx.ShouldBindJSON(&repair); err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		respondError(ctx, http.StatusBadRequest, err)
 		return
 	}
 
 	repair.ID = uint(id)
-	if err := c.vehicleService.UpdateVehicleRepair(&repair); err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	if err := c.vehicleService.WithContext(ctx.Request.Context()).UpdateVehicleRepair(&repair); err != nil {
+		respondError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -245,8 +242,8 @@ func (c *VehicleController) UpdateVehicleRepair(ctx *gin.Context) {
 // DeleteVehicleRepair 删除车辆维修记录
 func (c *VehicleController) DeleteVehicleRepair(ctx *gin.Context) {
 	id, _ := strconv.ParseUint(ctx.Param("id"), 10, 32)
-	if err := c.vehicleService.DeleteVehicleRepair(uint(id)); err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	if err := c.vehicleService.WithContext(ctx.Request.Context()).DeleteVehicleRepair(uint(id)); err != nil {
+		respondError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -256,8 +253,8 @@ func (c *VehicleController) DeleteVehicleRepair(ctx *gin.Context) {
 // CompleteVehicleRepair 完成车辆维修
 func (c *VehicleController) CompleteVehicleRepair(ctx *gin.Context) {
 	id, _ := strconv.ParseUint(ctx.Param("id"), 10, 32)
-	if err := c.vehicleService.CompleteVehicleRepair(uint(id)); err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	if err := c.vehicleService.WithContext(ctx.Request.Context()).CompleteVehicleRepair(uint(id)); err != nil {
+		respondError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -271,9 +268,9 @@ func (c *VehicleController) GetVehicleMaintenanceList(ctx *gin.Context) {
 	page, _ := strconv.Atoi(ctx.DefaultQuery("page", "1"))
 	pageSize, _ := strconv.Atoi(ctx.DefaultQuery("page_size", "10"))
 
-	maintenances, total, err := c.vehicleService.GetVehicleMaintenanceList(uint(vehicleID), status, page, pageSize)
+	maintenances, total, err := c.vehicleService.WithContext(ctx.Request.Context()).GetVehicleMaintenanceList(uint(vehicleID), status, page, pageSize)
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -286,9 +283,9 @@ func (c *VehicleController) GetVehicleMaintenanceList(ctx *gin.Context) {
 // GetVehicleMaintenanceByID 根据ID获取车辆保养记录
 func (c *VehicleController) GetVehicleMaintenanceByID(ctx *gin.Context) {
 	id, _ := strconv.ParseUint(ctx.Param("id"), 10, 32)
-	maintenance, err := c.vehicleService.GetVehicleMaintenanceByID(uint(id))
+	maintenance, err := c.vehicleService.WithContext(ctx.Request.Context()).GetVehicleMaintenanceByID(uint(id))
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -299,15 +296,12 @@ func (c *VehicleController) GetVehicleMaintenanceByID(ctx *gin.Context) {
 func (c *VehicleController) CreateVehicleMaintenance(ctx *gin.Context) {
 	var maintenance model.VehicleMaintenance
 	if err := ctx.ShouldBindJSON(&maintenance); err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		respondError(ctx, http.StatusBadRequest, err)
 		return
 	}
 
-	// TODO: 从JWT中获取当前用户ID
-	maintenance.CreatedBy = uint(1)
-
-	if err := c.vehicleService.CreateVehicleMaintenance(&maintenance); err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	if err := c.vehicleService.WithContext(ctx.Request.Context()).CreateVehicleMaintenance(&maintenance); err != nil {
+		respondError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -319,13 +313,13 @@ func (c *VehicleController) UpdateVehicleMaintenance(ctx *gin.Context) {
 	id, _ := strconv.ParseUint(ctx.Param("id"), 10, 32)
 	var maintenance model.VehicleMaintenance
 	if err := ctx.ShouldBindJSON(&maintenance); err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		respondError(ctx, http.StatusBadRequest, err)
 		return
 	}
 
 	maintenance.ID = uint(id)
-	if err := c.vehicleService.UpdateVehicleMaintenance(&maintenance); err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	if err := c.vehicleService.WithContext(ctx.Request.Context()).UpdateVehicleMaintenance(&maintenance); err != nil {
+		respondError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -335,8 +329,8 @@ func (c *VehicleController) UpdateVehicleMaintenance(ctx *gin.Context) {
 // DeleteVehicleMaintenance 删除车辆保养记录
 func (c *VehicleController) DeleteVehicleMaintenance(ctx *gin.Context) {
 	id, _ := strconv.ParseUint(ctx.Param("id"), 10, 32)
-	if err := c.vehicleService.DeleteVehicleMaintenance(uint(id)); err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	if err := c.vehicleService.WithContext(ctx.Request.Context()).DeleteVehicleMaintenance(uint(id)); err != nil {
+		respondError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -346,8 +340,8 @@ func (c *VehicleController) DeleteVehicleMaintenance(ctx *gin.Context) {
 // CompleteVehicleMaintenance 完成车辆保养
 func (c *VehicleController) CompleteVehicleMaintenance(ctx *gin.Context) {
 	id, _ := strconv.ParseUint(ctx.Param("id"), 10, 32)
-	if err := c.vehicleService.CompleteVehicleMaintenance(uint(id)); err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	if err := c.vehicleService.WithContext(ctx.Request.Context()).CompleteVehicleMaintenance(uint(id)); err != nil {
+		respondError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -360,9 +354,9 @@ func (c *VehicleController) GetVehicleMileageList(ctx *gin.Context) {
 	page, _ := strconv.Atoi(ctx.DefaultQuery("page", "1"))
 	pageSize, _ := strconv.Atoi(ctx.DefaultQuery("page_size", "10"))
 
-	mileages, total, err := c.vehicleService.GetVehicleMileageList(uint(vehicleID), page, pageSize)
+	mileages, total, err := c.vehicleService.WithContext(ctx.Request.Context()).GetVehicleMileageList(uint(vehicleID), page, pageSize)
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -375,9 +369,9 @@ func (c *VehicleController) GetVehicleMileageList(ctx *gin.Context) {
 // GetVehicleMileageByID 根据ID获取车辆里程记录
 func (c *VehicleController) GetVehicleMileageByID(ctx *gin.Context) {
 	id, _ := strconv.ParseUint(ctx.Param("id"), 10, 32)
-	mileage, err := c.vehicleService.GetVehicleMileageByID(uint(id))
+	mileage, err := c.vehicleService.WithContext(ctx.Request.Context()).GetVehicleMileageByID(uint(id))
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -388,15 +382,12 @@ func (c *VehicleController) GetVehicleMileageByID(ctx *gin.Context) {
 func (c *VehicleController) CreateVehicleMileage(ctx *gin.Context) {
 	var mileage model.VehicleMileage
 	if err := ctx.ShouldBindJSON(&mileage); err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		respondError(ctx, http.StatusBadRequest, err)
 		return
 	}
 
-	// TODO: 从JWT中获取当前用户ID
-	mileage.CreatedBy = uint(1)
-
-	if err := c.vehicleService.CreateVehicleMileage(&mileage); err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	if err := c.vehicleService.WithContext(ctx.Request.Context()).CreateVehicleMileage(&mileage); err != nil {
+		respondError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -408,13 +399,13 @@ func (c *VehicleController) UpdateVehicleMileage(ctx *gin.Context) {
 	id, _ := strconv.ParseUint(ctx.Param("id"), 10, 32)
 	var mileage model.VehicleMileage
 	if err := ctx.ShouldBindJSON(&mileage); err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		respondError(ctx, http.StatusBadRequest, err)
 		return
 	}
 
 	mileage.ID = uint(id)
-	if err := c.vehicleService.UpdateVehicleMileage(&mileage); err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	if err := c.vehicleService.WithContext(ctx.Request.Context()).UpdateVehicleMileage(&mileage); err != nil {
+		respondError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -424,8 +415,8 @@ func (c *VehicleController) UpdateVehicleMileage(ctx *gin.Context) {
 // DeleteVehicleMileage 删除车辆里程记录
 func (c *VehicleController) DeleteVehicleMileage(ctx *gin.Context) {
 	id, _ := strconv.ParseUint(ctx.Param("id"), 10, 32)
-	if err := c.vehicleService.DeleteVehicleMileage(uint(id)); err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	if err := c.vehicleService.WithContext(ctx.Request.Context()).DeleteVehicleMileage(uint(id)); err != nil {
+		respondError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -439,9 +430,9 @@ func (c *VehicleController) GetVehicleExpenseList(ctx *gin.Context) {
 	page, _ := strconv.Atoi(ctx.DefaultQuery("page", "1"))
 	pageSize, _ := strconv.Atoi(ctx.DefaultQuery("page_size", "10"))
 
-	expenses, total, err := c.vehicleService.GetVehicleExpenseList(uint(vehicleID), uint(expenseID), page, pageSize)
+	expenses, total, err := c.vehicleService.WithContext(ctx.Request.Context()).GetVehicleExpenseList(uint(vehicleID), uint(expenseID), page, pageSize)
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -454,9 +445,9 @@ func (c *VehicleController) GetVehicleExpenseList(ctx *gin.Context) {
 // GetVehicleExpenseByID 根据ID获取车辆费用记录
 func (c *VehicleController) GetVehicleExpenseByID(ctx *gin.Context) {
 	id, _ := strconv.ParseUint(ctx.Param("id"), 10, 32)
-	expense, err := c.vehicleService.GetVehicleExpenseByID(uint(id))
+	expense, err := c.vehicleService.WithContext(ctx.Request.Context()).GetVehicleExpenseByID(uint(id))
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -467,15 +458,12 @@ func (c *VehicleController) GetVehicleExpenseByID(ctx *gin.Context) {
 func (c *VehicleController) CreateVehicleExpense(ctx *gin.Context) {
 	var expense model.VehicleExpenseRecord
 	if err := ctx.ShouldBindJSON(&expense); err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		respondError(ctx, http.StatusBadRequest, err)
 		return
 	}
 
-	// TODO: 从JWT中获取当前用户ID
-	expense.CreatedBy = uint(1)
-
-	if err := c.vehicleService.CreateVehicleExpense(&expense); err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	if err := c.vehicleService.WithContext(ctx.Request.Context()).CreateVehicleExpense(&expense); err != nil {
+		respondError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -487,13 +475,13 @@ func (c *VehicleController) UpdateVehicleExpense(ctx *gin.Context) {
 	id, _ := strconv.ParseUint(ctx.Param("id"), 10, 32)
 	var expense model.VehicleExpenseRecord
 	if err := ctx.ShouldBindJSON(&expense); err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		respondError(ctx, http.StatusBadRequest, err)
 		return
 	}
 
 	expense.ID = uint(id)
-	if err := c.vehicleService.UpdateVehicleExpense(&expense); err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	if err := c.vehicleService.WithContext(ctx.Request.Context()).UpdateVehicleExpense(&expense); err != nil {
+		respondError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -503,8 +491,8 @@ func (c *VehicleController) UpdateVehicleExpense(ctx *gin.Context) {
 // DeleteVehicleExpense 删除车辆费用记录
 func (c *VehicleController) DeleteVehicleExpense(ctx *gin.Context) {
 	id, _ := strconv.ParseUint(ctx.Param("id"), 10, 32)
-	if err := c.vehicleService.DeleteVehicleExpense(uint(id)); err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	if err := c.vehicleService.WithContext(ctx.Request.Context()).DeleteVehicleExpense(uint(id)); err != nil {
+		respondError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -518,9 +506,9 @@ func (c *VehicleController) GetVehicleViolationList(ctx *gin.Context) {
 	page, _ := strconv.Atoi(ctx.DefaultQuery("page", "1"))
 	pageSize, _ := strconv.Atoi(ctx.DefaultQuery("page_size", "10"))
 
-	violations, total, err := c.vehicleService.GetVehicleViolationList(uint(vehicleID), status, page, pageSize)
+	violations, total, err := c.vehicleService.WithContext(ctx.Request.Context()).GetVehicleViolationList(uint(vehicleID), status, page, pageSize)
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -533,9 +521,9 @@ func (c *VehicleController) GetVehicleViolationList(ctx *gin.Context) {
 // GetVehicleViolationByID 根据ID获取车辆违章记录
 func (c *VehicleController) GetVehicleViolationByID(ctx *gin.Context) {
 	id, _ := strconv.ParseUint(ctx.Param("id"), 10, 32)
-	violation, err := c.vehicleService.GetVehicleViolationByID(uint(id))
+	violation, err := c.vehicleService.WithContext(ctx.Request.Context()).GetVehicleViolationByID(uint(id))
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -546,15 +534,12 @@ func (c *VehicleController) GetVehicleViolationByID(ctx *gin.Context) {
 func (c *VehicleController) CreateVehicleViolation(ctx *gin.Context) {
 	var violation model.VehicleViolation
 	if err := ctx.ShouldBindJSON(&violation); err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		respondError(ctx, http.StatusBadRequest, err)
 		return
 	}
 
-	// TODO: 从JWT中获取当前用户ID
-	violation.CreatedBy = uint(1)
-
-	if err := c.vehicleService.CreateVehicleViolation(&violation); err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	if err := c.vehicleService.WithContext(ctx.Request.Context()).CreateVehicleViolation(&violation); err != nil {
+		respondError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -566,13 +551,13 @@ func (c *VehicleController) UpdateVehicleViolation(ctx *gin.Context) {
 	id, _ := strconv.ParseUint(ctx.Param("id"), 10, 32)
 	var violation model.VehicleViolation
 	if err := ctx.ShouldBindJSON(&violation); err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		respondError(ctx, http.StatusBadRequest, err)
 		return
 	}
 
 	violation.ID = uint(id)
-	if err := c.vehicleService.UpdateVehicleViolation(&violation); err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	if err := c.vehicleService.WithContext(ctx.Request.Context()).UpdateVehicleViolation(&violation); err != nil {
+		respondError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -582,8 +567,8 @@ func (c *VehicleController) UpdateVehicleViolation(ctx *gin.Context) {
 // DeleteVehicleViolation 删除车辆违章记录
 func (c *VehicleController) DeleteVehicleViolation(ctx *gin.Context) {
 	id, _ := strconv.ParseUint(ctx.Param("id"), 10, 32)
-	if err := c.vehicleService.DeleteVehicleViolation(uint(id)); err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	if err := c.vehicleService.WithContext(ctx.Request.Context()).DeleteVehicleViolation(uint(id)); err != nil {
+		respondError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -597,12 +582,12 @@ func (c *VehicleController) HandleVehicleViolation(ctx *gin.Context) {
 		Status int `json:"status" binding:"required"`
 	}
 	if err := ctx.ShouldBindJSON(&req); err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		respondError(ctx, http.StatusBadRequest, err)
 		return
 	}
 
-	if err := c.vehicleService.HandleVehicleViolation(uint(id), req.Status); err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	if err := c.vehicleService.WithContext(ctx.Request.Context()).HandleVehicleViolation(uint(id), req.Status); err != nil {
+		respondError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -616,9 +601,9 @@ func (c *VehicleController) GetVehicleAccidentList(ctx *gin.Context) {
 	page, _ := strconv.Atoi(ctx.DefaultQuery("page", "1"))
 	pageSize, _ := strconv.Atoi(ctx.DefaultQuery("page_size", "10"))
 
-	accidents, total, err := c.vehicleService.GetVehicleAccidentList(uint(vehicleID), status, page, pageSize)
+	accidents, total, err := c.vehicleService.WithContext(ctx.Request.Context()).GetVehicleAccidentList(uint(vehicleID), status, page, pageSize)
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -631,9 +616,9 @@ func (c *VehicleController) GetVehicleAccidentList(ctx *gin.Context) {
 // GetVehicleAccidentByID 根据ID获取车辆事故记录
 func (c *VehicleController) GetVehicleAccidentByID(ctx *gin.Context) {
 	id, _ := strconv.ParseUint(ctx.Param("id"), 10, 32)
-	accident, err := c.vehicleService.GetVehicleAccidentByID(uint(id))
+	accident, err := c.vehicleService.WithContext(ctx.Request.Context()).GetVehicleAccidentByID(uint(id))
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -644,15 +629,12 @@ func (c *VehicleController) GetVehicleAccidentByID(ctx *gin.Context) {
 func (c *VehicleController) CreateVehicleAccident(ctx *gin.Context) {
 	var accident model.VehicleAccident
 	if err := ctx.ShouldBindJSON(&accident); err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		respondError(ctx, http.StatusBadRequest, err)
 		return
 	}
 
-	// TODO: 从JWT中获取当前用户ID
-	accident.CreatedBy = uint(1)
-
-	if err := c.vehicleService.CreateVehicleAccident(&accident); err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	if err := c.vehicleService.WithContext(ctx.Request.Context()).CreateVehicleAccident(&accident); err != nil {
+		respondError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -664,13 +646,13 @@ func (c *VehicleController) UpdateVehicleAccident(ctx *gin.Context) {
 	id, _ := strconv.ParseUint(ctx.Param("id"), 10, 32)
 	var accident model.VehicleAccident
 	if err := ctx.ShouldBindJSON(&accident); err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		respondError(ctx, http.StatusBadRequest, err)
 		return
 	}
 
 	accident.ID = uint(id)
-	if err := c.vehicleService.UpdateVehicleAccident(&accident); err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	if err := c.vehicleService.WithContext(ctx.Request.Context()).UpdateVehicleAccident(&accident); err != nil {
+		respondError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -680,8 +662,8 @@ func (c *VehicleController) UpdateVehicleAccident(ctx *gin.Context) {
 // DeleteVehicleAccident 删除车辆事故记录
 func (c *VehicleController) DeleteVehicleAccident(ctx *gin.Context) {
 	id, _ := strconv.ParseUint(ctx.Param("id"), 10, 32)
-	if err := c.vehicleService.DeleteVehicleAccident(uint(id)); err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	if err := c.vehicleService.WithContext(ctx.Request.Context()).DeleteVehicleAccident(uint(id)); err != nil {
+		respondError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -695,12 +677,12 @@ func (c *VehicleController) HandleVehicleAccident(ctx *gin.Context) {
 		Status int `json:"status" binding:"required"`
 	}
 	if err := ctx.ShouldBindJSON(&req); err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		respondError(ctx, http.StatusBadRequest, err)
 		return
 	}
 
-	if err := c.vehicleService.HandleVehicleAccident(uint(id), req.Status); err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	if err := c.vehicleService.WithContext(ctx.Request.Context()).HandleVehicleAccident(uint(id), req.Status); err != nil {
+		respondError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -714,9 +696,9 @@ func (c *VehicleController) GetVehicleApplicationList(ctx *gin.Context) {
 	pageSize, _ := strconv.Atoi(ctx.DefaultQuery("page_size", "10"))
 
 	status, _ := strconv.Atoi(ctx.Query("status"))
-	applications, total, err := c.vehicleService.GetVehicleApplicationList(uint(vehicleID), uint(status), page, pageSize, 0) // Add the missing argument
+	applications, total, err := c.vehicleService.WithContext(ctx.Request.Context()).GetVehicleApplicationList(uint(vehicleID), uint(status), page, pageSize, 0, middleware.CurrentDataScope(ctx)) // Add the missing argument
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -729,9 +711,9 @@ func (c *VehicleController) GetVehicleApplicationList(ctx *gin.Context) {
 // GetVehicleApplicationByID 根据ID获取车辆用车申请
 func (c *VehicleController) GetVehicleApplicationByID(ctx *gin.Context) {
 	id, _ := strconv.ParseUint(ctx.Param("id"), 10, 32)
-	application, err := c.vehicleService.GetVehicleApplicationByID(uint(id))
+	application, err := c.vehicleService.WithContext(ctx.Request.Context()).GetVehicleApplicationByID(uint(id), middleware.CurrentDataScope(ctx))
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -742,15 +724,12 @@ func (c *VehicleController) GetVehicleApplicationByID(ctx *gin.Context) {
 func (c *VehicleController) CreateVehicleApplication(ctx *gin.Context) {
 	var application model.VehicleApplication
 	if err := ctx.ShouldBindJSON(&application); err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		respondError(ctx, http.StatusBadRequest, err)
 		return
 	}
 
-	// TODO: 从JWT中获取当前用户ID
-	application.CreatedBy = uint(1)
-
-	if err := c.vehicleService.CreateVehicleApplication(&application); err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	if err := c.vehicleService.WithContext(ctx.Request.Context()).CreateVehicleApplication(&application); err != nil {
+		respondError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -762,13 +741,13 @@ func (c *VehicleController) UpdateVehicleApplication(ctx *gin.Context) {
 	id, _ := strconv.ParseUint(ctx.Param("id"), 10, 32)
 	var application model.VehicleApplication
 	if err := ctx.ShouldBindJSON(&application); err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		respondError(ctx, http.StatusBadRequest, err)
 		return
 	}
 
 	application.ID = uint(id)
-	if err := c.vehicleService.UpdateVehicleApplication(&application); err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	if err := c.vehicleService.WithContext(ctx.Request.Context()).UpdateVehicleApplication(&application); err != nil {
+		respondError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -778,8 +757,8 @@ func (c *VehicleController) UpdateVehicleApplication(ctx *gin.Context) {
 // DeleteVehicleApplication 删除车辆用车申请
 func (c *VehicleController) DeleteVehicleApplication(ctx *gin.Context) {
 	id, _ := strconv.ParseUint(ctx.Param("id"), 10, 32)
-	if err := c.vehicleService.DeleteVehicleApplication(uint(id)); err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	if err := c.vehicleService.WithContext(ctx.Request.Context()).DeleteVehicleApplication(uint(id)); err != nil {
+		respondError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -789,8 +768,8 @@ func (c *VehicleController) DeleteVehicleApplication(ctx *gin.Context) {
 // ApproveVehicleApplication 批准车辆用车申请
 func (c *VehicleController) ApproveVehicleApplication(ctx *gin.Context) {
 	id, _ := strconv.ParseUint(ctx.Param("id"), 10, 32)
-	if err := c.vehicleService.ApproveVehicleApplication(uint(id)); err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	if err := c.vehicleService.WithContext(ctx.Request.Context()).ApproveVehicleApplication(uint(id)); err != nil {
+		respondError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -800,8 +779,8 @@ func (c *VehicleController) ApproveVehicleApplication(ctx *gin.Context) {
 // RejectVehicleApplication 拒绝车辆用车申请
 func (c *VehicleController) RejectVehicleApplication(ctx *gin.Context) {
 	id, _ := strconv.ParseUint(ctx.Param("id"), 10, 32)
-	if err := c.vehicleService.RejectVehicleApplication(uint(id)); err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	if err := c.vehicleService.WithContext(ctx.Request.Context()).RejectVehicleApplication(uint(id)); err != nil {
+		respondError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -815,9 +794,9 @@ func (c *VehicleController) GetVehicleReturnList(ctx *gin.Context) {
 	page, _ := strconv.Atoi(ctx.DefaultQuery("page", "1"))
 	pageSize, _ := strconv.Atoi(ctx.DefaultQuery("page_size", "10"))
 
-	returns, total, err := c.vehicleService.GetVehicleReturnList(uint(vehicleID), status, page, pageSize)
+	returns, total, err := c.vehicleService.WithContext(ctx.Request.Context()).GetVehicleReturnList(uint(vehicleID), status, page, pageSize)
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -830,9 +809,9 @@ func (c *VehicleController) GetVehicleReturnList(ctx *gin.Context) {
 // GetVehicleReturnByID 根据ID获取车辆归还记录
 func (c *VehicleController) GetVehicleReturnByID(ctx *gin.Context) {
 	id, _ := strconv.ParseUint(ctx.Param("id"), 10, 32)
-	vehicleReturn, err := c.vehicleService.GetVehicleReturnByID(uint(id))
+	vehicleReturn, err := c.vehicleService.WithContext(ctx.Request.Context()).GetVehicleReturnByID(uint(id))
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -843,15 +822,12 @@ func (c *VehicleController) GetVehicleReturnByID(ctx *gin.Context) {
 func (c *VehicleController) CreateVehicleReturn(ctx *gin.Context) {
 	var vehicleReturn model.VehicleReturn
 	if err := ctx.ShouldBindJSON(&vehicleReturn); err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		respondError(ctx, http.StatusBadRequest, err)
 		return
 	}
 
-	// TODO: 从JWT中获取当前用户ID
-	vehicleReturn.CreatedBy = uint(1)
-
-	if err := c.vehicleService.CreateVehicleReturn(&vehicleReturn); err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	if err := c.vehicleService.WithContext(ctx.Request.Context()).CreateVehicleReturn(&vehicleReturn); err != nil {
+		respondError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -863,13 +839,13 @@ func (c *VehicleController) UpdateVehicleReturn(ctx *gin.Context) {
 	id, _ := strconv.ParseUint(ctx.Param("id"), 10, 32)
 	var vehicleReturn model.VehicleReturn
 	if err := ctx.ShouldBindJSON(&vehicleReturn); err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		respondError(ctx, http.StatusBadRequest, err)
 		return
 	}
 
 	vehicleReturn.ID = uint(id)
-	if err := c.vehicleService.UpdateVehicleReturn(&vehicleReturn); err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	if err := c.vehicleService.WithContext(ctx.Request.Context()).UpdateVehicleReturn(&vehicleReturn); err != nil {
+		respondError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -879,8 +855,8 @@ func (c *VehicleController) UpdateVehicleReturn(ctx *gin.Context) {
 // DeleteVehicleReturn 删除车辆归还记录
 func (c *VehicleController) DeleteVehicleReturn(ctx *gin.Context) {
 	id, _ := strconv.ParseUint(ctx.Param("id"), 10, 32)
-	if err := c.vehicleService.DeleteVehicleReturn(uint(id)); err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	if err := c.vehicleService.WithContext(ctx.Request.Context()).DeleteVehicleReturn(uint(id)); err != nil {
+		respondError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -4,6 +4,7 @@ import (
 	"net/http"
 	"strconv"
 
+	"github.com/lemonoa/LemonOA-Go/middleware"
 	"github.com/lemonoa/LemonOA-Go/model"
 	"github.com/lemonoa/LemonOA-Go/service"
 
@@ -23,6 +24,7 @@ func NewWorkflowController(workflowService *service.WorkflowService) *WorkflowCo
 // RegisterRoutes 注册路由
 func (c *WorkflowController) RegisterRoutes(r *gin.Engine) {
 	api := r.Group("/api/workflows")
+	api.Use(middleware.JWT())
 	{
 		// 流程类型管理
 		api.GET("/types", c.GetWorkflowTypeList)
@@ -73,9 +75,9 @@ func (c *WorkflowController) GetWorkflowTypeList(ctx *gin.Context) {
 	page, _ := strconv.Atoi(ctx.DefaultQuery("page", "1"))
 	pageSize, _ := strconv.Atoi(ctx.DefaultQuery("page_size", "10"))
 
-	types, total, err := c.workflowService.GetWorkflowTypeList(status, keyword, page, pageSize)
+	types, total, err := c.workflowService.WithContext(ctx.Request.Context()).GetWorkflowTypeList(status, keyword, page, pageSize)
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -88,9 +90,9 @@ func (c *WorkflowController) GetWorkflowTypeList(ctx *gin.Context) {
 // GetWorkflowTypeByID 根据ID获取流程类型
 func (c *WorkflowController) GetWorkflowTypeByID(ctx *gin.Context) {
 	id, _ := strconv.ParseUint(ctx.Param("id"), 10, 32)
-	workflowType, err := c.workflowService.GetWorkflowTypeByID(uint(id))
+	workflowType, err := c.workflowService.WithContext(ctx.Request.Context()).GetWorkflowTypeByID(uint(id))
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -101,15 +103,12 @@ func (c *WorkflowController) GetWorkflowTypeByID(ctx *gin.Context) {
 func (c *WorkflowController) CreateWorkflowType(ctx *gin.Context) {
 	var workflowType model.WorkflowType
 	if err := ctx.ShouldBindJSON(&workflowType); err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		respondError(ctx, http.StatusBadRequest, err)
 		return
 	}
 
-	// TODO: 从JWT中获取当前用户ID
-	workflowType.CreatedBy = uint(1)
-
-	if err := c.workflowService.CreateWorkflowType(&workflowType); err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	if err := c.workflowService.WithContext(ctx.Request.Context()).CreateWorkflowType(&workflowType); err != nil {
+		respondError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -121,13 +120,13 @@ func (c *WorkflowController) UpdateWorkflowType(ctx *gin.Context) {
 	id, _ := strconv.ParseUint(ctx.Param("id"), 10, 32)
 	var workflowType model.WorkflowType
 	if err := ctx.ShouldBindJSON(&workflowType); err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		respondError(ctx, http.StatusBadRequest, err)
 		return
 	}
 
 	workflowType.ID = uint(id)
-	if err := c.workflowService.UpdateWorkflowType(&workflowType); err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	if err := c.workflowService.WithContext(ctx.Request.Context()).UpdateWorkflowType(&workflowType); err != nil {
+		respondError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -137,8 +136,8 @@ func (c *WorkflowController) UpdateWorkflowType(ctx *gin.Context) {
 // DeleteWorkflowType 删除流程类型
 func (c *WorkflowController) DeleteWorkflowType(ctx *gin.Context) {
 	id, _ := strconv.ParseUint(ctx.Param("id"), 10, 32)
-	if err := c.workflowService.DeleteWorkflowType(uint(id)); err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	if err := c.workflowService.WithContext(ctx.Request.Context()).DeleteWorkflowType(uint(id)); err != nil {
+		respondError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -153,9 +152,9 @@ func (c *WorkflowController) GetWorkflowDefinitionList(ctx *gin.Context) {
 	page, _ := strconv.Atoi(ctx.DefaultQuery("page", "1"))
 	pageSize, _ := strconv.Atoi(ctx.DefaultQuery("page_size", "10"))
 
-	definitions, total, err := c.workflowService.GetWorkflowDefinitionList(uint(typeID), status, keyword, page, pageSize)
+	definitions, total, err := c.workflowService.WithContext(ctx.Request.Context()).GetWorkflowDefinitionList(uint(typeID), status, keyword, page, pageSize)
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -168,9 +167,9 @@ func (c *WorkflowController) GetWorkflowDefinitionList(ctx *gin.Context) {
 // GetWorkflowDefinitionByID 根据ID获取流程定义
 func (c *WorkflowController) GetWorkflowDefinitionByID(ctx *gin.Context) {
 	id, _ := strconv.ParseUint(ctx.Param("id"), 10, 32)
-	definition, err := c.workflowService.GetWorkflowDefinitionByID(uint(id))
+	definition, err := c.workflowService.WithContext(ctx.Request.Context()).GetWorkflowDefinitionByID(uint(id))
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -181,15 +180,12 @@ func (c *WorkflowController) GetWorkflowDefinitionByID(ctx *gin.Context) {
 func (c *WorkflowController) CreateWorkflowDefinition(ctx *gin.Context) {
 	var definition model.WorkflowDefinition
 	if err := ctx.ShouldBindJSON(&definition); err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		respondError(ctx, http.StatusBadRequest, err)
 		return
 	}
 
-	// TODO: 从JWT中获取当前用户ID
-	definition.CreatedBy = uint(1)
-
-	if err := c.workflowService.CreateWorkflowDefinition(&definition); err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	if err := c.workflowService.WithContext(ctx.Request.Context()).CreateWorkflowDefinition(&definition); err != nil {
+		respondError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -201,13 +197,13 @@ func (c *WorkflowController) UpdateWorkflowDefinition(ctx *gin.Context) {
 	id, _ := strconv.ParseUint(ctx.Param("id"), 10, 32)
 	var definition model.WorkflowDefinition
 	if err := ctx.ShouldBindJSON(&definition); err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		respondError(ctx, http.StatusBadRequest, err)
 		return
 	}
 
 	definition.ID = uint(id)
-	if err := c.workflowService.UpdateWorkflowDefinition(&definition); err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	if err := c.workflowService.WithContext(ctx.Request.Context()).UpdateWorkflowDefinition(&definition); err != nil {
+		respondError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -217,8 +213,8 @@ func (c *WorkflowController) UpdateWorkflowDefinition(ctx *gin.Context) {
 // DeleteWorkflowDefinition 删除流程定义
 func (c *WorkflowController) DeleteWorkflowDefinition(ctx *gin.Context) {
 	id, _ := strconv.ParseUint(ctx.Param("id"), 10, 32)
-	if err := c.workflowService.DeleteWorkflowDefinition(uint(id)); err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	if err := c.workflowService.WithContext(ctx.Request.Context()).DeleteWorkflowDefinition(uint(id)); err != nil {
+		respondError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -228,8 +224,8 @@ func (c *WorkflowController) DeleteWorkflowDefinition(ctx *gin.Context) {
 // PublishWorkflowDefinition 发布流程定义
 func (c *WorkflowController) PublishWorkflowDefinition(ctx *gin.Context) {
 	id, _ := strconv.ParseUint(ctx.Param("id"), 10, 32)
-	if err := c.workflowService.PublishWorkflowDefinition(uint(id)); err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	if err := c.workflowService.WithContext(ctx.Request.Context()).PublishWorkflowDefinition(uint(id)); err != nil {
+		respondError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -239,8 +235,8 @@ func (c *WorkflowController) PublishWorkflowDefinition(ctx *gin.Context) {
 // DisableWorkflowDefinition 停用流程定义
 func (c *WorkflowController) DisableWorkflowDefinition(ctx *gin.Context) {
 	id, _ := strconv.ParseUint(ctx.Param("id"), 10, 32)
-	if err := c.workflowService.DisableWorkflowDefinition(uint(id)); err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	if err := c.workflowService.WithContext(ctx.Request.Context()).DisableWorkflowDefinition(uint(id)); err != nil {
+		respondError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -250,9 +246,9 @@ func (c *WorkflowController) DisableWorkflowDefinition(ctx *gin.Context) {
 // GetWorkflowNodeList 获取流程节点列表
 func (c *WorkflowController) GetWorkflowNodeList(ctx *gin.Context) {
 	id, _ := strconv.ParseUint(ctx.Param("id"), 10, 32)
-	nodes, err := c.workflowService.GetWorkflowNodeList(uint(id))
+	nodes, err := c.workflowService.WithContext(ctx.Request.Context()).GetWorkflowNodeList(uint(id))
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -262,9 +258,9 @@ func (c *WorkflowController) GetWorkflowNodeList(ctx *gin.Context) {
 // GetWorkflowNodeByID 根据ID获取流程节点
 func (c *WorkflowController) GetWorkflowNodeByID(ctx *gin.Context) {
 	id, _ := strconv.ParseUint(ctx.Param("id"), 10, 32)
-	node, err := c.workflowService.GetWorkflowNodeByID(uint(id))
+	node, err := c.workflowService.WithContext(ctx.Request.Context()).GetWorkflowNodeByID(uint(id))
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -275,15 +271,12 @@ func (c *WorkflowController) GetWorkflowNodeByID(ctx *gin.Context) {
 func (c *WorkflowController) CreateWorkflowNode(ctx *gin.Context) {
 	var node model.WorkflowNode
 	if err := ctx.ShouldBindJSON(&node); err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		respondError(ctx, http.StatusBadRequest, err)
 		return
 	}
 
-	// TODO: 从JWT中获取当前用户ID
-	node.CreatedBy = uint(1)
-
-	if err := c.workflowService.CreateWorkflowNode(&node); err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	if err := c.workflowService.WithContext(ctx.Request.Context()).CreateWorkflowNode(&node); err != nil {
+		respondError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -295,13 +288,13 @@ func (c *WorkflowController) UpdateWorkflowNode(ctx *gin.Context) {
 	id, _ := strconv.ParseUint(ctx.Param("id"), 10, 32)
 	var node model.WorkflowNode
 	if err := ctx.ShouldBindJSON(&node); err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		respondError(ctx, http.StatusBadRequest, err)
 		return
 	}
 
 	node.ID = uint(id)
-	if err := c.workflowService.UpdateWorkflowNode(&node); err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	if err := c.workflowService.WithContext(ctx.Request.Context()).UpdateWorkflowNode(&node); err != nil {
+		respondError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -311,8 +304,8 @@ func (c *WorkflowController) UpdateWorkflowNode(ctx *gin.Context) {
 // DeleteWorkflowNode 删除流程节点
 func (c *WorkflowController) DeleteWorkflowNode(ctx *gin.Context) {
 	id, _ := strconv.ParseUint(ctx.Param("id"), 10, 32)
-	if err := c.workflowService.DeleteWorkflowNode(uint(id)); err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	if err := c.workflowService.WithContext(ctx.Request.Context()).DeleteWorkflowNode(uint(id)); err != nil {
+		respondError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -327,9 +320,9 @@ func (c *WorkflowController) GetWorkflowInstanceList(ctx *gin.Context) {
 	page, _ := strconv.Atoi(ctx.DefaultQuery("page", "1"))
 	pageSize, _ := strconv.Atoi(ctx.DefaultQuery("page_size", "10"))
 
-	instances, total, err := c.workflowService.GetWorkflowInstanceList(uint(definitionID), status, keyword, page, pageSize)
+	instances, total, err := c.workflowService.WithContext(ctx.Request.Context()).GetWorkflowInstanceList(uint(definitionID), status, keyword, page, pageSize)
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -342,9 +335,9 @@ func (c *WorkflowController) GetWorkflowInstanceList(ctx *gin.Context) {
 // GetWorkflowInstanceByID 根据ID获取流程实例
 func (c *WorkflowController) GetWorkflowInstanceByID(ctx *gin.Context) {
 	id, _ := strconv.ParseUint(ctx.Param("id"), 10, 32)
-	instance, err := c.workflowService.GetWorkflowInstanceByID(uint(id))
+	instance, err := c.workflowService.WithContext(ctx.Request.Context()).GetWorkflowInstanceByID(uint(id))
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -355,15 +348,12 @@ func (c *WorkflowController) GetWorkflowInstanceByID(ctx *gin.Context) {
 func (c *WorkflowController) CreateWorkflowInstance(ctx *gin.Context) {
 	var instance model.WorkflowInstance
 	if err := ctx.ShouldBindJSON(&instance); err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		respondError(ctx, http.StatusBadRequest, err)
 		return
 	}
 
-	// TODO: 从JWT中获取当前用户ID
-	instance.CreatedBy = uint(1)
-
-	if err := c.workflowService.CreateWorkflowInstance(&instance); err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	if err := c.workflowService.WithContext(ctx.Request.Context()).CreateWorkflowInstance(&instance); err != nil {
+		respondError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -375,13 +365,13 @@ func (c *WorkflowController) UpdateWorkflowInstance(ctx *gin.Context) {
 	id, _ := strconv.ParseUint(ctx.Param("id"), 10, 32)
 	var instance model.WorkflowInstance
 	if err := ctx.ShouldBindJSON(&instance); err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		respondError(ctx, http.StatusBadRequest, err)
 		return
 	}
 
 	instance.ID = uint(id)
-	if err := c.workflowService.UpdateWorkflowInstance(&instance); err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	if err := c.workflowService.WithContext(ctx.Request.Context()).UpdateWorkflowInstance(&instance); err != nil {
+		respondError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -391,8 +381,8 @@ func (c *WorkflowController) UpdateWorkflowInstance(ctx *gin.Context) {
 // DeleteWorkflowInstance 删除流程实例
 func (c *WorkflowController) DeleteWorkflowInstance(ctx *gin.Context) {
 	id, _ := strconv.ParseUint(ctx.Param("id"), 10, 32)
-	if err := c.workflowService.DeleteWorkflowInstance(uint(id)); err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	if err := c.workflowService.WithContext(ctx.Request.Context()).DeleteWorkflowInstance(uint(id)); err != nil {
+		respondError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -402,8 +392,8 @@ func (c *WorkflowController) DeleteWorkflowInstance(ctx *gin.Context) {
 // CancelWorkflowInstance 取消流程实例
 func (c *WorkflowController) CancelWorkflowInstance(ctx *gin.Context) {
 	id, _ := strconv.ParseUint(ctx.Param("id"), 10, 32)
-	if err := c.workflowService.CancelWorkflowInstance(uint(id)); err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	if err := c.workflowService.WithContext(ctx.Request.Context()).CancelWorkflowInstance(uint(id)); err != nil {
+		respondError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -418,9 +408,9 @@ func (c *WorkflowController) GetWorkflowTaskList(ctx *gin.Context) {
 	page, _ := strconv.Atoi(ctx.DefaultQuery("page", "1"))
 	pageSize, _ := strconv.Atoi(ctx.DefaultQuery("page_size", "10"))
 
-	tasks, total, err := c.workflowService.GetWorkflowTaskList(uint(instanceID), uint(assigneeID), status, page, pageSize)
+	tasks, total, err := c.workflowService.WithContext(ctx.Request.Context()).GetWorkflowTaskList(uint(instanceID), uint(assigneeID), status, page, pageSize)
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -433,9 +423,9 @@ func (c *WorkflowController) GetWorkflowTaskList(ctx *gin.Context) {
 // GetWorkflowTaskByID 根据ID获取流程任务
 func (c *WorkflowController) GetWorkflowTaskByID(ctx *gin.Context) {
 	id, _ := strconv.ParseUint(ctx.Param("id"), 10, 32)
-	task, err := c.workflowService.GetWorkflowTaskByID(uint(id))
+	task, err := c.workflowService.WithContext(ctx.Request.Context()).GetWorkflowTaskByID(uint(id))
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -446,12 +436,12 @@ func (c *WorkflowController) GetWorkflowTaskByID(ctx *gin.Context) {
 func (c *WorkflowController) CreateWorkflowTask(ctx *gin.Context) {
 	var task model.WorkflowTask
 	if err := ctx.ShouldBindJSON(&task); err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		respondError(ctx, http.StatusBadRequest, err)
 		return
 	}
 
-	if err := c.workflowService.CreateWorkflowTask(&task); err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	if err := c.workflowService.WithContext(ctx.Request.Context()).CreateWorkflowTask(&task); err != nil {
+		respondError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -463,13 +453,13 @@ func (c *WorkflowController) UpdateWorkflowTask(ctx *gin.Context) {
 	id, _ := strconv.ParseUint(ctx.Param("id"), 10, 32)
 	var task model.WorkflowTask
 	if err := ctx.ShouldBindJSON(&task); err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		respondError(ctx, http.StatusBadRequest, err)
 		return
 	}
 
 	task.ID = uint(id)
-	if err := c.workflowService.UpdateWorkflowTask(&task); err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	if err := c.workflowService.WithContext(ctx.Request.Context()).UpdateWorkflowTask(&task); err != nil {
+		respondError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -479,8 +469,8 @@ func (c *WorkflowController) UpdateWorkflowTask(ctx *gin.Context) {
 // DeleteWorkflowTask 删除流程任务
 func (c *WorkflowController) DeleteWorkflowTask(ctx *gin.Context) {
 	id, _ := strconv.ParseUint(ctx.Param("id"), 10, 32)
-	if err := c.workflowService.DeleteWorkflowTask(uint(id)); err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	if err := c.workflowService.WithContext(ctx.Request.Context()).DeleteWorkflowTask(uint(id)); err != nil {
+		respondError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -495,12 +485,12 @@ func (c *WorkflowController) HandleWorkflowTask(ctx *gin.Context) {
 		Comment string `json:"comment"`
 	}
 	if err := ctx.ShouldBindJSON(&data); err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		respondError(ctx, http.StatusBadRequest, err)
 		return
 	}
 
-	if err := c.workflowService.HandleWorkflowTask(uint(id), data.Action, data.Comment); err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	if err := c.workflowService.WithContext(ctx.Request.Context()).HandleWorkflowTask(uint(id), data.Action, data.Comment); err != nil {
+		respondError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -514,12 +504,12 @@ func (c *WorkflowController) TransferWorkflowTask(ctx *gin.Context) {
 		AssigneeID uint `json:"assignee_id" binding:"required"`
 	}
 	if err := ctx.ShouldBindJSON(&data); err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		respondError(ctx, http.StatusBadRequest, err)
 		return
 	}
 
-	if err := c.workflowService.TransferWorkflowTask(uint(id), data.AssigneeID); err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	if err := c.workflowService.WithContext(ctx.Request.Context()).TransferWorkflowTask(uint(id), data.AssigneeID); err != nil {
+		respondError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -0,0 +1,84 @@
+package database
+
+import (
+	"context"
+	"reflect"
+
+	"gorm.io/gorm"
+)
+
+type actorKey struct{}
+
+// WithActor 将当前操作用户存储在上下文中，经 db.WithContext 传入后由数据库回调填充创建人和更新人
+func WithActor(ctx context.Context, userID uint) context.Context {
+	return context.WithValue(ctx, actorKey{}, userID)
+}
+
+// ActorFromContext 获取上下文中的当前操作用户，没有时为0
+func ActorFromContext(ctx context.Context) uint {
+	if ctx == nil {
+		return 0
+	}
+	userID, _ := ctx.Value(actorKey{}).(uint)
+	return userID
+}
+
+// 注册填充操作人的回调：创建时 CreatedBy 和 UpdatedBy 取当前操作用户，忽略请求中传入的值；
+// 更新时填充 UpdatedBy，且不允许修改 CreatedBy。上下文中没有操作用户时(如后台任务)保持原值
+func registerActorCallbacks(db *gorm.DB) error {
+	if err := db.Callback().Create().Before("gorm:create").Register("lemonoa:actor_create", fillCreatedBy); err != nil {
+		return err
+	}
+	return db.Callback().Update().Before("gorm:update").Register("lemonoa:actor_update", fillUpdatedBy)
+}
+
+func fillCreatedBy(db *gorm.DB) {
+	stmt := db.Statement
+	if stmt.Schema == nil {
+		return
+	}
+	actor := ActorFromContext(stmt.Context)
+	if actor == 0 {
+		return
+	}
+
+	for _, name := range []string{"CreatedBy", "UpdatedBy"} {
+		field := stmt.Schema.LookUpField(name)
+		if field == nil {
+			continue
+		}
+		switch stmt.ReflectValue.Kind() {
+		case reflect.Slice, reflect.Array:
+			for i := 0; i < stmt.ReflectValue.Len(); i++ {
+				if err := field.Set(stmt.Context, stmt.ReflectValue.Index(i), actor); err != nil {
+					db.AddError(err)
+					return
+				}
+			}
+		case reflect.Struct:
+			if err := field.Set(stmt.Context, stmt.ReflectValue, actor); err != nil {
+				db.AddError(err)
+				return
+			}
+		}
+	}
+}
+
+func fillUpdatedBy(db *gorm.DB) {
+	stmt := db.Statement
+	if stmt.Schema == nil {
+		return
+	}
+	actor := ActorFromContext(stmt.Context)
+	if actor == 0 {
+		return
+	}
+
+	// 更新时以请求参数整体保存的结构体可能带有篡改的创建人
+	if field := stmt.Schema.LookUpField("CreatedBy"); field != nil {
+		stmt.Omits = append(stmt.Omits, field.DBName)
+	}
+	if field := stmt.Schema.LookUpField("UpdatedBy"); field != nil {
+		stmt.SetColumn(field.DBName, actor, true)
+	}
+}
@@ -0,0 +1,2235 @@
+// Package baseline 基线迁移（版本1）的数据表结构快照，按 v1 发布时的 model 包冻结
+// 此后的结构变更只能通过新增迁移版本完成，不得修改本包中的定义
+package baseline
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Models 基线版本的全部模型，顺序与建表顺序一致
+func Models() []interface{} {
+	return []interface{}{
+		// 认证管理
+		&User{},
+		&UserRole{},
+		&Permission{},
+		&RolePermission{},
+		&LoginLog{},
+		&RefreshToken{},
+		&RevokedToken{},
+		&UserRecoveryCode{},
+		&RoleDepartment{},
+		&ApiKey{},
+		&PasswordHistory{},
+		&UserSession{},
+		&OIDCLoginState{},
+
+		// 系统管理
+		&SystemConfig{},
+		&Module{},
+		&ModuleConfig{},
+		&Role{},
+		&OperationLog{},
+		&Attachment{},
+		&AttachmentUpload{},
+		&BackupRecord{},
+		&ScheduledTask{},
+		&ScheduledTaskRun{},
+
+		// 工作台
+		&Department{},
+		&Employee{},
+		&Notification{},
+		&ApprovalType{},
+		&ApprovalFlow{},
+		&ApprovalNode{},
+		&ApprovalRecord{},
+		&ApprovalNodeRecord{},
+		&Todo{},
+
+		// 工作流
+		&WorkflowType{},
+		&WorkflowDefinition{},
+		&WorkflowNode{},
+		&WorkflowInstance{},
+		&WorkflowTask{},
+
+		// 公告
+		&Notice{},
+		&NoticeRead{},
+
+		// 人事管理
+		&Position{},
+		&EmployeeArchive{},
+		&RewardPunishmentRecord{},
+		&CareRecord{},
+		&Transfer{},
+		&Resignation{},
+		&Contract{},
+		&Probation{},
+
+		// 考勤管理
+		&AttendanceRule{},
+		&AttendanceRecord{},
+		&LeaveApplication{},
+		&OvertimeApplication{},
+		&BusinessTripApplication{},
+
+		// 固定资产管理
+		&Asset{},
+		&AssetRepair{},
+		&AssetBorrow{},
+		&AssetDisposal{},
+
+		// 车辆管理
+		&Vehicle{},
+		&VehicleRepair{},
+		&VehicleMaintenance{},
+		&VehicleMileage{},
+		&VehicleExpenseRecord{},
+		&VehicleViolation{},
+		&VehicleAccident{},
+		&VehicleApplication{},
+		&VehicleReturn{},
+
+		// 会议室管理
+		&MeetingRoom{},
+		&MeetingReservation{},
+		&MeetingMinutes{},
+		&MeetingRoomMaintenance{},
+
+		// 印章管理
+		&Seal{},
+		&SealApplication{},
+		&SealRecord{},
+
+		// 文档管理
+		&DocumentType{},
+		&Document{},
+		&DocumentApproval{},
+		&DocumentDistribution{},
+		&DocumentArchive{},
+		&DocumentBorrow{},
+
+		// 基础数据-公共模块
+		&Enterprise{},
+		&Region{},
+		&MessageTemplate{},
+
+		// 基础数据-人事模块
+		&RewardPunishment{},
+		&CareProject{},
+		&CommonData{},
+
+		// 基础数据-行政模块
+		&AssetCategory{},
+		&AssetBrand{},
+		&AssetUnit{},
+		&SealType{},
+		&VehicleExpense{},
+		&NoticeType{},
+
+		// 基础数据-财务模块
+		&ExpenseType{},
+
+		// 基础数据-客户模块
+		&CustomerLevel{},
+		&CustomerChannel{},
+		&Industry{},
+		&CustomerStatus{},
+		&CustomerIntention{},
+		&FollowUpMethod{},
+		&SalesStage{},
+
+		// 基础数据-合同模块
+		&ContractCategory{},
+		&ProductCategory{},
+		&Product{},
+		&ServiceContent{},
+		&Supplier{},
+		&PurchaseCategory{},
+		&PurchaseItem{},
+
+		// 基础数据-项目模块
+		&ProjectStage{},
+		&ProjectCategory{},
+		&WorkType{},
+	}
+} // ApprovalType 审批类型
+type ApprovalType struct {
+	ID        uint           `gorm:"primarykey" json:"id"`
+	Name      string         `gorm:"size:50;not null" json:"name"`
+	Code      string         `gorm:"size:50;not null;unique" json:"code"`
+	Sort      int            `gorm:"default:0" json:"sort"`
+	Status    int            `gorm:"default:1" json:"status"` // 1:启用 2:禁用
+	CreatedAt time.Time      `json:"created_at"`
+	UpdatedAt time.Time      `json:"updated_at"`
+	DeletedAt gorm.DeletedAt `gorm:"index" json:"deleted_at"`
+}
+
+// ApprovalFlow 审批流程
+type ApprovalFlow struct {
+	ID             uint           `gorm:"primarykey" json:"id"`
+	ApprovalTypeID uint           `gorm:"not null" json:"approval_type_id"`
+	Name           string         `gorm:"size:50;not null" json:"name"`
+	Description    string         `gorm:"size:255" json:"description"`
+	Status         int            `gorm:"default:1" json:"status"` // 1:启用 2:禁用
+	CreatedAt      time.Time      `json:"created_at"`
+	UpdatedAt      time.Time      `json:"updated_at"`
+	DeletedAt      gorm.DeletedAt `gorm:"index" json:"deleted_at"`
+}
+
+// ApprovalNode 审批节点
+type ApprovalNode struct {
+	ID             uint           `gorm:"primarykey" json:"id"`
+	ApprovalFlowID uint           `gorm:"not null" json:"approval_flow_id"`
+	Name           string         `gorm:"size:50;not null" json:"name"`
+	Type           int            `gorm:"not null" json:"type"` // 1:指定人员 2:指定角色 3:指定部门负责人
+	ApproverID     *uint          `json:"approver_id"`          // 指定人员ID
+	RoleID         *uint          `json:"role_id"`              // 指定角色ID
+	Sort           int            `gorm:"default:0" json:"sort"`
+	CreatedAt      time.Time      `json:"created_at"`
+	UpdatedAt      time.Time      `json:"updated_at"`
+	DeletedAt      gorm.DeletedAt `gorm:"index" json:"deleted_at"`
+}
+
+// ApprovalRecord 审批记录
+type ApprovalRecord struct {
+	ID             uint           `gorm:"primarykey" json:"id"`
+	ApprovalFlowID uint           `gorm:"not null" json:"approval_flow_id"`
+	Title          string         `gorm:"size:100;not null" json:"title"`
+	Content        string         `gorm:"type:text" json:"content"`
+	Status         int            `gorm:"default:1" json:"status"` // 1:待审批 2:审批中 3:已通过 4:已驳回
+	ApplicantID    uint           `gorm:"not null" json:"applicant_id"`
+	CurrentNodeID  uint           `gorm:"not null" json:"current_node_id"`
+	CreatedAt      time.Time      `json:"created_at"`
+	UpdatedAt      time.Time      `json:"updated_at"`
+	DeletedAt      gorm.DeletedAt `gorm:"index" json:"deleted_at"`
+}
+
+// ApprovalNodeRecord 审批节点记录
+type ApprovalNodeRecord struct {
+	ID               uint           `gorm:"primarykey" json:"id"`
+	ApprovalRecordID uint           `gorm:"not null" json:"approval_record_id"`
+	ApprovalNodeID   uint           `gorm:"not null" json:"approval_node_id"`
+	ApproverID       uint           `gorm:"not null" json:"approver_id"`
+	Status           int            `gorm:"default:1" json:"status"` // 1:待审批 2:已通过 3:已驳回
+	Comment          string         `gorm:"type:text" json:"comment"`
+	CreatedAt        time.Time      `json:"created_at"`
+	UpdatedAt        time.Time      `json:"updated_at"`
+	DeletedAt        gorm.DeletedAt `gorm:"index" json:"deleted_at"`
+}
+
+// TableName 指定表名
+func (ApprovalType) TableName() string {
+	return "approval_types"
+}
+
+func (ApprovalFlow) TableName() string {
+	return "approval_flows"
+}
+
+func (ApprovalNode) TableName() string {
+	return "approval_nodes"
+}
+
+func (ApprovalRecord) TableName() string {
+	return "approval_records"
+}
+
+func (ApprovalNodeRecord) TableName() string {
+	return "approval_node_records"
+}
+
+// Asset 固定资产
+type Asset struct {
+	ID           uint           `gorm:"primarykey" json:"id"`
+	Name         string         `gorm:"size:100;not null" json:"name"`   // 资产名称
+	Code         string         `gorm:"size:50;unique" json:"code"`      // 资产编号
+	CategoryID   uint           `gorm:"not null" json:"category_id"`     // 资产分类ID
+	BrandID      uint           `gorm:"not null" json:"brand_id"`        // 品牌ID
+	Model        string         `gorm:"size:100" json:"model"`           // 规格型号
+	UnitID       uint           `gorm:"not null" json:"unit_id"`         // 单位ID
+	Price        float64        `gorm:"type:decimal(10,2)" json:"price"` // 采购价格
+	PurchaseDate *time.Time     `json:"purchase_date"`                   // 购买日期
+	WarrantyDate *time.Time     `json:"warranty_date"`                   // 保修期限
+	Status       int            `gorm:"default:1" json:"status"`         // 1:闲置 2:在用 3:维修中 4:报废
+	UserID       *uint          `json:"user_id"`                         // 使用人ID
+	DepartmentID *uint          `json:"department_id"`                   // 使用部门ID
+	Location     string         `gorm:"size:255" json:"location"`        // 存放位置
+	Description  string         `gorm:"size:500" json:"description"`     // 资产描述
+	Files        string         `gorm:"type:text" json:"files"`          // 附件，JSON数组
+	Remark       string         `gorm:"size:500" json:"remark"`          // 备注
+	CreatedBy    uint           `gorm:"not null" json:"created_by"`      // 创建人ID
+	CreatedAt    time.Time      `json:"created_at"`
+	UpdatedAt    time.Time      `json:"updated_at"`
+	DeletedAt    gorm.DeletedAt `gorm:"index" json:"deleted_at"`
+}
+
+// AssetRepair 资产维修记录
+type AssetRepair struct {
+	ID          uint           `gorm:"primarykey" json:"id"`
+	AssetID     uint           `gorm:"not null" json:"asset_id"`       // 资产ID
+	Type        int            `gorm:"not null" json:"type"`           // 1:内部维修 2:外部维修
+	Reason      string         `gorm:"size:500" json:"reason"`         // 维修原因
+	Description string         `gorm:"size:500" json:"description"`    // 维修说明
+	StartDate   *time.Time     `json:"start_date"`                     // 维修开始日期
+	EndDate     *time.Time     `json:"end_date"`                       // 维修结束日期
+	Cost        float64        `gorm:"type:decimal(10,2)" json:"cost"` // 维修费用
+	RepairBy    string         `gorm:"size:100" json:"repair_by"`      // 维修人/维修单位
+	Status      int            `gorm:"default:1" json:"status"`        // 1:待维修 2:维修中 3:已完成
+	Result      string         `gorm:"size:500" json:"result"`         // 维修结果
+	Files       string         `gorm:"type:text" json:"files"`         // 附件，JSON数组
+	Remark      string         `gorm:"size:500" json:"remark"`         // 备注
+	CreatedBy   uint           `gorm:"not null" json:"created_by"`     // 创建人ID
+	CreatedAt   time.Time      `json:"created_at"`
+	UpdatedAt   time.Time      `json:"updated_at"`
+	DeletedAt   gorm.DeletedAt `gorm:"index" json:"deleted_at"`
+}
+
+// AssetBorrow 资产领用记录
+type AssetBorrow struct {
+	ID           uint           `gorm:"primarykey" json:"id"`
+	AssetID      uint           `gorm:"not null" json:"asset_id"`      // 资产ID
+	BorrowerID   uint           `gorm:"not null" json:"borrower_id"`   // 借用人ID
+	DepartmentID uint           `gorm:"not null" json:"department_id"` // 借用部门ID
+	Purpose      string         `gorm:"size:500" json:"purpose"`       // 借用用途
+	BorrowDate   *time.Time     `json:"borrow_date"`                   // 借用日期
+	ReturnDate   *time.Time     `json:"return_date"`                   // 归还日期
+	Status       int            `gorm:"default:1" json:"status"`       // 1:已借出 2:已归还 3:已逾期
+	Files        string         `gorm:"type:text" json:"files"`        // 附件，JSON数组
+	Remark       string         `gorm:"size:500" json:"remark"`        // 备注
+	CreatedBy    uint           `gorm:"not null" json:"created_by"`    // 创建人ID
+	CreatedAt    time.Time      `json:"created_at"`
+	UpdatedAt    time.Time      `json:"updated_at"`
+	DeletedAt    gorm.DeletedAt `gorm:"index" json:"deleted_at"`
+}
+
+// AssetDisposal 资产报废记录
+type AssetDisposal struct {
+	ID               uint           `gorm:"primarykey" json:"id"`
+	AssetID          uint           `gorm:"not null" json:"asset_id"`         // 资产ID
+	Reason           string         `gorm:"size:500" json:"reason"`           // 报废原因
+	Method           int            `gorm:"not null" json:"method"`           // 1:销毁 2:捐赠 3:转卖
+	Amount           float64        `gorm:"type:decimal(10,2)" json:"amount"` // 处置金额
+	DisposalDate     *time.Time     `json:"disposal_date"`                    // 报废日期
+	Status           int            `gorm:"default:1" json:"status"`          // 1:待审批 2:已通过 3:已驳回 4:已取消
+	ApprovalRecordID *uint          `json:"approval_record_id"`               // 关联的审批记录ID
+	Files            string         `gorm:"type:text" json:"files"`           // 附件，JSON数组
+	Remark           string         `gorm:"size:500" json:"remark"`           // 备注
+	CreatedBy        uint           `gorm:"not null" json:"created_by"`       // 创建人ID
+	CreatedAt        time.Time      `json:"created_at"`
+	UpdatedAt        time.Time      `json:"updated_at"`
+	DeletedAt        gorm.DeletedAt `gorm:"index" json:"deleted_at"`
+}
+
+// TableName 指定表名
+func (Asset) TableName() string {
+	return "assets"
+}
+
+func (AssetRepair) TableName() string {
+	return "asset_repairs"
+}
+
+func (AssetBorrow) TableName() string {
+	return "asset_borrows"
+}
+
+func (AssetDisposal) TableName() string {
+	return "asset_disposals"
+}
+
+// AttendanceRule 考勤规则
+type AttendanceRule struct {
+	ID             uint           `gorm:"primarykey" json:"id"`
+	Name           string         `gorm:"size:50;not null" json:"name"`           // 规则名称
+	WorkStartTime  string         `gorm:"size:5;not null" json:"work_start_time"` // 上班时间，格式：HH:mm
+	WorkEndTime    string         `gorm:"size:5;not null" json:"work_end_time"`   // 下班时间，格式：HH:mm
+	LateMinutes    int            `gorm:"default:0" json:"late_minutes"`          // 迟到判定分钟数
+	EarlyMinutes   int            `gorm:"default:0" json:"early_minutes"`         // 早退判定分钟数
+	RestStartTime  string         `gorm:"size:5" json:"rest_start_time"`          // 休息开始时间，格式：HH:mm
+	RestEndTime    string         `gorm:"size:5" json:"rest_end_time"`            // 休息结束时间，格式：HH:mm
+	WorkDays       string         `gorm:"size:20;not null" json:"work_days"`      // 工作日，例如：1,2,3,4,5
+	EffectiveDate  *time.Time     `json:"effective_date"`                         // 生效日期
+	ExpirationDate *time.Time     `json:"expiration_date"`                        // 失效日期
+	Status         int            `gorm:"default:1" json:"status"`                // 1:启用 2:禁用
+	Description    string         `gorm:"size:500" json:"description"`            // 规则说明
+	CreatedBy      uint           `gorm:"not null" json:"created_by"`             // 创建人ID
+	CreatedAt      time.Time      `json:"created_at"`
+	UpdatedAt      time.Time      `json:"updated_at"`
+	DeletedAt      gorm.DeletedAt `gorm:"index" json:"deleted_at"`
+}
+
+// AttendanceRecord 考勤记录
+type AttendanceRecord struct {
+	ID           uint           `gorm:"primarykey" json:"id"`
+	EmployeeID   uint           `gorm:"not null" json:"employee_id"`          // 员工ID
+	Date         *time.Time     `gorm:"not null" json:"date"`                 // 考勤日期
+	CheckInTime  *time.Time     `json:"check_in_time"`                        // 签到时间
+	CheckOutTime *time.Time     `json:"check_out_time"`                       // 签退时间
+	Status       int            `gorm:"default:1" json:"status"`              // 1:正常 2:迟到 3:早退 4:旷工 5:请假 6:出差
+	LateMinutes  int            `gorm:"default:0" json:"late_minutes"`        // 迟到分钟数
+	EarlyMinutes int            `gorm:"default:0" json:"early_minutes"`       // 早退分钟数
+	WorkHours    float64        `gorm:"type:decimal(10,2)" json:"work_hours"` // 工作时长
+	Location     string         `gorm:"size:255" json:"location"`             // 签到/签退地点
+	Remark       string         `gorm:"size:500" json:"remark"`               // 备注
+	CreatedAt    time.Time      `json:"created_at"`
+	UpdatedAt    time.Time      `json:"updated_at"`
+	DeletedAt    gorm.DeletedAt `gorm:"index" json:"deleted_at"`
+}
+
+// LeaveApplication 请假申请
+type LeaveApplication struct {
+	ID               uint           `gorm:"primarykey" json:"id"`
+	EmployeeID       uint           `gorm:"not null" json:"employee_id"`    // 员工ID
+	Type             int            `gorm:"not null" json:"type"`           // 1:事假 2:病假 3:婚假 4:产假 5:丧假
+	StartTime        *time.Time     `gorm:"not null" json:"start_time"`     // 开始时间
+	EndTime          *time.Time     `gorm:"not null" json:"end_time"`       // 结束时间
+	Days             float64        `gorm:"type:decimal(10,2)" json:"days"` // 请假天数
+	Reason           string         `gorm:"size:500" json:"reason"`         // 请假原因
+	Status           int            `gorm:"default:1" json:"status"`        // 1:待审批 2:已通过 3:已驳回 4:已取消
+	ApprovalRecordID *uint          `json:"approval_record_id"`             // 关联的审批记录ID
+	Files            string         `gorm:"type:text" json:"files"`         // 附件，JSON数组
+	Remark           string         `gorm:"size:500" json:"remark"`         // 备注
+	CreatedAt        time.Time      `json:"created_at"`
+	UpdatedAt        time.Time      `json:"updated_at"`
+	DeletedAt        gorm.DeletedAt `gorm:"index" json:"deleted_at"`
+}
+
+// OvertimeApplication 加班申请
+type OvertimeApplication struct {
+	ID               uint           `gorm:"primarykey" json:"id"`
+	EmployeeID       uint           `gorm:"not null" json:"employee_id"`     // 员工ID
+	Type             int            `gorm:"not null" json:"type"`            // 1:工作日加班 2:休息日加班 3:节假日加班
+	StartTime        *time.Time     `gorm:"not null" json:"start_time"`      // 开始时间
+	EndTime          *time.Time     `gorm:"not null" json:"end_time"`        // 结束时间
+	Hours            float64        `gorm:"type:decimal(10,2)" json:"hours"` // 加班小时数
+	Reason           string         `gorm:"size:500" json:"reason"`          // 加班原因
+	Status           int            `gorm:"default:1" json:"status"`         // 1:待审批 2:已通过 3:已驳回 4:已取消
+	ApprovalRecordID *uint          `json:"approval_record_id"`              // 关联的审批记录ID
+	Files            string         `gorm:"type:text" json:"files"`          // 附件，JSON数组
+	Remark           string         `gorm:"size:500" json:"remark"`          // 备注
+	CreatedAt        time.Time      `json:"created_at"`
+	UpdatedAt        time.Time      `json:"updated_at"`
+	DeletedAt        gorm.DeletedAt `gorm:"index" json:"deleted_at"`
+}
+
+// BusinessTripApplication 出差申请
+type BusinessTripApplication struct {
+	ID               uint           `gorm:"primarykey" json:"id"`
+	EmployeeID       uint           `gorm:"not null" json:"employee_id"`    // 员工ID
+	Destination      string         `gorm:"size:255" json:"destination"`    // 目的地
+	StartTime        *time.Time     `gorm:"not null" json:"start_time"`     // 开始时间
+	EndTime          *time.Time     `gorm:"not null" json:"end_time"`       // 结束时间
+	Days             float64        `gorm:"type:decimal(10,2)" json:"days"` // 出差天数
+	Purpose          string         `gorm:"size:500" json:"purpose"`        // 出差目的
+	Status           int            `gorm:"default:1" json:"status"`        // 1:待审批 2:已通过 3:已驳回 4:已取消
+	ApprovalRecordID *uint          `json:"approval_record_id"`             // 关联的审批记录ID
+	Files            string         `gorm:"type:text" json:"files"`         // 附件，JSON数组
+	Remark           string         `gorm:"size:500" json:"remark"`         // 备注
+	CreatedAt        time.Time      `json:"created_at"`
+	UpdatedAt        time.Time      `json:"updated_at"`
+	DeletedAt        gorm.DeletedAt `gorm:"index" json:"deleted_at"`
+}
+
+// TableName 指定表名
+func (AttendanceRule) TableName() string {
+	return "attendance_rules"
+}
+
+func (AttendanceRecord) TableName() string {
+	return "attendance_records"
+}
+
+func (LeaveApplication) TableName() string {
+	return "leave_applications"
+}
+
+func (OvertimeApplication) TableName() string {
+	return "overtime_applications"
+}
+
+func (BusinessTripApplication) TableName() string {
+	return "business_trip_applications"
+}
+
+// User 用户表
+type User struct {
+	ID                 uint           `gorm:"primarykey" json:"id"`
+	Username           string         `gorm:"size:50;not null;unique" json:"username"`                   // 用户名
+	Password           string         `gorm:"size:255;not null" json:"-"`                                // 密码哈希，编码串包含算法及参数
+	Salt               string         `gorm:"size:32;not null" json:"-"`                                 // 旧版SHA-512密码盐值
+	RealName           string         `gorm:"size:50" json:"real_name"`                                  // 真实姓名
+	Avatar             string         `gorm:"size:255" json:"avatar"`                                    // 头像
+	Email              string         `gorm:"size:100" json:"email"`                                     // 邮箱
+	Mobile             string         `gorm:"size:255;serializer:encrypted" json:"mobile" mask:"mobile"` // 手机号，加密存储
+	Type               int            `gorm:"default:1" json:"type"`                                     // 1:普通用户 2:服务账号
+	Source             string         `gorm:"size:20;default:local" json:"source"`                       // 用户来源 local:本地 ldap:LDAP/AD oidc:单点登录
+	Status             int            `gorm:"default:1" json:"status"`                                   // 1:正常 2:禁用
+	LastLoginAt        *time.Time     `json:"last_login_at"`                                             // 最后登录时间
+	LastLoginIP        string         `gorm:"size:50" json:"last_login_ip"`                              // 最后登录IP
+	TokenRevokedAt     *time.Time     `json:"-"`                                                         // token吊销时间，此前签发的token全部失效
+	PasswordChangedAt  *time.Time     `json:"password_changed_at"`                                       // 密码修改时间，用于计算密码有效期
+	MustChangePassword bool           `gorm:"default:false" json:"must_change_password"`                 // 下次登录时必须修改密码
+	EmployeeID         *uint          `gorm:"uniqueIndex" json:"employee_id"`                            // 关联员工ID，用于数据权限的部门归属
+	LoginFailures      int            `gorm:"default:0" json:"login_failures"`                           // 连续登录失败次数
+	LockedUntil        *time.Time     `json:"locked_until"`                                              // 账号锁定截止时间
+	TOTPSecret         string         `gorm:"column:totp_secret;size:64" json:"-"`                       // 两步验证TOTP密钥
+	TOTPEnabled        bool           `gorm:"column:totp_enabled;default:false" json:"totp_enabled"`     // 是否已开启两步验证
+	TOTPLastCounter    int64          `gorm:"column:totp_last_counter;default:0" json:"-"`               // 最后一次使用的TOTP时间步，防止验证码重放
+	CreatedBy          uint           `gorm:"not null" json:"created_by"`                                // 创建人ID
+	CreatedAt          time.Time      `json:"created_at"`
+	UpdatedAt          time.Time      `json:"updated_at"`
+	DeletedAt          gorm.DeletedAt `gorm:"index" json:"deleted_at"`
+}
+
+// UserRole 用户角色关联表
+type UserRole struct {
+	ID        uint           `gorm:"primarykey" json:"id"`
+	UserID    uint           `gorm:"not null" json:"user_id"`    // 用户ID
+	RoleID    uint           `gorm:"not null" json:"role_id"`    // 角色ID
+	CreatedBy uint           `gorm:"not null" json:"created_by"` // 创建人ID
+	CreatedAt time.Time      `json:"created_at"`
+	UpdatedAt time.Time      `json:"updated_at"`
+	DeletedAt gorm.DeletedAt `gorm:"index" json:"deleted_at"`
+}
+
+// Permission 权限表
+type Permission struct {
+	ID          uint           `gorm:"primarykey" json:"id"`
+	Name        string         `gorm:"size:50;not null" json:"name"`        // 权限名称
+	Code        string         `gorm:"size:50;not null;unique" json:"code"` // 权限编码
+	Type        int            `gorm:"not null" json:"type"`                // 1:菜单 2:按钮 3:接口
+	ModuleID    *uint          `gorm:"index" json:"module_id"`              // 所属功能模块ID
+	ParentID    *uint          `json:"parent_id"`                           // 父级ID
+	Path        string         `gorm:"size:200" json:"path"`                // 路由路径，接口类型按路由模板匹配，如 /api/assets/:id
+	Method      string         `gorm:"size:10" json:"method"`               // 请求方法，接口类型为空时匹配所有方法
+	Component   string         `gorm:"size:200" json:"component"`           // 前端组件
+	Icon        string         `gorm:"size:50" json:"icon"`                 // 图标
+	Sort        int            `gorm:"default:0" json:"sort"`               // 排序
+	Status      int            `gorm:"default:1" json:"status"`             // 1:启用 2:禁用
+	Description string         `gorm:"size:200" json:"description"`         // 描述
+	CreatedBy   uint           `gorm:"not null" json:"created_by"`          // 创建人ID
+	CreatedAt   time.Time      `json:"created_at"`
+	UpdatedAt   time.Time      `json:"updated_at"`
+	DeletedAt   gorm.DeletedAt `gorm:"index" json:"deleted_at"`
+}
+
+// RolePermission 角色权限关联表
+type RolePermission struct {
+	ID           uint           `gorm:"primarykey" json:"id"`
+	RoleID       uint           `gorm:"not null" json:"role_id"`       // 角色ID
+	PermissionID uint           `gorm:"not null" json:"permission_id"` // 权限ID
+	CreatedBy    uint           `gorm:"not null" json:"created_by"`    // 创建人ID
+	CreatedAt    time.Time      `json:"created_at"`
+	UpdatedAt    time.Time      `json:"updated_at"`
+	DeletedAt    gorm.DeletedAt `gorm:"index" json:"deleted_at"`
+}
+
+// LoginLog 登录日志
+type LoginLog struct {
+	ID        uint           `gorm:"primarykey" json:"id"`
+	UserID    uint           `gorm:"not null" json:"user_id"`    // 用户ID，用户名不存在时为0
+	IP        string         `gorm:"size:50;index" json:"ip"`    // 登录IP
+	UserAgent string         `gorm:"size:500" json:"user_agent"` // User-Agent
+	Status    int            `gorm:"default:1" json:"status"`    // 1:成功 2:失败
+	Message   string         `gorm:"size:200" json:"message"`    // 失败原因
+	CreatedAt time.Time      `json:"created_at"`
+	UpdatedAt time.Time      `json:"updated_at"`
+	DeletedAt gorm.DeletedAt `gorm:"index" json:"deleted_at"`
+}
+
+// RefreshToken 刷新令牌
+type RefreshToken struct {
+	ID        uint           `gorm:"primarykey" json:"id"`
+	UserID    uint           `gorm:"not null;index" json:"user_id"`           // 用户ID
+	TokenHash string         `gorm:"size:64;not null;uniqueIndex" json:"-"`   // 令牌SHA-256哈希
+	FamilyID  string         `gorm:"size:32;not null;index" json:"family_id"` // 令牌族ID，同一次登录轮换出的令牌共用
+	ExpiresAt time.Time      `gorm:"not null" json:"expires_at"`              // 过期时间
+	RevokedAt *time.Time     `json:"revoked_at"`                              // 吊销时间
+	IP        string         `gorm:"size:50" json:"ip"`                       // 签发时的IP
+	UserAgent string         `gorm:"size:500" json:"user_agent"`              // 签发时的User-Agent
+	CreatedAt time.Time      `json:"created_at"`
+	UpdatedAt time.Time      `json:"updated_at"`
+	DeletedAt gorm.DeletedAt `gorm:"index" json:"deleted_at"`
+}
+
+// UserSession 登录会话，每次登录创建一个会话，与刷新令牌族一一对应
+type UserSession struct {
+	ID         uint           `gorm:"primarykey" json:"id"`
+	UserID     uint           `gorm:"not null;index" json:"user_id"`         // 用户ID
+	SessionID  string         `gorm:"size:32;not null;uniqueIndex" json:"-"` // 会话ID，即刷新令牌族ID，写入访问令牌的sid
+	IP         string         `gorm:"size:50" json:"ip"`                     // 最近访问IP
+	UserAgent  string         `gorm:"size:500" json:"user_agent"`            // 登录时的User-Agent
+	IssuedAt   time.Time      `gorm:"not null" json:"issued_at"`             // 登录时间
+	LastSeenAt time.Time      `gorm:"not null" json:"last_seen_at"`          // 最近活跃时间
+	ExpiresAt  time.Time      `gorm:"not null" json:"expires_at"`            // 过期时间，随刷新令牌延长
+	RevokedAt  *time.Time     `json:"revoked_at"`                            // 吊销时间
+	Current    bool           `gorm:"-" json:"current"`                      // 是否当前请求所属的会话，不存储
+	CreatedAt  time.Time      `json:"created_at"`
+	UpdatedAt  time.Time      `json:"updated_at"`
+	DeletedAt  gorm.DeletedAt `gorm:"index" json:"deleted_at"`
+}
+
+// RevokedToken 已吊销的访问令牌
+type RevokedToken struct {
+	ID        uint      `gorm:"primarykey" json:"id"`
+	JTI       string    `gorm:"size:32;not null;uniqueIndex" json:"jti"` // 令牌ID
+	UserID    uint      `gorm:"not null" json:"user_id"`                 // 用户ID
+	ExpiresAt time.Time `gorm:"not null;index" json:"expires_at"`        // 令牌原过期时间，过期后可清理
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// Role 角色表
+type Role struct {
+	ID          uint           `gorm:"primarykey" json:"id"`
+	Name        string         `gorm:"size:50;not null" json:"name"`                        // 角色名称
+	Code        string         `gorm:"size:50;not null;unique" json:"code"`                 // 角色编码
+	Description string         `gorm:"size:255" json:"description"`                         // 描述
+	Status      int            `gorm:"default:1" json:"status"`                             // 1:启用 2:禁用
+	Require2FA  bool           `gorm:"column:require_2fa;default:false" json:"require_2fa"` // 是否要求该角色的用户开启两步验证
+	DataScope   int            `gorm:"default:1" json:"data_scope"`                         // 数据权限 1:全部数据 2:自定义部门 3:本部门及下级部门 4:本部门 5:仅本人
+	CreatedBy   uint           `gorm:"not null" json:"created_by"`                          // 创建人ID
+	CreatedAt   time.Time      `json:"created_at"`
+	UpdatedAt   time.Time      `json:"updated_at"`
+	DeletedAt   gorm.DeletedAt `gorm:"index" json:"deleted_at"`
+}
+
+// RoleDepartment 角色自定义数据权限部门
+type RoleDepartment struct {
+	ID           uint      `gorm:"primarykey" json:"id"`
+	RoleID       uint      `gorm:"not null;index" json:"role_id"` // 角色ID
+	DepartmentID uint      `gorm:"not null" json:"department_id"` // 部门ID
+	CreatedBy    uint      `gorm:"not null" json:"created_by"`    // 创建人ID
+	CreatedAt    time.Time `json:"created_at"`
+}
+
+// PasswordHistory 历史密码，用于禁止重复使用最近的密码
+type PasswordHistory struct {
+	ID        uint      `gorm:"primarykey" json:"id"`
+	UserID    uint      `gorm:"not null;index" json:"user_id"` // 用户ID
+	Password  string    `gorm:"size:255;not null" json:"-"`    // 密码哈希
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// OIDCLoginState OIDC单点登录的授权请求状态，回调时校验并删除，只能使用一次
+type OIDCLoginState struct {
+	ID           uint      `gorm:"primarykey" json:"id"`
+	State        string    `gorm:"size:64;not null;uniqueIndex" json:"state"` // state参数，防止CSRF
+	Nonce        string    `gorm:"size:64;not null" json:"-"`                 // 写入ID令牌的nonce，防止重放
+	CodeVerifier string    `gorm:"size:128;not null" json:"-"`                // PKCE code_verifier
+	ExpiresAt    time.Time `gorm:"not null;index" json:"expires_at"`          // 过期时间
+	CreatedAt    time.Time `json:"created_at"`
+}
+
+// UserRecoveryCode 两步验证恢复码，每个恢复码只能使用一次
+type UserRecoveryCode struct {
+	ID        uint       `gorm:"primarykey" json:"id"`
+	UserID    uint       `gorm:"not null;index" json:"user_id"` // 用户ID
+	CodeHash  string     `gorm:"size:64;not null" json:"-"`     // 恢复码SHA-256哈希
+	UsedAt    *time.Time `json:"used_at"`                       // 使用时间
+	CreatedAt time.Time  `json:"created_at"`
+}
+
+// ApiKey API密钥，归属于服务账号，供系统集成调用接口
+type ApiKey struct {
+	ID         uint           `gorm:"primarykey" json:"id"`
+	UserID     uint           `gorm:"not null;index" json:"user_id"`         // 所属用户ID
+	Name       string         `gorm:"size:50;not null" json:"name"`          // 名称
+	Prefix     string         `gorm:"size:16;not null" json:"prefix"`        // 密钥前缀，用于识别密钥
+	KeyHash    string         `gorm:"size:64;not null;uniqueIndex" json:"-"` // 密钥SHA-256哈希
+	Scopes     string         `gorm:"size:1000" json:"scopes"`               // 允许使用的权限编码，逗号分隔，为空时与所属用户权限一致
+	ExpiresAt  *time.Time     `json:"expires_at"`                            // 过期时间，为空表示永不过期
+	LastUsedAt *time.Time     `json:"last_used_at"`                          // 最后使用时间
+	LastUsedIP string         `gorm:"size:50" json:"last_used_ip"`           // 最后使用IP
+	RevokedAt  *time.Time     `json:"revoked_at"`                            // 吊销时间
+	CreatedBy  uint           `gorm:"not null" json:"created_by"`            // 创建人ID
+	CreatedAt  time.Time      `json:"created_at"`
+	UpdatedAt  time.Time      `json:"updated_at"`
+	DeletedAt  gorm.DeletedAt `gorm:"index" json:"deleted_at"`
+}
+
+// TableName 指定表名
+func (User) TableName() string {
+	return "users"
+}
+
+func (UserRole) TableName() string {
+	return "user_roles"
+}
+
+func (Permission) TableName() string {
+	return "permissions"
+}
+
+func (RolePermission) TableName() string {
+	return "role_permissions"
+}
+
+func (LoginLog) TableName() string {
+	return "login_logs"
+}
+
+func (RefreshToken) TableName() string {
+	return "refresh_tokens"
+}
+
+func (RevokedToken) TableName() string {
+	return "revoked_tokens"
+}
+
+func (UserRecoveryCode) TableName() string {
+	return "user_recovery_codes"
+}
+
+func (RoleDepartment) TableName() string {
+	return "role_departments"
+}
+
+func (ApiKey) TableName() string {
+	return "api_keys"
+}
+
+func (PasswordHistory) TableName() string {
+	return "password_histories"
+}
+
+func (UserSession) TableName() string {
+	return "user_sessions"
+}
+
+func (OIDCLoginState) TableName() string {
+	return "oidc_login_states"
+}
+
+// Enterprise 企业主体
+type Enterprise struct {
+	ID          uint           `gorm:"primarykey" json:"id"`
+	Name        string         `gorm:"size:100;not null" json:"name"`
+	Code        string         `gorm:"size:50;unique" json:"code"`
+	Address     string         `gorm:"size:255" json:"address"`
+	Phone       string         `gorm:"size:20" json:"phone"`
+	Email       string         `gorm:"size:100" json:"email"`
+	Website     string         `gorm:"size:255" json:"website"`
+	Logo        string         `gorm:"size:255" json:"logo"`
+	Description string         `gorm:"size:500" json:"description"`
+	Status      int            `gorm:"default:1" json:"status"` // 1:正常 2:禁用
+	CreatedAt   time.Time      `json:"created_at"`
+	UpdatedAt   time.Time      `json:"updated_at"`
+	DeletedAt   gorm.DeletedAt `gorm:"index" json:"deleted_at"`
+}
+
+// Region 地区
+type Region struct {
+	ID        uint           `gorm:"primarykey" json:"id"`
+	Name      string         `gorm:"size:50;not null" json:"name"`
+	Code      string         `gorm:"size:20;unique" json:"code"`
+	ParentID  *uint          `json:"parent_id"`
+	Level     int            `gorm:"default:1" json:"level"` // 1:省 2:市 3:区
+	Sort      int            `gorm:"default:0" json:"sort"`
+	CreatedAt time.Time      `json:"created_at"`
+	UpdatedAt time.Time      `json:"updated_at"`
+	DeletedAt gorm.DeletedAt `gorm:"index" json:"deleted_at"`
+}
+
+// MessageTemplate 消息模板
+type MessageTemplate struct {
+	ID        uint           `gorm:"primarykey" json:"id"`
+	Name      string         `gorm:"size:50;not null" json:"name"`
+	Code      string         `gorm:"size:50;unique" json:"code"`
+	Type      int            `gorm:"not null" json:"type"` // 1:邮件 2:短信
+	Content   string         `gorm:"type:text" json:"content"`
+	Params    string         `gorm:"type:text" json:"params"` // JSON格式的参数列表
+	Status    int            `gorm:"default:1" json:"status"` // 1:启用 2:禁用
+	CreatedAt time.Time      `json:"created_at"`
+	UpdatedAt time.Time      `json:"updated_at"`
+	DeletedAt gorm.DeletedAt `gorm:"index" json:"deleted_at"`
+}
+
+// RewardPunishment 奖惩项目
+type RewardPunishment struct {
+	ID          uint           `gorm:"primarykey" json:"id"`
+	Name        string         `gorm:"size:50;not null" json:"name"`
+	Type        int            `gorm:"not null" json:"type"` // 1:奖励 2:惩罚
+	Amount      float64        `gorm:"type:decimal(10,2)" json:"amount"`
+	Description string         `gorm:"size:255" json:"description"`
+	Status      int            `gorm:"default:1" json:"status"` // 1:启用 2:禁用
+	CreatedAt   time.Time      `json:"created_at"`
+	UpdatedAt   time.Time      `json:"updated_at"`
+	DeletedAt   gorm.DeletedAt `gorm:"index" json:"deleted_at"`
+}
+
+// CareProject 关怀项目
+type CareProject struct {
+	ID          uint           `gorm:"primarykey" json:"id"`
+	Name        string         `gorm:"size:50;not null" json:"name"`
+	Type        int            `gorm:"not null" json:"type"` // 1:生日 2:节日 3:其他
+	Amount      float64        `gorm:"type:decimal(10,2)" json:"amount"`
+	Description string         `gorm:"size:255" json:"description"`
+	Status      int            `gorm:"default:1" json:"status"` // 1:启用 2:禁用
+	CreatedAt   time.Time      `json:"created_at"`
+	UpdatedAt   time.Time      `json:"updated_at"`
+	DeletedAt   gorm.DeletedAt `gorm:"index" json:"deleted_at"`
+}
+
+// CommonData 常规数据
+type CommonData struct {
+	ID        uint           `gorm:"primarykey" json:"id"`
+	Name      string         `gorm:"size:50;not null" json:"name"`
+	Code      string         `gorm:"size:50;not null" json:"code"`
+	Type      string         `gorm:"size:50;not null" json:"type"` // education:学历 marriage:婚姻状况 等
+	Value     string         `gorm:"size:50" json:"value"`
+	Sort      int            `gorm:"default:0" json:"sort"`
+	Status    int            `gorm:"default:1" json:"status"` // 1:启用 2:禁用
+	CreatedAt time.Time      `json:"created_at"`
+	UpdatedAt time.Time      `json:"updated_at"`
+	DeletedAt gorm.DeletedAt `gorm:"index" json:"deleted_at"`
+}
+
+// AssetCategory 资产分类
+type AssetCategory struct {
+	ID          uint           `gorm:"primarykey" json:"id"`
+	Name        string         `gorm:"size:50;not null" json:"name"`
+	Code        string         `gorm:"size:50;unique" json:"code"`
+	ParentID    *uint          `json:"parent_id"`
+	Description string         `gorm:"size:255" json:"description"`
+	Sort        int            `gorm:"default:0" json:"sort"`
+	Status      int            `gorm:"default:1" json:"status"` // 1:启用 2:禁用
+	CreatedAt   time.Time      `json:"created_at"`
+	UpdatedAt   time.Time      `json:"updated_at"`
+	DeletedAt   gorm.DeletedAt `gorm:"index" json:"deleted_at"`
+}
+
+// AssetBrand 资产品牌
+type AssetBrand struct {
+	ID          uint           `gorm:"primarykey" json:"id"`
+	Name        string         `gorm:"size:50;not null" json:"name"`
+	Logo        string         `gorm:"size:255" json:"logo"`
+	Description string         `gorm:"size:255" json:"description"`
+	Sort        int            `gorm:"default:0" json:"sort"`
+	Status      int            `gorm:"default:1" json:"status"` // 1:启用 2:禁用
+	CreatedAt   time.Time      `json:"created_at"`
+	UpdatedAt   time.Time      `json:"updated_at"`
+	DeletedAt   gorm.DeletedAt `gorm:"index" json:"deleted_at"`
+}
+
+// AssetUnit 资产单位
+type AssetUnit struct {
+	ID        uint           `gorm:"primarykey" json:"id"`
+	Name      string         `gorm:"size:50;not null" json:"name"`
+	Code      string         `gorm:"size:20;unique" json:"code"`
+	Sort      int            `gorm:"default:0" json:"sort"`
+	Status    int            `gorm:"default:1" json:"status"` // 1:启用 2:禁用
+	CreatedAt time.Time      `json:"created_at"`
+	UpdatedAt time.Time      `json:"updated_at"`
+	DeletedAt gorm.DeletedAt `gorm:"index" json:"deleted_at"`
+}
+
+// SealType 印章类型
+type SealType struct {
+	ID          uint           `gorm:"primarykey" json:"id"`
+	Name        string         `gorm:"size:50;not null" json:"name"`
+	Description string         `gorm:"size:255" json:"description"`
+	Sort        int            `gorm:"default:0" json:"sort"`
+	Status      int            `gorm:"default:1" json:"status"` // 1:启用 2:禁用
+	CreatedAt   time.Time      `json:"created_at"`
+	UpdatedAt   time.Time      `json:"updated_at"`
+	DeletedAt   gorm.DeletedAt `gorm:"index" json:"deleted_at"`
+}
+
+// VehicleExpense 车辆费用
+type VehicleExpense struct {
+	ID          uint           `gorm:"primarykey" json:"id"`
+	Name        string         `gorm:"size:50;not null" json:"name"`
+	Code        string         `gorm:"size:50;unique" json:"code"`
+	Description string         `gorm:"size:255" json:"description"`
+	Sort        int            `gorm:"default:0" json:"sort"`
+	Status      int            `gorm:"default:1" json:"status"` // 1:启用 2:禁用
+	CreatedAt   time.Time      `json:"created_at"`
+	UpdatedAt   time.Time      `json:"updated_at"`
+	DeletedAt   gorm.DeletedAt `gorm:"index" json:"deleted_at"`
+}
+
+// NoticeType 公告类型
+type NoticeType struct {
+	ID          uint           `gorm:"primarykey" json:"id"`
+	Name        string         `gorm:"size:50;not null" json:"name"`
+	Code        string         `gorm:"size:50;unique" json:"code"`
+	Description string         `gorm:"size:255" json:"description"`
+	Sort        int            `gorm:"default:0" json:"sort"`
+	Status      int            `gorm:"default:1" json:"status"` // 1:启用 2:禁用
+	CreatedAt   time.Time      `json:"created_at"`
+	UpdatedAt   time.Time      `json:"updated_at"`
+	DeletedAt   gorm.DeletedAt `gorm:"index" json:"deleted_at"`
+}
+
+// ExpenseType 费用类型
+type ExpenseType struct {
+	ID          uint           `gorm:"primarykey" json:"id"`
+	Name        string         `gorm:"size:50;not null" json:"name"`
+	Code        string         `gorm:"size:50;unique" json:"code"`
+	ParentID    *uint          `json:"parent_id"`
+	Description string         `gorm:"size:255" json:"description"`
+	Sort        int            `gorm:"default:0" json:"sort"`
+	Status      int            `gorm:"default:1" json:"status"` // 1:启用 2:禁用
+	CreatedAt   time.Time      `json:"created_at"`
+	UpdatedAt   time.Time      `json:"updated_at"`
+	DeletedAt   gorm.DeletedAt `gorm:"index" json:"deleted_at"`
+}
+
+// CustomerLevel 客户等级
+type CustomerLevel struct {
+	ID          uint           `gorm:"primarykey" json:"id"`
+	Name        string         `gorm:"size:50;not null" json:"name"`
+	Code        string         `gorm:"size:50;unique" json:"code"`
+	Description string         `gorm:"size:255" json:"description"`
+	Sort        int            `gorm:"default:0" json:"sort"`
+	Status      int            `gorm:"default:1" json:"status"` // 1:启用 2:禁用
+	CreatedAt   time.Time      `json:"created_at"`
+	UpdatedAt   time.Time      `json:"updated_at"`
+	DeletedAt   gorm.DeletedAt `gorm:"index" json:"deleted_at"`
+}
+
+// CustomerChannel 客户渠道
+type CustomerChannel struct {
+	ID          uint           `gorm:"primarykey" json:"id"`
+	Name        string         `gorm:"size:50;not null" json:"name"`
+	Code        string         `gorm:"size:50;unique" json:"code"`
+	Description string         `gorm:"size:255" json:"description"`
+	Sort        int            `gorm:"default:0" json:"sort"`
+	Status      int            `gorm:"default:1" json:"status"` // 1:启用 2:禁用
+	CreatedAt   time.Time      `json:"created_at"`
+	UpdatedAt   time.Time      `json:"updated_at"`
+	DeletedAt   gorm.DeletedAt `gorm:"index" json:"deleted_at"`
+}
+
+// Industry 行业类型
+type Industry struct {
+	ID          uint           `gorm:"primarykey" json:"id"`
+	Name        string         `gorm:"size:50;not null" json:"name"`
+	Code        string         `gorm:"size:50;unique" json:"code"`
+	ParentID    *uint          `json:"parent_id"`
+	Description string         `gorm:"size:255" json:"description"`
+	Sort        int            `gorm:"default:0" json:"sort"`
+	Status      int            `gorm:"default:1" json:"status"` // 1:启用 2:禁用
+	CreatedAt   time.Time      `json:"created_at"`
+	UpdatedAt   time.Time      `json:"updated_at"`
+	DeletedAt   gorm.DeletedAt `gorm:"index" json:"deleted_at"`
+}
+
+// CustomerStatus 客户状态
+type CustomerStatus struct {
+	ID          uint           `gorm:"primarykey" json:"id"`
+	Name        string         `gorm:"size:50;not null" json:"name"`
+	Code        string         `gorm:"size:50;unique" json:"code"`
+	Description string         `gorm:"size:255" json:"description"`
+	Sort        int            `gorm:"default:0" json:"sort"`
+	Status      int            `gorm:"default:1" json:"status"` // 1:启用 2:禁用
+	CreatedAt   time.Time      `json:"created_at"`
+	UpdatedAt   time.Time      `json:"updated_at"`
+	DeletedAt   gorm.DeletedAt `gorm:"index" json:"deleted_at"`
+}
+
+// CustomerIntention 客户意向
+type CustomerIntention struct {
+	ID          uint           `gorm:"primarykey" json:"id"`
+	Name        string         `gorm:"size:50;not null" json:"name"`
+	Code        string         `gorm:"size:50;unique" json:"code"`
+	Description string         `gorm:"size:255" json:"description"`
+	Sort        int            `gorm:"default:0" json:"sort"`
+	Status      int            `gorm:"default:1" json:"status"` // 1:启用 2:禁用
+	CreatedAt   time.Time      `json:"created_at"`
+	UpdatedAt   time.Time      `json:"updated_at"`
+	DeletedAt   gorm.DeletedAt `gorm:"index" json:"deleted_at"`
+}
+
+// FollowUpMethod 跟进方式
+type FollowUpMethod struct {
+	ID          uint           `gorm:"primarykey" json:"id"`
+	Name        string         `gorm:"size:50;not null" json:"name"`
+	Code        string         `gorm:"size:50;unique" json:"code"`
+	Description string         `gorm:"size:255" json:"description"`
+	Sort        int            `gorm:"default:0" json:"sort"`
+	Status      int            `gorm:"default:1" json:"status"` // 1:启用 2:禁用
+	CreatedAt   time.Time      `json:"created_at"`
+	UpdatedAt   time.Time      `json:"updated_at"`
+	DeletedAt   gorm.DeletedAt `gorm:"index" json:"deleted_at"`
+}
+
+// SalesStage 销售阶段
+type SalesStage struct {
+	ID          uint           `gorm:"primarykey" json:"id"`
+	Name        string         `gorm:"size:50;not null" json:"name"`
+	Code        string         `gorm:"size:50;unique" json:"code"`
+	Description string         `gorm:"size:255" json:"description"`
+	Sort        int            `gorm:"default:0" json:"sort"`
+	Status      int            `gorm:"default:1" json:"status"` // 1:启用 2:禁用
+	CreatedAt   time.Time      `json:"created_at"`
+	UpdatedAt   time.Time      `json:"updated_at"`
+	DeletedAt   gorm.DeletedAt `gorm:"index" json:"deleted_at"`
+}
+
+// ContractCategory 合同分类
+type ContractCategory struct {
+	ID          uint           `gorm:"primarykey" json:"id"`
+	Name        string         `gorm:"size:50;not null" json:"name"`
+	Code        string         `gorm:"size:50;unique" json:"code"`
+	Description string         `gorm:"size:255" json:"description"`
+	Sort        int            `gorm:"default:0" json:"sort"`
+	Status      int            `gorm:"default:1" json:"status"` // 1:启用 2:禁用
+	CreatedAt   time.Time      `json:"created_at"`
+	UpdatedAt   time.Time      `json:"updated_at"`
+	DeletedAt   gorm.DeletedAt `gorm:"index" json:"deleted_at"`
+}
+
+// ProductCategory 产品分类
+type ProductCategory struct {
+	ID          uint           `gorm:"primarykey" json:"id"`
+	Name        string         `gorm:"size:50;not null" json:"name"`
+	Code        string         `gorm:"size:50;unique" json:"code"`
+	ParentID    *uint          `json:"parent_id"`
+	Description string         `gorm:"size:255" json:"description"`
+	Sort        int            `gorm:"default:0" json:"sort"`
+	Status      int            `gorm:"default:1" json:"status"` // 1:启用 2:禁用
+	CreatedAt   time.Time      `json:"created_at"`
+	UpdatedAt   time.Time      `json:"updated_at"`
+	DeletedAt   gorm.DeletedAt `gorm:"index" json:"deleted_at"`
+}
+
+// Product 产品
+type Product struct {
+	ID          uint           `gorm:"primarykey" json:"id"`
+	Name        string         `gorm:"size:100;not null" json:"name"`
+	Code        string         `gorm:"size:50;unique" json:"code"`
+	CategoryID  uint           `gorm:"not null" json:"category_id"`
+	Price       float64        `gorm:"type:decimal(10,2)" json:"price"`
+	Unit        string         `gorm:"size:20" json:"unit"`
+	Description string         `gorm:"size:500" json:"description"`
+	Status      int            `gorm:"default:1" json:"status"` // 1:上架 2:下架
+	CreatedAt   time.Time      `json:"created_at"`
+	UpdatedAt   time.Time      `json:"updated_at"`
+	DeletedAt   gorm.DeletedAt `gorm:"index" json:"deleted_at"`
+}
+
+// ServiceContent 服务内容
+type ServiceContent struct {
+	ID          uint           `gorm:"primarykey" json:"id"`
+	Name        string         `gorm:"size:100;not null" json:"name"`
+	Code        string         `gorm:"size:50;unique" json:"code"`
+	Description string         `gorm:"size:500" json:"description"`
+	Sort        int            `gorm:"default:0" json:"sort"`
+	Status      int            `gorm:"default:1" json:"status"` // 1:启用 2:禁用
+	CreatedAt   time.Time      `json:"created_at"`
+	UpdatedAt   time.Time      `json:"updated_at"`
+	DeletedAt   gorm.DeletedAt `gorm:"index" json:"deleted_at"`
+}
+
+// Supplier 供应商
+type Supplier struct {
+	ID          uint           `gorm:"primarykey" json:"id"`
+	Name        string         `gorm:"size:100;not null" json:"name"`
+	Code        string         `gorm:"size:50;unique" json:"code"`
+	Contact     string         `gorm:"size:50" json:"contact"`
+	Phone       string         `gorm:"size:20" json:"phone"`
+	Email       string         `gorm:"size:100" json:"email"`
+	Address     string         `gorm:"size:255" json:"address"`
+	Description string         `gorm:"size:500" json:"description"`
+	Status      int            `gorm:"default:1" json:"status"` // 1:正常 2:禁用
+	CreatedAt   time.Time      `json:"created_at"`
+	UpdatedAt   time.Time      `json:"updated_at"`
+	DeletedAt   gorm.DeletedAt `gorm:"index" json:"deleted_at"`
+}
+
+// PurchaseCategory 采购品分类
+type PurchaseCategory struct {
+	ID          uint           `gorm:"primarykey" json:"id"`
+	Name        string         `gorm:"size:50;not null" json:"name"`
+	Code        string         `gorm:"size:50;unique" json:"code"`
+	ParentID    *uint          `json:"parent_id"`
+	Description string         `gorm:"size:255" json:"description"`
+	Sort        int            `gorm:"default:0" json:"sort"`
+	Status      int            `gorm:"default:1" json:"status"` // 1:启用 2:禁用
+	CreatedAt   time.Time      `json:"created_at"`
+	UpdatedAt   time.Time      `json:"updated_at"`
+	DeletedAt   gorm.DeletedAt `gorm:"index" json:"deleted_at"`
+}
+
+// PurchaseItem 采购品
+type PurchaseItem struct {
+	ID          uint           `gorm:"primarykey" json:"id"`
+	Name        string         `gorm:"size:100;not null" json:"name"`
+	Code        string         `gorm:"size:50;unique" json:"code"`
+	CategoryID  uint           `gorm:"not null" json:"category_id"`
+	Price       float64        `gorm:"type:decimal(10,2)" json:"price"`
+	Unit        string         `gorm:"size:20" json:"unit"`
+	Description string         `gorm:"size:500" json:"description"`
+	Status      int            `gorm:"default:1" json:"status"` // 1:启用 2:禁用
+	CreatedAt   time.Time      `json:"created_at"`
+	UpdatedAt   time.Time      `json:"updated_at"`
+	DeletedAt   gorm.DeletedAt `gorm:"index" json:"deleted_at"`
+}
+
+// ProjectStage 项目阶段
+type ProjectStage struct {
+	ID          uint           `gorm:"primarykey" json:"id"`
+	Name        string         `gorm:"size:50;not null" json:"name"`
+	Code        string         `gorm:"size:50;unique" json:"code"`
+	Description string         `gorm:"size:255" json:"description"`
+	Sort        int            `gorm:"default:0" json:"sort"`
+	Status      int            `gorm:"default:1" json:"status"` // 1:启用 2:禁用
+	CreatedAt   time.Time      `json:"created_at"`
+	UpdatedAt   time.Time      `json:"updated_at"`
+	DeletedAt   gorm.DeletedAt `gorm:"index" json:"deleted_at"`
+}
+
+// ProjectCategory 项目分类
+type ProjectCategory struct {
+	ID          uint           `gorm:"primarykey" json:"id"`
+	Name        string         `gorm:"size:50;not null" json:"name"`
+	Code        string         `gorm:"size:50;unique" json:"code"`
+	Description string         `gorm:"size:255" json:"description"`
+	Sort        int            `gorm:"default:0" json:"sort"`
+	Status      int            `gorm:"default:1" json:"status"` // 1:启用 2:禁用
+	CreatedAt   time.Time      `json:"created_at"`
+	UpdatedAt   time.Time      `json:"updated_at"`
+	DeletedAt   gorm.DeletedAt `gorm:"index" json:"deleted_at"`
+}
+
+// WorkType 工作类型
+type WorkType struct {
+	ID          uint           `gorm:"primarykey" json:"id"`
+	Name        string         `gorm:"size:50;not null" json:"name"`
+	Code        string         `gorm:"size:50;unique" json:"code"`
+	Description string         `gorm:"size:255" json:"description"`
+	Sort        int            `gorm:"default:0" json:"sort"`
+	Status      int            `gorm:"default:1" json:"status"` // 1:启用 2:禁用
+	CreatedAt   time.Time      `json:"created_at"`
+	UpdatedAt   time.Time      `json:"updated_at"`
+	DeletedAt   gorm.DeletedAt `gorm:"index" json:"deleted_at"`
+}
+
+// TableName 指定表名
+func (Enterprise) TableName() string {
+	return "enterprises"
+}
+
+func (Region) TableName() string {
+	return "regions"
+}
+
+func (MessageTemplate) TableName() string {
+	return "message_templates"
+}
+
+func (RewardPunishment) TableName() string {
+	return "reward_punishments"
+}
+
+func (CareProject) TableName() string {
+	return "care_projects"
+}
+
+func (CommonData) TableName() string {
+	return "common_data"
+}
+
+func (AssetCategory) TableName() string {
+	return "asset_categories"
+}
+
+func (AssetBrand) TableName() string {
+	return "asset_brands"
+}
+
+func (AssetUnit) TableName() string {
+	return "asset_units"
+}
+
+func (SealType) TableName() string {
+	return "seal_types"
+}
+
+func (VehicleExpense) TableName() string {
+	return "vehicle_expenses"
+}
+
+func (NoticeType) TableName() string {
+	return "notice_types"
+}
+
+func (ExpenseType) TableName() string {
+	return "expense_types"
+}
+
+func (CustomerLevel) TableName() string {
+	return "customer_levels"
+}
+
+func (CustomerChannel) TableName() string {
+	return "customer_channels"
+}
+
+func (Industry) TableName() string {
+	return "industries"
+}
+
+func (CustomerStatus) TableName() string {
+	return "customer_statuses"
+}
+
+func (CustomerIntention) TableName() string {
+	return "customer_intentions"
+}
+
+func (FollowUpMethod) TableName() string {
+	return "follow_up_methods"
+}
+
+func (SalesStage) TableName() string {
+	return "sales_stages"
+}
+
+func (ContractCategory) TableName() string {
+	return "contract_categories"
+}
+
+func (ProductCategory) TableName() string {
+	return "product_categories"
+}
+
+func (Product) TableName() string {
+	return "products"
+}
+
+func (ServiceContent) TableName() string {
+	return "service_contents"
+}
+
+func (Supplier) TableName() string {
+	return "suppliers"
+}
+
+func (PurchaseCategory) TableName() string {
+	return "purchase_categories"
+}
+
+func (PurchaseItem) TableName() string {
+	return "purchase_items"
+}
+
+func (ProjectStage) TableName() string {
+	return "project_stages"
+}
+
+func (ProjectCategory) TableName() string {
+	return "project_categories"
+}
+
+func (WorkType) TableName() string {
+	return "work_types"
+}
+
+// Department 部门模型
+type Department struct {
+	ID        uint           `gorm:"primarykey" json:"id"`
+	Name      string         `gorm:"size:50;not null" json:"name"`
+	ParentID  *uint          `gorm:"default:null" json:"parent_id"`
+	Level     int            `gorm:"default:1" json:"level"`
+	Sort      int            `gorm:"default:0" json:"sort"`
+	CreatedAt time.Time      `json:"created_at"`
+	UpdatedAt time.Time      `json:"updated_at"`
+	DeletedAt gorm.DeletedAt `gorm:"index" json:"deleted_at"`
+}
+
+// TableName 指定表名
+func (Department) TableName() string {
+	return "departments"
+}
+
+// DocumentType 公文类型
+type DocumentType struct {
+	ID          uint           `gorm:"primarykey" json:"id"`
+	Name        string         `gorm:"size:50;not null" json:"name"` // 类型名称
+	Code        string         `gorm:"size:50;unique" json:"code"`   // 类型编码
+	Description string         `gorm:"size:500" json:"description"`  // 类型说明
+	Sort        int            `gorm:"default:0" json:"sort"`        // 排序
+	Status      int            `gorm:"default:1" json:"status"`      // 1:启用 2:禁用
+	CreatedBy   uint           `gorm:"not null" json:"created_by"`   // 创建人ID
+	CreatedAt   time.Time      `json:"created_at"`
+	UpdatedAt   time.Time      `json:"updated_at"`
+	DeletedAt   gorm.DeletedAt `gorm:"index" json:"deleted_at"`
+}
+
+// Document 公文信息
+type Document struct {
+	ID            uint           `gorm:"primarykey" json:"id"`
+	Title         string         `gorm:"size:200;not null" json:"title"`  // 公文标题
+	Code          string         `gorm:"size:50;unique" json:"code"`      // 公文编号
+	TypeID        uint           `gorm:"not null" json:"type_id"`         // 公文类型ID
+	SecurityLevel int            `gorm:"default:1" json:"security_level"` // 密级：1:普通 2:秘密 3:机密 4:绝密
+	UrgencyLevel  int            `gorm:"default:1" json:"urgency_level"`  // 紧急程度：1:普通 2:紧急 3:特急
+	Content       string         `gorm:"type:text" json:"content"`        // 公文内容
+	Keywords      string         `gorm:"size:200" json:"keywords"`        // 关键词
+	DraftUserID   uint           `gorm:"not null" json:"draft_user_id"`   // 拟稿人ID
+	DraftDeptID   uint           `gorm:"not null" json:"draft_dept_id"`   // 拟稿部门ID
+	DraftDate     *time.Time     `json:"draft_date"`                      // 拟稿日期
+	SignDate      *time.Time     `json:"sign_date"`                       // 签发日期
+	Status        int            `gorm:"default:1" json:"status"`         // 1:草稿 2:审批中 3:已签发 4:已归档 5:已作废
+	Files         string         `gorm:"type:text" json:"files"`          // 附件，JSON数组
+	Remark        string         `gorm:"size:500" json:"remark"`          // 备注
+	CreatedBy     uint           `gorm:"not null" json:"created_by"`      // 创建人ID
+	CreatedAt     time.Time      `json:"created_at"`
+	UpdatedAt     time.Time      `json:"updated_at"`
+	DeletedAt     gorm.DeletedAt `gorm:"index" json:"deleted_at"`
+}
+
+// DocumentApproval 公文审批流程
+type DocumentApproval struct {
+	ID             uint           `gorm:"primarykey" json:"id"`
+	DocumentID     uint           `gorm:"not null" json:"document_id"`      // 公文ID
+	ApproverID     uint           `gorm:"not null" json:"approver_id"`      // 审批人ID
+	ApproverDeptID uint           `gorm:"not null" json:"approver_dept_id"` // 审批部门ID
+	Sort           int            `gorm:"default:0" json:"sort"`            // 审批顺序
+	Status         int            `gorm:"default:1" json:"status"`          // 1:待审批 2:已通过 3:已驳回
+	Comment        string         `gorm:"size:500" json:"comment"`          // 审批意见
+	ApprovalTime   *time.Time     `json:"approval_time"`                    // 审批时间
+	CreatedAt      time.Time      `json:"created_at"`
+	UpdatedAt      time.Time      `json:"updated_at"`
+	DeletedAt      gorm.DeletedAt `gorm:"index" json:"deleted_at"`
+}
+
+// DocumentDistribution 公文分发
+type DocumentDistribution struct {
+	ID             uint           `gorm:"primarykey" json:"id"`
+	DocumentID     uint           `gorm:"not null" json:"document_id"`      // 公文ID
+	ReceiverID     uint           `gorm:"not null" json:"receiver_id"`      // 接收人ID
+	ReceiverDeptID uint           `gorm:"not null" json:"receiver_dept_id"` // 接收部门ID
+	Status         int            `gorm:"default:1" json:"status"`          // 1:未读 2:已读
+	ReadTime       *time.Time     `json:"read_time"`                        // 阅读时间
+	CreatedBy      uint           `gorm:"not null" json:"created_by"`       // 分发人ID
+	CreatedAt      time.Time      `json:"created_at"`
+	UpdatedAt      time.Time      `json:"updated_at"`
+	DeletedAt      gorm.DeletedAt `gorm:"index" json:"deleted_at"`
+}
+
+// DocumentArchive 公文归档
+type DocumentArchive struct {
+	ID          uint           `gorm:"primarykey" json:"id"`
+	DocumentID  uint           `gorm:"not null" json:"document_id"`      // 公文ID
+	ArchiveNo   string         `gorm:"size:50;unique" json:"archive_no"` // 归档号
+	Location    string         `gorm:"size:200" json:"location"`         // 存放位置
+	Status      int            `gorm:"default:1" json:"status"`          // 1:已归档 2:已借阅 3:已销毁
+	ArchiveDate *time.Time     `json:"archive_date"`                     // 归档日期
+	DestroyDate *time.Time     `json:"destroy_date"`                     // 销毁日期
+	Files       string         `gorm:"type:text" json:"files"`           // 附件，JSON数组
+	Remark      string         `gorm:"size:500" json:"remark"`           // 备注
+	CreatedBy   uint           `gorm:"not null" json:"created_by"`       // 创建人ID
+	CreatedAt   time.Time      `json:"created_at"`
+	UpdatedAt   time.Time      `json:"updated_at"`
+	DeletedAt   gorm.DeletedAt `gorm:"index" json:"deleted_at"`
+}
+
+// DocumentBorrow 公文借阅
+type DocumentBorrow struct {
+	ID             uint           `gorm:"primarykey" json:"id"`
+	DocumentID     uint           `gorm:"not null" json:"document_id"`      // 公文ID
+	BorrowerID     uint           `gorm:"not null" json:"borrower_id"`      // 借阅人ID
+	BorrowerDeptID uint           `gorm:"not null" json:"borrower_dept_id"` // 借阅部门ID
+	Purpose        string         `gorm:"size:500" json:"purpose"`          // 借阅用途
+	BorrowDate     *time.Time     `json:"borrow_date"`                      // 借阅日期
+	ReturnDate     *time.Time     `json:"return_date"`                      // 归还日期
+	Status         int            `gorm:"default:1" json:"status"`          // 1:已借出 2:已归还 3:已逾期
+	Files          string         `gorm:"type:text" json:"files"`           // 附件，JSON数组
+	Remark         string         `gorm:"size:500" json:"remark"`           // 备注
+	CreatedBy      uint           `gorm:"not null" json:"created_by"`       // 创建人ID
+	CreatedAt      time.Time      `json:"created_at"`
+	UpdatedAt      time.Time      `json:"updated_at"`
+	DeletedAt      gorm.DeletedAt `gorm:"index" json:"deleted_at"`
+}
+
+// TableName 指定表名
+func (DocumentType) TableName() string {
+	return "document_types"
+}
+
+func (Document) TableName() string {
+	return "documents"
+}
+
+func (DocumentApproval) TableName() string {
+	return "document_approvals"
+}
+
+func (DocumentDistribution) TableName() string {
+	return "document_distributions"
+}
+
+func (DocumentArchive) TableName() string {
+	return "document_archives"
+}
+
+func (DocumentBorrow) TableName() string {
+	return "document_borrows"
+}
+
+// Employee 员工模型
+type Employee struct {
+	ID           uint           `gorm:"primarykey" json:"id"`
+	Name         string         `gorm:"size:50;not null" json:"name"`
+	Email        string         `gorm:"size:100;unique" json:"email"`
+	Phone        string         `gorm:"size:255;serializer:encrypted" json:"phone" mask:"mobile"` // 手机号，加密存储
+	Avatar       string         `gorm:"size:255" json:"avatar"`
+	DepartmentID uint           `gorm:"not null" json:"department_id"`
+	Position     string         `gorm:"size:50" json:"position"`
+	Status       int            `gorm:"default:1" json:"status"` // 1:在职 2:离职
+	CreatedAt    time.Time      `json:"created_at"`
+	UpdatedAt    time.Time      `json:"updated_at"`
+	DeletedAt    gorm.DeletedAt `gorm:"index" json:"deleted_at"`
+}
+
+// TableName 指定表名
+func (Employee) TableName() string {
+	return "employees"
+}
+
+// Position 岗位职称
+type Position struct {
+	ID          uint           `gorm:"primarykey" json:"id"`
+	Name        string         `gorm:"size:50;not null" json:"name"`
+	Code        string         `gorm:"size:50;unique" json:"code"`
+	Description string         `gorm:"size:255" json:"description"`
+	Sort        int            `gorm:"default:0" json:"sort"`
+	Status      int            `gorm:"default:1" json:"status"` // 1:启用 2:禁用
+	CreatedAt   time.Time      `json:"created_at"`
+	UpdatedAt   time.Time      `json:"updated_at"`
+	DeletedAt   gorm.DeletedAt `gorm:"index" json:"deleted_at"`
+}
+
+// EmployeeArchive 员工档案
+type EmployeeArchive struct {
+	ID            uint           `gorm:"primarykey" json:"id"`
+	EmployeeID    uint           `gorm:"not null" json:"employee_id"`
+	Education     string         `gorm:"size:50" json:"education"`                                           // 学历
+	School        string         `gorm:"size:100" json:"school"`                                             // 毕业院校
+	Major         string         `gorm:"size:100" json:"major"`                                              // 专业
+	GraduationAt  *time.Time     `json:"graduation_at"`                                                      // 毕业时间
+	WorkStartAt   *time.Time     `json:"work_start_at"`                                                      // 参加工作时间
+	MaritalStatus string         `gorm:"size:20" json:"marital_status"`                                      // 婚姻状况
+	Political     string         `gorm:"size:50" json:"political"`                                           // 政治面貌
+	IDCard        string         `gorm:"size:255;serializer:encrypted" json:"id_card" mask:"id_card"`        // 身份证号，加密存储
+	Birthday      *time.Time     `gorm:"type:varchar(255);serializer:encrypted" json:"birthday" mask:"date"` // 出生日期，加密存储
+	Native        string         `gorm:"size:100" json:"native"`                                             // 籍贯
+	Address       string         `gorm:"type:text;serializer:encrypted" json:"address" mask:"address"`       // 现居地址，加密存储
+	Files         string         `gorm:"type:text" json:"files"`                                             // 档案附件，JSON数组
+	CreatedAt     time.Time      `json:"created_at"`
+	UpdatedAt     time.Time      `json:"updated_at"`
+	DeletedAt     gorm.DeletedAt `gorm:"index" json:"deleted_at"`
+}
+
+// RewardPunishmentRecord 奖惩记录
+type RewardPunishmentRecord struct {
+	ID                 uint           `gorm:"primarykey" json:"id"`
+	EmployeeID         uint           `gorm:"not null" json:"employee_id"`
+	RewardPunishmentID uint           `gorm:"not null" json:"reward_punishment_id"`
+	Amount             float64        `gorm:"type:decimal(10,2)" json:"amount"`
+	Reason             string         `gorm:"size:500" json:"reason"`
+	Date               *time.Time     `json:"date"`
+	Remark             string         `gorm:"size:500" json:"remark"`
+	Files              string         `gorm:"type:text" json:"files"` // 附件，JSON数组
+	CreatedAt          time.Time      `json:"created_at"`
+	UpdatedAt          time.Time      `json:"updated_at"`
+	DeletedAt          gorm.DeletedAt `gorm:"index" json:"deleted_at"`
+}
+
+// CareRecord 关怀记录
+type CareRecord struct {
+	ID            uint           `gorm:"primarykey" json:"id"`
+	EmployeeID    uint           `gorm:"not null" json:"employee_id"`
+	CareProjectID uint           `gorm:"not null" json:"care_project_id"`
+	Amount        float64        `gorm:"type:decimal(10,2)" json:"amount"`
+	Date          *time.Time     `json:"date"`
+	Remark        string         `gorm:"size:500" json:"remark"`
+	Files         string         `gorm:"type:text" json:"files"` // 附件，JSON数组
+	CreatedAt     time.Time      `json:"created_at"`
+	UpdatedAt     time.Time      `json:"updated_at"`
+	DeletedAt     gorm.DeletedAt `gorm:"index" json:"deleted_at"`
+}
+
+// Transfer 人事调动
+type Transfer struct {
+	ID               uint           `gorm:"primarykey" json:"id"`
+	EmployeeID       uint           `gorm:"not null" json:"employee_id"`
+	OldDepartmentID  uint           `gorm:"not null" json:"old_department_id"`
+	NewDepartmentID  uint           `gorm:"not null" json:"new_department_id"`
+	OldPositionID    uint           `gorm:"not null" json:"old_position_id"`
+	NewPositionID    uint           `gorm:"not null" json:"new_position_id"`
+	EffectiveDate    *time.Time     `json:"effective_date"`
+	Reason           string         `gorm:"size:500" json:"reason"`
+	Status           int            `gorm:"default:1" json:"status"` // 1:待审批 2:已通过 3:已驳回
+	ApprovalRecordID *uint          `json:"approval_record_id"`      // 关联的审批记录ID
+	Files            string         `gorm:"type:text" json:"files"`  // 附件，JSON数组
+	CreatedAt        time.Time      `json:"created_at"`
+	UpdatedAt        time.Time      `json:"updated_at"`
+	DeletedAt        gorm.DeletedAt `gorm:"index" json:"deleted_at"`
+}
+
+// Resignation 离职档案
+type Resignation struct {
+	ID               uint           `gorm:"primarykey" json:"id"`
+	EmployeeID       uint           `gorm:"not null" json:"employee_id"`
+	ResignationType  int            `gorm:"not null" json:"resignation_type"` // 1:主动离职 2:被动离职
+	Reason           string         `gorm:"size:500" json:"reason"`
+	LastWorkingDay   *time.Time     `json:"last_working_day"`
+	HandoverTo       uint           `json:"handover_to"`                       // 工作交接人
+	HandoverContent  string         `gorm:"type:text" json:"handover_content"` // 工作交接内容
+	Status           int            `gorm:"default:1" json:"status"`           // 1:待审批 2:已通过 3:已驳回
+	ApprovalRecordID *uint          `json:"approval_record_id"`                // 关联的审批记录ID
+	Files            string         `gorm:"type:text" json:"files"`            // 附件，JSON数组
+	CreatedAt        time.Time      `json:"created_at"`
+	UpdatedAt        time.Time      `json:"updated_at"`
+	DeletedAt        gorm.DeletedAt `gorm:"index" json:"deleted_at"`
+}
+
+// Contract 员工合同
+type Contract struct {
+	ID         uint           `gorm:"primarykey" json:"id"`
+	EmployeeID uint           `gorm:"not null" json:"employee_id"`
+	ContractNo string         `gorm:"size:50;unique" json:"contract_no"` // 合同编号
+	Type       int            `gorm:"not null" json:"type"`              // 1:固定期限 2:无固定期限 3:实习
+	StartDate  *time.Time     `json:"start_date"`                        // 合同开始日期
+	EndDate    *time.Time     `json:"end_date"`                          // 合同结束日期
+	Status     int            `gorm:"default:1" json:"status"`           // 1:生效中 2:已终止 3:已到期
+	Files      string         `gorm:"type:text" json:"files"`            // 附件，JSON数组
+	CreatedAt  time.Time      `json:"created_at"`
+	UpdatedAt  time.Time      `json:"updated_at"`
+	DeletedAt  gorm.DeletedAt `gorm:"index" json:"deleted_at"`
+}
+
+// Probation 转正
+type Probation struct {
+	ID               uint           `gorm:"primarykey" json:"id"`
+	EmployeeID       uint           `gorm:"not null" json:"employee_id"`
+	ProbationEndDate *time.Time     `json:"probation_end_date"`          // 试用期结束日期
+	Assessment       string         `gorm:"type:text" json:"assessment"` // 试用期评估
+	Status           int            `gorm:"default:1" json:"status"`     // 1:待审批 2:已通过 3:已驳回
+	ApprovalRecordID *uint          `json:"approval_record_id"`          // 关联的审批记录ID
+	Files            string         `gorm:"type:text" json:"files"`      // 附件，JSON数组
+	CreatedAt        time.Time      `json:"created_at"`
+	UpdatedAt        time.Time      `json:"updated_at"`
+	DeletedAt        gorm.DeletedAt `gorm:"index" json:"deleted_at"`
+}
+
+// TableName 指定表名
+func (Position) TableName() string {
+	return "positions"
+}
+
+func (EmployeeArchive) TableName() string {
+	return "employee_archives"
+}
+
+func (RewardPunishmentRecord) TableName() string {
+	return "reward_punishment_records"
+}
+
+func (CareRecord) TableName() string {
+	return "care_records"
+}
+
+func (Transfer) TableName() string {
+	return "transfers"
+}
+
+func (Resignation) TableName() string {
+	return "resignations"
+}
+
+func (Contract) TableName() string {
+	return "contracts"
+}
+
+func (Probation) TableName() string {
+	return "probations"
+}
+
+// MeetingRoom 会议室
+type MeetingRoom struct {
+	ID          uint           `gorm:"primarykey" json:"id"`
+	Name        string         `gorm:"size:50;not null" json:"name"` // 会议室名称
+	Location    string         `gorm:"size:255" json:"location"`     // 位置
+	Capacity    int            `gorm:"not null" json:"capacity"`     // 容纳人数
+	Facilities  string         `gorm:"type:text" json:"facilities"`  // 设施配置，JSON数组
+	Description string         `gorm:"size:500" json:"description"`  // 描述
+	Status      int            `gorm:"default:1" json:"status"`      // 1:可用 2:维护中 3:停用
+	Sort        int            `gorm:"default:0" json:"sort"`        // 排序
+	CreatedBy   uint           `gorm:"not null" json:"created_by"`   // 创建人ID
+	CreatedAt   time.Time      `json:"created_at"`
+	UpdatedAt   time.Time      `json:"updated_at"`
+	DeletedAt   gorm.DeletedAt `gorm:"index" json:"deleted_at"`
+}
+
+// MeetingReservation 会议室预约
+type MeetingReservation struct {
+	ID           uint           `gorm:"primarykey" json:"id"`
+	RoomID       uint           `gorm:"not null" json:"room_id"`        // 会议室ID
+	Title        string         `gorm:"size:100;not null" json:"title"` // 会议主题
+	StartTime    *time.Time     `gorm:"not null" json:"start_time"`     // 开始时间
+	EndTime      *time.Time     `gorm:"not null" json:"end_time"`       // 结束时间
+	UserID       uint           `gorm:"not null" json:"user_id"`        // 预约人ID
+	DepartmentID uint           `gorm:"not null" json:"department_id"`  // 预约部门ID
+	Attendees    string         `gorm:"type:text" json:"attendees"`     // 参会人员，JSON数组
+	Purpose      string         `gorm:"size:500" json:"purpose"`        // 会议用途
+	Requirements string         `gorm:"type:text" json:"requirements"`  // 会议要求，JSON数组
+	Status       int            `gorm:"default:1" json:"status"`        // 1:待审批 2:已通过 3:已驳回 4:已取消
+	ApproverID   *uint          `json:"approver_id"`                    // 审批人ID
+	ApprovalTime *time.Time     `json:"approval_time"`                  // 审批时间
+	CancelReason string         `gorm:"size:500" json:"cancel_reason"`  // 取消原因
+	CancelTime   *time.Time     `json:"cancel_time"`                    // 取消时间
+	CheckInTime  *time.Time     `json:"check_in_time"`                  // 签到时间
+	CheckOutTime *time.Time     `json:"check_out_time"`                 // 签退时间
+	Files        string         `gorm:"type:text" json:"files"`         // 附件，JSON数组
+	Remark       string         `gorm:"size:500" json:"remark"`         // 备注
+	CreatedAt    time.Time      `json:"created_at"`
+	UpdatedAt    time.Time      `json:"updated_at"`
+	DeletedAt    gorm.DeletedAt `gorm:"index" json:"deleted_at"`
+}
+
+// MeetingMinutes 会议纪要
+type MeetingMinutes struct {
+	ID            uint           `gorm:"primarykey" json:"id"`
+	ReservationID uint           `gorm:"not null" json:"reservation_id"` // 会议预约ID
+	Content       string         `gorm:"type:text" json:"content"`       // 会议纪要内容
+	Participants  string         `gorm:"type:text" json:"participants"`  // 实际参会人员，JSON数组
+	Files         string         `gorm:"type:text" json:"files"`         // 附件，JSON数组
+	CreatedBy     uint           `gorm:"not null" json:"created_by"`     // 创建人ID
+	CreatedAt     time.Time      `json:"created_at"`
+	UpdatedAt     time.Time      `json:"updated_at"`
+	DeletedAt     gorm.DeletedAt `gorm:"index" json:"deleted_at"`
+}
+
+// MeetingRoomMaintenance 会议室维护记录
+type MeetingRoomMaintenance struct {
+	ID          uint           `gorm:"primarykey" json:"id"`
+	RoomID      uint           `gorm:"not null" json:"room_id"`     // 会议室ID
+	Type        int            `gorm:"not null" json:"type"`        // 1:日常维护 2:设备维修 3:环境整治
+	Description string         `gorm:"size:500" json:"description"` // 维护说明
+	StartTime   *time.Time     `json:"start_time"`                  // 开始时间
+	EndTime     *time.Time     `json:"end_time"`                    // 结束时间
+	Status      int            `gorm:"default:1" json:"status"`     // 1:待处理 2:处理中 3:已完成
+	Result      string         `gorm:"size:500" json:"result"`      // 维护结果
+	Files       string         `gorm:"type:text" json:"files"`      // 附件，JSON数组
+	CreatedBy   uint           `gorm:"not null" json:"created_by"`  // 创建人ID
+	CreatedAt   time.Time      `json:"created_at"`
+	UpdatedAt   time.Time      `json:"updated_at"`
+	DeletedAt   gorm.DeletedAt `gorm:"index" json:"deleted_at"`
+}
+
+// TableName 指定表名
+func (MeetingRoom) TableName() string {
+	return "meeting_rooms"
+}
+
+func (MeetingReservation) TableName() string {
+	return "meeting_reservations"
+}
+
+func (MeetingMinutes) TableName() string {
+	return "meeting_minutes"
+}
+
+func (MeetingRoomMaintenance) TableName() string {
+	return "meeting_room_maintenances"
+}
+
+// Notice 公告信息
+type Notice struct {
+	ID        uint           `gorm:"primarykey" json:"id"`
+	Title     string         `gorm:"size:200;not null" json:"title"` // 公告标题
+	TypeID    uint           `gorm:"not null" json:"type_id"`        // 公告类型ID
+	Content   string         `gorm:"type:text" json:"content"`       // 公告内容
+	StartTime *time.Time     `json:"start_time"`                     // 生效时间
+	EndTime   *time.Time     `json:"end_time"`                       // 失效时间
+	Priority  int            `gorm:"default:1" json:"priority"`      // 优先级：1:普通 2:重要 3:紧急
+	Status    int            `gorm:"default:1" json:"status"`        // 1:草稿 2:已发布 3:已撤回
+	Files     string         `gorm:"type:text" json:"files"`         // 附件，JSON数组
+	Remark    string         `gorm:"size:500" json:"remark"`         // 备注
+	CreatedBy uint           `gorm:"not null" json:"created_by"`     // 创建人ID
+	CreatedAt time.Time      `json:"created_at"`
+	UpdatedAt time.Time      `json:"updated_at"`
+	DeletedAt gorm.DeletedAt `gorm:"index" json:"deleted_at"`
+}
+
+// NoticeRead 公告阅读记录
+type NoticeRead struct {
+	ID        uint           `gorm:"primarykey" json:"id"`
+	NoticeID  uint           `gorm:"not null" json:"notice_id"` // 公告ID
+	UserID    uint           `gorm:"not null" json:"user_id"`   // 用户ID
+	ReadTime  *time.Time     `json:"read_time"`                 // 阅读时间
+	CreatedAt time.Time      `json:"created_at"`
+	UpdatedAt time.Time      `json:"updated_at"`
+	DeletedAt gorm.DeletedAt `gorm:"index" json:"deleted_at"`
+}
+
+func (Notice) TableName() string {
+	return "notices"
+}
+
+func (NoticeRead) TableName() string {
+	return "notice_reads"
+}
+
+// Notification 消息通知模型
+type Notification struct {
+	ID        uint           `gorm:"primarykey" json:"id"`
+	Title     string         `gorm:"size:100;not null" json:"title"`
+	Content   string         `gorm:"type:text" json:"content"`
+	Type      int            `gorm:"not null" json:"type"`    // 1:系统消息 2:审批通知
+	Status    int            `gorm:"default:1" json:"status"` // 1:未读 2:已读
+	UserID    uint           `gorm:"not null" json:"user_id"`
+	CreatedAt time.Time      `json:"created_at"`
+	UpdatedAt time.Time      `json:"updated_at"`
+	DeletedAt gorm.DeletedAt `gorm:"index" json:"deleted_at"`
+}
+
+// TableName 指定表名
+func (Notification) TableName() string {
+	return "notifications"
+}
+
+// Seal 印章信息
+type Seal struct {
+	ID          uint           `gorm:"primarykey" json:"id"`
+	Name        string         `gorm:"size:50;not null" json:"name"` // 印章名称
+	Code        string         `gorm:"size:50;unique" json:"code"`   // 印章编号
+	TypeID      uint           `gorm:"not null" json:"type_id"`      // 印章类型ID
+	Image       string         `gorm:"size:255" json:"image"`        // 印章图片
+	Status      int            `gorm:"default:1" json:"status"`      // 1:在库 2:借出 3:作废
+	KeeperID    uint           `gorm:"not null" json:"keeper_id"`    // 保管人ID
+	Description string         `gorm:"size:500" json:"description"`  // 印章说明
+	Files       string         `gorm:"type:text" json:"files"`       // 附件，JSON数组
+	Remark      string         `gorm:"size:500" json:"remark"`       // 备注
+	CreatedBy   uint           `gorm:"not null" json:"created_by"`   // 创建人ID
+	CreatedAt   time.Time      `json:"created_at"`
+	UpdatedAt   time.Time      `json:"updated_at"`
+	DeletedAt   gorm.DeletedAt `gorm:"index" json:"deleted_at"`
+}
+
+// SealApplication 用印申请
+type SealApplication struct {
+	ID               uint           `gorm:"primarykey" json:"id"`
+	SealID           uint           `gorm:"not null" json:"seal_id"`       // 印章ID
+	UserID           uint           `gorm:"not null" json:"user_id"`       // 申请人ID
+	DepartmentID     uint           `gorm:"not null" json:"department_id"` // 申请部门ID
+	Purpose          string         `gorm:"size:500" json:"purpose"`       // 用印事由
+	Content          string         `gorm:"type:text" json:"content"`      // 用印内容
+	Quantity         int            `gorm:"not null" json:"quantity"`      // 用印数量
+	StartTime        *time.Time     `json:"start_time"`                    // 用印开始时间
+	EndTime          *time.Time     `json:"end_time"`                      // 用印结束时间
+	Status           int            `gorm:"default:1" json:"status"`       // 1:待审批 2:已通过 3:已驳回 4:已取消
+	ApprovalRecordID *uint          `json:"approval_record_id"`            // 关联的审批记录ID
+	Files            string         `gorm:"type:text" json:"files"`        // 附件，JSON数组
+	Remark           string         `gorm:"size:500" json:"remark"`        // 备注
+	CreatedAt        time.Time      `json:"created_at"`
+	UpdatedAt        time.Time      `json:"updated_at"`
+	DeletedAt        gorm.DeletedAt `gorm:"index" json:"deleted_at"`
+}
+
+// SealRecord 用印记录
+type SealRecord struct {
+	ID            uint           `gorm:"primarykey" json:"id"`
+	ApplicationID uint           `gorm:"not null" json:"application_id"` // 用印申请ID
+	BorrowTime    *time.Time     `json:"borrow_time"`                    // 借出时间
+	ReturnTime    *time.Time     `json:"return_time"`                    // 归还时间
+	Status        int            `gorm:"default:1" json:"status"`        // 1:已借出 2:已归还 3:异常
+	Problem       string         `gorm:"size:500" json:"problem"`        // 问题说明
+	Files         string         `gorm:"type:text" json:"files"`         // 附件，JSON数组
+	Remark        string         `gorm:"size:500" json:"remark"`         // 备注
+	CreatedBy     uint           `gorm:"not null" json:"created_by"`     // 创建人ID
+	CreatedAt     time.Time      `json:"created_at"`
+	UpdatedAt     time.Time      `json:"updated_at"`
+	DeletedAt     gorm.DeletedAt `gorm:"index" json:"deleted_at"`
+}
+
+// TableName 指定表名
+func (Seal) TableName() string {
+	return "seals"
+}
+
+func (SealApplication) TableName() string {
+	return "seal_applications"
+}
+
+func (SealRecord) TableName() string {
+	return "seal_records"
+}
+
+// SystemConfig 系统配置
+type SystemConfig struct {
+	ID        uint           `gorm:"primarykey" json:"id"`
+	Key       string         `gorm:"size:50;not null;unique" json:"key"`
+	Value     string         `gorm:"type:text" json:"value"`
+	Desc      string         `gorm:"size:255" json:"desc"`
+	CreatedAt time.Time      `json:"created_at"`
+	UpdatedAt time.Time      `json:"updated_at"`
+	DeletedAt gorm.DeletedAt `gorm:"index" json:"deleted_at"`
+}
+
+// Module 功能模块
+type Module struct {
+	ID        uint           `gorm:"primarykey" json:"id"`
+	Name      string         `gorm:"size:50;not null" json:"name"`
+	Code      string         `gorm:"size:50;not null;unique" json:"code"`
+	Icon      string         `gorm:"size:50" json:"icon"`
+	Sort      int            `gorm:"default:0" json:"sort"`
+	Status    int            `gorm:"default:1" json:"status"` // 1:启用 2:禁用
+	ParentID  *uint          `json:"parent_id"`
+	CreatedAt time.Time      `json:"created_at"`
+	UpdatedAt time.Time      `json:"updated_at"`
+	DeletedAt gorm.DeletedAt `gorm:"index" json:"deleted_at"`
+}
+
+// ModuleConfig 模块配置
+type ModuleConfig struct {
+	ID        uint           `gorm:"primarykey" json:"id"`
+	ModuleID  uint           `gorm:"not null" json:"module_id"`
+	Key       string         `gorm:"size:50;not null" json:"key"`
+	Value     string         `gorm:"type:text" json:"value"`
+	Desc      string         `gorm:"size:255" json:"desc"`
+	CreatedAt time.Time      `json:"created_at"`
+	UpdatedAt time.Time      `json:"updated_at"`
+	DeletedAt gorm.DeletedAt `gorm:"index" json:"deleted_at"`
+}
+
+// OperationLog 操作日志
+type OperationLog struct {
+	ID                 uint           `gorm:"primarykey" json:"id"`
+	UserID             uint           `gorm:"not null;index" json:"user_id"`     // 实际操作人
+	ImpersonatedUserID uint           `gorm:"index" json:"impersonated_user_id"` // 模拟登录时被模拟的用户
+	Module             string         `gorm:"size:50;index" json:"module"`
+	Action             string         `gorm:"size:50" json:"action"`
+	Method             string         `gorm:"size:10" json:"method"`
+	Path               string         `gorm:"size:255" json:"path"`
+	Params             string         `gorm:"type:text" json:"params"`   // 请求参数，密码等敏感字段已脱敏
+	Status             int            `json:"status"`                    // 响应状态码
+	Response           string         `gorm:"type:text" json:"response"` // 响应摘要
+	IP                 string         `gorm:"size:50" json:"ip"`
+	UserAgent          string         `gorm:"size:255" json:"user_agent"`
+	CreatedAt          time.Time      `gorm:"index" json:"created_at"`
+	UpdatedAt          time.Time      `json:"updated_at"`
+	DeletedAt          gorm.DeletedAt `gorm:"index" json:"deleted_at"`
+}
+
+// Attachment 附件
+type Attachment struct {
+	ID          uint           `gorm:"primarykey" json:"id"`
+	Name        string         `gorm:"size:255;not null" json:"name"`
+	Path        string         `gorm:"size:255;not null" json:"-"` // 存储路径，内容相同的附件共用同一个文件
+	Storage     string         `gorm:"size:20" json:"storage"`     // 存储方式 local / s3
+	Hash        string         `gorm:"size:64;index" json:"hash"`  // 文件内容SHA-256
+	Size        int64          `gorm:"not null" json:"size"`
+	Type        string         `gorm:"size:100" json:"type"` // 按文件内容识别的MIME类型
+	UploadedBy  uint           `gorm:"not null" json:"uploaded_by"`
+	Module      string         `gorm:"size:50" json:"module"`
+	RelatedID   uint           `gorm:"index:idx_attachment_related,priority:2" json:"related_id"`           // 所属记录ID
+	RelatedType string         `gorm:"size:50;index:idx_attachment_related,priority:1" json:"related_type"` // 所属记录类型，如 asset、document
+	CreatedAt   time.Time      `json:"created_at"`
+	UpdatedAt   time.Time      `json:"updated_at"`
+	DeletedAt   gorm.DeletedAt `gorm:"index" json:"deleted_at"`
+}
+
+// AttachmentUpload 分片上传任务，分片暂存在本地，全部上传后合并为附件
+type AttachmentUpload struct {
+	ID          uint      `gorm:"primarykey" json:"-"`
+	UploadID    string    `gorm:"size:32;not null;uniqueIndex" json:"upload_id"`
+	Name        string    `gorm:"size:255;not null" json:"name"`
+	Size        int64     `gorm:"not null" json:"size"`
+	ChunkSize   int64     `gorm:"not null" json:"chunk_size"`
+	TotalChunks int       `gorm:"not null" json:"total_chunks"`
+	UserID      uint      `gorm:"not null;index" json:"user_id"` // 上传人，只能由本人续传
+	ExpiresAt   time.Time `gorm:"index" json:"expires_at"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+// BackupRecord 备份记录
+type BackupRecord struct {
+	ID           uint           `gorm:"primarykey" json:"id"`
+	Name         string         `gorm:"size:255;not null" json:"name"`
+	Path         string         `gorm:"size:255;not null" json:"-"` // 备份文件路径
+	Size         int64          `gorm:"not null" json:"size"`
+	Type         int            `gorm:"default:1" json:"type"`   // 1:全量备份 2:增量备份
+	Status       int            `gorm:"default:1" json:"status"` // 1:备份中 2:备份成功 3:备份失败
+	BaseID       uint           `gorm:"index" json:"base_id"`    // 增量备份基于的上一次备份
+	Since        *time.Time     `json:"since"`                   // 增量备份包含该时间之后修改的记录
+	IncludeFiles bool           `json:"include_files"`           // 是否包含附件文件
+	Version      int            `json:"version"`                 // 备份文件格式版本
+	Checksum     string         `gorm:"size:64" json:"checksum"` // 备份文件SHA-256
+	TableCount   int            `json:"table_count"`
+	RowCount     int64          `json:"row_count"`
+	FileCount    int            `json:"file_count"`
+	Progress     int            `json:"progress"`                // 备份进度(0-100)
+	Message      string         `gorm:"size:500" json:"message"` // 失败原因
+	FinishedAt   *time.Time     `json:"finished_at"`
+	RestoredAt   *time.Time     `json:"restored_at"` // 最近一次恢复时间
+	CreatedBy    uint           `json:"created_by"`
+	CreatedAt    time.Time      `json:"created_at"`
+	UpdatedAt    time.Time      `json:"updated_at"`
+	DeletedAt    gorm.DeletedAt `gorm:"index" json:"deleted_at"`
+}
+
+// ScheduledTask 定时任务
+type ScheduledTask struct {
+	ID          uint       `gorm:"primarykey" json:"id"`
+	Name        string     `gorm:"size:50;not null" json:"name"`
+	Description string     `gorm:"size:255" json:"description"`
+	Cron        string     `gorm:"size:50;not null" json:"cron"`      // cron表达式，支持秒级(6位)及 @daily、@every 1h 等写法
+	Command     string     `gorm:"type:text;not null" json:"command"` // 执行的任务名称，须为已注册的任务，见 /api/system/scheduled-jobs
+	Params      string     `gorm:"type:text" json:"params"`           // 任务参数，一般为JSON
+	Status      int        `gorm:"default:1" json:"status"`           // 1:启用 2:禁用
+	LastRunAt   *time.Time `json:"last_run_at"`                       // 最近一次按计划运行的时间
+	LastStatus  int        `json:"last_status"`                       // 最近一次运行结果 1:运行中 2:成功 3:失败
+	LockedBy    string     `gorm:"size:100" json:"locked_by"`         // 正在运行该任务的实例
+	LockedUntil *time.Time `json:"-"`                                 // 运行锁过期时间，实例异常退出后锁到期自动释放
+}
+
+// ScheduledTaskRun 定时任务运行记录
+type ScheduledTaskRun struct {
+	ID          uint       `gorm:"primarykey" json:"id"`
+	TaskID      uint       `gorm:"not null;index" json:"task_id"`
+	Command     string     `gorm:"size:100" json:"command"`
+	TriggerType string     `gorm:"size:20" json:"trigger_type"` // schedule:按计划 manual:手动运行
+	TriggeredBy uint       `json:"triggered_by"`                // 手动运行的用户
+	Instance    string     `gorm:"size:100" json:"instance"`
+	Status      int        `gorm:"default:1" json:"status"` // 1:运行中 2:成功 3:失败
+	Output      string     `gorm:"type:text" json:"output"`
+	Error       string     `gorm:"type:text" json:"error"`
+	StartedAt   time.Time  `gorm:"index" json:"started_at"`
+	FinishedAt  *time.Time `json:"finished_at"`
+	Duration    int64      `json:"duration"` // 耗时(毫秒)
+}
+
+// Todo 待办事项模型
+type Todo struct {
+	ID          uint           `gorm:"primarykey" json:"id"`
+	Title       string         `gorm:"size:100;not null" json:"title"`
+	Content     string         `gorm:"type:text" json:"content"`
+	Type        int            `gorm:"not null" json:"type"`    // 1:审批任务 2:工作任务
+	Status      int            `gorm:"default:1" json:"status"` // 1:待完成 2:已完成
+	UserID      uint           `gorm:"not null" json:"user_id"`
+	DueDate     *time.Time     `json:"due_date"`
+	CompletedAt *time.Time     `json:"completed_at"`
+	CreatedAt   time.Time      `json:"created_at"`
+	UpdatedAt   time.Time      `json:"updated_at"`
+	DeletedAt   gorm.DeletedAt `gorm:"index" json:"deleted_at"`
+}
+
+// TableName 指定表名
+func (Todo) TableName() string {
+	return "todos"
+}
+
+// Vehicle 车辆信息
+type Vehicle struct {
+	ID           uint           `gorm:"primarykey" json:"id"`
+	PlateNumber  string         `gorm:"size:20;not null;unique" json:"plate_number"` // 车牌号
+	Brand        string         `gorm:"size:50;not null" json:"brand"`               // 品牌
+	Model        string         `gorm:"size:100;not null" json:"model"`              // 型号
+	Color        string         `gorm:"size:20" json:"color"`                        // 颜色
+	PurchaseDate *time.Time     `json:"purchase_date"`                               // 购买日期
+	Price        float64        `gorm:"type:decimal(10,2)" json:"price"`             // 购买价格
+	EngineNumber string         `gorm:"size:50" json:"engine_number"`                // 发动机号
+	VIN          string         `gorm:"size:50" json:"vin"`                          // 车架号
+	Status       int            `gorm:"default:1" json:"status"`                     // 1:闲置 2:使用中 3:维修中 4:报废
+	UserID       *uint          `json:"user_id"`                                     // 使用人ID
+	DepartmentID *uint          `json:"department_id"`                               // 使用部门ID
+	Files        string         `gorm:"type:text" json:"files"`                      // 附件，JSON数组
+	Remark       string         `gorm:"size:500" json:"remark"`                      // 备注
+	CreatedBy    uint           `gorm:"not null" json:"created_by"`                  // 创建人ID
+	CreatedAt    time.Time      `json:"created_at"`
+	UpdatedAt    time.Time      `json:"updated_at"`
+	DeletedAt    gorm.DeletedAt `gorm:"index" json:"deleted_at"`
+}
+
+// VehicleRepair 车辆维修记录
+type VehicleRepair struct {
+	ID          uint           `gorm:"primarykey" json:"id"`
+	VehicleID   uint           `gorm:"not null" json:"vehicle_id"`     // 车辆ID
+	Type        int            `gorm:"not null" json:"type"`           // 1:内部维修 2:外部维修
+	Reason      string         `gorm:"size:500" json:"reason"`         // 维修原因
+	Description string         `gorm:"size:500" json:"description"`    // 维修说明
+	StartDate   *time.Time     `json:"start_date"`                     // 维修开始日期
+	EndDate     *time.Time     `json:"end_date"`                       // 维修结束日期
+	Cost        float64        `gorm:"type:decimal(10,2)" json:"cost"` // 维修费用
+	RepairBy    string         `gorm:"size:100" json:"repair_by"`      // 维修人/维修单位
+	Status      int            `gorm:"default:1" json:"status"`        // 1:待维修 2:维修中 3:已完成
+	Result      string         `gorm:"size:500" json:"result"`         // 维修结果
+	Files       string         `gorm:"type:text" json:"files"`         // 附件，JSON数组
+	Remark      string         `gorm:"size:500" json:"remark"`         // 备注
+	CreatedBy   uint           `gorm:"not null" json:"created_by"`     // 创建人ID
+	CreatedAt   time.Time      `json:"created_at"`
+	UpdatedAt   time.Time      `json:"updated_at"`
+	DeletedAt   gorm.DeletedAt `gorm:"index" json:"deleted_at"`
+}
+
+// VehicleMaintenance 车辆保养记录
+type VehicleMaintenance struct {
+	ID            uint           `gorm:"primarykey" json:"id"`
+	VehicleID     uint           `gorm:"not null" json:"vehicle_id"`     // 车辆ID
+	Type          int            `gorm:"not null" json:"type"`           // 1:定期保养 2:临时保养
+	Description   string         `gorm:"size:500" json:"description"`    // 保养说明
+	StartDate     *time.Time     `json:"start_date"`                     // 保养开始日期
+	EndDate       *time.Time     `json:"end_date"`                       // 保养结束日期
+	Cost          float64        `gorm:"type:decimal(10,2)" json:"cost"` // 保养费用
+	MaintenanceBy string         `gorm:"size:100" json:"maintenance_by"` // 保养人/保养单位
+	Status        int            `gorm:"default:1" json:"status"`        // 1:待保养 2:保养中 3:已完成
+	Result        string         `gorm:"size:500" json:"result"`         // 保养结果
+	Files         string         `gorm:"type:text" json:"files"`         // 附件，JSON数组
+	Remark        string         `gorm:"size:500" json:"remark"`         // 备注
+	CreatedBy     uint           `gorm:"not null" json:"created_by"`     // 创建人ID
+	CreatedAt     time.Time      `json:"created_at"`
+	UpdatedAt     time.Time      `json:"updated_at"`
+	DeletedAt     gorm.DeletedAt `gorm:"index" json:"deleted_at"`
+}
+
+// VehicleMileage 车辆里程记录
+type VehicleMileage struct {
+	ID           uint           `gorm:"primarykey" json:"id"`
+	VehicleID    uint           `gorm:"not null" json:"vehicle_id"`              // 车辆ID
+	Date         *time.Time     `json:"date"`                                    // 记录日期
+	StartMileage float64        `gorm:"type:decimal(10,2)" json:"start_mileage"` // 起始里程
+	EndMileage   float64        `gorm:"type:decimal(10,2)" json:"end_mileage"`   // 结束里程
+	Distance     float64        `gorm:"type:decimal(10,2)" json:"distance"`      // 行驶里程
+	Files        string         `gorm:"type:text" json:"files"`                  // 附件，JSON数组
+	Remark       string         `gorm:"size:500" json:"remark"`                  // 备注
+	CreatedBy    uint           `gorm:"not null" json:"created_by"`              // 创建人ID
+	CreatedAt    time.Time      `json:"created_at"`
+	UpdatedAt    time.Time      `json:"updated_at"`
+	DeletedAt    gorm.DeletedAt `gorm:"index" json:"deleted_at"`
+}
+
+// VehicleExpenseRecord 车辆费用记录
+type VehicleExpenseRecord struct {
+	ID        uint           `gorm:"primarykey" json:"id"`
+	VehicleID uint           `gorm:"not null" json:"vehicle_id"`       // 车辆ID
+	ExpenseID uint           `gorm:"not null" json:"expense_id"`       // 费用类型ID
+	Date      *time.Time     `json:"date"`                             // 费用日期
+	Amount    float64        `gorm:"type:decimal(10,2)" json:"amount"` // 费用金额
+	Files     string         `gorm:"type:text" json:"files"`           // 附件，JSON数组
+	Remark    string         `gorm:"size:500" json:"remark"`           // 备注
+	CreatedBy uint           `gorm:"not null" json:"created_by"`       // 创建人ID
+	CreatedAt time.Time      `json:"created_at"`
+	UpdatedAt time.Time      `json:"updated_at"`
+	DeletedAt gorm.DeletedAt `gorm:"index" json:"deleted_at"`
+}
+
+// VehicleViolation 车辆违章记录
+type VehicleViolation struct {
+	ID          uint           `gorm:"primarykey" json:"id"`
+	VehicleID   uint           `gorm:"not null" json:"vehicle_id"`       // 车辆ID
+	Date        *time.Time     `json:"date"`                             // 违章日期
+	Location    string         `gorm:"size:255" json:"location"`         // 违章地点
+	Description string         `gorm:"size:500" json:"description"`      // 违章说明
+	Points      int            `json:"points"`                           // 扣分
+	Amount      float64        `gorm:"type:decimal(10,2)" json:"amount"` // 罚款金额
+	Status      int            `gorm:"default:1" json:"status"`          // 1:未处理 2:处理中 3:已处理
+	Files       string         `gorm:"type:text" json:"files"`           // 附件，JSON数组
+	Remark      string         `gorm:"size:500" json:"remark"`           // 备注
+	CreatedBy   uint           `gorm:"not null" json:"created_by"`       // 创建人ID
+	CreatedAt   time.Time      `json:"created_at"`
+	UpdatedAt   time.Time      `json:"updated_at"`
+	DeletedAt   gorm.DeletedAt `gorm:"index" json:"deleted_at"`
+}
+
+// VehicleAccident 车辆事故记录
+type VehicleAccident struct {
+	ID             uint           `gorm:"primarykey" json:"id"`
+	VehicleID      uint           `gorm:"not null" json:"vehicle_id"`       // 车辆ID
+	Date           *time.Time     `json:"date"`                             // 事故日期
+	Location       string         `gorm:"size:255" json:"location"`         // 事故地点
+	Description    string         `gorm:"size:500" json:"description"`      // 事故说明
+	Type           int            `gorm:"not null" json:"type"`             // 1:轻微事故 2:一般事故 3:重大事故
+	Responsibility int            `gorm:"default:1" json:"responsibility"`  // 1:全责 2:主责 3:次责 4:无责
+	Amount         float64        `gorm:"type:decimal(10,2)" json:"amount"` // 损失金额
+	Status         int            `gorm:"default:1" json:"status"`          // 1:未处理 2:处理中 3:已处理
+	Files          string         `gorm:"type:text" json:"files"`           // 附件，JSON数组
+	Remark         string         `gorm:"size:500" json:"remark"`           // 备注
+	CreatedBy      uint           `gorm:"not null" json:"created_by"`       // 创建人ID
+	CreatedAt      time.Time      `json:"created_at"`
+	UpdatedAt      time.Time      `json:"updated_at"`
+	DeletedAt      gorm.DeletedAt `gorm:"index" json:"deleted_at"`
+}
+
+// VehicleApplication 用车申请
+type VehicleApplication struct {
+	ID               uint           `gorm:"primarykey" json:"id"`
+	VehicleID        uint           `gorm:"not null" json:"vehicle_id"`    // 车辆ID
+	UserID           uint           `gorm:"not null" json:"user_id"`       // 申请人ID
+	DepartmentID     uint           `gorm:"not null" json:"department_id"` // 申请部门ID
+	StartDate        *time.Time     `json:"start_date"`                    // 用车开始日期
+	EndDate          *time.Time     `json:"end_date"`                      // 用车结束日期
+	Destination      string         `gorm:"size:255" json:"destination"`   // 目的地
+	Purpose          string         `gorm:"size:500" json:"purpose"`       // 用车事由
+	Passengers       string         `gorm:"size:500" json:"passengers"`    // 随行人员
+	Status           int            `gorm:"default:1" json:"status"`       // 1:待审批 2:已通过 3:已驳回
+	ApprovalRecordID *uint          `json:"approval_record_id"`            // 关联的审批记录ID
+	Files            string         `gorm:"type:text" json:"files"`        // 附件，JSON数组
+	Remark           string         `gorm:"size:500" json:"remark"`        // 备注
+	CreatedAt        time.Time      `json:"created_at"`
+	UpdatedAt        time.Time      `json:"updated_at"`
+	DeletedAt        gorm.DeletedAt `gorm:"index" json:"deleted_at"`
+	CreatedBy        uint           `json:"created_by"`
+}
+
+// VehicleReturn 车辆归还记录
+type VehicleReturn struct {
+	ID            uint           `gorm:"primarykey" json:"id"`
+	ApplicationID uint           `gorm:"not null" json:"application_id"`          // 用车申请ID
+	ReturnDate    *time.Time     `json:"return_date"`                             // 归还日期
+	StartMileage  float64        `gorm:"type:decimal(10,2)" json:"start_mileage"` // 起始里程
+	EndMileage    float64        `gorm:"type:decimal(10,2)" json:"end_mileage"`   // 结束里程
+	Distance      float64        `gorm:"type:decimal(10,2)" json:"distance"`      // 行驶里程
+	Status        int            `gorm:"default:1" json:"status"`                 // 1:正常 2:异常
+	Problem       string         `gorm:"size:500" json:"problem"`                 // 问题说明
+	Files         string         `gorm:"type:text" json:"files"`                  // 附件，JSON数组
+	Remark        string         `gorm:"size:500" json:"remark"`                  // 备注
+	CreatedBy     uint           `gorm:"not null" json:"created_by"`              // 创建人ID
+	CreatedAt     time.Time      `json:"created_at"`
+	UpdatedAt     time.Time      `json:"updated_at"`
+	DeletedAt     gorm.DeletedAt `gorm:"index" json:"deleted_at"`
+}
+
+// TableName 指定表名
+func (Vehicle) TableName() string {
+	return "vehicles"
+}
+
+func (VehicleRepair) TableName() string {
+	return "vehicle_repairs"
+}
+
+func (VehicleMaintenance) TableName() string {
+	return "vehicle_maintenances"
+}
+
+func (VehicleMileage) TableName() string {
+	return "vehicle_mileages"
+}
+
+func (VehicleExpenseRecord) TableName() string {
+	return "vehicle_expense_records"
+}
+
+func (VehicleViolation) TableName() string {
+	return "vehicle_violations"
+}
+
+func (VehicleAccident) TableName() string {
+	return "vehicle_accidents"
+}
+
+func (VehicleApplication) TableName() string {
+	return "vehicle_applications"
+}
+
+func (VehicleReturn) TableName() string {
+	return "vehicle_returns"
+}
+
+// WorkflowType 流程类型
+type WorkflowType struct {
+	ID          uint           `gorm:"primarykey" json:"id"`
+	Name        string         `gorm:"size:50;not null" json:"name"` // 类型名称
+	Code        string         `gorm:"size:50;unique" json:"code"`   // 类型编码
+	Description string         `gorm:"size:500" json:"description"`  // 类型说明
+	Sort        int            `gorm:"default:0" json:"sort"`        // 排序
+	Status      int            `gorm:"default:1" json:"status"`      // 1:启用 2:禁用
+	CreatedBy   uint           `gorm:"not null" json:"created_by"`   // 创建人ID
+	CreatedAt   time.Time      `json:"created_at"`
+	UpdatedAt   time.Time      `json:"updated_at"`
+	DeletedAt   gorm.DeletedAt `gorm:"index" json:"deleted_at"`
+}
+
+// WorkflowDefinition 流程定义
+type WorkflowDefinition struct {
+	ID          uint           `gorm:"primarykey" json:"id"`
+	Name        string         `gorm:"size:100;not null" json:"name"` // 流程名称
+	TypeID      uint           `gorm:"not null" json:"type_id"`       // 流程类型ID
+	Description string         `gorm:"size:500" json:"description"`   // 流程说明
+	Form        string         `gorm:"type:text" json:"form"`         // 表单配置，JSON格式
+	Status      int            `gorm:"default:1" json:"status"`       // 1:草稿 2:已发布 3:已停用
+	Version     int            `gorm:"default:1" json:"version"`      // 版本号
+	CreatedBy   uint           `gorm:"not null" json:"created_by"`    // 创建人ID
+	CreatedAt   time.Time      `json:"created_at"`
+	UpdatedAt   time.Time      `json:"updated_at"`
+	DeletedAt   gorm.DeletedAt `gorm:"index" json:"deleted_at"`
+}
+
+// WorkflowNode 流程节点
+type WorkflowNode struct {
+	ID           uint           `gorm:"primarykey" json:"id"`
+	DefinitionID uint           `gorm:"not null" json:"definition_id"` // 流程定义ID
+	Name         string         `gorm:"size:50;not null" json:"name"`  // 节点名称
+	Type         int            `gorm:"not null" json:"type"`          // 1:开始 2:审批 3:抄送 4:条件 5:并行 6:结束
+	Config       string         `gorm:"type:text" json:"config"`       // 节点配置，JSON格式
+	Sort         int            `gorm:"default:0" json:"sort"`         // 排序
+	CreatedBy    uint           `gorm:"not null" json:"created_by"`    // 创建人ID
+	CreatedAt    time.Time      `json:"created_at"`
+	UpdatedAt    time.Time      `json:"updated_at"`
+	DeletedAt    gorm.DeletedAt `gorm:"index" json:"deleted_at"`
+}
+
+// WorkflowInstance 流程实例
+type WorkflowInstance struct {
+	ID           uint           `gorm:"primarykey" json:"id"`
+	DefinitionID uint           `gorm:"not null" json:"definition_id"`  // 流程定义ID
+	Title        string         `gorm:"size:200;not null" json:"title"` // 流程标题
+	Content      string         `gorm:"type:text" json:"content"`       // 流程内容
+	FormData     string         `gorm:"type:text" json:"form_data"`     // 表单数据，JSON格式
+	Status       int            `gorm:"default:1" json:"status"`        // 1:进行中 2:已完成 3:已取消
+	StartTime    *time.Time     `json:"start_time"`                     // 开始时间
+	EndTime      *time.Time     `json:"end_time"`                       // 结束时间
+	Files        string         `gorm:"type:text" json:"files"`         // 附件，JSON数组
+	CreatedBy    uint           `gorm:"not null" json:"created_by"`     // 创建人ID
+	CreatedAt    time.Time      `json:"created_at"`
+	UpdatedAt    time.Time      `json:"updated_at"`
+	DeletedAt    gorm.DeletedAt `gorm:"index" json:"deleted_at"`
+}
+
+// WorkflowTask 流程任务
+type WorkflowTask struct {
+	ID         uint           `gorm:"primarykey" json:"id"`
+	InstanceID uint           `gorm:"not null" json:"instance_id"` // 流程实例ID
+	NodeID     uint           `gorm:"not null" json:"node_id"`     // 流程节点ID
+	AssigneeID uint           `gorm:"not null" json:"assignee_id"` // 处理人ID
+	Action     int            `gorm:"default:0" json:"action"`     // 0:未处理 1:同意 2:驳回 3:转办 4:已阅
+	Comment    string         `gorm:"size:500" json:"comment"`     // 处理意见
+	HandleTime *time.Time     `json:"handle_time"`                 // 处理时间
+	Status     int            `gorm:"default:1" json:"status"`     // 1:待处理 2:已处理 3:已转办 4:已取消
+	CreatedAt  time.Time      `json:"created_at"`
+	UpdatedAt  time.Time      `json:"updated_at"`
+	DeletedAt  gorm.DeletedAt `gorm:"index" json:"deleted_at"`
+}
+
+// TableName 指定表名
+func (WorkflowType) TableName() string {
+	return "workflow_types"
+}
+
+func (WorkflowDefinition) TableName() string {
+	return "workflow_definitions"
+}
+
+func (WorkflowNode) TableName() string {
+	return "workflow_nodes"
+}
+
+func (WorkflowInstance) TableName() string {
+	return "workflow_instances"
+}
+
+func (WorkflowTask) TableName() string {
+	return "workflow_tasks"
+}
@@ -0,0 +1,145 @@
+package database
+
+import (
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/spf13/viper"
+	"gorm.io/gorm"
+)
+
+// 支持的数据库
+const (
+	DriverMySQL    = "mysql"
+	DriverPostgres = "postgres"
+	DriverSQLite   = "sqlite"
+)
+
+var DB *gorm.DB
+
+// Init 初始化数据库连接并检查数据库结构，存在未执行的迁移时拒绝启动，需先执行 migrate up
+func Init() error {
+	if err := Open(); err != nil {
+		return err
+	}
+	if err := CheckSchema(DB); err != nil {
+		return fmt.Errorf("%w, run `lemon-oa migrate up` first", err)
+	}
+	return nil
+}
+
+// Open 按 database.driver 配置打开数据库连接：mysql(默认)、postgres，或适合小型部署和本地开发的 sqlite，
+// 连接参数分别见 mysql、postgres、sqlite 配置节点，不检查数据库结构
+func Open() error {
+	dialector, err := openDialector(viper.GetString("database.driver"))
+	if err != nil {
+		return err
+	}
+
+	db, err := connect(dialector)
+	if err != nil {
+		return fmt.Errorf("failed to connect to database: %v", err)
+	}
+
+	sqlDB, err := db.DB()
+	if err != nil {
+		return fmt.Errorf("failed to get database instance: %v", err)
+	}
+
+	sqlDB.SetMaxIdleConns(poolSetting("max_idle_conns"))
+	sqlDB.SetMaxOpenConns(poolSetting("max_open_conns"))
+
+	// 数据库语句耗时和连接池指标
+	if err := db.Use(metricsPlugin{}); err != nil {
+		return fmt.Errorf("failed to register metrics plugin: %v", err)
+	}
+	if err := registerPoolMetrics(sqlDB, db.Dialector.Name()); err != nil {
+		return fmt.Errorf("failed to register pool metrics: %v", err)
+	}
+
+	// 写入数据时从上下文填充创建人
+	if err := registerActorCallbacks(db); err != nil {
+		return fmt.Errorf("failed to register callbacks: %v", err)
+	}
+
+	// 加载字段加密密钥，迁移和读写加密字段前必须完成
+	if err := InitFieldEncryption(); err != nil {
+		return fmt.Errorf("failed to initialize field encryption: %v", err)
+	}
+
+	DB = db
+	return nil
+}
+
+// 连接数据库，失败时按 database.connect_retries 次数重试，间隔从 database.connect_backoff 秒开始翻倍，
+// 便于数据库与程序同时启动时(如容器编排)等待数据库就绪
+func connect(dialector gorm.Dialector) (*gorm.DB, error) {
+	retries := viper.GetInt("database.connect_retries")
+	backoff := time.Duration(viper.GetInt("database.connect_backoff")) * time.Second
+	if backoff <= 0 {
+		backoff = time.Second
+	}
+
+	for attempt := 1; ; attempt++ {
+		db, err := gorm.Open(dialector, &gorm.Config{Logger: newSlogLogger()})
+		if err == nil {
+			return db, nil
+		}
+		if db != nil {
+			if sqlDB, dbErr := db.DB(); dbErr == nil {
+				sqlDB.Close()
+			}
+		}
+		if attempt > retries {
+			return nil, err
+		}
+
+		slog.Warn("连接数据库失败，稍后重试", "backoff", backoff.String(), "attempt", attempt, "error", err)
+		time.Sleep(backoff)
+		backoff *= 2
+		if backoff > maxConnectBackoff {
+			backoff = maxConnectBackoff
+		}
+	}
+}
+
+// 重试间隔上限
+const maxConnectBackoff = 30 * time.Second
+
+func openDialector(driver string) (gorm.Dialector, error) {
+	switch driver {
+	case "", DriverMySQL:
+		return mysqlDialector(), nil
+	case DriverPostgres:
+		return postgresDialector(), nil
+	case DriverSQLite:
+		return sqliteDialector()
+	default:
+		return nil, fmt.Errorf("unsupported database driver %q", driver)
+	}
+}
+
+// 连接池配置，database 节点未配置时沿用 mysql 节点中的配置
+func poolSetting(key string) int {
+	if viper.IsSet("database." + key) {
+		return viper.GetInt("database." + key)
+	}
+	return viper.GetInt("mysql." + key)
+}
+
+// Close 关闭数据库和Redis连接，服务退出时调用
+func Close() {
+	if DB != nil {
+		if sqlDB, err := DB.DB(); err == nil {
+			if err := sqlDB.Close(); err != nil {
+				slog.Error("关闭数据库连接失败", "error", err)
+			}
+		}
+	}
+	if Redis != nil {
+		if err := Redis.Close(); err != nil {
+			slog.Error("关闭Redis连接失败", "error", err)
+		}
+	}
+}
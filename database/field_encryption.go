@@ -0,0 +1,243 @@
+package database
+
+import (
+	"bytes"
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/lemonoa/LemonOA-Go/model"
+
+	"github.com/spf13/viper"
+	"gorm.io/gorm"
+	"gorm.io/gorm/schema"
+)
+
+// 密文格式为 "enc:" + 密钥ID + ":" + base64(随机数 + AES-GCM密文)，不带前缀的值视为加密上线前的明文
+const encryptedPrefix = "enc:"
+
+// 包含加密字段的模型，轮换密钥时重新加密
+var encryptedModels = []interface{}{
+	&model.User{},
+	&model.Employee{},
+	&model.EmployeeArchive{},
+}
+
+var (
+	fieldKeysMu        sync.RWMutex
+	fieldKeys          map[string]cipher.AEAD
+	fieldActiveKey     string
+	errNoFieldKey      = errors.New("field encryption key is not configured")
+	errUnknownFieldKey = errors.New("unknown field encryption key")
+)
+
+func init() {
+	schema.RegisterSerializer("encrypted", EncryptedSerializer{})
+}
+
+// InitFieldEncryption 加载字段加密密钥，encryption.keys 为密钥ID到base64编码的32字节密钥的映射，
+// 新数据使用 encryption.active_key 加密，旧密钥保留用于解密，轮换后可调用 ReencryptFields 重新加密
+func InitFieldEncryption() error {
+	keys := make(map[string]cipher.AEAD)
+	for id, value := range viper.GetStringMapString("encryption.keys") {
+		raw, err := base64.StdEncoding.DecodeString(value)
+		if err != nil || len(raw) != 32 {
+			return fmt.Errorf("encryption key %q must be 32 bytes encoded in base64", id)
+		}
+		// 拒绝配置文件中的示例密钥
+		if bytes.Count(raw, []byte{0}) == len(raw) {
+			return fmt.Errorf("encryption key %q is all zeros, generate one with openssl rand -base64 32", id)
+		}
+		block, err := aes.NewCipher(raw)
+		if err != nil {
+			return err
+		}
+		aead, err := cipher.NewGCM(block)
+		if err != nil {
+			return err
+		}
+		keys[id] = aead
+	}
+
+	active := viper.GetString("encryption.active_key")
+	if _, ok := keys[active]; !ok {
+		return fmt.Errorf("encryption active key %q is not configured", active)
+	}
+
+	fieldKeysMu.Lock()
+	fieldKeys = keys
+	fieldActiveKey = active
+	fieldKeysMu.Unlock()
+	return nil
+}
+
+// 使用当前密钥加密
+func encryptField(plain []byte) (string, error) {
+	fieldKeysMu.RLock()
+	id, aead := fieldActiveKey, fieldKeys[fieldActiveKey]
+	fieldKeysMu.RUnlock()
+	if aead == nil {
+		return "", errNoFieldKey
+	}
+
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", err
+	}
+	sealed := aead.Seal(nonce, nonce, plain, nil)
+	return encryptedPrefix + id + ":" + base64.StdEncoding.EncodeToString(sealed), nil
+}
+
+// 按密文中的密钥ID解密，不带前缀的明文原样返回
+func decryptField(value string) ([]byte, error) {
+	if !strings.HasPrefix(value, encryptedPrefix) {
+		return []byte(value), nil
+	}
+	parts := strings.SplitN(strings.TrimPrefix(value, encryptedPrefix), ":", 2)
+	if len(parts) != 2 {
+		return nil, errors.New("malformed encrypted field")
+	}
+
+	fieldKeysMu.RLock()
+	aead := fieldKeys[parts[0]]
+	fieldKeysMu.RUnlock()
+	if aead == nil {
+		return nil, errUnknownFieldKey
+	}
+
+	sealed, err := base64.StdEncoding.DecodeString(parts[1])
+	if err != nil || len(sealed) < aead.NonceSize() {
+		return nil, errors.New("malformed encrypted field")
+	}
+	nonce, ciphertext := sealed[:aead.NonceSize()], sealed[aead.NonceSize():]
+	return aead.Open(nil, nonce, ciphertext, nil)
+}
+
+// EncryptedSerializer 字段加密序列化器，字段标记 `gorm:"serializer:encrypted"` 后写入时加密、读取时解密
+// 字符串字段直接加密，其他类型先序列化为JSON；空字符串和nil不加密
+type EncryptedSerializer struct{}
+
+// Scan 解密数据库中的值
+func (EncryptedSerializer) Scan(ctx context.Context, field *schema.Field, dst reflect.Value, dbValue interface{}) error {
+	fieldValue := reflect.New(field.FieldType)
+
+	var raw string
+	switch v := dbValue.(type) {
+	case nil:
+	case []byte:
+		raw = string(v)
+	case string:
+		raw = v
+	case time.Time:
+		// 加密上线前的日期列
+		raw = v.Format(time.RFC3339)
+	default:
+		return fmt.Errorf("failed to decrypt field %s: unsupported value %#v", field.Name, dbValue)
+	}
+
+	if raw != "" {
+		plain, err := decryptField(raw)
+		if err != nil {
+			return fmt.Errorf("failed to decrypt field %s: %v", field.Name, err)
+		}
+		if err := decodeFieldValue(fieldValue.Elem(), plain, !strings.HasPrefix(raw, encryptedPrefix)); err != nil {
+			return fmt.Errorf("failed to decode field %s: %v", field.Name, err)
+		}
+	}
+
+	field.ReflectValueOf(ctx, dst).Set(fieldValue.Elem())
+	return nil
+}
+
+// Value 加密字段值
+func (EncryptedSerializer) Value(ctx context.Context, field *schema.Field, dst reflect.Value, fieldValue interface{}) (interface{}, error) {
+	rv := reflect.ValueOf(fieldValue)
+	if !rv.IsValid() || (rv.Kind() == reflect.Ptr && rv.IsNil()) {
+		return nil, nil
+	}
+
+	var plain []byte
+	if rv.Kind() == reflect.String {
+		if rv.String() == "" {
+			return "", nil
+		}
+		plain = []byte(rv.String())
+	} else {
+		data, err := json.Marshal(fieldValue)
+		if err != nil {
+			return nil, err
+		}
+		plain = data
+	}
+	return encryptField(plain)
+}
+
+// 解码明文，legacy 为true时是加密上线前的明文数据，日期可能是数据库的日期格式
+func decodeFieldValue(dst reflect.Value, plain []byte, legacy bool) error {
+	if dst.Kind() == reflect.String {
+		dst.SetString(string(plain))
+		return nil
+	}
+
+	err := json.Unmarshal(plain, dst.Addr().Interface())
+	if err == nil || !legacy {
+		return err
+	}
+	for _, layout := range []string{time.RFC3339, "2006-01-02 15:04:05", "2006-01-02"} {
+		if t, perr := time.ParseInLocation(layout, string(plain), time.Local); perr == nil {
+			data, _ := json.Marshal(t)
+			return json.Unmarshal(data, dst.Addr().Interface())
+		}
+	}
+	return err
+}
+
+// ReencryptFields 使用当前密钥重新加密全部加密字段，用于轮换密钥或加密上线前的明文数据，返回处理的记录数
+func ReencryptFields(db *gorm.DB) (int64, error) {
+	var total int64
+	for _, m := range encryptedModels {
+		if !db.Migrator().HasTable(m) {
+			continue
+		}
+
+		stmt := &gorm.Statement{DB: db}
+		if err := stmt.Parse(m); err != nil {
+			return total, err
+		}
+		var columns []string
+		for _, field := range stmt.Schema.Fields {
+			if strings.EqualFold(field.TagSettings["SERIALIZER"], "encrypted") {
+				columns = append(columns, field.DBName)
+			}
+		}
+		if len(columns) == 0 {
+			continue
+		}
+
+		rows := reflect.New(reflect.SliceOf(reflect.TypeOf(m).Elem()))
+		result := db.Unscoped().Model(m).FindInBatches(rows.Interface(), 200, func(tx *gorm.DB, batch int) error {
+			list := rows.Elem()
+			for i := 0; i < list.Len(); i++ {
+				row := list.Index(i).Addr().Interface()
+				if err := db.Unscoped().Model(row).Select(columns).UpdateColumns(row).Error; err != nil {
+					return err
+				}
+			}
+			total += int64(list.Len())
+			return nil
+		})
+		if result.Error != nil {
+			return total, result.Error
+		}
+	}
+	return total, nil
+}
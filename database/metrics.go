@@ -0,0 +1,76 @@
+package database
+
+import (
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/lemonoa/LemonOA-Go/metrics"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"gorm.io/gorm"
+)
+
+const metricsStartKey = "lemonoa:metrics_start"
+
+// 统计各类数据库语句耗时的GORM插件
+type metricsPlugin struct{}
+
+func (metricsPlugin) Name() string {
+	return "lemonoa:metrics"
+}
+
+func (metricsPlugin) Initialize(db *gorm.DB) error {
+	cb := db.Callback()
+	return errors.Join(
+		cb.Create().Before("*").Register("lemonoa:metrics_before_create", startTimer),
+		cb.Create().After("*").Register("lemonoa:metrics_after_create", observeQuery("create")),
+		cb.Query().Before("*").Register("lemonoa:metrics_before_query", startTimer),
+		cb.Query().After("*").Register("lemonoa:metrics_after_query", observeQuery("query")),
+		cb.Update().Before("*").Register("lemonoa:metrics_before_update", startTimer),
+		cb.Update().After("*").Register("lemonoa:metrics_after_update", observeQuery("update")),
+		cb.Delete().Before("*").Register("lemonoa:metrics_before_delete", startTimer),
+		cb.Delete().After("*").Register("lemonoa:metrics_after_delete", observeQuery("delete")),
+		cb.Row().Before("*").Register("lemonoa:metrics_before_row", startTimer),
+		cb.Row().After("*").Register("lemonoa:metrics_after_row", observeQuery("row")),
+		cb.Raw().Before("*").Register("lemonoa:metrics_before_raw", startTimer),
+		cb.Raw().After("*").Register("lemonoa:metrics_after_raw", observeQuery("raw")),
+	)
+}
+
+func startTimer(db *gorm.DB) {
+	db.InstanceSet(metricsStartKey, time.Now())
+}
+
+func observeQuery(operation string) func(db *gorm.DB) {
+	return func(db *gorm.DB) {
+		value, ok := db.InstanceGet(metricsStartKey)
+		if !ok {
+			return
+		}
+		start, ok := value.(time.Time)
+		if !ok {
+			return
+		}
+
+		table := db.Statement.Table
+		if table == "" {
+			table = "unknown"
+		}
+		metrics.DBQueryDuration.WithLabelValues(operation, table).Observe(time.Since(start).Seconds())
+		if db.Error != nil && !errors.Is(db.Error, gorm.ErrRecordNotFound) {
+			metrics.DBQueryErrors.WithLabelValues(operation, table).Inc()
+		}
+	}
+}
+
+// 注册连接池指标，包括 max_open_conns 上限、使用中和空闲连接数、等待次数和时长
+func registerPoolMetrics(sqlDB *sql.DB, name string) error {
+	err := metrics.Registry.Register(collectors.NewDBStatsCollector(sqlDB, name))
+	var registered prometheus.AlreadyRegisteredError
+	if errors.As(err, &registered) {
+		return nil
+	}
+	return err
+}
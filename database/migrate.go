@@ -0,0 +1,184 @@
+package database
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Migration 一个版本的数据库结构变更，按 Version 升序执行，已发布的迁移不可修改，结构变更需新增版本
+type Migration struct {
+	Version uint
+	Name    string
+	Up      func(tx *gorm.DB) error
+	Down    func(tx *gorm.DB) error
+}
+
+// MigrationState 迁移的执行状态，AppliedAt 为空表示尚未执行
+type MigrationState struct {
+	Version   uint       `json:"version"`
+	Name      string     `json:"name"`
+	AppliedAt *time.Time `json:"applied_at"`
+}
+
+var (
+	ErrSchemaOutdated   = errors.New("database schema is out of date")
+	ErrSchemaTooNew     = errors.New("database schema is newer than this build")
+	ErrBaselineRollback = errors.New("rolling back the baseline migration drops every table")
+)
+
+// 基线迁移版本，回滚该版本会删除全部数据表
+const baselineVersion = 1
+
+// 已执行的迁移记录
+type schemaMigration struct {
+	Version   uint   `gorm:"primaryKey;autoIncrement:false"`
+	Name      string `gorm:"size:100;not null"`
+	AppliedAt time.Time
+}
+
+func (schemaMigration) TableName() string {
+	return "schema_migrations"
+}
+
+// Migrations 按版本排序的全部迁移
+func Migrations() []Migration {
+	list := make([]Migration, len(migrations))
+	copy(list, migrations)
+	sort.Slice(list, func(i, j int) bool { return list[i].Version < list[j].Version })
+	return list
+}
+
+// 已执行的迁移，迁移记录表不存在时视为均未执行
+func appliedMigrations(db *gorm.DB) (map[uint]schemaMigration, error) {
+	if !db.Migrator().HasTable(&schemaMigration{}) {
+		return map[uint]schemaMigration{}, nil
+	}
+	var records []schemaMigration
+	if err := db.Find(&records).Error; err != nil {
+		return nil, err
+	}
+	applied := make(map[uint]schemaMigration, len(records))
+	for _, record := range records {
+		applied[record.Version] = record
+	}
+	return applied, nil
+}
+
+// Migrate 按版本顺序执行全部未执行的迁移，返回本次执行的迁移
+// 每个迁移与其版本记录在同一事务中提交，MySQL的DDL语句会隐式提交，失败时需按报错手动处理
+func Migrate(db *gorm.DB) ([]Migration, error) {
+	if err := db.AutoMigrate(&schemaMigration{}); err != nil {
+		return nil, err
+	}
+	applied, err := appliedMigrations(db)
+	if err != nil {
+		return nil, err
+	}
+
+	var done []Migration
+	for _, m := range Migrations() {
+		if _, ok := applied[m.Version]; ok {
+			continue
+		}
+		err := db.Transaction(func(tx *gorm.DB) error {
+			if err := m.Up(tx); err != nil {
+				return err
+			}
+			return tx.Create(&schemaMigration{Version: m.Version, Name: m.Name, AppliedAt: time.Now()}).Error
+		})
+		if err != nil {
+			return done, fmt.Errorf("migration %d_%s: %w", m.Version, m.Name, err)
+		}
+		done = append(done, m)
+	}
+	return done, nil
+}
+
+// Rollback 按版本倒序回滚最近执行的 steps 个迁移，返回本次回滚的迁移
+// 回滚将涉及基线版本时，未指定 force 则不执行任何回滚并返回 ErrBaselineRollback
+func Rollback(db *gorm.DB, steps int, force bool) ([]Migration, error) {
+	applied, err := appliedMigrations(db)
+	if err != nil {
+		return nil, err
+	}
+
+	list := Migrations()
+	var targets []Migration
+	for i := len(list) - 1; i >= 0 && len(targets) < steps; i-- {
+		if _, ok := applied[list[i].Version]; ok {
+			targets = append(targets, list[i])
+		}
+	}
+	for _, m := range targets {
+		if m.Version == baselineVersion && !force {
+			return nil, ErrBaselineRollback
+		}
+	}
+
+	var done []Migration
+	for _, m := range targets {
+		err := db.Transaction(func(tx *gorm.DB) error {
+			if m.Down != nil {
+				if err := m.Down(tx); err != nil {
+					return err
+				}
+			}
+			return tx.Delete(&schemaMigration{}, m.Version).Error
+		})
+		if err != nil {
+			return done, fmt.Errorf("rollback %d_%s: %w", m.Version, m.Name, err)
+		}
+		done = append(done, m)
+	}
+	return done, nil
+}
+
+// MigrationStatus 全部迁移的执行状态
+func MigrationStatus(db *gorm.DB) ([]MigrationState, error) {
+	applied, err := appliedMigrations(db)
+	if err != nil {
+		return nil, err
+	}
+
+	list := Migrations()
+	states := make([]MigrationState, 0, len(list))
+	for _, m := range list {
+		state := MigrationState{Version: m.Version, Name: m.Name}
+		if record, ok := applied[m.Version]; ok {
+			appliedAt := record.AppliedAt
+			state.AppliedAt = &appliedAt
+		}
+		states = append(states, state)
+	}
+	return states, nil
+}
+
+// CheckSchema 检查数据库结构是否与程序一致，存在未执行的迁移或数据库版本高于程序时返回错误
+func CheckSchema(db *gorm.DB) error {
+	applied, err := appliedMigrations(db)
+	if err != nil {
+		return err
+	}
+
+	known := make(map[uint]bool, len(migrations))
+	var pending []uint
+	for _, m := range Migrations() {
+		known[m.Version] = true
+		if _, ok := applied[m.Version]; !ok {
+			pending = append(pending, m.Version)
+		}
+	}
+	for version := range applied {
+		if !known[version] {
+			return fmt.Errorf("%w: unknown migration %d", ErrSchemaTooNew, version)
+		}
+	}
+	if len(pending) > 0 {
+		return fmt.Errorf("%w: pending migrations %v", ErrSchemaOutdated, pending)
+	}
+	return nil
+}
@@ -0,0 +1,109 @@
+package database
+
+import (
+	"github.com/lemonoa/LemonOA-Go/database/baseline"
+
+	"gorm.io/gorm"
+)
+
+// 数据库迁移列表，新增迁移追加到末尾并使用递增的版本号
+var migrations = []Migration{
+	{
+		// 基线版本，按冻结的结构快照创建全部数据表；已有数据表的旧版部署执行时只补齐缺失的表和字段
+		// 回滚会删除全部数据表，需显式强制执行
+		Version: 1,
+		Name:    "baseline",
+		Up: func(tx *gorm.DB) error {
+			return tx.AutoMigrate(baseline.Models()...)
+		},
+		Down: func(tx *gorm.DB) error {
+			models := baseline.Models()
+			for i := len(models) - 1; i >= 0; i-- {
+				if err := tx.Migrator().DropTable(models[i]); err != nil {
+					return err
+				}
+			}
+			return nil
+		},
+	},
+	{
+		// 合并旧版功能节点权限数据，旧表已重命名为 *_legacy 保留，回滚时不做处理
+		Version: 2,
+		Name:    "merge_legacy_rbac",
+		Up:      migrateLegacyRBAC,
+	},
+	{
+		// 单点登录账号按签发方和 sub 关联用户
+		Version: 3,
+		Name:    "user_oidc_subject",
+		Up: func(tx *gorm.DB) error {
+			return tx.AutoMigrate(&userOIDCSubject{})
+		},
+		Down: func(tx *gorm.DB) error {
+			if err := tx.Migrator().DropIndex(&userOIDCSubject{}, "idx_users_oidc_subject"); err != nil {
+				return err
+			}
+			if err := tx.Migrator().DropColumn(&userOIDCSubject{}, "oidc_subject"); err != nil {
+				return err
+			}
+			return tx.Migrator().DropColumn(&userOIDCSubject{}, "oidc_issuer")
+		},
+	},
+	{
+		// 新建角色的数据权限默认改为仅本人，已有角色保持全部数据
+		Version: 4,
+		Name:    "role_data_scope_default_self",
+		Up: func(tx *gorm.DB) error {
+			if err := tx.Model(&roleDataScopeSelf{}).
+				Where("data_scope IS NULL OR data_scope = 0").
+				Update("data_scope", 1).Error; err != nil {
+				return err
+			}
+			return alterRoleDataScope(tx, &roleDataScopeSelf{})
+		},
+		Down: func(tx *gorm.DB) error {
+			return alterRoleDataScope(tx, &roleDataScopeAll{})
+		},
+	},
+}
+
+// 版本3新增的用户字段
+type userOIDCSubject struct {
+	OIDCIssuer  *string `gorm:"column:oidc_issuer;size:255;uniqueIndex:idx_users_oidc_subject"`
+	OIDCSubject *string `gorm:"column:oidc_subject;size:255;uniqueIndex:idx_users_oidc_subject"`
+}
+
+func (userOIDCSubject) TableName() string {
+	return "users"
+}
+
+// 版本4修改的角色数据权限字段默认值
+type roleDataScopeSelf struct {
+	DataScope int            `gorm:"default:5"`
+	DeletedAt gorm.DeletedAt `gorm:"index"`
+}
+
+func (roleDataScopeSelf) TableName() string {
+	return "roles"
+}
+
+// 版本4之前的角色数据权限字段，回滚时恢复默认值
+type roleDataScopeAll struct {
+	DataScope int            `gorm:"default:1"`
+	DeletedAt gorm.DeletedAt `gorm:"index"`
+}
+
+func (roleDataScopeAll) TableName() string {
+	return "roles"
+}
+
+// 修改角色数据权限字段的默认值；SQLite修改字段时会重建数据表并丢失索引，需补建 deleted_at 索引
+func alterRoleDataScope(tx *gorm.DB, role interface{}) error {
+	if err := tx.Migrator().AlterColumn(role, "DataScope"); err != nil {
+		return err
+	}
+	if tx.Migrator().HasIndex(role, "DeletedAt") {
+		return nil
+	}
+	return tx.Migrator().CreateIndex(role, "DeletedAt")
+}
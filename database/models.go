@@ -0,0 +1,154 @@
+package database
+
+import "github.com/lemonoa/LemonOA-Go/model"
+
+// Models model 包中的全部模型，备份与恢复以此为准，新增模型需加入此列表并新增迁移
+func Models() []interface{} {
+	return []interface{}{
+		// 认证管理
+		&model.User{},
+		&model.UserRole{},
+		&model.Permission{},
+		&model.RolePermission{},
+		&model.LoginLog{},
+		&model.RefreshToken{},
+		&model.RevokedToken{},
+		&model.UserRecoveryCode{},
+		&model.RoleDepartment{},
+		&model.ApiKey{},
+		&model.PasswordHistory{},
+		&model.UserSession{},
+		&model.OIDCLoginState{},
+
+		// 系统管理
+		&model.SystemConfig{},
+		&model.Module{},
+		&model.ModuleConfig{},
+		&model.Role{},
+		&model.OperationLog{},
+		&model.Attachment{},
+		&model.AttachmentUpload{},
+		&model.BackupRecord{},
+		&model.ScheduledTask{},
+		&model.ScheduledTaskRun{},
+
+		// 工作台
+		&model.Department{},
+		&model.Employee{},
+		&model.Notification{},
+		&model.ApprovalType{},
+		&model.ApprovalFlow{},
+		&model.ApprovalNode{},
+		&model.ApprovalRecord{},
+		&model.ApprovalNodeRecord{},
+		&model.Todo{},
+
+		// 工作流
+		&model.WorkflowType{},
+		&model.WorkflowDefinition{},
+		&model.WorkflowNode{},
+		&model.WorkflowInstance{},
+		&model.WorkflowTask{},
+
+		// 公告
+		&model.Notice{},
+		&model.NoticeRead{},
+
+		// 人事管理
+		&model.Position{},
+		&model.EmployeeArchive{},
+		&model.RewardPunishmentRecord{},
+		&model.CareRecord{},
+		&model.Transfer{},
+		&model.Resignation{},
+		&model.Contract{},
+		&model.Probation{},
+
+		// 考勤管理
+		&model.AttendanceRule{},
+		&model.AttendanceRecord{},
+		&model.LeaveApplication{},
+		&model.OvertimeApplication{},
+		&model.BusinessTripApplication{},
+
+		// 固定资产管理
+		&model.Asset{},
+		&model.AssetRepair{},
+		&model.AssetBorrow{},
+		&model.AssetDisposal{},
+
+		// 车辆管理
+		&model.Vehicle{},
+		&model.VehicleRepair{},
+		&model.VehicleMaintenance{},
+		&model.VehicleMileage{},
+		&model.VehicleExpenseRecord{},
+		&model.VehicleViolation{},
+		&model.VehicleAccident{},
+		&model.VehicleApplication{},
+		&model.VehicleReturn{},
+
+		// 会议室管理
+		&model.MeetingRoom{},
+		&model.MeetingReservation{},
+		&model.MeetingMinutes{},
+		&model.MeetingRoomMaintenance{},
+
+		// 印章管理
+		&model.Seal{},
+		&model.SealApplication{},
+		&model.SealRecord{},
+
+		// 文档管理
+		&model.DocumentType{},
+		&model.Document{},
+		&model.DocumentApproval{},
+		&model.DocumentDistribution{},
+		&model.DocumentArchive{},
+		&model.DocumentBorrow{},
+
+		// 基础数据-公共模块
+		&model.Enterprise{},
+		&model.Region{},
+		&model.MessageTemplate{},
+
+		// 基础数据-人事模块
+		&model.RewardPunishment{},
+		&model.CareProject{},
+		&model.CommonData{},
+
+		// 基础数据-行政模块
+		&model.AssetCategory{},
+		&model.AssetBrand{},
+		&model.AssetUnit{},
+		&model.SealType{},
+		&model.VehicleExpense{},
+		&model.NoticeType{},
+
+		// 基础数据-财务模块
+		&model.ExpenseType{},
+
+		// 基础数据-客户模块
+		&model.CustomerLevel{},
+		&model.CustomerChannel{},
+		&model.Industry{},
+		&model.CustomerStatus{},
+		&model.CustomerIntention{},
+		&model.FollowUpMethod{},
+		&model.SalesStage{},
+
+		// 基础数据-合同模块
+		&model.ContractCategory{},
+		&model.ProductCategory{},
+		&model.Product{},
+		&model.ServiceContent{},
+		&model.Supplier{},
+		&model.PurchaseCategory{},
+		&model.PurchaseItem{},
+
+		// 基础数据-项目模块
+		&model.ProjectStage{},
+		&model.ProjectCategory{},
+		&model.WorkType{},
+	}
+}
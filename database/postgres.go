@@ -0,0 +1,35 @@
+package database
+
+import (
+	"net"
+	"net/url"
+	"strconv"
+
+	"github.com/spf13/viper"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+)
+
+// 使用URL格式的连接串，用户名、密码等含空格或特殊字符时也能正确解析
+func postgresDialector() gorm.Dialector {
+	sslMode := viper.GetString("postgres.sslmode")
+	if sslMode == "" {
+		sslMode = "disable"
+	}
+	timeZone := viper.GetString("postgres.timezone")
+	if timeZone == "" {
+		timeZone = "Asia/Shanghai"
+	}
+
+	query := url.Values{}
+	query.Set("sslmode", sslMode)
+	query.Set("TimeZone", timeZone)
+	dsn := url.URL{
+		Scheme:   "postgres",
+		User:     url.UserPassword(viper.GetString("postgres.username"), viper.GetString("postgres.password")),
+		Host:     net.JoinHostPort(viper.GetString("postgres.host"), strconv.Itoa(viper.GetInt("postgres.port"))),
+		Path:     "/" + viper.GetString("postgres.database"),
+		RawQuery: query.Encode(),
+	}
+	return postgres.Open(dsn.String())
+}
@@ -0,0 +1,140 @@
+package database
+
+import (
+	"time"
+
+	"github.com/lemonoa/LemonOA-Go/model"
+
+	"gorm.io/gorm"
+)
+
+// 旧版功能节点表，权限统一后仅用于数据迁移
+type legacyFunctionNode struct {
+	ID        uint
+	ModuleID  uint
+	Name      string
+	Code      string
+	Type      int
+	Path      string
+	Method    string
+	Sort      int
+	Status    int
+	CreatedAt time.Time
+	DeletedAt gorm.DeletedAt
+}
+
+func (legacyFunctionNode) TableName() string {
+	return "function_nodes"
+}
+
+// 旧版角色功能权限表
+type legacyRoleFunction struct {
+	ID             uint
+	RoleID         uint
+	FunctionNodeID uint
+	DeletedAt      gorm.DeletedAt
+}
+
+func (legacyRoleFunction) TableName() string {
+	return "role_functions"
+}
+
+// migrateLegacyRBAC 将 function_nodes / role_functions 合并到 permissions / role_permissions
+// 编码相同的功能节点与已有权限合并，迁移完成后旧表重命名为 *_legacy 保留备查，重复执行不会产生影响
+func migrateLegacyRBAC(db *gorm.DB) error {
+	migrator := db.Migrator()
+	if !migrator.HasTable(&legacyFunctionNode{}) {
+		return nil
+	}
+
+	return db.Transaction(func(tx *gorm.DB) error {
+		var nodes []legacyFunctionNode
+		if err := tx.Find(&nodes).Error; err != nil {
+			return err
+		}
+
+		// 旧功能节点ID -> 权限ID
+		idMap := make(map[uint]uint, len(nodes))
+		for _, node := range nodes {
+			var perm model.Permission
+			err := tx.Unscoped().Where("code = ?", node.Code).First(&perm).Error
+			if err != nil && err != gorm.ErrRecordNotFound {
+				return err
+			}
+
+			if err == gorm.ErrRecordNotFound {
+				perm = model.Permission{
+					Name:   node.Name,
+					Code:   node.Code,
+					Type:   node.Type,
+					Path:   node.Path,
+					Method: node.Method,
+					Sort:   node.Sort,
+					Status: node.Status,
+				}
+				if node.ModuleID > 0 {
+					moduleID := node.ModuleID
+					perm.ModuleID = &moduleID
+				}
+				if err := tx.Create(&perm).Error; err != nil {
+					return err
+				}
+			} else {
+				// 已存在同编码的权限，只补充缺失的模块和路由信息
+				updates := map[string]interface{}{}
+				if perm.ModuleID == nil && node.ModuleID > 0 {
+					updates["module_id"] = node.ModuleID
+				}
+				if perm.Path == "" && node.Path != "" {
+					updates["path"] = node.Path
+				}
+				if perm.Method == "" && node.Method != "" {
+					updates["method"] = node.Method
+				}
+				if len(updates) > 0 {
+					if err := tx.Model(&perm).Updates(updates).Error; err != nil {
+						return err
+					}
+				}
+			}
+			idMap[node.ID] = perm.ID
+		}
+
+		if migrator.HasTable(&legacyRoleFunction{}) {
+			var roleFunctions []legacyRoleFunction
+			if err := tx.Find(&roleFunctions).Error; err != nil {
+				return err
+			}
+
+			for _, rf := range roleFunctions {
+				permissionID, ok := idMap[rf.FunctionNodeID]
+				if !ok {
+					continue
+				}
+
+				var count int64
+				if err := tx.Model(&model.RolePermission{}).
+					Where("role_id = ? AND permission_id = ?", rf.RoleID, permissionID).
+					Count(&count).Error; err != nil {
+					return err
+				}
+				if count > 0 {
+					continue
+				}
+
+				if err := tx.Create(&model.RolePermission{
+					RoleID:       rf.RoleID,
+					PermissionID: permissionID,
+				}).Error; err != nil {
+					return err
+				}
+			}
+
+			if err := migrator.RenameTable("role_functions", "role_functions_legacy"); err != nil {
+				return err
+			}
+		}
+
+		return migrator.RenameTable("function_nodes", "function_nodes_legacy")
+	})
+}
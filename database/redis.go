@@ -0,0 +1,35 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/spf13/viper"
+)
+
+// Redis 未启用时为nil
+var Redis *redis.Client
+
+// InitRedis 初始化Redis连接，redis.enabled 为false时跳过
+func InitRedis() error {
+	if !viper.GetBool("redis.enabled") {
+		return nil
+	}
+
+	client := redis.NewClient(&redis.Options{
+		Addr:     fmt.Sprintf("%s:%d", viper.GetString("redis.host"), viper.GetInt("redis.port")),
+		Password: viper.GetString("redis.password"),
+		DB:       viper.GetInt("redis.db"),
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := client.Ping(ctx).Err(); err != nil {
+		return fmt.Errorf("failed to connect to redis: %v", err)
+	}
+
+	Redis = client
+	return nil
+}
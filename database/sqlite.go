@@ -0,0 +1,22 @@
+package database
+
+import (
+	"os"
+	"path/filepath"
+
+	"github.com/spf13/viper"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+// SQLite 使用WAL模式，读写可并发；写入冲突时等待而不是立即报错
+func sqliteDialector() (gorm.Dialector, error) {
+	path := viper.GetString("sqlite.path")
+	if path == "" {
+		path = "./data/lemonoa.db"
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return nil, err
+	}
+	return sqlite.Open("file:" + path + "?_journal_mode=WAL&_busy_timeout=5000&_foreign_keys=1"), nil
+}
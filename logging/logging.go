@@ -0,0 +1,79 @@
+package logging
+
+import (
+	"context"
+	"log/slog"
+	"os"
+	"strings"
+
+	"github.com/spf13/viper"
+)
+
+type requestIDKey struct{}
+
+type traceIDKey struct{}
+
+// Init 按 log.level 和 log.format 配置初始化全局日志，标准库 log 的输出同样转到该日志
+func Init() {
+	opts := &slog.HandlerOptions{Level: parseLevel(viper.GetString("log.level"))}
+	var handler slog.Handler
+	if strings.EqualFold(viper.GetString("log.format"), "text") {
+		handler = slog.NewTextHandler(os.Stdout, opts)
+	} else {
+		handler = slog.NewJSONHandler(os.Stdout, opts)
+	}
+	slog.SetDefault(slog.New(handler))
+}
+
+func parseLevel(level string) slog.Level {
+	switch strings.ToLower(level) {
+	case "debug":
+		return slog.LevelDebug
+	case "warn":
+		return slog.LevelWarn
+	case "error":
+		return slog.LevelError
+	default:
+		return slog.LevelInfo
+	}
+}
+
+// WithRequestID 将请求ID存储在上下文中，经 db.WithContext 传入后数据库日志同样带有请求ID
+func WithRequestID(ctx context.Context, requestID string) context.Context {
+	return context.WithValue(ctx, requestIDKey{}, requestID)
+}
+
+// RequestIDFromContext 获取上下文中的请求ID，没有时为空
+func RequestIDFromContext(ctx context.Context) string {
+	if ctx == nil {
+		return ""
+	}
+	requestID, _ := ctx.Value(requestIDKey{}).(string)
+	return requestID
+}
+
+// WithTraceID 将调用方传入的链路ID存储在上下文中，用于与上游服务的日志关联
+func WithTraceID(ctx context.Context, traceID string) context.Context {
+	return context.WithValue(ctx, traceIDKey{}, traceID)
+}
+
+// TraceIDFromContext 获取上下文中的链路ID，没有时为空
+func TraceIDFromContext(ctx context.Context) string {
+	if ctx == nil {
+		return ""
+	}
+	traceID, _ := ctx.Value(traceIDKey{}).(string)
+	return traceID
+}
+
+// FromContext 带有请求ID和链路ID的日志
+func FromContext(ctx context.Context) *slog.Logger {
+	logger := slog.Default()
+	if requestID := RequestIDFromContext(ctx); requestID != "" {
+		logger = logger.With("request_id", requestID)
+	}
+	if traceID := TraceIDFromContext(ctx); traceID != "" {
+		logger = logger.With("trace_id", traceID)
+	}
+	return logger
+}
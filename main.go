@@ -1,11 +1,21 @@
 package main
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"log/slog"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
 
 	"github.com/lemonoa/LemonOA-Go/database"
 
 	"github.com/lemonoa/LemonOA-Go/controller"
+	"github.com/lemonoa/LemonOA-Go/logging"
+	"github.com/lemonoa/LemonOA-Go/metrics"
 	"github.com/lemonoa/LemonOA-Go/middleware"
 	"github.com/lemonoa/LemonOA-Go/service"
 
@@ -22,23 +32,63 @@ func init() {
 		panic(fmt.Errorf("fatal error config file: %w", err))
 	}
 
+	// 初始化日志
+	logging.Init()
+}
+
+// 初始化数据库、缓存和后台任务
+func setup() {
 	// 初始化数据库连接
-	if err := database.InitMySQL(); err != nil {
+	if err := database.Init(); err != nil {
 		panic(fmt.Errorf("failed to initialize database: %w", err))
 	}
 
+	// 初始化Redis连接，未启用时权限缓存使用进程内缓存
+	if err := database.InitRedis(); err != nil {
+		panic(fmt.Errorf("failed to initialize redis: %w", err))
+	}
+	service.InitPermissionCache()
+
+	// 启动操作日志写入器
+	service.InitOperationLogWriter(database.DB)
+
+	// 初始化附件存储
+	if err := service.InitStorage(); err != nil {
+		panic(fmt.Errorf("failed to initialize storage: %w", err))
+	}
+
+	// 启动定时任务调度器
+	if err := service.InitScheduler(database.DB); err != nil {
+		panic(fmt.Errorf("failed to initialize scheduler: %w", err))
+	}
+
 	// 设置gin模式
 	gin.SetMode(viper.GetString("server.mode"))
 }
 
 func main() {
-	r := gin.Default()
+	// 数据库迁移命令：lemon-oa migrate up|down [n]|status
+	if len(os.Args) > 1 && os.Args[1] == "migrate" {
+		if err := runMigrate(os.Args[2:]); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	setup()
+
+	r := gin.New()
+
+	// 请求ID、访问日志、请求指标和panic恢复，请求ID需最先注册
+	r.Use(middleware.RequestID(), middleware.AccessLog(), middleware.Metrics(), middleware.Recovery())
 
 	// 配置跨域中间件
 	r.Use(func(c *gin.Context) {
 		c.Writer.Header().Set("Access-Control-Allow-Origin", "*")
 		c.Writer.Header().Set("Access-Control-Allow-Credentials", "true")
-		c.Writer.Header().Set("Access-Control-Allow-Headers", "Content-Type, Content-Length, Accept-Encoding, X-CSRF-Token, Authorization, accept, origin, Cache-Control, X-Requested-With")
+		c.Writer.Header().Set("Access-Control-Allow-Headers", "Content-Type, Content-Length, Accept-Encoding, X-CSRF-Token, Authorization, accept, origin, Cache-Control, X-Requested-With, X-Request-ID, traceparent")
+		c.Writer.Header().Set("Access-Control-Expose-Headers", "X-Request-ID")
 		c.Writer.Header().Set("Access-Control-Allow-Methods", "POST, OPTIONS, GET, PUT, DELETE")
 
 		if c.Request.Method == "OPTIONS" {
@@ -49,6 +99,9 @@ func main() {
 		c.Next()
 	})
 
+	// 记录新增、修改、删除操作的日志
+	r.Use(middleware.OperationLog())
+
 	// 初始化认证服务和控制器
 	authService := service.NewAuthService(database.DB)
 	authController := controller.NewAuthController(authService)
@@ -121,12 +174,19 @@ func main() {
 	// 初始化控制器
 	attendanceController := controller.NewAttendanceController(attendanceService)
 
+	// 注册存活和就绪检查路由
+	healthController := controller.NewHealthController()
+	healthController.RegisterRoutes(r)
+
+	// Prometheus指标，不需要认证，应仅允许监控系统访问
+	if viper.GetBool("metrics.enabled") {
+		r.GET("/metrics", gin.WrapH(metrics.Handler()))
+	}
+
 	// 注册认证路由
 	authController.RegisterRoutes(r)
 
-	// 需要JWT认证的路由组
-	api := r.Group("/api")
-	api.Use(middleware.JWT())
+	// 业务路由，各路由组均挂载JWT认证中间件
 	{
 		// 注册路由
 		addressBookController.RegisterRoutes(r)
@@ -167,8 +227,48 @@ func main() {
 	}
 
 	// 启动服务器
-	port := viper.GetString("server.port")
-	if err := r.Run(":" + port); err != nil {
-		panic(err)
+	srv := &http.Server{
+		Addr:    ":" + viper.GetString("server.port"),
+		Handler: r,
+	}
+	go func() {
+		if err := srv.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			panic(err)
+		}
+	}()
+
+	// 收到退出信号后停止接收新请求，等待处理中的请求和后台任务结束
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	<-ctx.Done()
+	stop()
+	slog.Info("收到退出信号，开始关闭服务")
+	shutdown(srv, healthController)
+}
+
+// 优雅退出，整个过程不超过 server.shutdown_timeout 秒
+func shutdown(srv *http.Server, healthController *controller.HealthController) {
+	timeout := viper.GetInt("server.shutdown_timeout")
+	if timeout <= 0 {
+		timeout = 30
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(timeout)*time.Second)
+	defer cancel()
+
+	// 就绪检查先失败，等待负载均衡摘除本实例后再停止接收请求
+	healthController.Drain()
+	if delay := viper.GetInt("server.drain_delay"); delay > 0 {
+		select {
+		case <-time.After(time.Duration(delay) * time.Second):
+		case <-ctx.Done():
+		}
+	}
+
+	if err := srv.Shutdown(ctx); err != nil {
+		slog.WarnContext(ctx, "等待请求处理完成超时", "error", err)
 	}
+	service.StopScheduler(ctx)
+	service.WaitBackup(ctx)
+	service.CloseOperationLogWriter()
+	database.Close()
+	slog.Info("服务已关闭")
 }
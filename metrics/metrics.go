@@ -0,0 +1,94 @@
+package metrics
+
+import (
+	"net/http"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+const namespace = "lemonoa"
+
+var (
+	// HTTPRequests HTTP请求数，route 为路由模板，未匹配路由时为 unmatched
+	HTTPRequests = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "http_requests_total",
+		Help:      "HTTP requests by method, route template and status code.",
+	}, []string{"method", "route", "status"})
+
+	// HTTPRequestDuration HTTP请求耗时
+	HTTPRequestDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "http_request_duration_seconds",
+		Help:      "HTTP request latency by method and route template.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"method", "route"})
+
+	// DBQueryDuration 数据库语句耗时，operation 为 create/query/update/delete/row/raw
+	DBQueryDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "db_query_duration_seconds",
+		Help:      "Database statement latency by operation and table.",
+		Buckets:   []float64{.001, .0025, .005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5},
+	}, []string{"operation", "table"})
+
+	// DBQueryErrors 数据库语句错误数，不含记录不存在
+	DBQueryErrors = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "db_query_errors_total",
+		Help:      "Database statement errors by operation and table, excluding record not found.",
+	}, []string{"operation", "table"})
+
+	// Logins 登录次数，result 为 success/failure
+	Logins = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "logins_total",
+		Help:      "Login attempts by result.",
+	}, []string{"result"})
+
+	// ApprovalsStarted 发起的审批数
+	ApprovalsStarted = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "approvals_started_total",
+		Help:      "Approvals started.",
+	})
+
+	// ApprovalsFinished 结束的审批数，result 为 approved/rejected
+	ApprovalsFinished = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "approvals_finished_total",
+		Help:      "Approvals finished by result.",
+	}, []string{"result"})
+
+	// AttendanceChecks 考勤打卡次数，type 为 in/out
+	AttendanceChecks = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "attendance_checks_total",
+		Help:      "Attendance check-ins and check-outs.",
+	}, []string{"type"})
+)
+
+// Registry 本服务的指标，包含Go运行时和进程指标
+var Registry = prometheus.NewRegistry()
+
+func init() {
+	Registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		HTTPRequests,
+		HTTPRequestDuration,
+		DBQueryDuration,
+		DBQueryErrors,
+		Logins,
+		ApprovalsStarted,
+		ApprovalsFinished,
+		AttendanceChecks,
+	)
+}
+
+// Handler 以Prometheus格式输出指标
+func Handler() http.Handler {
+	return promhttp.HandlerFor(Registry, promhttp.HandlerOpts{Registry: Registry})
+}
@@ -4,10 +4,12 @@ import (
 	"errors"
 	"net/http"
 	"strings"
+	"time"
 
 	"github.com/lemonoa/LemonOA-Go/database"
 
 	"github.com/lemonoa/LemonOA-Go/model"
+	"github.com/lemonoa/LemonOA-Go/service"
 
 	"github.com/dgrijalva/jwt-go"
 	"github.com/gin-gonic/gin"
@@ -17,35 +19,147 @@ import (
 // JWT中间件
 func JWT() gin.HandlerFunc {
 	return func(c *gin.Context) {
+		// 系统集成使用服务账号的API密钥访问
+		if apiKey := c.GetHeader("X-API-Key"); apiKey != "" {
+			authenticateApiKey(c, apiKey)
+			return
+		}
+
 		token := c.GetHeader("Authorization")
 		if token == "" {
-			c.JSON(http.StatusUnauthorized, gin.H{"error": "未提供token"})
-			c.Abort()
+			abortWithError(c, http.StatusUnauthorized, "未提供token")
 			return
 		}
 
 		// 从Bearer token中提取JWT
 		parts := strings.Split(token, " ")
 		if len(parts) != 2 || parts[0] != "Bearer" {
-			c.JSON(http.StatusUnauthorized, gin.H{"error": "token格式错误"})
-			c.Abort()
+			abortWithError(c, http.StatusUnauthorized, "token格式错误")
 			return
 		}
 
 		// 解析JWT
 		claims, err := parseToken(parts[1])
 		if err != nil {
-			c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
-			c.Abort()
+			abortWithError(c, http.StatusUnauthorized, err.Error())
+			return
+		}
+
+		// 检查token是否已被吊销
+		userID, err := checkTokenRevoked(claims)
+		if err != nil {
+			abortWithError(c, http.StatusUnauthorized, err.Error())
+			return
+		}
+
+		// 检查token所属的会话是否已被终止，会话功能上线前签发的token没有sid
+		sid, _ := claims["sid"].(string)
+		if sid != "" {
+			if err := service.ValidateSession(database.DB, sid, c.ClientIP()); err != nil {
+				abortWithError(c, http.StatusUnauthorized, service.ErrSessionRevoked.Error())
+				return
+			}
+		}
+
+		// 模拟登录令牌携带实际操作人，实际操作人失效或不再是超级管理员时令牌随之失效
+		impersonatorID, err := checkImpersonator(claims)
+		if err != nil {
+			abortWithError(c, http.StatusUnauthorized, err.Error())
 			return
 		}
 
-		// 将用户ID存储在上下文中
-		c.Set("user_id", claims["user_id"])
+		// 将用户ID及token信息存储在上下文中，请求上下文中的操作用户经服务传入数据库回调
+		c.Set("user_id", userID)
+		c.Request = c.Request.WithContext(database.WithActor(c.Request.Context(), userID))
+		if impersonatorID != 0 {
+			c.Set("impersonator_id", impersonatorID)
+		}
+		c.Set("session_id", sid)
+		c.Set("token_jti", claims["jti"])
+		if exp, ok := claims["exp"].(float64); ok {
+			c.Set("token_exp", time.Unix(int64(exp), 0))
+		}
+
+		// 路由配置了接口权限时，需拥有其中任意一个权限
+		if status, msg := checkRoutePermission(c, userID); status != 0 {
+			abortWithError(c, status, msg)
+			return
+		}
 		c.Next()
 	}
 }
 
+// CurrentSessionID 获取当前请求所属的会话ID，使用API密钥访问时为空
+func CurrentSessionID(c *gin.Context) string {
+	return c.GetString("session_id")
+}
+
+// 使用API密钥认证，密钥的权限范围存储在上下文中，由权限检查进一步限制
+func authenticateApiKey(c *gin.Context, plain string) {
+	key, err := service.AuthenticateApiKey(database.DB, plain, c.ClientIP())
+	if err != nil {
+		abortWithError(c, http.StatusUnauthorized, err.Error())
+		return
+	}
+
+	c.Set("user_id", key.UserID)
+	c.Request = c.Request.WithContext(database.WithActor(c.Request.Context(), key.UserID))
+	c.Set("api_key_id", key.ID)
+	c.Set("api_key_scopes", service.ApiKeyScopes(key))
+
+	if status, msg := checkRoutePermission(c, key.UserID); status != 0 {
+		abortWithError(c, status, msg)
+		return
+	}
+	c.Next()
+}
+
+// CurrentUserID 获取当前登录用户ID
+func CurrentUserID(c *gin.Context) uint {
+	return c.GetUint("user_id")
+}
+
+// CurrentImpersonatorID 获取模拟登录的实际操作人ID，未模拟登录时为0
+func CurrentImpersonatorID(c *gin.Context) uint {
+	return c.GetUint("impersonator_id")
+}
+
+// CurrentActorID 获取实际操作人ID，模拟登录时为模拟者，否则为当前登录用户
+func CurrentActorID(c *gin.Context) uint {
+	if id := CurrentImpersonatorID(c); id != 0 {
+		return id
+	}
+	return CurrentUserID(c)
+}
+
+// DenyImpersonation 模拟登录期间禁止的操作，如修改密码、两步验证设置及用户、角色、系统配置等管理操作，需在JWT中间件之后使用
+func DenyImpersonation() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if CurrentImpersonatorID(c) != 0 {
+			abortWithError(c, http.StatusForbidden, service.ErrImpersonationDenied.Error())
+			return
+		}
+		c.Next()
+	}
+}
+
+// 检查模拟登录的实际操作人，非模拟登录令牌返回0
+func checkImpersonator(claims jwt.MapClaims) (uint, error) {
+	raw, ok := claims["impersonator_id"]
+	if !ok {
+		return 0, nil
+	}
+	impersonatorID, ok := raw.(float64)
+	if !ok || impersonatorID <= 0 {
+		return 0, errors.New("无效的token")
+	}
+	iat, _ := claims["iat"].(float64)
+	if err := service.ValidateImpersonator(database.DB, uint(impersonatorID), time.Unix(int64(iat), 0)); err != nil {
+		return 0, errors.New("模拟登录已失效")
+	}
+	return uint(impersonatorID), nil
+}
+
 // 解析JWT token
 func parseToken(tokenString string) (jwt.MapClaims, error) {
 	token, err := jwt.Parse(tokenString, func(token *jwt.Token) (interface{}, error) {
@@ -67,75 +181,162 @@ func parseToken(tokenString string) (jwt.MapClaims, error) {
 	return claims, nil
 }
 
-// RequirePermission 权限验证中间件
+// 检查token是否已被吊销：用户被禁用、token签发于用户令牌吊销时间之前、或token已退出登录
+func checkTokenRevoked(claims jwt.MapClaims) (uint, error) {
+	userIDClaim, ok := claims["user_id"].(float64)
+	if !ok {
+		return 0, errors.New("无效的token")
+	}
+	iat, ok := claims["iat"].(float64)
+	if !ok {
+		return 0, errors.New("token已失效")
+	}
+	// 两步验证挑战令牌等专用令牌不能用于访问接口
+	if typ, _ := claims["typ"].(string); typ != "" {
+		return 0, errors.New("无效的token")
+	}
+	jti, _ := claims["jti"].(string)
+	if jti == "" {
+		return 0, errors.New("token已失效")
+	}
+
+	// 用户状态及吊销记录经缓存读取，吊销时缓存随之失效
+	userID := uint(userIDClaim)
+	if err := service.CheckAccessToken(database.DB, userID, jti, time.Unix(int64(iat), 0)); err != nil {
+		switch {
+		case errors.Is(err, service.ErrTokenUserNotFound), errors.Is(err, service.ErrTokenUserDisabled), errors.Is(err, service.ErrTokenRevoked):
+			return 0, err
+		default:
+			return 0, errors.New("验证token失败")
+		}
+	}
+	return userID, nil
+}
+
+// 按接口权限表检查当前路由，路由未配置接口权限时放行，返回0表示通过
+func checkRoutePermission(c *gin.Context, userID uint) (int, string) {
+	path := c.FullPath()
+	if path == "" {
+		return 0, ""
+	}
+
+	codes, err := service.GetRoutePermissions(database.DB, c.Request.Method, path)
+	if err != nil {
+		return http.StatusInternalServerError, "验证权限失败"
+	}
+	if len(codes) == 0 {
+		return 0, ""
+	}
+
+	perms, err := currentPermissions(c, userID)
+	if err != nil {
+		return http.StatusInternalServerError, "验证权限失败"
+	}
+	for _, code := range codes {
+		if perms.Has(code) {
+			return 0, ""
+		}
+	}
+	return http.StatusForbidden, "没有操作权限"
+}
+
+// 获取当前请求的有效权限，使用API密钥访问时限定在密钥的权限范围内
+func currentPermissions(c *gin.Context, userID uint) (*service.UserPermissions, error) {
+	perms, err := service.GetUserEffectivePermissions(database.DB, userID)
+	if err != nil {
+		return nil, err
+	}
+	if scopes, ok := c.Get("api_key_scopes"); ok {
+		return perms.Restrict(scopes.([]string)), nil
+	}
+	return perms, nil
+}
+
+// RequirePermission 权限验证中间件，用户权限集合经缓存读取
 func RequirePermission(permissionCode string) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		userID, exists := c.Get("user_id")
 		if !exists {
-			c.JSON(http.StatusUnauthorized, gin.H{"error": "未登录"})
-			c.Abort()
+			abortWithError(c, http.StatusUnauthorized, "未登录")
 			return
 		}
 
-		// 查询用户角色
-		var userRoles []model.UserRole
-		if err := database.DB.Where("user_id = ?", userID).Find(&userRoles).Error; err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "获取用户角色失败"})
-			c.Abort()
+		perms, err := currentPermissions(c, userID.(uint))
+		if err != nil {
+			abortWithError(c, http.StatusInternalServerError, "验证权限失败")
 			return
 		}
 
 		// 如果没有任何角色
-		if len(userRoles) == 0 {
-			c.JSON(http.StatusForbidden, gin.H{"error": "没有任何角色权限"})
-			c.Abort()
+		if !perms.HasRole {
+			abortWithError(c, http.StatusForbidden, "没有任何角色权限")
 			return
 		}
 
-		// 获取角色ID列表
-		var roleIDs []uint
-		for _, ur := range userRoles {
-			roleIDs = append(roleIDs, ur.RoleID)
-		}
-
-		// 查询角色是否包含超级管理员
-		var count int64
-		if err := database.DB.Model(&model.Role{}).Where("id IN ? AND code = ?", roleIDs, "super_admin").Count(&count).Error; err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "验证超级管理员失败"})
-			c.Abort()
-			return
-		}
-
-		// 如果是超级管理员，直接放行
-		if count > 0 {
-			c.Next()
+		// 超级管理员拥有全部权限
+		if !perms.Has(permissionCode) {
+			abortWithError(c, http.StatusForbidden, "没有操作权限")
 			return
 		}
 
-		// 查询角色权限
-		var hasPermission bool
-		err := database.DB.Raw(`
-			SELECT EXISTS (
-				SELECT 1 FROM permissions p
-				INNER JOIN role_permissions rp ON p.id = rp.permission_id
-				WHERE rp.role_id IN ?
-				AND p.code = ?
-				AND p.status = 1
-			)
-		`, roleIDs, permissionCode).Scan(&hasPermission).Error
+		c.Next()
+	}
+}
 
+// DataScope 数据权限中间件，计算当前用户的数据范围并存储在上下文中，需在JWT中间件之后使用
+func DataScope() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		scope, err := service.GetDataScope(database.DB, CurrentUserID(c))
 		if err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "验证权限失败"})
-			c.Abort()
+			abortWithError(c, http.StatusInternalServerError, "获取数据权限失败")
 			return
 		}
 
-		if !hasPermission {
-			c.JSON(http.StatusForbidden, gin.H{"error": "没有操作权限"})
-			c.Abort()
+		c.Set("data_scope", scope)
+		c.Next()
+	}
+}
+
+// RequireEmployee 解析当前用户关联的员工并存储在上下文中，未关联员工时拒绝访问，需在JWT中间件之后使用
+// 考勤、请假、会议预约等以员工身份办理的业务通过该中间件确定办理人，不信任请求中的员工ID
+func RequireEmployee() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		employee, err := service.GetCurrentEmployee(database.DB, CurrentUserID(c))
+		if err != nil {
+			if errors.Is(err, service.ErrEmployeeNotBound) {
+				abortWithError(c, http.StatusForbidden, err.Error())
+			} else {
+				abortWithError(c, http.StatusInternalServerError, "获取员工信息失败")
+			}
 			return
 		}
 
+		c.Set("employee", employee)
 		c.Next()
 	}
 }
+
+// CurrentEmployee 获取当前用户关联的员工，需经过RequireEmployee中间件
+func CurrentEmployee(c *gin.Context) *model.Employee {
+	if employee, ok := c.Get("employee"); ok {
+		return employee.(*model.Employee)
+	}
+	return nil
+}
+
+// CurrentEmployeeID 获取当前用户关联的员工ID，未经过RequireEmployee中间件时为0
+func CurrentEmployeeID(c *gin.Context) uint {
+	if employee := CurrentEmployee(c); employee != nil {
+		return employee.ID
+	}
+	return 0
+}
+
+// CurrentDataScope 获取当前用户的数据权限范围
+func CurrentDataScope(c *gin.Context) *service.DataScope {
+	if scope, ok := c.Get("data_scope"); ok {
+		return scope.(*service.DataScope)
+	}
+	// 未经过数据权限中间件时只能访问本人数据
+	return &service.DataScope{UserID: CurrentUserID(c)}
+}
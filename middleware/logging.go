@@ -0,0 +1,158 @@
+package middleware
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"log/slog"
+	"net/http"
+	"runtime/debug"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/lemonoa/LemonOA-Go/logging"
+
+	"github.com/gin-gonic/gin"
+)
+
+const requestIDHeader = "X-Request-ID"
+
+// RequestID 请求ID中间件，沿用调用方传入的 X-Request-ID，没有或不合法时生成，并在响应头中返回；
+// 请求带有W3C traceparent时同时记录其中的链路ID。需注册在最前面，后续日志均带有请求ID
+func RequestID() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		requestID := c.GetHeader(requestIDHeader)
+		if !validRequestID(requestID) {
+			requestID = newRequestID()
+		}
+		c.Set("request_id", requestID)
+		c.Header(requestIDHeader, requestID)
+
+		ctx := logging.WithRequestID(c.Request.Context(), requestID)
+		if traceID := parseTraceParent(c.GetHeader("traceparent")); traceID != "" {
+			ctx = logging.WithTraceID(ctx, traceID)
+		}
+		c.Request = c.Request.WithContext(ctx)
+		c.Next()
+	}
+}
+
+// CurrentRequestID 获取当前请求ID
+func CurrentRequestID(c *gin.Context) string {
+	return c.GetString("request_id")
+}
+
+// 中断请求并返回错误响应，与控制器的错误响应一致带有请求ID，错误同时记录到请求上下文中由访问日志输出
+func abortWithError(c *gin.Context, status int, msg string) {
+	_ = c.Error(errors.New(msg))
+	c.AbortWithStatusJSON(status, gin.H{"error": msg, "request_id": CurrentRequestID(c)})
+}
+
+func newRequestID() string {
+	buf := make([]byte, 16)
+	if _, err := rand.Read(buf); err != nil {
+		return strconv.FormatInt(time.Now().UnixNano(), 36)
+	}
+	return hex.EncodeToString(buf)
+}
+
+// 调用方传入的请求ID只允许字母、数字和 -_.: ，避免日志注入
+func validRequestID(id string) bool {
+	if id == "" || len(id) > 64 {
+		return false
+	}
+	for _, r := range id {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9':
+		case r == '-', r == '_', r == '.', r == ':':
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+// 解析 traceparent 中的链路ID，格式为 版本-链路ID(32位十六进制)-父ID-标志
+func parseTraceParent(header string) string {
+	parts := strings.Split(header, "-")
+	if len(parts) != 4 || len(parts[1]) != 32 || parts[1] == strings.Repeat("0", 32) {
+		return ""
+	}
+	if _, err := hex.DecodeString(parts[1]); err != nil {
+		return ""
+	}
+	return strings.ToLower(parts[1])
+}
+
+// AccessLog 访问日志中间件，5xx 记为错误，4xx 记为警告，健康检查和指标请求只在debug级别记录
+func AccessLog() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		start := time.Now()
+		c.Next()
+
+		status := c.Writer.Status()
+		level := slog.LevelInfo
+		switch {
+		case status >= http.StatusInternalServerError:
+			level = slog.LevelError
+		case status >= http.StatusBadRequest:
+			level = slog.LevelWarn
+		case isProbePath(c.Request.URL.Path):
+			level = slog.LevelDebug
+		}
+
+		attrs := []any{
+			"method", c.Request.Method,
+			"path", c.Request.URL.Path,
+			"route", c.FullPath(),
+			"status", status,
+			"duration_ms", time.Since(start).Milliseconds(),
+			"ip", c.ClientIP(),
+			"size", c.Writer.Size(),
+		}
+		if userID := CurrentUserID(c); userID != 0 {
+			attrs = append(attrs, "user_id", userID)
+		}
+		if len(c.Errors) > 0 {
+			attrs = append(attrs, "error", strings.Join(c.Errors.Errors(), "; "))
+		}
+		logging.FromContext(c.Request.Context()).Log(c.Request.Context(), level, "请求完成", attrs...)
+	}
+}
+
+func isProbePath(path string) bool {
+	return path == "/healthz" || path == "/readyz" || path == "/metrics"
+}
+
+// Recovery 捕获处理请求时的panic，记录调用栈并返回500及请求ID，便于按请求ID查找日志
+func Recovery() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		defer func() {
+			r := recover()
+			if r == nil {
+				return
+			}
+			// 客户端断开等情况下由 net/http 处理，不作为错误
+			if r == http.ErrAbortHandler {
+				panic(r)
+			}
+
+			logging.FromContext(c.Request.Context()).Error("处理请求时发生panic",
+				"panic", r,
+				"method", c.Request.Method,
+				"path", c.Request.URL.Path,
+				"stack", string(debug.Stack()),
+			)
+			if c.Writer.Written() {
+				c.Abort()
+				return
+			}
+			c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{
+				"error":      "internal server error",
+				"request_id": CurrentRequestID(c),
+			})
+		}()
+		c.Next()
+	}
+}
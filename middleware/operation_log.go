@@ -0,0 +1,146 @@
+package middleware
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/lemonoa/LemonOA-Go/model"
+	"github.com/lemonoa/LemonOA-Go/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// 记录日志时读取的请求体和响应体上限，超出部分不记录
+const (
+	operationLogBodyLimit     = 64 << 10
+	operationLogResponseLimit = 4 << 10
+)
+
+// 操作日志中各请求方法对应的操作
+var operationActions = map[string]string{
+	http.MethodPost:   "create",
+	http.MethodPut:    "update",
+	http.MethodPatch:  "update",
+	http.MethodDelete: "delete",
+}
+
+// OperationLog 操作日志中间件，记录已登录用户的新增、修改、删除请求，以及模拟登录期间的全部请求，日志异步批量写入
+// 需注册在全局，JWT中间件在路由组中执行后即可取得当前用户；未登录的请求(如登录)不记录，登录情况见登录日志
+func OperationLog() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !isMutatingMethod(c.Request.Method) {
+			c.Next()
+			if impersonatorID := CurrentImpersonatorID(c); impersonatorID != 0 {
+				recordImpersonatedRequest(c, impersonatorID, CurrentUserID(c))
+			}
+			return
+		}
+
+		body := readRequestBody(c)
+		writer := &responseRecorder{ResponseWriter: c.Writer}
+		c.Writer = writer
+
+		c.Next()
+
+		userID := CurrentUserID(c)
+		if userID == 0 {
+			return
+		}
+
+		path := c.FullPath()
+		if path == "" {
+			path = c.Request.URL.Path
+		}
+		module, action := operationOf(c.Request.Method, path)
+
+		entry := &model.OperationLog{
+			UserID:    CurrentActorID(c),
+			Module:    module,
+			Action:    action,
+			Method:    c.Request.Method,
+			Path:      c.Request.URL.Path,
+			Params:    service.SanitizeParams(c.Request.URL.RawQuery, body, strings.HasPrefix(path, "/api/auth/")),
+			Status:    c.Writer.Status(),
+			Response:  service.SummarizeResponse(writer.body.Bytes()),
+			IP:        c.ClientIP(),
+			UserAgent: c.Request.UserAgent(),
+		}
+		if CurrentImpersonatorID(c) != 0 {
+			entry.ImpersonatedUserID = userID
+		}
+		service.RecordOperationLog(c.Request.Context(), entry)
+	}
+}
+
+// 记录模拟登录期间的查询请求，操作人为实际操作人
+func recordImpersonatedRequest(c *gin.Context, impersonatorID, userID uint) {
+	service.RecordOperationLog(c.Request.Context(), &model.OperationLog{
+		UserID:             impersonatorID,
+		ImpersonatedUserID: userID,
+		Module:             service.ImpersonationLogModule,
+		Action:             "request",
+		Method:             c.Request.Method,
+		Path:               c.Request.URL.Path,
+		Params:             c.Request.URL.RawQuery,
+		Status:             c.Writer.Status(),
+		IP:                 c.ClientIP(),
+		UserAgent:          c.Request.UserAgent(),
+	})
+}
+
+func isMutatingMethod(method string) bool {
+	_, ok := operationActions[method]
+	return ok
+}
+
+// 读取JSON请求体后重新放回，文件上传等其他请求体不读取
+func readRequestBody(c *gin.Context) []byte {
+	if c.Request.Body == nil || !strings.HasPrefix(c.ContentType(), "application/json") {
+		return nil
+	}
+	if c.Request.ContentLength > operationLogBodyLimit {
+		return nil
+	}
+
+	body, err := io.ReadAll(io.LimitReader(c.Request.Body, operationLogBodyLimit))
+	if err != nil {
+		return nil
+	}
+	c.Request.Body = io.NopCloser(io.MultiReader(bytes.NewReader(body), c.Request.Body))
+	return body
+}
+
+// 按路由提取模块和操作：模块为 /api 后的第一段；以动作结尾的路由(如 /records/:id/approve)操作为该动作，否则按请求方法
+func operationOf(method, path string) (string, string) {
+	segments := strings.Split(strings.Trim(strings.TrimPrefix(path, "/api"), "/"), "/")
+	module := segments[0]
+
+	action := operationActions[method]
+	if n := len(segments); n >= 2 && strings.HasPrefix(segments[n-2], ":") && !strings.HasPrefix(segments[n-1], ":") {
+		action = segments[n-1]
+	}
+	return module, action
+}
+
+// responseRecorder 记录响应体开头部分，用于提取操作结果摘要
+type responseRecorder struct {
+	gin.ResponseWriter
+	body bytes.Buffer
+}
+
+func (w *responseRecorder) Write(data []byte) (int, error) {
+	if remain := operationLogResponseLimit - w.body.Len(); remain > 0 {
+		if len(data) > remain {
+			w.body.Write(data[:remain])
+		} else {
+			w.body.Write(data)
+		}
+	}
+	return w.ResponseWriter.Write(data)
+}
+
+func (w *responseRecorder) WriteString(s string) (int, error) {
+	return w.Write([]byte(s))
+}
@@ -0,0 +1,47 @@
+package middleware
+
+import (
+	"strconv"
+
+	"github.com/lemonoa/LemonOA-Go/model"
+	"github.com/lemonoa/LemonOA-Go/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// MaskSensitive 返回数据前调用：没有查看敏感信息权限时对敏感字段脱敏，有权限时返回明文并记录查看日志
+func MaskSensitive(c *gin.Context, v interface{}) {
+	count := service.CountSensitive(v)
+	if count == 0 {
+		return
+	}
+
+	if userID := CurrentUserID(c); userID != 0 {
+		perms, err := currentPermissions(c, userID)
+		if err == nil && perms.Has(model.PermissionSensitiveView) {
+			recordSensitiveView(c, count)
+			return
+		}
+	}
+
+	service.MaskSensitive(v)
+}
+
+// 记录查看明文敏感信息的日志，操作人为实际操作人
+func recordSensitiveView(c *gin.Context, count int) {
+	log := &model.OperationLog{
+		UserID:    CurrentActorID(c),
+		Module:    "sensitive",
+		Action:    "view",
+		Method:    c.Request.Method,
+		Path:      c.Request.URL.Path,
+		Params:    c.Request.URL.RawQuery,
+		Response:  strconv.Itoa(count),
+		IP:        c.ClientIP(),
+		UserAgent: c.Request.UserAgent(),
+	}
+	if CurrentImpersonatorID(c) != 0 {
+		log.ImpersonatedUserID = CurrentUserID(c)
+	}
+	service.RecordOperationLog(c.Request.Context(), log)
+}
@@ -0,0 +1,79 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+
+	"github.com/lemonoa/LemonOA-Go/database"
+)
+
+const migrateUsage = `用法: lemon-oa migrate <命令>
+  up          执行全部未执行的迁移
+  down [n] [--force]
+              回滚最近执行的 n 个迁移，默认 1；回滚基线版本会删除全部数据表，需指定 --force
+  status      查看迁移执行状态`
+
+// 执行数据库迁移命令，只打开数据库连接，不检查数据库结构
+func runMigrate(args []string) error {
+	if len(args) == 0 {
+		return errors.New(migrateUsage)
+	}
+
+	if err := database.Open(); err != nil {
+		return fmt.Errorf("failed to open database: %w", err)
+	}
+	db := database.DB
+
+	switch args[0] {
+	case "up":
+		done, err := database.Migrate(db)
+		for _, m := range done {
+			fmt.Printf("已执行 %d_%s\n", m.Version, m.Name)
+		}
+		if err != nil {
+			return err
+		}
+		if len(done) == 0 {
+			fmt.Println("数据库结构已是最新")
+		}
+	case "down":
+		steps, force := 1, false
+		for _, arg := range args[1:] {
+			if arg == "--force" {
+				force = true
+				continue
+			}
+			n, err := strconv.Atoi(arg)
+			if err != nil || n <= 0 {
+				return fmt.Errorf("invalid steps %q", arg)
+			}
+			steps = n
+		}
+		done, err := database.Rollback(db, steps, force)
+		for _, m := range done {
+			fmt.Printf("已回滚 %d_%s\n", m.Version, m.Name)
+		}
+		if errors.Is(err, database.ErrBaselineRollback) {
+			return fmt.Errorf("%w, add --force to confirm", err)
+		}
+		if err != nil {
+			return err
+		}
+	case "status":
+		states, err := database.MigrationStatus(db)
+		if err != nil {
+			return err
+		}
+		for _, state := range states {
+			appliedAt := "未执行"
+			if state.AppliedAt != nil {
+				appliedAt = state.AppliedAt.Format("2006-01-02 15:04:05")
+			}
+			fmt.Printf("%4d  %-30s %s\n", state.Version, state.Name, appliedAt)
+		}
+	default:
+		return errors.New(migrateUsage)
+	}
+	return nil
+}
@@ -6,14 +6,85 @@ import (
 	"gorm.io/gorm"
 )
 
+// 角色数据权限范围
+const (
+	DataScopeAll      = 1 // 全部数据
+	DataScopeCustom   = 2 // 自定义部门
+	DataScopeDeptTree = 3 // 本部门及下级部门
+	DataScopeDept     = 4 // 本部门
+	DataScopeSelf     = 5 // 仅本人
+)
+
+// 用户类型
+const (
+	UserTypeNormal  = 1 // 普通用户
+	UserTypeService = 2 // 服务账号，只能通过API密钥访问，不能登录
+)
+
+// 用户来源，即用户的认证方式
+const (
+	UserSourceLocal = "local" // 本地账号，密码保存在users表
+	UserSourceLDAP  = "ldap"  // LDAP/AD账号，首次登录时自动创建
+	UserSourceOIDC  = "oidc"  // OIDC单点登录账号，首次登录时自动创建
+)
+
 // 系统管理权限
 const (
 	// 用户管理
-	PermissionUserList     = "system:user:list"
-	PermissionUserCreate   = "system:user:create"
-	PermissionUserUpdate   = "system:user:update"
-	PermissionUserDelete   = "system:user:delete"
-	PermissionUserResetPwd = "system:user:reset-password"
+	PermissionUserList        = "system:user:list"
+	PermissionUserCreate      = "system:user:create"
+	PermissionUserUpdate      = "system:user:update"
+	PermissionUserDelete      = "system:user:delete"
+	PermissionUserResetPwd    = "system:user:reset-password"
+	PermissionUserForceLogout = "system:user:force-logout"
+	PermissionUserUnlock      = "system:user:unlock"
+	PermissionUserReset2FA    = "system:user:reset-2fa"
+
+	// 服务账号及API密钥
+	PermissionServiceAccountCreate = "system:service-account:create"
+	PermissionApiKeyList           = "system:api-key:list"
+	PermissionApiKeyCreate         = "system:api-key:create"
+	PermissionApiKeyRevoke         = "system:api-key:revoke"
+
+	// 在线会话
+	PermissionSessionList   = "system:session:list"
+	PermissionSessionRevoke = "system:session:revoke"
+
+	// 敏感信息
+	PermissionSensitiveView       = "system:sensitive:view"
+	PermissionEncryptionKeyRotate = "system:encryption:rotate"
+
+	// 系统配置及功能模块
+	PermissionConfigUpdate       = "system:config:update"
+	PermissionModuleCreate       = "system:module:create"
+	PermissionModuleUpdate       = "system:module:update"
+	PermissionModuleDelete       = "system:module:delete"
+	PermissionModuleConfigUpdate = "system:module-config:update"
+
+	// 操作日志
+	PermissionOperationLogList   = "system:operation-log:list"
+	PermissionOperationLogExport = "system:operation-log:export"
+
+	// 附件管理，附件按上传人纳入数据权限
+	PermissionAttachmentList     = "system:attachment:list"
+	PermissionAttachmentUpload   = "system:attachment:upload"
+	PermissionAttachmentDownload = "system:attachment:download"
+	PermissionAttachmentUpdate   = "system:attachment:update"
+	PermissionAttachmentDelete   = "system:attachment:delete"
+
+	// 备份管理
+	PermissionBackupList     = "system:backup:list"
+	PermissionBackupCreate   = "system:backup:create"
+	PermissionBackupDownload = "system:backup:download"
+	PermissionBackupRestore  = "system:backup:restore"
+	PermissionBackupDelete   = "system:backup:delete"
+
+	// 定时任务
+	PermissionTaskList   = "system:task:list"
+	PermissionTaskCreate = "system:task:create"
+	PermissionTaskUpdate = "system:task:update"
+	PermissionTaskDelete = "system:task:delete"
+	PermissionTaskRun    = "system:task:run"
 
 	// 角色管理
 	PermissionRoleList        = "system:role:list"
@@ -36,6 +107,7 @@ const (
 	PermissionAttendanceRuleDelete   = "attendance:rule:delete"
 	PermissionAttendanceRecordList   = "attendance:record:list"
 	PermissionAttendanceRecordCreate = "attendance:record:create"
+	PermissionAttendanceCheckIn      = "attendance:check-in"
 	PermissionLeaveList              = "attendance:leave:list"
 	PermissionLeaveCreate            = "attendance:leave:create"
 	PermissionLeaveApprove           = "attendance:leave:approve"
@@ -60,21 +132,34 @@ const (
 
 // User 用户表
 type User struct {
-	ID          uint           `gorm:"primarykey" json:"id"`
-	Username    string         `gorm:"size:50;not null;unique" json:"username"` // 用户名
-	Password    string         `gorm:"size:100;not null" json:"-"`              // 密码
-	Salt        string         `gorm:"size:32;not null" json:"-"`               // 密码盐值
-	RealName    string         `gorm:"size:50" json:"real_name"`                // 真实姓名
-	Avatar      string         `gorm:"size:255" json:"avatar"`                  // 头像
-	Email       string         `gorm:"size:100" json:"email"`                   // 邮箱
-	Mobile      string         `gorm:"size:20" json:"mobile"`                   // 手机号
-	Status      int            `gorm:"default:1" json:"status"`                 // 1:正常 2:禁用
-	LastLoginAt *time.Time     `json:"last_login_at"`                           // 最后登录时间
-	LastLoginIP string         `gorm:"size:50" json:"last_login_ip"`            // 最后登录IP
-	CreatedBy   uint           `gorm:"not null" json:"created_by"`              // 创建人ID
-	CreatedAt   time.Time      `json:"created_at"`
-	UpdatedAt   time.Time      `json:"updated_at"`
-	DeletedAt   gorm.DeletedAt `gorm:"index" json:"deleted_at"`
+	ID                 uint           `gorm:"primarykey" json:"id"`
+	Username           string         `gorm:"size:50;not null;unique" json:"username"`                                  // 用户名
+	Password           string         `gorm:"size:255;not null" json:"-"`                                               // 密码哈希，编码串包含算法及参数
+	Salt               string         `gorm:"size:32;not null" json:"-"`                                                // 旧版SHA-512密码盐值
+	RealName           string         `gorm:"size:50" json:"real_name"`                                                 // 真实姓名
+	Avatar             string         `gorm:"size:255" json:"avatar"`                                                   // 头像
+	Email              string         `gorm:"size:100" json:"email"`                                                    // 邮箱
+	Mobile             string         `gorm:"size:255;serializer:encrypted" json:"mobile" mask:"mobile"`                // 手机号，加密存储
+	Type               int            `gorm:"default:1" json:"type"`                                                    // 1:普通用户 2:服务账号
+	Source             string         `gorm:"size:20;default:local" json:"source"`                                      // 用户来源 local:本地 ldap:LDAP/AD oidc:单点登录
+	OIDCIssuer         *string        `gorm:"column:oidc_issuer;size:255;uniqueIndex:idx_users_oidc_subject" json:"-"`  // 单点登录签发方，与 OIDCSubject 共同标识IdP账号
+	OIDCSubject        *string        `gorm:"column:oidc_subject;size:255;uniqueIndex:idx_users_oidc_subject" json:"-"` // 单点登录用户标识(sub)
+	Status             int            `gorm:"default:1" json:"status"`                                                  // 1:正常 2:禁用
+	LastLoginAt        *time.Time     `json:"last_login_at"`                                                            // 最后登录时间
+	LastLoginIP        string         `gorm:"size:50" json:"last_login_ip"`                                             // 最后登录IP
+	TokenRevokedAt     *time.Time     `json:"-"`                                                                        // token吊销时间，此前签发的token全部失效
+	PasswordChangedAt  *time.Time     `json:"password_changed_at"`                                                      // 密码修改时间，用于计算密码有效期
+	MustChangePassword bool           `gorm:"default:false" json:"must_change_password"`                                // 下次登录时必须修改密码
+	EmployeeID         *uint          `gorm:"uniqueIndex" json:"employee_id"`                                           // 关联员工ID，用于数据权限的部门归属
+	LoginFailures      int            `gorm:"default:0" json:"login_failures"`                                          // 连续登录失败次数
+	LockedUntil        *time.Time     `json:"locked_until"`                                                             // 账号锁定截止时间
+	TOTPSecret         string         `gorm:"column:totp_secret;size:64" json:"-"`                                      // 两步验证TOTP密钥
+	TOTPEnabled        bool           `gorm:"column:totp_enabled;default:false" json:"totp_enabled"`                    // 是否已开启两步验证
+	TOTPLastCounter    int64          `gorm:"column:totp_last_counter;default:0" json:"-"`                              // 最后一次使用的TOTP时间步，防止验证码重放
+	CreatedBy          uint           `gorm:"not null" json:"created_by"`                                               // 创建人ID
+	CreatedAt          time.Time      `json:"created_at"`
+	UpdatedAt          time.Time      `json:"updated_at"`
+	DeletedAt          gorm.DeletedAt `gorm:"index" json:"deleted_at"`
 }
 
 // UserRole 用户角色关联表
@@ -94,8 +179,10 @@ type Permission struct {
 	Name        string         `gorm:"size:50;not null" json:"name"`        // 权限名称
 	Code        string         `gorm:"size:50;not null;unique" json:"code"` // 权限编码
 	Type        int            `gorm:"not null" json:"type"`                // 1:菜单 2:按钮 3:接口
+	ModuleID    *uint          `gorm:"index" json:"module_id"`              // 所属功能模块ID
 	ParentID    *uint          `json:"parent_id"`                           // 父级ID
-	Path        string         `gorm:"size:200" json:"path"`                // 路由路径
+	Path        string         `gorm:"size:200" json:"path"`                // 路由路径，接口类型按路由模板匹配，如 /api/assets/:id
+	Method      string         `gorm:"size:10" json:"method"`               // 请求方法，接口类型为空时匹配所有方法
 	Component   string         `gorm:"size:200" json:"component"`           // 前端组件
 	Icon        string         `gorm:"size:50" json:"icon"`                 // 图标
 	Sort        int            `gorm:"default:0" json:"sort"`               // 排序
@@ -121,8 +208,8 @@ type RolePermission struct {
 // LoginLog 登录日志
 type LoginLog struct {
 	ID        uint           `gorm:"primarykey" json:"id"`
-	UserID    uint           `gorm:"not null" json:"user_id"`    // 用户ID
-	IP        string         `gorm:"size:50" json:"ip"`          // 登录IP
+	UserID    uint           `gorm:"not null" json:"user_id"`    // 用户ID，用户名不存在时为0
+	IP        string         `gorm:"size:50;index" json:"ip"`    // 登录IP
 	UserAgent string         `gorm:"size:500" json:"user_agent"` // User-Agent
 	Status    int            `gorm:"default:1" json:"status"`    // 1:成功 2:失败
 	Message   string         `gorm:"size:200" json:"message"`    // 失败原因
@@ -131,19 +218,116 @@ type LoginLog struct {
 	DeletedAt gorm.DeletedAt `gorm:"index" json:"deleted_at"`
 }
 
+// RefreshToken 刷新令牌
+type RefreshToken struct {
+	ID        uint           `gorm:"primarykey" json:"id"`
+	UserID    uint           `gorm:"not null;index" json:"user_id"`           // 用户ID
+	TokenHash string         `gorm:"size:64;not null;uniqueIndex" json:"-"`   // 令牌SHA-256哈希
+	FamilyID  string         `gorm:"size:32;not null;index" json:"family_id"` // 令牌族ID，同一次登录轮换出的令牌共用
+	ExpiresAt time.Time      `gorm:"not null" json:"expires_at"`              // 过期时间
+	RevokedAt *time.Time     `json:"revoked_at"`                              // 吊销时间
+	IP        string         `gorm:"size:50" json:"ip"`                       // 签发时的IP
+	UserAgent string         `gorm:"size:500" json:"user_agent"`              // 签发时的User-Agent
+	CreatedAt time.Time      `json:"created_at"`
+	UpdatedAt time.Time      `json:"updated_at"`
+	DeletedAt gorm.DeletedAt `gorm:"index" json:"deleted_at"`
+}
+
+// UserSession 登录会话，每次登录创建一个会话，与刷新令牌族一一对应
+type UserSession struct {
+	ID         uint           `gorm:"primarykey" json:"id"`
+	UserID     uint           `gorm:"not null;index" json:"user_id"`         // 用户ID
+	SessionID  string         `gorm:"size:32;not null;uniqueIndex" json:"-"` // 会话ID，即刷新令牌族ID，写入访问令牌的sid
+	IP         string         `gorm:"size:50" json:"ip"`                     // 最近访问IP
+	UserAgent  string         `gorm:"size:500" json:"user_agent"`            // 登录时的User-Agent
+	IssuedAt   time.Time      `gorm:"not null" json:"issued_at"`             // 登录时间
+	LastSeenAt time.Time      `gorm:"not null" json:"last_seen_at"`          // 最近活跃时间
+	ExpiresAt  time.Time      `gorm:"not null" json:"expires_at"`            // 过期时间，随刷新令牌延长
+	RevokedAt  *time.Time     `json:"revoked_at"`                            // 吊销时间
+	Current    bool           `gorm:"-" json:"current"`                      // 是否当前请求所属的会话，不存储
+	CreatedAt  time.Time      `json:"created_at"`
+	UpdatedAt  time.Time      `json:"updated_at"`
+	DeletedAt  gorm.DeletedAt `gorm:"index" json:"deleted_at"`
+}
+
+// RevokedToken 已吊销的访问令牌
+type RevokedToken struct {
+	ID        uint      `gorm:"primarykey" json:"id"`
+	JTI       string    `gorm:"size:32;not null;uniqueIndex" json:"jti"` // 令牌ID
+	UserID    uint      `gorm:"not null" json:"user_id"`                 // 用户ID
+	ExpiresAt time.Time `gorm:"not null;index" json:"expires_at"`        // 令牌原过期时间，过期后可清理
+	CreatedAt time.Time `json:"created_at"`
+}
+
 // Role 角色表
 type Role struct {
 	ID          uint           `gorm:"primarykey" json:"id"`
-	Name        string         `gorm:"size:50;not null" json:"name"`        // 角色名称
-	Code        string         `gorm:"size:50;not null;unique" json:"code"` // 角色编码
-	Description string         `gorm:"size:255" json:"description"`         // 描述
-	Status      int            `gorm:"default:1" json:"status"`             // 1:启用 2:禁用
-	CreatedBy   uint           `gorm:"not null" json:"created_by"`          // 创建人ID
+	Name        string         `gorm:"size:50;not null" json:"name"`                        // 角色名称
+	Code        string         `gorm:"size:50;not null;unique" json:"code"`                 // 角色编码
+	Description string         `gorm:"size:255" json:"description"`                         // 描述
+	Status      int            `gorm:"default:1" json:"status"`                             // 1:启用 2:禁用
+	Require2FA  bool           `gorm:"column:require_2fa;default:false" json:"require_2fa"` // 是否要求该角色的用户开启两步验证
+	DataScope   int            `gorm:"default:5" json:"data_scope"`                         // 数据权限 1:全部数据 2:自定义部门 3:本部门及下级部门 4:本部门 5:仅本人，默认仅本人
+	CreatedBy   uint           `gorm:"not null" json:"created_by"`                          // 创建人ID
 	CreatedAt   time.Time      `json:"created_at"`
 	UpdatedAt   time.Time      `json:"updated_at"`
 	DeletedAt   gorm.DeletedAt `gorm:"index" json:"deleted_at"`
 }
 
+// RoleDepartment 角色自定义数据权限部门
+type RoleDepartment struct {
+	ID           uint      `gorm:"primarykey" json:"id"`
+	RoleID       uint      `gorm:"not null;index" json:"role_id"` // 角色ID
+	DepartmentID uint      `gorm:"not null" json:"department_id"` // 部门ID
+	CreatedBy    uint      `gorm:"not null" json:"created_by"`    // 创建人ID
+	CreatedAt    time.Time `json:"created_at"`
+}
+
+// PasswordHistory 历史密码，用于禁止重复使用最近的密码
+type PasswordHistory struct {
+	ID        uint      `gorm:"primarykey" json:"id"`
+	UserID    uint      `gorm:"not null;index" json:"user_id"` // 用户ID
+	Password  string    `gorm:"size:255;not null" json:"-"`    // 密码哈希
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// OIDCLoginState OIDC单点登录的授权请求状态，回调时校验并删除，只能使用一次
+type OIDCLoginState struct {
+	ID           uint      `gorm:"primarykey" json:"id"`
+	State        string    `gorm:"size:64;not null;uniqueIndex" json:"state"` // state参数，防止CSRF
+	Nonce        string    `gorm:"size:64;not null" json:"-"`                 // 写入ID令牌的nonce，防止重放
+	CodeVerifier string    `gorm:"size:128;not null" json:"-"`                // PKCE code_verifier
+	ExpiresAt    time.Time `gorm:"not null;index" json:"expires_at"`          // 过期时间
+	CreatedAt    time.Time `json:"created_at"`
+}
+
+// UserRecoveryCode 两步验证恢复码，每个恢复码只能使用一次
+type UserRecoveryCode struct {
+	ID        uint       `gorm:"primarykey" json:"id"`
+	UserID    uint       `gorm:"not null;index" json:"user_id"` // 用户ID
+	CodeHash  string     `gorm:"size:64;not null" json:"-"`     // 恢复码SHA-256哈希
+	UsedAt    *time.Time `json:"used_at"`                       // 使用时间
+	CreatedAt time.Time  `json:"created_at"`
+}
+
+// ApiKey API密钥，归属于服务账号，供系统集成调用接口
+type ApiKey struct {
+	ID         uint           `gorm:"primarykey" json:"id"`
+	UserID     uint           `gorm:"not null;index" json:"user_id"`         // 所属用户ID
+	Name       string         `gorm:"size:50;not null" json:"name"`          // 名称
+	Prefix     string         `gorm:"size:16;not null" json:"prefix"`        // 密钥前缀，用于识别密钥
+	KeyHash    string         `gorm:"size:64;not null;uniqueIndex" json:"-"` // 密钥SHA-256哈希
+	Scopes     string         `gorm:"size:1000" json:"scopes"`               // 允许使用的权限编码，逗号分隔，为空时与所属用户权限一致
+	ExpiresAt  *time.Time     `json:"expires_at"`                            // 过期时间，为空表示永不过期
+	LastUsedAt *time.Time     `json:"last_used_at"`                          // 最后使用时间
+	LastUsedIP string         `gorm:"size:50" json:"last_used_ip"`           // 最后使用IP
+	RevokedAt  *time.Time     `json:"revoked_at"`                            // 吊销时间
+	CreatedBy  uint           `gorm:"not null" json:"created_by"`            // 创建人ID
+	CreatedAt  time.Time      `json:"created_at"`
+	UpdatedAt  time.Time      `json:"updated_at"`
+	DeletedAt  gorm.DeletedAt `gorm:"index" json:"deleted_at"`
+}
+
 // TableName 指定表名
 func (User) TableName() string {
 	return "users"
@@ -164,3 +348,35 @@ func (RolePermission) TableName() string {
 func (LoginLog) TableName() string {
 	return "login_logs"
 }
+
+func (RefreshToken) TableName() string {
+	return "refresh_tokens"
+}
+
+func (RevokedToken) TableName() string {
+	return "revoked_tokens"
+}
+
+func (UserRecoveryCode) TableName() string {
+	return "user_recovery_codes"
+}
+
+func (RoleDepartment) TableName() string {
+	return "role_departments"
+}
+
+func (ApiKey) TableName() string {
+	return "api_keys"
+}
+
+func (PasswordHistory) TableName() string {
+	return "password_histories"
+}
+
+func (UserSession) TableName() string {
+	return "user_sessions"
+}
+
+func (OIDCLoginState) TableName() string {
+	return "oidc_login_states"
+}
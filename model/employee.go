@@ -11,7 +11,7 @@ type Employee struct {
 	ID           uint           `gorm:"primarykey" json:"id"`
 	Name         string         `gorm:"size:50;not null" json:"name"`
 	Email        string         `gorm:"size:100;unique" json:"email"`
-	Phone        string         `gorm:"size:20" json:"phone"`
+	Phone        string         `gorm:"size:255;serializer:encrypted" json:"phone" mask:"mobile"` // 手机号，加密存储
 	Avatar       string         `gorm:"size:255" json:"avatar"`
 	DepartmentID uint           `gorm:"not null" json:"department_id"`
 	Position     string         `gorm:"size:50" json:"position"`
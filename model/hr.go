@@ -23,18 +23,18 @@ type Position struct {
 type EmployeeArchive struct {
 	ID            uint           `gorm:"primarykey" json:"id"`
 	EmployeeID    uint           `gorm:"not null" json:"employee_id"`
-	Education     string         `gorm:"size:50" json:"education"`      // 学历
-	School        string         `gorm:"size:100" json:"school"`        // 毕业院校
-	Major         string         `gorm:"size:100" json:"major"`         // 专业
-	GraduationAt  *time.Time     `json:"graduation_at"`                 // 毕业时间
-	WorkStartAt   *time.Time     `json:"work_start_at"`                 // 参加工作时间
-	MaritalStatus string         `gorm:"size:20" json:"marital_status"` // 婚姻状况
-	Political     string         `gorm:"size:50" json:"political"`      // 政治面貌
-	IDCard        string         `gorm:"size:20" json:"id_card"`        // 身份证号
-	Birthday      *time.Time     `json:"birthday"`                      // 出生日期
-	Native        string         `gorm:"size:100" json:"native"`        // 籍贯
-	Address       string         `gorm:"size:255" json:"address"`       // 现居地址
-	Files         string         `gorm:"type:text" json:"files"`        // 档案附件，JSON数组
+	Education     string         `gorm:"size:50" json:"education"`                                           // 学历
+	School        string         `gorm:"size:100" json:"school"`                                             // 毕业院校
+	Major         string         `gorm:"size:100" json:"major"`                                              // 专业
+	GraduationAt  *time.Time     `json:"graduation_at"`                                                      // 毕业时间
+	WorkStartAt   *time.Time     `json:"work_start_at"`                                                      // 参加工作时间
+	MaritalStatus string         `gorm:"size:20" json:"marital_status"`                                      // 婚姻状况
+	Political     string         `gorm:"size:50" json:"political"`                                           // 政治面貌
+	IDCard        string         `gorm:"size:255;serializer:encrypted" json:"id_card" mask:"id_card"`        // 身份证号，加密存储
+	Birthday      *time.Time     `gorm:"type:varchar(255);serializer:encrypted" json:"birthday" mask:"date"` // 出生日期，加密存储
+	Native        string         `gorm:"size:100" json:"native"`                                             // 籍贯
+	Address       string         `gorm:"type:text;serializer:encrypted" json:"address" mask:"address"`       // 现居地址，加密存储
+	Files         string         `gorm:"type:text" json:"files"`                                             // 档案附件，JSON数组
 	CreatedAt     time.Time      `json:"created_at"`
 	UpdatedAt     time.Time      `json:"updated_at"`
 	DeletedAt     gorm.DeletedAt `gorm:"index" json:"deleted_at"`
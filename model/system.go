@@ -43,85 +43,133 @@ type ModuleConfig struct {
 	DeletedAt gorm.DeletedAt `gorm:"index" json:"deleted_at"`
 }
 
-// FunctionNode 功能节点
-type FunctionNode struct {
-	ID        uint           `gorm:"primarykey" json:"id"`
-	ModuleID  uint           `gorm:"not null" json:"module_id"`
-	Name      string         `gorm:"size:50;not null" json:"name"`
-	Code      string         `gorm:"size:50;not null;unique" json:"code"`
-	Type      int            `gorm:"default:1" json:"type"` // 1:菜单 2:按钮 3:接口
-	Path      string         `gorm:"size:255" json:"path"`
-	Method    string         `gorm:"size:10" json:"method"`
-	Sort      int            `gorm:"default:0" json:"sort"`
-	Status    int            `gorm:"default:1" json:"status"` // 1:启用 2:禁用
-	CreatedAt time.Time      `json:"created_at"`
-	UpdatedAt time.Time      `json:"updated_at"`
-	DeletedAt gorm.DeletedAt `gorm:"index" json:"deleted_at"`
-}
-
-// RoleFunction 角色功能权限
-type RoleFunction struct {
-	ID             uint           `gorm:"primarykey" json:"id"`
-	RoleID         uint           `gorm:"not null" json:"role_id"`
-	FunctionNodeID uint           `gorm:"not null" json:"function_node_id"`
-	CreatedAt      time.Time      `json:"created_at"`
-	UpdatedAt      time.Time      `json:"updated_at"`
-	DeletedAt      gorm.DeletedAt `gorm:"index" json:"deleted_at"`
-}
+// FunctionNode 功能节点，已与权限统一，功能节点即权限表中的记录
+type FunctionNode = Permission
 
 // OperationLog 操作日志
 type OperationLog struct {
-	ID        uint           `gorm:"primarykey" json:"id"`
-	UserID    uint           `gorm:"not null" json:"user_id"`
-	Module    string         `gorm:"size:50" json:"module"`
-	Action    string         `gorm:"size:50" json:"action"`
-	Method    string         `gorm:"size:10" json:"method"`
-	Path      string         `gorm:"size:255" json:"path"`
-	Params    string         `gorm:"type:text" json:"params"`
-	Response  string         `gorm:"type:text" json:"response"`
-	IP        string         `gorm:"size:50" json:"ip"`
-	UserAgent string         `gorm:"size:255" json:"user_agent"`
-	CreatedAt time.Time      `json:"created_at"`
-	UpdatedAt time.Time      `json:"updated_at"`
-	DeletedAt gorm.DeletedAt `gorm:"index" json:"deleted_at"`
+	ID                 uint           `gorm:"primarykey" json:"id"`
+	UserID             uint           `gorm:"not null;index" json:"user_id"`     // 实际操作人
+	ImpersonatedUserID uint           `gorm:"index" json:"impersonated_user_id"` // 模拟登录时被模拟的用户
+	Module             string         `gorm:"size:50;index" json:"module"`
+	Action             string         `gorm:"size:50" json:"action"`
+	Method             string         `gorm:"size:10" json:"method"`
+	Path               string         `gorm:"size:255" json:"path"`
+	Params             string         `gorm:"type:text" json:"params"`   // 请求参数，密码等敏感字段已脱敏
+	Status             int            `json:"status"`                    // 响应状态码
+	Response           string         `gorm:"type:text" json:"response"` // 响应摘要
+	IP                 string         `gorm:"size:50" json:"ip"`
+	UserAgent          string         `gorm:"size:255" json:"user_agent"`
+	CreatedAt          time.Time      `gorm:"index" json:"created_at"`
+	UpdatedAt          time.Time      `json:"updated_at"`
+	DeletedAt          gorm.DeletedAt `gorm:"index" json:"deleted_at"`
 }
 
 // Attachment 附件
 type Attachment struct {
 	ID          uint           `gorm:"primarykey" json:"id"`
 	Name        string         `gorm:"size:255;not null" json:"name"`
-	Path        string         `gorm:"size:255;not null" json:"path"`
+	Path        string         `gorm:"size:255;not null" json:"-"` // 存储路径，内容相同的附件共用同一个文件
+	Storage     string         `gorm:"size:20" json:"storage"`     // 存储方式 local / s3
+	Hash        string         `gorm:"size:64;index" json:"hash"`  // 文件内容SHA-256
 	Size        int64          `gorm:"not null" json:"size"`
-	Type        string         `gorm:"size:50" json:"type"`
+	Type        string         `gorm:"size:100" json:"type"` // 按文件内容识别的MIME类型
 	UploadedBy  uint           `gorm:"not null" json:"uploaded_by"`
 	Module      string         `gorm:"size:50" json:"module"`
-	RelatedID   uint           `json:"related_id"`
-	RelatedType string         `gorm:"size:50" json:"related_type"`
+	RelatedID   uint           `gorm:"index:idx_attachment_related,priority:2" json:"related_id"`           // 所属记录ID
+	RelatedType string         `gorm:"size:50;index:idx_attachment_related,priority:1" json:"related_type"` // 所属记录类型，如 asset、document
 	CreatedAt   time.Time      `json:"created_at"`
 	UpdatedAt   time.Time      `json:"updated_at"`
 	DeletedAt   gorm.DeletedAt `gorm:"index" json:"deleted_at"`
 }
 
+// AttachmentUpload 分片上传任务，分片暂存在本地，全部上传后合并为附件
+type AttachmentUpload struct {
+	ID          uint      `gorm:"primarykey" json:"-"`
+	UploadID    string    `gorm:"size:32;not null;uniqueIndex" json:"upload_id"`
+	Name        string    `gorm:"size:255;not null" json:"name"`
+	Size        int64     `gorm:"not null" json:"size"`
+	ChunkSize   int64     `gorm:"not null" json:"chunk_size"`
+	TotalChunks int       `gorm:"not null" json:"total_chunks"`
+	UserID      uint      `gorm:"not null;index" json:"user_id"` // 上传人，只能由本人续传
+	ExpiresAt   time.Time `gorm:"index" json:"expires_at"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+// 备份类型
+const (
+	BackupTypeFull        = 1 // 全量备份
+	BackupTypeIncremental = 2 // 增量备份，只包含上一次备份以来新增和修改的记录
+)
+
+// 备份状态
+const (
+	BackupStatusRunning = 1 // 备份中
+	BackupStatusSuccess = 2 // 备份成功
+	BackupStatusFailed  = 3 // 备份失败
+)
+
 // BackupRecord 备份记录
 type BackupRecord struct {
-	ID        uint           `gorm:"primarykey" json:"id"`
-	Name      string         `gorm:"size:255;not null" json:"name"`
-	Path      string         `gorm:"size:255;not null" json:"path"`
-	Size      int64          `gorm:"not null" json:"size"`
-	Type      int            `gorm:"default:1" json:"type"`   // 1:全量备份 2:增量备份
-	Status    int            `gorm:"default:1" json:"status"` // 1:备份中 2:备份成功 3:备份失败
-	CreatedAt time.Time      `json:"created_at"`
-	UpdatedAt time.Time      `json:"updated_at"`
-	DeletedAt gorm.DeletedAt `gorm:"index" json:"deleted_at"`
+	ID           uint           `gorm:"primarykey" json:"id"`
+	Name         string         `gorm:"size:255;not null" json:"name"`
+	Path         string         `gorm:"size:255;not null" json:"-"` // 备份文件路径
+	Size         int64          `gorm:"not null" json:"size"`
+	Type         int            `gorm:"default:1" json:"type"`   // 1:全量备份 2:增量备份
+	Status       int            `gorm:"default:1" json:"status"` // 1:备份中 2:备份成功 3:备份失败
+	BaseID       uint           `gorm:"index" json:"base_id"`    // 增量备份基于的上一次备份
+	Since        *time.Time     `json:"since"`                   // 增量备份包含该时间之后修改的记录
+	IncludeFiles bool           `json:"include_files"`           // 是否包含附件文件
+	Version      int            `json:"version"`                 // 备份文件格式版本
+	Checksum     string         `gorm:"size:64" json:"checksum"` // 备份文件SHA-256
+	TableCount   int            `json:"table_count"`
+	RowCount     int64          `json:"row_count"`
+	FileCount    int            `json:"file_count"`
+	Progress     int            `json:"progress"`                // 备份进度(0-100)
+	Message      string         `gorm:"size:500" json:"message"` // 失败原因
+	FinishedAt   *time.Time     `json:"finished_at"`
+	RestoredAt   *time.Time     `json:"restored_at"` // 最近一次恢复时间
+	CreatedBy    uint           `json:"created_by"`
+	CreatedAt    time.Time      `json:"created_at"`
+	UpdatedAt    time.Time      `json:"updated_at"`
+	DeletedAt    gorm.DeletedAt `gorm:"index" json:"deleted_at"`
 }
 
+// 定时任务运行结果
+const (
+	TaskRunRunning = 1 // 运行中
+	TaskRunSuccess = 2 // 成功
+	TaskRunFailed  = 3 // 失败
+)
+
 // ScheduledTask 定时任务
 type ScheduledTask struct {
 	ID          uint       `gorm:"primarykey" json:"id"`
 	Name        string     `gorm:"size:50;not null" json:"name"`
 	Description string     `gorm:"size:255" json:"description"`
-	Cron        string     `gorm:"size:50;not null" json:"cron"`
-	Command     string     `gorm:"type:text;not null" json:"command"`
-	Status      int        `gorm:"default:1" json:"status"` // 1:启用 2:禁用
-	LastRunAt   *time.Time `json:"last_run_at"`
+	Cron        string     `gorm:"size:50;not null" json:"cron"`      // cron表达式，支持秒级(6位)及 @daily、@every 1h 等写法
+	Command     string     `gorm:"type:text;not null" json:"command"` // 执行的任务名称，须为已注册的任务，见 /api/system/scheduled-jobs
+	Params      string     `gorm:"type:text" json:"params"`           // 任务参数，一般为JSON
+	Status      int        `gorm:"default:1" json:"status"`           // 1:启用 2:禁用
+	LastRunAt   *time.Time `json:"last_run_at"`                       // 最近一次按计划运行的时间
+	LastStatus  int        `json:"last_status"`                       // 最近一次运行结果 1:运行中 2:成功 3:失败
+	LockedBy    string     `gorm:"size:100" json:"locked_by"`         // 正在运行该任务的实例
+	LockedUntil *time.Time `json:"-"`                                 // 运行锁过期时间，实例异常退出后锁到期自动释放
+}
+
+// ScheduledTaskRun 定时任务运行记录
+type ScheduledTaskRun struct {
+	ID          uint       `gorm:"primarykey" json:"id"`
+	TaskID      uint       `gorm:"not null;index" json:"task_id"`
+	Command     string     `gorm:"size:100" json:"command"`
+	TriggerType string     `gorm:"size:20" json:"trigger_type"` // schedule:按计划 manual:手动运行
+	TriggeredBy uint       `json:"triggered_by"`                // 手动运行的用户
+	Instance    string     `gorm:"size:100" json:"instance"`
+	Status      int        `gorm:"default:1" json:"status"` // 1:运行中 2:成功 3:失败
+	Output      string     `gorm:"type:text" json:"output"`
+	Error       string     `gorm:"type:text" json:"error"`
+	StartedAt   time.Time  `gorm:"index" json:"started_at"`
+	FinishedAt  *time.Time `json:"finished_at"`
+	Duration    int64      `json:"duration"` // 耗时(毫秒)
 }
@@ -1,16 +1,13 @@
 package main
 
 import (
-	"crypto/rand"
-	"crypto/sha512"
-	"encoding/hex"
 	"fmt"
 	"log"
-	"time"
 
 	"github.com/lemonoa/LemonOA-Go/database"
 
 	"github.com/lemonoa/LemonOA-Go/model"
+	"github.com/lemonoa/LemonOA-Go/service"
 
 	"github.com/spf13/viper"
 )
@@ -26,7 +23,7 @@ func init() {
 	}
 
 	// 初始化数据库连接
-	if err := database.InitMySQL(); err != nil {
+	if err := database.Init(); err != nil {
 		log.Fatalf("初始化数据库连接失败: %v", err)
 	}
 }
@@ -40,6 +37,8 @@ func main() {
 		Code:        "super_admin",
 		Description: "系统超级管理员,拥有所有权限",
 		Status:      1,
+		Require2FA:  true,
+		DataScope:   model.DataScopeAll,
 		CreatedBy:   1,
 	}
 	if err := db.Create(superAdminRole).Error; err != nil {
@@ -52,6 +51,7 @@ func main() {
 		Code:        "admin",
 		Description: "系统管理员,拥有大部分系统管理权限",
 		Status:      1,
+		DataScope:   model.DataScopeAll,
 		CreatedBy:   1,
 	}
 	if err := db.Create(adminRole).Error; err != nil {
@@ -66,6 +66,40 @@ func main() {
 		{Name: "更新用户", Code: model.PermissionUserUpdate, Type: 3, Status: 1, CreatedBy: 1},
 		{Name: "删除用户", Code: model.PermissionUserDelete, Type: 3, Status: 1, CreatedBy: 1},
 		{Name: "重置密码", Code: model.PermissionUserResetPwd, Type: 3, Status: 1, CreatedBy: 1},
+		{Name: "强制下线", Code: model.PermissionUserForceLogout, Type: 3, Status: 1, CreatedBy: 1},
+		{Name: "解除锁定", Code: model.PermissionUserUnlock, Type: 3, Status: 1, CreatedBy: 1},
+		{Name: "重置两步验证", Code: model.PermissionUserReset2FA, Type: 3, Status: 1, CreatedBy: 1},
+		{Name: "创建服务账号", Code: model.PermissionServiceAccountCreate, Type: 3, Status: 1, CreatedBy: 1},
+		{Name: "API密钥列表", Code: model.PermissionApiKeyList, Type: 3, Status: 1, CreatedBy: 1},
+		{Name: "创建API密钥", Code: model.PermissionApiKeyCreate, Type: 3, Status: 1, CreatedBy: 1},
+		{Name: "吊销API密钥", Code: model.PermissionApiKeyRevoke, Type: 3, Status: 1, CreatedBy: 1},
+		{Name: "在线会话列表", Code: model.PermissionSessionList, Type: 3, Status: 1, CreatedBy: 1},
+		{Name: "终止会话", Code: model.PermissionSessionRevoke, Type: 3, Status: 1, CreatedBy: 1},
+		{Name: "查看敏感信息", Code: model.PermissionSensitiveView, Type: 3, Status: 1, CreatedBy: 1},
+		{Name: "轮换加密密钥", Code: model.PermissionEncryptionKeyRotate, Type: 3, Status: 1, CreatedBy: 1},
+		{Name: "操作日志列表", Code: model.PermissionOperationLogList, Type: 3, Status: 1, CreatedBy: 1},
+		{Name: "导出操作日志", Code: model.PermissionOperationLogExport, Type: 3, Status: 1, CreatedBy: 1},
+		{Name: "附件列表", Code: model.PermissionAttachmentList, Type: 3, Status: 1, CreatedBy: 1},
+		{Name: "上传附件", Code: model.PermissionAttachmentUpload, Type: 3, Status: 1, CreatedBy: 1},
+		{Name: "下载附件", Code: model.PermissionAttachmentDownload, Type: 3, Status: 1, CreatedBy: 1},
+		{Name: "关联附件", Code: model.PermissionAttachmentUpdate, Type: 3, Status: 1, CreatedBy: 1},
+		{Name: "删除附件", Code: model.PermissionAttachmentDelete, Type: 3, Status: 1, CreatedBy: 1},
+		{Name: "备份记录列表", Code: model.PermissionBackupList, Type: 3, Status: 1, CreatedBy: 1},
+		{Name: "创建备份", Code: model.PermissionBackupCreate, Type: 3, Status: 1, CreatedBy: 1},
+		{Name: "下载备份", Code: model.PermissionBackupDownload, Type: 3, Status: 1, CreatedBy: 1},
+		{Name: "恢复备份", Code: model.PermissionBackupRestore, Type: 3, Status: 1, CreatedBy: 1},
+		{Name: "删除备份", Code: model.PermissionBackupDelete, Type: 3, Status: 1, CreatedBy: 1},
+		{Name: "定时任务列表", Code: model.PermissionTaskList, Type: 3, Status: 1, CreatedBy: 1},
+		{Name: "创建定时任务", Code: model.PermissionTaskCreate, Type: 3, Status: 1, CreatedBy: 1},
+		{Name: "更新定时任务", Code: model.PermissionTaskUpdate, Type: 3, Status: 1, CreatedBy: 1},
+		{Name: "删除定时任务", Code: model.PermissionTaskDelete, Type: 3, Status: 1, CreatedBy: 1},
+		{Name: "运行定时任务", Code: model.PermissionTaskRun, Type: 3, Status: 1, CreatedBy: 1},
+
+		{Name: "更新系统配置", Code: model.PermissionConfigUpdate, Type: 3, Status: 1, CreatedBy: 1},
+		{Name: "创建功能模块", Code: model.PermissionModuleCreate, Type: 3, Status: 1, CreatedBy: 1},
+		{Name: "更新功能模块", Code: model.PermissionModuleUpdate, Type: 3, Status: 1, CreatedBy: 1},
+		{Name: "删除功能模块", Code: model.PermissionModuleDelete, Type: 3, Status: 1, CreatedBy: 1},
+		{Name: "更新模块配置", Code: model.PermissionModuleConfigUpdate, Type: 3, Status: 1, CreatedBy: 1},
 
 		{Name: "角色列表", Code: model.PermissionRoleList, Type: 3, Status: 1, CreatedBy: 1},
 		{Name: "创建角色", Code: model.PermissionRoleCreate, Type: 3, Status: 1, CreatedBy: 1},
@@ -86,6 +120,7 @@ func main() {
 		{Name: "删除考勤规则", Code: model.PermissionAttendanceRuleDelete, Type: 3, Status: 1, CreatedBy: 1},
 		{Name: "考勤记录列表", Code: model.PermissionAttendanceRecordList, Type: 3, Status: 1, CreatedBy: 1},
 		{Name: "创建考勤记录", Code: model.PermissionAttendanceRecordCreate, Type: 3, Status: 1, CreatedBy: 1},
+		{Name: "考勤打卡", Code: model.PermissionAttendanceCheckIn, Type: 3, Status: 1, CreatedBy: 1},
 		{Name: "请假列表", Code: model.PermissionLeaveList, Type: 3, Status: 1, CreatedBy: 1},
 		{Name: "创建请假", Code: model.PermissionLeaveCreate, Type: 3, Status: 1, CreatedBy: 1},
 		{Name: "审批请假", Code: model.PermissionLeaveApprove, Type: 3, Status: 1, CreatedBy: 1},
@@ -126,14 +161,18 @@ func main() {
 	}
 
 	// 5. 创建超级管理员用户
-	salt := generateSalt()
+	password, err := service.HashPassword("admin123")
+	if err != nil {
+		log.Fatalf("生成管理员密码失败: %v", err)
+	}
+	// 初始密码为弱密码，首次登录时必须修改
 	adminUser := &model.User{
-		Username:  "admin",
-		Password:  encryptPassword("admin123", salt),
-		Salt:      salt,
-		RealName:  "超级管理员",
-		Status:    1,
-		CreatedBy: 1,
+		Username:           "admin",
+		Password:           password,
+		RealName:           "超级管理员",
+		Status:             1,
+		MustChangePassword: true,
+		CreatedBy:          1,
 	}
 	if err := db.Create(adminUser).Error; err != nil {
 		log.Fatalf("创建超级管理员用户失败: %v", err)
@@ -149,23 +188,21 @@ func main() {
 		log.Fatalf("分配超级管理员角色失败: %v", err)
 	}
 
-	fmt.Println("初始化完成!")
-	fmt.Println("超级管理员账号: admin")
-	fmt.Println("初始密码: admin123")
-}
-
-// 生成32位随机盐值
-func generateSalt() string {
-	bytes := make([]byte, 16)
-	if _, err := rand.Read(bytes); err != nil {
-		return time.Now().Format("20060102150405")
+	// 7. 初始化密码策略
+	passwordPolicies := []model.SystemConfig{
+		{Key: service.PasswordPolicyMinLength, Value: "8", Desc: "密码最小长度"},
+		{Key: service.PasswordPolicyMinClasses, Value: "3", Desc: "密码至少包含的字符种类数(大写字母、小写字母、数字、特殊字符)"},
+		{Key: service.PasswordPolicyDictionaryCheck, Value: "true", Desc: "禁止使用常见弱密码及包含用户名的密码"},
+		{Key: service.PasswordPolicyHistoryCount, Value: "5", Desc: "新密码不能与最近几次使用过的密码相同"},
+		{Key: service.PasswordPolicyMaxAgeDays, Value: "90", Desc: "密码有效天数，过期后登录时必须修改，0表示永不过期"},
+	}
+	for _, config := range passwordPolicies {
+		if err := db.Where(model.SystemConfig{Key: config.Key}).FirstOrCreate(&config).Error; err != nil {
+			log.Fatalf("初始化密码策略失败: %v", err)
+		}
 	}
-	return hex.EncodeToString(bytes)
-}
 
-// 加密密码
-func encryptPassword(password, salt string) string {
-	hash := sha512.New()
-	hash.Write([]byte(password + salt))
-	return hex.EncodeToString(hash.Sum(nil))
+	fmt.Println("初始化完成!")
+	fmt.Println("超级管理员账号: admin")
+	fmt.Println("初始密码: admin123 (首次登录时需修改)")
 }
@@ -1,6 +1,7 @@
 package service
 
 import (
+	"context"
 	"errors"
 
 	"github.com/lemonoa/LemonOA-Go/model"
@@ -16,6 +17,11 @@ func NewAddressBookService(db *gorm.DB) *AddressBookService {
 	return &AddressBookService{db: db}
 }
 
+// WithContext 返回使用请求上下文的服务，写入数据时由数据库回调填充当前操作用户
+func (s *AddressBookService) WithContext(ctx context.Context) *AddressBookService {
+	return &AddressBookService{db: s.db.WithContext(ctx)}
+}
+
 // GetEmployeeList 获取员工列表
 func (s *AddressBookService) GetEmployeeList(departmentID uint, page, pageSize int) ([]model.Employee, int64, error) {
 	var employees []model.Employee
@@ -56,12 +62,25 @@ func (s *AddressBookService) UpdateEmployee(employee *model.Employee) error {
 	if employee.ID == 0 {
 		return errors.New("employee id is required")
 	}
-	return s.db.Model(employee).Updates(employee).Error
+	ClearMaskedSensitive(employee)
+	if err := s.db.Model(employee).Updates(employee).Error; err != nil {
+		return err
+	}
+	return invalidateEmployeePermissions(s.db, employee.ID)
 }
 
 // DeleteEmployee 删除员工
 func (s *AddressBookService) DeleteEmployee(id uint) error {
-	return s.db.Delete(&model.Employee{}, id).Error
+	if err := invalidateEmployeePermissions(s.db, id); err != nil {
+		return err
+	}
+	return s.db.Transaction(func(tx *gorm.DB) error {
+		// 解除关联的登录账号
+		if err := tx.Model(&model.User{}).Where("employee_id = ?", id).Update("employee_id", nil).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&model.Employee{}, id).Error
+	})
 }
 
 // CreateDepartment 创建部门
@@ -75,7 +94,12 @@ func (s *AddressBookService) CreateDepartment(department *model.Department) erro
 	} else {
 		department.Level = 1
 	}
-	return s.db.Create(department).Error
+	if err := s.db.Create(department).Error; err != nil {
+		return err
+	}
+	// 部门层级变化影响按本部门及下级计算的数据权限
+	permissionCache.InvalidateAll()
+	return nil
 }
 
 // UpdateDepartment 更新部门信息
@@ -83,7 +107,11 @@ func (s *AddressBookService) UpdateDepartment(department *model.Department) erro
 	if department.ID == 0 {
 		return errors.New("department id is required")
 	}
-	return s.db.Model(department).Updates(department).Error
+	if err := s.db.Model(department).Updates(department).Error; err != nil {
+		return err
+	}
+	permissionCache.InvalidateAll()
+	return nil
 }
 
 // DeleteDepartment 删除部门
@@ -105,5 +133,9 @@ func (s *AddressBookService) DeleteDepartment(id uint) error {
 		return errors.New("cannot delete department with employees")
 	}
 
-	return s.db.Delete(&model.Department{}, id).Error
+	if err := s.db.Delete(&model.Department{}, id).Error; err != nil {
+		return err
+	}
+	permissionCache.InvalidateAll()
+	return nil
 }
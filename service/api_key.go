@@ -0,0 +1,207 @@
+package service
+
+import (
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/lemonoa/LemonOA-Go/model"
+
+	"gorm.io/gorm"
+)
+
+// API密钥格式为 "lemon_" + 8位前缀 + "_" + 随机串，前缀明文保存用于识别，整串只保存哈希
+const apiKeyPrefix = "lemon_"
+
+// 最后使用时间的更新间隔，避免每次请求都写库
+const apiKeyTouchInterval = time.Minute
+
+var (
+	ErrInvalidApiKey = errors.New("无效的API密钥")
+	ErrApiKeyExpired = errors.New("API密钥已过期")
+)
+
+// ApiKeyCreated 新建的API密钥，明文密钥只在创建时返回一次
+type ApiKeyCreated struct {
+	*model.ApiKey
+	Key string `json:"key"` // 明文密钥
+}
+
+// CreateServiceAccount 创建服务账号，服务账号没有可用的登录密码
+func (s *AuthService) CreateServiceAccount(user *model.User) error {
+	password, err := randomHex(32)
+	if err != nil {
+		return err
+	}
+	policy, err := GetPasswordPolicy(s.db)
+	if err != nil {
+		return err
+	}
+
+	user.Type = model.UserTypeService
+	user.Password = password
+	user.TOTPEnabled = false
+	return s.createUser(policy, user)
+}
+
+// GetApiKeyList 获取用户的API密钥列表
+func (s *AuthService) GetApiKeyList(userID uint) ([]model.ApiKey, error) {
+	var keys []model.ApiKey
+	err := s.db.Where("user_id = ?", userID).Order("id DESC").Find(&keys).Error
+	return keys, err
+}
+
+// CreateApiKey 为服务账号创建API密钥
+// scopes 限定密钥可使用的权限，只能是服务账号已拥有权限的子集，为空时可使用服务账号的全部权限；
+// 操作人不能签发超出自身权限的密钥
+func (s *AuthService) CreateApiKey(userID uint, name string, scopes []string, expiresAt *time.Time, operatorID uint) (*ApiKeyCreated, error) {
+	var user model.User
+	if err := s.db.First(&user, userID).Error; err != nil {
+		return nil, err
+	}
+	if user.Type != model.UserTypeService {
+		return nil, errors.New("api keys can only be created for service accounts")
+	}
+	if expiresAt != nil && !expiresAt.After(time.Now()) {
+		return nil, errors.New("expires_at must be in the future")
+	}
+
+	perms, err := GetUserEffectivePermissions(s.db, userID)
+	if err != nil {
+		return nil, err
+	}
+	for _, code := range scopes {
+		if !perms.Has(code) {
+			return nil, errors.New("scope " + code + " is not granted to the service account")
+		}
+	}
+
+	operator, err := GetUserEffectivePermissions(s.db, operatorID)
+	if err != nil {
+		return nil, err
+	}
+	if !operator.SuperAdmin {
+		granted := scopes
+		if len(granted) == 0 {
+			if perms.SuperAdmin {
+				return nil, errors.New("only super admins can create keys for a super admin account")
+			}
+			granted = perms.CodeList
+		}
+		for _, code := range granted {
+			if !operator.Has(code) {
+				return nil, errors.New("scope " + code + " is not granted to you")
+			}
+		}
+	}
+
+	prefix, err := randomHex(4)
+	if err != nil {
+		return nil, err
+	}
+	secret, err := randomHex(24)
+	if err != nil {
+		return nil, err
+	}
+	plain := apiKeyPrefix + prefix + "_" + secret
+
+	key := &model.ApiKey{
+		UserID:    userID,
+		Name:      name,
+		Prefix:    apiKeyPrefix + prefix,
+		KeyHash:   hashToken(plain),
+		Scopes:    strings.Join(scopes, ","),
+		ExpiresAt: expiresAt,
+		CreatedBy: operatorID,
+	}
+	if err := s.db.Create(key).Error; err != nil {
+		return nil, err
+	}
+
+	return &ApiKeyCreated{ApiKey: key, Key: plain}, nil
+}
+
+// RevokeApiKey 吊销API密钥，立即生效
+func (s *AuthService) RevokeApiKey(id uint) error {
+	now := time.Now()
+	result := s.db.Model(&model.ApiKey{}).
+		Where("id = ? AND revoked_at IS NULL", id).
+		Update("revoked_at", &now)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+// AuthenticateApiKey 校验API密钥，返回密钥记录，所属用户需为启用状态的服务账号
+func AuthenticateApiKey(db *gorm.DB, plain, ip string) (*model.ApiKey, error) {
+	if !strings.HasPrefix(plain, apiKeyPrefix) {
+		return nil, ErrInvalidApiKey
+	}
+
+	var key model.ApiKey
+	if err := db.Where("key_hash = ?", hashToken(plain)).First(&key).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, ErrInvalidApiKey
+		}
+		return nil, err
+	}
+	if key.RevokedAt != nil {
+		return nil, ErrInvalidApiKey
+	}
+	now := time.Now()
+	if key.ExpiresAt != nil && now.After(*key.ExpiresAt) {
+		return nil, ErrApiKeyExpired
+	}
+
+	var user model.User
+	if err := db.Select("id", "type", "status").First(&user, key.UserID).Error; err != nil {
+		return nil, ErrInvalidApiKey
+	}
+	if user.Type != model.UserTypeService || user.Status != 1 {
+		return nil, ErrInvalidApiKey
+	}
+
+	if key.LastUsedAt == nil || now.Sub(*key.LastUsedAt) >= apiKeyTouchInterval || key.LastUsedIP != ip {
+		db.Model(&key).UpdateColumns(map[string]interface{}{
+			"last_used_at": &now,
+			"last_used_ip": ip,
+		})
+	}
+
+	return &key, nil
+}
+
+// ApiKeyScopes 密钥允许使用的权限编码，为空表示不额外限制
+func ApiKeyScopes(key *model.ApiKey) []string {
+	if key.Scopes == "" {
+		return nil
+	}
+	var codes []string
+	for _, code := range strings.Split(key.Scopes, ",") {
+		if code = strings.TrimSpace(code); code != "" {
+			codes = append(codes, code)
+		}
+	}
+	return codes
+}
+
+// Restrict 返回限定在指定权限编码内的权限集合，用于API密钥的权限范围
+// 限定后即使是超级管理员也只保留指定的权限
+func (p *UserPermissions) Restrict(codes []string) *UserPermissions {
+	if codes == nil {
+		return p
+	}
+
+	restricted := &UserPermissions{HasRole: p.HasRole, Codes: map[string]struct{}{}}
+	for _, code := range codes {
+		if p.Has(code) {
+			restricted.Codes[code] = struct{}{}
+			restricted.CodeList = append(restricted.CodeList, code)
+		}
+	}
+	return restricted
+}
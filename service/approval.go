@@ -1,8 +1,10 @@
 package service
 
 import (
+	"context"
 	"errors"
 
+	"github.com/lemonoa/LemonOA-Go/metrics"
 	"github.com/lemonoa/LemonOA-Go/model"
 
 	"gorm.io/gorm"
@@ -16,6 +18,11 @@ func NewApprovalService(db *gorm.DB) *ApprovalService {
 	return &ApprovalService{db: db}
 }
 
+// WithContext 返回使用请求上下文的服务，写入数据时由数据库回调填充当前操作用户
+func (s *ApprovalService) WithContext(ctx context.Context) *ApprovalService {
+	return &ApprovalService{db: s.db.WithContext(ctx)}
+}
+
 // GetApprovalTypeList 获取审批类型列表
 func (s *ApprovalService) GetApprovalTypeList() ([]model.ApprovalType, error) {
 	var types []model.ApprovalType
@@ -114,11 +121,12 @@ func (s *ApprovalService) DeleteApprovalNode(id uint) error {
 }
 
 // GetApprovalRecordList 获取审批记录列表
-func (s *ApprovalService) GetApprovalRecordList(userID uint, status int, page, pageSize int) ([]model.ApprovalRecord, int64, error) {
+func (s *ApprovalService) GetApprovalRecordList(userID uint, status int, page, pageSize int, scope *DataScope) ([]model.ApprovalRecord, int64, error) {
 	var records []model.ApprovalRecord
 	var total int64
 
-	query := s.db.Model(&model.ApprovalRecord{})
+	query := s.db.Model(&model.ApprovalRecord{}).
+		Scopes(scope.Filter(approvalRecordScope))
 	if userID > 0 {
 		query = query.Where("applicant_id = ?", userID)
 	}
@@ -151,7 +159,7 @@ func (s *ApprovalService) CreateApprovalRecord(record *model.ApprovalRecord) err
 	record.CurrentNodeID = firstNode.ID
 	record.Status = 2 // 设置为审批中状态
 
-	return s.db.Transaction(func(tx *gorm.DB) error {
+	err = s.db.Transaction(func(tx *gorm.DB) error {
 		// 创建审批记录
 		if err := tx.Create(record).Error; err != nil {
 			return err
@@ -166,11 +174,17 @@ func (s *ApprovalService) CreateApprovalRecord(record *model.ApprovalRecord) err
 		}
 		return tx.Create(nodeRecord).Error
 	})
+	if err != nil {
+		return err
+	}
+	metrics.ApprovalsStarted.Inc()
+	return nil
 }
 
 // ApproveRecord 审批通过
 func (s *ApprovalService) ApproveRecord(recordID, nodeID, approverID uint, comment string) error {
-	return s.db.Transaction(func(tx *gorm.DB) error {
+	finished := false
+	err := s.db.Transaction(func(tx *gorm.DB) error {
 		// 更新当前节点记录
 		nodeRecord := &model.ApprovalNodeRecord{}
 		err := tx.Where("approval_record_id = ? AND approval_node_id = ? AND approver_id = ?", recordID, nodeID, approverID).First(nodeRecord).Error
@@ -193,6 +207,7 @@ func (s *ApprovalService) ApproveRecord(recordID, nodeID, approverID uint, comme
 		err = tx.Where("approval_flow_id = ? AND sort > ?", currentNode.ApprovalFlowID, currentNode.Sort).Order("sort asc").First(&nextNode).Error
 		if err == gorm.ErrRecordNotFound {
 			// 没有下一个节点，审批流程结束
+			finished = true
 			return tx.Model(&model.ApprovalRecord{}).Where("id = ?", recordID).Update("status", 3).Error
 		}
 		if err != nil {
@@ -213,11 +228,18 @@ func (s *ApprovalService) ApproveRecord(recordID, nodeID, approverID uint, comme
 		// 更新审批记录的当前节点
 		return tx.Model(&model.ApprovalRecord{}).Where("id = ?", recordID).Update("current_node_id", nextNode.ID).Error
 	})
+	if err != nil {
+		return err
+	}
+	if finished {
+		metrics.ApprovalsFinished.WithLabelValues("approved").Inc()
+	}
+	return nil
 }
 
 // RejectRecord 审批驳回
 func (s *ApprovalService) RejectRecord(recordID, nodeID, approverID uint, comment string) error {
-	return s.db.Transaction(func(tx *gorm.DB) error {
+	err := s.db.Transaction(func(tx *gorm.DB) error {
 		// 更新当前节点记录
 		nodeRecord := &model.ApprovalNodeRecord{}
 		err := tx.Where("approval_record_id = ? AND approval_node_id = ? AND approver_id = ?", recordID, nodeID, approverID).First(nodeRecord).Error
@@ -234,6 +256,11 @@ func (s *ApprovalService) RejectRecord(recordID, nodeID, approverID uint, commen
 		// 更新审批记录状态为已驳回
 		return tx.Model(&model.ApprovalRecord{}).Where("id = ?", recordID).Update("status", 4).Error
 	})
+	if err != nil {
+		return err
+	}
+	metrics.ApprovalsFinished.WithLabelValues("rejected").Inc()
+	return nil
 }
 
 // GetPendingApprovalList 获取待审批列表
@@ -1,6 +1,7 @@
 package service
 
 import (
+	"context"
 	"errors"
 	"time"
 
@@ -17,12 +18,18 @@ func NewAssetService(db *gorm.DB) *AssetService {
 	return &AssetService{db: db}
 }
 
+// WithContext 返回使用请求上下文的服务，写入数据时由数据库回调填充当前操作用户
+func (s *AssetService) WithContext(ctx context.Context) *AssetService {
+	return &AssetService{db: s.db.WithContext(ctx)}
+}
+
 // GetAssetList 获取资产列表
-func (s *AssetService) GetAssetList(categoryID, brandID uint, status int, keyword string, page, pageSize int) ([]model.Asset, int64, error) {
+func (s *AssetService) GetAssetList(categoryID, brandID uint, status int, keyword string, page, pageSize int, scope *DataScope) ([]model.Asset, int64, error) {
 	var assets []model.Asset
 	var total int64
 
-	query := s.db.Model(&model.Asset{})
+	query := s.db.Model(&model.Asset{}).
+		Scopes(scope.Filter(assetScope))
 	if categoryID > 0 {
 		query = query.Where("category_id = ?", categoryID)
 	}
@@ -50,9 +57,9 @@ func (s *AssetService) GetAssetList(categoryID, brandID uint, status int, keywor
 }
 
 // GetAssetByID 根据ID获取资产
-func (s *AssetService) GetAssetByID(id uint) (*model.Asset, error) {
+func (s *AssetService) GetAssetByID(id uint, scope *DataScope) (*model.Asset, error) {
 	var asset model.Asset
-	err := s.db.First(&asset, id).Error
+	err := s.db.Scopes(scope.Filter(assetScope)).First(&asset, id).Error
 	if err != nil {
 		return nil, err
 	}
@@ -156,11 +163,12 @@ func (s *AssetService) DeleteAsset(id uint) error {
 }
 
 // GetAssetRepairList 获取资产维修记录列表
-func (s *AssetService) GetAssetRepairList(assetID uint, status int, page, pageSize int) ([]model.AssetRepair, int64, error) {
+func (s *AssetService) GetAssetRepairList(assetID uint, status int, page, pageSize int, scope *DataScope) ([]model.AssetRepair, int64, error) {
 	var repairs []model.AssetRepair
 	var total int64
 
-	query := s.db.Model(&model.AssetRepair{})
+	query := s.db.Model(&model.AssetRepair{}).
+		Scopes(scope.Filter(assetRepairScope))
 	if assetID > 0 {
 		query = query.Where("asset_id = ?", assetID)
 	}
@@ -182,9 +190,9 @@ func (s *AssetService) GetAssetRepairList(assetID uint, status int, page, pageSi
 }
 
 // GetAssetRepairByID 根据ID获取资产维修记录
-func (s *AssetService) GetAssetRepairByID(id uint) (*model.AssetRepair, error) {
+func (s *AssetService) GetAssetRepairByID(id uint, scope *DataScope) (*model.AssetRepair, error) {
 	var repair model.AssetRepair
-	err := s.db.First(&repair, id).Error
+	err := s.db.Scopes(scope.Filter(assetRepairScope)).First(&repair, id).Error
 	if err != nil {
 		return nil, err
 	}
@@ -258,11 +266,12 @@ func (s *AssetService) CompleteAssetRepair(id uint) error {
 }
 
 // GetAssetBorrowList 获取资产领用记录列表
-func (s *AssetService) GetAssetBorrowList(assetID, borrowerID uint, status int, page, pageSize int) ([]model.AssetBorrow, int64, error) {
+func (s *AssetService) GetAssetBorrowList(assetID, borrowerID uint, status int, page, pageSize int, scope *DataScope) ([]model.AssetBorrow, int64, error) {
 	var borrows []model.AssetBorrow
 	var total int64
 
-	query := s.db.Model(&model.AssetBorrow{})
+	query := s.db.Model(&model.AssetBorrow{}).
+		Scopes(scope.Filter(assetBorrowScope))
 	if assetID > 0 {
 		query = query.Where("asset_id = ?", assetID)
 	}
@@ -287,9 +296,9 @@ func (s *AssetService) GetAssetBorrowList(assetID, borrowerID uint, status int,
 }
 
 // GetAssetBorrowByID 根据ID获取资产领用记录
-func (s *AssetService) GetAssetBorrowByID(id uint) (*model.AssetBorrow, error) {
+func (s *AssetService) GetAssetBorrowByID(id uint, scope *DataScope) (*model.AssetBorrow, error) {
 	var borrow model.AssetBorrow
-	err := s.db.First(&borrow, id).Error
+	err := s.db.Scopes(scope.Filter(assetBorrowScope)).First(&borrow, id).Error
 	if err != nil {
 		return nil, err
 	}
@@ -372,11 +381,12 @@ func (s *AssetService) ReturnAsset(id uint) error {
 }
 
 // GetAssetDisposalList 获取资产报废记录列表
-func (s *AssetService) GetAssetDisposalList(assetID uint, status int, page, pageSize int) ([]model.AssetDisposal, int64, error) {
+func (s *AssetService) GetAssetDisposalList(assetID uint, status int, page, pageSize int, scope *DataScope) ([]model.AssetDisposal, int64, error) {
 	var disposals []model.AssetDisposal
 	var total int64
 
-	query := s.db.Model(&model.AssetDisposal{})
+	query := s.db.Model(&model.AssetDisposal{}).
+		Scopes(scope.Filter(assetDisposalScope))
 	if assetID > 0 {
 		query = query.Where("asset_id = ?", assetID)
 	}
@@ -398,9 +408,9 @@ func (s *AssetService) GetAssetDisposalList(assetID uint, status int, page, page
 }
 
 // GetAssetDisposalByID 根据ID获取资产报废记录
-func (s *AssetService) GetAssetDisposalByID(id uint) (*model.AssetDisposal, error) {
+func (s *AssetService) GetAssetDisposalByID(id uint, scope *DataScope) (*model.AssetDisposal, error) {
 	var disposal model.AssetDisposal
-	err := s.db.First(&disposal, id).Error
+	err := s.db.Scopes(scope.Filter(assetDisposalScope)).First(&disposal, id).Error
 	if err != nil {
 		return nil, err
 	}
@@ -0,0 +1,496 @@
+package service
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/lemonoa/LemonOA-Go/logging"
+	"github.com/lemonoa/LemonOA-Go/model"
+
+	"github.com/spf13/viper"
+	"gorm.io/gorm"
+)
+
+// 分片上传任务的有效期，过期后未完成的分片会被清理
+const attachmentUploadExpire = 24 * time.Hour
+
+var (
+	ErrAttachmentNotFound      = errors.New("attachment not found")
+	ErrAttachmentTooLarge      = errors.New("file size exceeds the limit")
+	ErrAttachmentSizeInvalid   = errors.New("invalid file size")
+	ErrAttachmentTypeForbidden = errors.New("file type is not allowed")
+	ErrAttachmentUploadExpired = errors.New("upload not found or expired")
+	ErrAttachmentIncomplete    = errors.New("upload is incomplete")
+	ErrAttachmentChunkInvalid  = errors.New("invalid chunk")
+)
+
+// 未配置 upload.allowed_exts 时允许上传的扩展名
+var defaultAllowedExts = []string{
+	".jpg", ".jpeg", ".png", ".gif", ".bmp", ".webp",
+	".pdf", ".doc", ".docx", ".xls", ".xlsx", ".ppt", ".pptx", ".wps", ".txt", ".csv",
+	".zip", ".rar", ".7z", ".mp3", ".mp4",
+}
+
+// AttachmentOwner 附件所属的业务记录
+type AttachmentOwner struct {
+	Module      string `json:"module" form:"module"`
+	RelatedType string `json:"related_type" form:"related_type"`
+	RelatedID   uint   `json:"related_id" form:"related_id"`
+}
+
+// AttachmentUploadStatus 分片上传进度，Received 为已上传的分片序号
+type AttachmentUploadStatus struct {
+	*model.AttachmentUpload
+	Received []int `json:"received"`
+}
+
+// UploadMaxSize 单个文件大小上限，upload.max_size 单位为MB
+func UploadMaxSize() int64 {
+	if size := viper.GetInt64("upload.max_size"); size > 0 {
+		return size << 20
+	}
+	return 50 << 20
+}
+
+// 分片大小，upload.chunk_size 单位为MB
+func uploadChunkSize() int64 {
+	if size := viper.GetInt64("upload.chunk_size"); size > 0 {
+		return size << 20
+	}
+	return 5 << 20
+}
+
+func checkFileExt(name string) (string, error) {
+	ext := strings.ToLower(filepath.Ext(name))
+	allowed := viper.GetStringSlice("upload.allowed_exts")
+	if len(allowed) == 0 {
+		allowed = defaultAllowedExts
+	}
+	for _, item := range allowed {
+		if ext != "" && strings.EqualFold(item, ext) {
+			return ext, nil
+		}
+	}
+	return "", ErrAttachmentTypeForbidden
+}
+
+// 按文件内容识别的类型与扩展名不符时拒绝，防止以图片等扩展名上传网页
+func checkFileContent(ext, contentType string) error {
+	if strings.HasPrefix(contentType, "text/html") || strings.HasPrefix(contentType, "text/xml") {
+		return ErrAttachmentTypeForbidden
+	}
+	switch ext {
+	case ".jpg", ".jpeg", ".png", ".gif", ".bmp", ".webp":
+		if !strings.HasPrefix(contentType, "image/") {
+			return ErrAttachmentTypeForbidden
+		}
+	case ".pdf":
+		if contentType != "application/pdf" {
+			return ErrAttachmentTypeForbidden
+		}
+	}
+	return nil
+}
+
+// 识别文件的MIME类型
+func sniffContentType(file *os.File) (string, error) {
+	head := make([]byte, 512)
+	n, err := file.ReadAt(head, 0)
+	if err != nil && err != io.EOF {
+		return "", err
+	}
+	return http.DetectContentType(head[:n]), nil
+}
+
+// 写入临时文件并计算哈希，超过大小上限时返回 ErrAttachmentTooLarge
+func spoolFile(src io.Reader) (*os.File, int64, string, error) {
+	dir := filepath.Join(uploadSavePath(), ".tmp")
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, 0, "", err
+	}
+	tmp, err := os.CreateTemp(dir, "upload-*")
+	if err != nil {
+		return nil, 0, "", err
+	}
+
+	limit := UploadMaxSize()
+	hash := sha256.New()
+	size, err := io.Copy(io.MultiWriter(tmp, hash), io.LimitReader(src, limit+1))
+	if err == nil && size > limit {
+		err = ErrAttachmentTooLarge
+	}
+	if err != nil {
+		removeSpoolFile(tmp)
+		return nil, 0, "", err
+	}
+	return tmp, size, hex.EncodeToString(hash.Sum(nil)), nil
+}
+
+func removeSpoolFile(file *os.File) {
+	file.Close()
+	os.Remove(file.Name())
+}
+
+// UploadAttachment 上传附件，内容相同的文件只保存一份
+func (s *SystemService) UploadAttachment(src io.Reader, name string, owner AttachmentOwner, userID uint) (*model.Attachment, error) {
+	if _, err := checkFileExt(name); err != nil {
+		return nil, err
+	}
+
+	tmp, size, hash, err := spoolFile(src)
+	if err != nil {
+		return nil, err
+	}
+	defer removeSpoolFile(tmp)
+
+	return s.storeAttachment(tmp, name, size, hash, owner, userID)
+}
+
+// 保存临时文件为附件：已有相同内容的文件时直接引用，否则写入存储
+func (s *SystemService) storeAttachment(tmp *os.File, name string, size int64, hash string, owner AttachmentOwner, userID uint) (*model.Attachment, error) {
+	ext, err := checkFileExt(name)
+	if err != nil {
+		return nil, err
+	}
+	contentType, err := sniffContentType(tmp)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkFileContent(ext, contentType); err != nil {
+		return nil, err
+	}
+
+	attachment := model.Attachment{
+		Name:        filepath.Base(name),
+		Storage:     fileStorage.Name(),
+		Hash:        hash,
+		Size:        size,
+		Type:        contentType,
+		UploadedBy:  userID,
+		Module:      owner.Module,
+		RelatedType: owner.RelatedType,
+		RelatedID:   owner.RelatedID,
+	}
+
+	var existing model.Attachment
+	err = s.db.Where("hash = ? AND size = ? AND storage = ?", hash, size, attachment.Storage).First(&existing).Error
+	switch {
+	case err == nil:
+		attachment.Path = existing.Path
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		attachment.Path = fmt.Sprintf("%s/%s/%s%s", hash[:2], hash[2:4], hash, ext)
+		if _, err := tmp.Seek(0, io.SeekStart); err != nil {
+			return nil, err
+		}
+		if err := fileStorage.Put(s.db.Statement.Context, attachment.Path, tmp, size, contentType); err != nil {
+			return nil, err
+		}
+	default:
+		return nil, err
+	}
+
+	if err := s.db.Create(&attachment).Error; err != nil {
+		return nil, err
+	}
+	return &attachment, nil
+}
+
+// CreateAttachmentUpload 创建分片上传任务，前端按返回的分片大小切分文件
+func (s *SystemService) CreateAttachmentUpload(name string, size int64, userID uint) (*model.AttachmentUpload, error) {
+	if _, err := checkFileExt(name); err != nil {
+		return nil, err
+	}
+	if size <= 0 {
+		return nil, ErrAttachmentSizeInvalid
+	}
+	if size > UploadMaxSize() {
+		return nil, ErrAttachmentTooLarge
+	}
+
+	uploadID, err := randomHex(16)
+	if err != nil {
+		return nil, err
+	}
+
+	// 顺带清理过期的上传任务
+	s.cleanExpiredUploads()
+
+	chunkSize := uploadChunkSize()
+	upload := model.AttachmentUpload{
+		UploadID:    uploadID,
+		Name:        filepath.Base(name),
+		Size:        size,
+		ChunkSize:   chunkSize,
+		TotalChunks: int((size + chunkSize - 1) / chunkSize),
+		UserID:      userID,
+		ExpiresAt:   time.Now().Add(attachmentUploadExpire),
+	}
+	if err := s.db.Create(&upload).Error; err != nil {
+		return nil, err
+	}
+	return &upload, nil
+}
+
+// GetAttachmentUpload 获取分片上传进度，用于断点续传
+func (s *SystemService) GetAttachmentUpload(uploadID string, userID uint) (*AttachmentUploadStatus, error) {
+	upload, err := s.findAttachmentUpload(uploadID, userID)
+	if err != nil {
+		return nil, err
+	}
+	received, err := receivedChunks(uploadID)
+	if err != nil {
+		return nil, err
+	}
+	return &AttachmentUploadStatus{AttachmentUpload: upload, Received: received}, nil
+}
+
+// SaveAttachmentChunk 保存一个分片，重复上传的分片会被覆盖；除最后一个分片外大小必须等于分片大小
+func (s *SystemService) SaveAttachmentChunk(uploadID string, userID uint, index int, src io.Reader) error {
+	upload, err := s.findAttachmentUpload(uploadID, userID)
+	if err != nil {
+		return err
+	}
+	if index < 0 || index >= upload.TotalChunks {
+		return ErrAttachmentChunkInvalid
+	}
+	expected := upload.ChunkSize
+	if index == upload.TotalChunks-1 {
+		expected = upload.Size - upload.ChunkSize*int64(upload.TotalChunks-1)
+	}
+
+	dir := chunkDir(uploadID)
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(dir, ".chunk-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	n, err := io.Copy(tmp, io.LimitReader(src, expected+1))
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return err
+	}
+	if n != expected {
+		return ErrAttachmentChunkInvalid
+	}
+	return os.Rename(tmp.Name(), filepath.Join(dir, strconv.Itoa(index)))
+}
+
+// CompleteAttachmentUpload 合并全部分片为附件并结束上传任务
+func (s *SystemService) CompleteAttachmentUpload(uploadID string, userID uint, owner AttachmentOwner) (*model.Attachment, error) {
+	upload, err := s.findAttachmentUpload(uploadID, userID)
+	if err != nil {
+		return nil, err
+	}
+	received, err := receivedChunks(uploadID)
+	if err != nil {
+		return nil, err
+	}
+	if len(received) != upload.TotalChunks {
+		return nil, ErrAttachmentIncomplete
+	}
+
+	readers := make([]io.Reader, 0, upload.TotalChunks)
+	for i := 0; i < upload.TotalChunks; i++ {
+		chunk, err := os.Open(filepath.Join(chunkDir(uploadID), strconv.Itoa(i)))
+		if err != nil {
+			return nil, err
+		}
+		defer chunk.Close()
+		readers = append(readers, chunk)
+	}
+
+	tmp, size, hash, err := spoolFile(io.MultiReader(readers...))
+	if err != nil {
+		return nil, err
+	}
+	defer removeSpoolFile(tmp)
+	if size != upload.Size {
+		return nil, ErrAttachmentIncomplete
+	}
+
+	attachment, err := s.storeAttachment(tmp, upload.Name, size, hash, owner, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	s.removeAttachmentUpload(upload)
+	return attachment, nil
+}
+
+// CancelAttachmentUpload 取消分片上传，删除已上传的分片
+func (s *SystemService) CancelAttachmentUpload(uploadID string, userID uint) error {
+	upload, err := s.findAttachmentUpload(uploadID, userID)
+	if err != nil {
+		return err
+	}
+	s.removeAttachmentUpload(upload)
+	return nil
+}
+
+func (s *SystemService) findAttachmentUpload(uploadID string, userID uint) (*model.AttachmentUpload, error) {
+	var upload model.AttachmentUpload
+	err := s.db.Where("upload_id = ? AND user_id = ? AND expires_at > ?", uploadID, userID, time.Now()).First(&upload).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrAttachmentUploadExpired
+		}
+		return nil, err
+	}
+	return &upload, nil
+}
+
+func (s *SystemService) removeAttachmentUpload(upload *model.AttachmentUpload) {
+	if err := os.RemoveAll(chunkDir(upload.UploadID)); err != nil {
+		logging.FromContext(s.db.Statement.Context).ErrorContext(s.db.Statement.Context, "删除上传分片失败", "upload_id", upload.UploadID, "error", err)
+	}
+	s.db.Delete(upload)
+}
+
+func (s *SystemService) cleanExpiredUploads() {
+	var expired []model.AttachmentUpload
+	if err := s.db.Where("expires_at <= ?", time.Now()).Find(&expired).Error; err != nil {
+		return
+	}
+	for i := range expired {
+		s.removeAttachmentUpload(&expired[i])
+	}
+}
+
+// 分片暂存目录，使用S3存储时分片同样暂存在本地
+func chunkDir(uploadID string) string {
+	return filepath.Join(uploadSavePath(), ".chunks", uploadID)
+}
+
+func receivedChunks(uploadID string) ([]int, error) {
+	entries, err := os.ReadDir(chunkDir(uploadID))
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return []int{}, nil
+		}
+		return nil, err
+	}
+	received := make([]int, 0, len(entries))
+	for _, entry := range entries {
+		if index, err := strconv.Atoi(entry.Name()); err == nil {
+			received = append(received, index)
+		}
+	}
+	sort.Ints(received)
+	return received, nil
+}
+
+// OpenAttachment 打开数据权限范围内的附件内容用于下载，调用方负责关闭
+func (s *SystemService) OpenAttachment(id uint, scope *DataScope) (*model.Attachment, io.ReadSeekCloser, error) {
+	attachment, err := s.findAttachment(id, scope)
+	if err != nil {
+		return nil, nil, err
+	}
+	// 旧版只保存了元数据的附件没有文件内容
+	if attachment.Hash == "" {
+		return nil, nil, ErrStorageObjectNotFound
+	}
+	if attachment.Storage != fileStorage.Name() {
+		return nil, nil, fmt.Errorf("attachment is stored in %s storage", attachment.Storage)
+	}
+
+	file, err := fileStorage.Open(s.db.Statement.Context, attachment.Path)
+	if err != nil {
+		return nil, nil, err
+	}
+	return attachment, file, nil
+}
+
+// 查找附件，数据权限范围外的附件视为不存在
+func (s *SystemService) findAttachment(id uint, scope *DataScope) (*model.Attachment, error) {
+	var attachment model.Attachment
+	if err := s.db.Scopes(scope.Filter(attachmentScope)).First(&attachment, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrAttachmentNotFound
+		}
+		return nil, err
+	}
+	return &attachment, nil
+}
+
+// LinkAttachment 关联数据权限范围内的附件到业务记录，先上传附件后保存记录时使用
+func (s *SystemService) LinkAttachment(id uint, owner AttachmentOwner, scope *DataScope) error {
+	attachment, err := s.findAttachment(id, scope)
+	if err != nil {
+		return err
+	}
+	return s.db.Model(attachment).Updates(map[string]interface{}{
+		"module":       owner.Module,
+		"related_type": owner.RelatedType,
+		"related_id":   owner.RelatedID,
+	}).Error
+}
+
+// GetAttachmentList 获取数据权限范围内的附件列表
+func (s *SystemService) GetAttachmentList(module, relatedType string, relatedID uint, page, pageSize int, scope *DataScope) ([]model.Attachment, int64, error) {
+	var attachments []model.Attachment
+	var total int64
+
+	query := s.db.Model(&model.Attachment{}).Scopes(scope.Filter(attachmentScope))
+	if module != "" {
+		query = query.Where("module = ?", module)
+	}
+	if relatedType != "" {
+		query = query.Where("related_type = ?", relatedType)
+	}
+	if relatedID > 0 {
+		query = query.Where("related_id = ?", relatedID)
+	}
+
+	err := query.Count(&total).Error
+	if err != nil {
+		return nil, 0, err
+	}
+
+	err = query.Order("created_at desc").Offset((page - 1) * pageSize).Limit(pageSize).Find(&attachments).Error
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return attachments, total, nil
+}
+
+// DeleteAttachment 删除数据权限范围内的附件，没有其他附件引用同一文件时删除文件
+func (s *SystemService) DeleteAttachment(id uint, scope *DataScope) error {
+	attachment, err := s.findAttachment(id, scope)
+	if err != nil {
+		return err
+	}
+	if err := s.db.Delete(attachment).Error; err != nil {
+		return err
+	}
+	if attachment.Hash == "" || attachment.Storage != fileStorage.Name() {
+		return nil
+	}
+
+	var count int64
+	if err := s.db.Model(&model.Attachment{}).
+		Where("path = ? AND storage = ?", attachment.Path, attachment.Storage).
+		Count(&count).Error; err != nil {
+		return err
+	}
+	if count > 0 {
+		return nil
+	}
+	return fileStorage.Delete(s.db.Statement.Context, attachment.Path)
+}
@@ -0,0 +1,174 @@
+package service
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/lemonoa/LemonOA-Go/model"
+
+	"github.com/spf13/viper"
+)
+
+// 创建附件服务，上传目录和本地存储使用临时目录
+func newAttachmentTestService(t *testing.T) *SystemService {
+	t.Helper()
+	db := newTestDB(t)
+	viper.Set("upload.save_path", t.TempDir())
+	viper.Set("upload.chunk_size", 1)
+
+	original := fileStorage
+	if err := InitStorage(); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		fileStorage = original
+	})
+	return NewSystemService(db)
+}
+
+func TestSaveAttachmentChunk(t *testing.T) {
+	s := newAttachmentTestService(t)
+	// 1MB分片，共3个分片，最后一个分片为1KB
+	const size = 2<<20 + 1<<10
+	upload, err := s.CreateAttachmentUpload("report.txt", size, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if upload.TotalChunks != 3 {
+		t.Fatalf("TotalChunks = %d, want 3", upload.TotalChunks)
+	}
+
+	tests := []struct {
+		name    string
+		userID  uint
+		index   int
+		size    int
+		wantErr error
+	}{
+		{name: "first chunk", userID: 1, index: 0, size: 1 << 20},
+		{name: "last chunk", userID: 1, index: 2, size: 1 << 10},
+		{name: "negative index", userID: 1, index: -1, size: 1 << 20, wantErr: ErrAttachmentChunkInvalid},
+		{name: "index out of range", userID: 1, index: 3, size: 1 << 10, wantErr: ErrAttachmentChunkInvalid},
+		{name: "chunk too small", userID: 1, index: 1, size: 1<<20 - 1, wantErr: ErrAttachmentChunkInvalid},
+		{name: "chunk too large", userID: 1, index: 1, size: 1<<20 + 1, wantErr: ErrAttachmentChunkInvalid},
+		{name: "last chunk wrong size", userID: 1, index: 2, size: 1 << 20, wantErr: ErrAttachmentChunkInvalid},
+		{name: "other user", userID: 2, index: 1, size: 1 << 20, wantErr: ErrAttachmentUploadExpired},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := s.SaveAttachmentChunk(upload.UploadID, tt.userID, tt.index, bytes.NewReader(make([]byte, tt.size)))
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("SaveAttachmentChunk() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+
+	status, err := s.GetAttachmentUpload(upload.UploadID, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(status.Received) != 2 || status.Received[0] != 0 || status.Received[1] != 2 {
+		t.Errorf("Received = %v, want [0 2]", status.Received)
+	}
+	if _, err := s.CompleteAttachmentUpload(upload.UploadID, 1, AttachmentOwner{}); !errors.Is(err, ErrAttachmentIncomplete) {
+		t.Errorf("CompleteAttachmentUpload() error = %v, want %v", err, ErrAttachmentIncomplete)
+	}
+}
+
+func TestCreateAttachmentUploadValidation(t *testing.T) {
+	s := newAttachmentTestService(t)
+	viper.Set("upload.max_size", 1)
+
+	tests := []struct {
+		name    string
+		file    string
+		size    int64
+		wantErr error
+	}{
+		{name: "empty file", file: "a.txt", size: 0, wantErr: ErrAttachmentSizeInvalid},
+		{name: "too large", file: "a.txt", size: 1<<20 + 1, wantErr: ErrAttachmentTooLarge},
+		{name: "forbidden type", file: "a.html", size: 10, wantErr: ErrAttachmentTypeForbidden},
+		{name: "no extension", file: "README", size: 10, wantErr: ErrAttachmentTypeForbidden},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := s.CreateAttachmentUpload(tt.file, tt.size, 1); !errors.Is(err, tt.wantErr) {
+				t.Fatalf("CreateAttachmentUpload() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestUploadAttachmentRejectsDisguisedHTML(t *testing.T) {
+	s := newAttachmentTestService(t)
+
+	_, err := s.UploadAttachment(strings.NewReader("<!DOCTYPE html><script>alert(1)</script>"), "photo.png", AttachmentOwner{}, 1)
+	if !errors.Is(err, ErrAttachmentTypeForbidden) {
+		t.Fatalf("UploadAttachment() error = %v, want %v", err, ErrAttachmentTypeForbidden)
+	}
+}
+
+func TestDeleteAttachmentReferenceCounting(t *testing.T) {
+	s := newAttachmentTestService(t)
+	all := &DataScope{All: true}
+
+	first, err := s.UploadAttachment(strings.NewReader("same content"), "a.txt", AttachmentOwner{}, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	second, err := s.UploadAttachment(strings.NewReader("same content"), "b.txt", AttachmentOwner{}, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if first.Path != second.Path {
+		t.Fatalf("duplicate content stored twice: %q and %q", first.Path, second.Path)
+	}
+	path, err := fileStorage.(*LocalStorage).path(first.Path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// 仍有其他附件引用时保留文件
+	if err := s.DeleteAttachment(first.ID, all); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(path); err != nil {
+		t.Fatalf("shared file removed while still referenced: %v", err)
+	}
+	_, file, err := s.OpenAttachment(second.ID, all)
+	if err != nil {
+		t.Fatalf("OpenAttachment() error = %v", err)
+	}
+	file.Close()
+
+	// 最后一个引用删除后删除文件
+	if err := s.DeleteAttachment(second.ID, all); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Errorf("file still exists after the last reference was deleted: %v", err)
+	}
+}
+
+func TestAttachmentDataScope(t *testing.T) {
+	s := newAttachmentTestService(t)
+
+	attachment, err := s.UploadAttachment(strings.NewReader("private"), "a.txt", AttachmentOwner{}, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	other := &DataScope{UserID: 2}
+
+	if _, _, err := s.OpenAttachment(attachment.ID, other); !errors.Is(err, ErrAttachmentNotFound) {
+		t.Errorf("OpenAttachment() error = %v, want %v", err, ErrAttachmentNotFound)
+	}
+	if err := s.DeleteAttachment(attachment.ID, other); !errors.Is(err, ErrAttachmentNotFound) {
+		t.Errorf("DeleteAttachment() error = %v, want %v", err, ErrAttachmentNotFound)
+	}
+	if err := s.db.First(&model.Attachment{}, attachment.ID).Error; err != nil {
+		t.Errorf("attachment deleted outside the data scope: %v", err)
+	}
+}
@@ -1,9 +1,11 @@
 package service
 
 import (
+	"context"
 	"errors"
 	"time"
 
+	"github.com/lemonoa/LemonOA-Go/metrics"
 	"github.com/lemonoa/LemonOA-Go/model"
 
 	"gorm.io/gorm"
@@ -17,6 +19,11 @@ func NewAttendanceService(db *gorm.DB) *AttendanceService {
 	return &AttendanceService{db: db}
 }
 
+// WithContext 返回使用请求上下文的服务，写入数据时由数据库回调填充当前操作用户
+func (s *AttendanceService) WithContext(ctx context.Context) *AttendanceService {
+	return &AttendanceService{db: s.db.WithContext(ctx)}
+}
+
 // GetAttendanceRuleList 获取考勤规则列表
 func (s *AttendanceService) GetAttendanceRuleList(status int, keyword string, page, pageSize int) ([]model.AttendanceRule, int64, error) {
 	var rules []model.AttendanceRule
@@ -72,11 +79,12 @@ func (s *AttendanceService) DeleteAttendanceRule(id uint) error {
 }
 
 // GetAttendanceRecordList 获取考勤记录列表
-func (s *AttendanceService) GetAttendanceRecordList(employeeID uint, status int, startDate, endDate *time.Time, page, pageSize int) ([]model.AttendanceRecord, int64, error) {
+func (s *AttendanceService) GetAttendanceRecordList(employeeID uint, status int, startDate, endDate *time.Time, page, pageSize int, scope *DataScope) ([]model.AttendanceRecord, int64, error) {
 	var records []model.AttendanceRecord
 	var total int64
 
-	query := s.db.Model(&model.AttendanceRecord{})
+	query := s.db.Model(&model.AttendanceRecord{}).
+		Scopes(scope.Filter(attendanceRecordScope))
 	if employeeID > 0 {
 		query = query.Where("employee_id = ?", employeeID)
 	}
@@ -104,9 +112,9 @@ func (s *AttendanceService) GetAttendanceRecordList(employeeID uint, status int,
 }
 
 // GetAttendanceRecordByID 根据ID获取考勤记录
-func (s *AttendanceService) GetAttendanceRecordByID(id uint) (*model.AttendanceRecord, error) {
+func (s *AttendanceService) GetAttendanceRecordByID(id uint, scope *DataScope) (*model.AttendanceRecord, error) {
 	var record model.AttendanceRecord
-	err := s.db.First(&record, id).Error
+	err := s.db.Scopes(scope.Filter(attendanceRecordScope)).First(&record, id).Error
 	if err != nil {
 		return nil, err
 	}
@@ -118,6 +126,122 @@ func (s *AttendanceService) CreateAttendanceRecord(record *model.AttendanceRecor
 	return s.db.Create(record).Error
 }
 
+// CheckIn 员工签到，按当日生效的考勤规则判定是否迟到
+func (s *AttendanceService) CheckIn(employeeID uint, location string) (*model.AttendanceRecord, error) {
+	now := time.Now()
+	record, err := s.todayRecord(employeeID, now)
+	if err != nil {
+		return nil, err
+	}
+	if record.CheckInTime != nil {
+		return nil, errors.New("already checked in today")
+	}
+
+	record.CheckInTime = &now
+	record.Location = location
+	rule, err := s.effectiveRule(now)
+	if err != nil {
+		return nil, err
+	}
+	if rule != nil {
+		if start, ok := ruleClock(now, rule.WorkStartTime); ok {
+			if late := int(now.Sub(start).Minutes()); late > rule.LateMinutes {
+				record.Status = 2
+				record.LateMinutes = late
+			}
+		}
+	}
+
+	if err := s.db.Save(record).Error; err != nil {
+		return nil, err
+	}
+	metrics.AttendanceChecks.WithLabelValues("in").Inc()
+	return record, nil
+}
+
+// CheckOut 员工签退，按当日生效的考勤规则判定是否早退并计算工作时长
+func (s *AttendanceService) CheckOut(employeeID uint, location string) (*model.AttendanceRecord, error) {
+	now := time.Now()
+	record, err := s.todayRecord(employeeID, now)
+	if err != nil {
+		return nil, err
+	}
+	if record.CheckInTime == nil {
+		return nil, errors.New("not checked in today")
+	}
+	if record.CheckOutTime != nil {
+		return nil, errors.New("already checked out today")
+	}
+
+	record.CheckOutTime = &now
+	record.WorkHours = now.Sub(*record.CheckInTime).Hours()
+	if location != "" {
+		record.Location = location
+	}
+	rule, err := s.effectiveRule(now)
+	if err != nil {
+		return nil, err
+	}
+	if rule != nil {
+		if end, ok := ruleClock(now, rule.WorkEndTime); ok {
+			if early := int(end.Sub(now).Minutes()); early > rule.EarlyMinutes {
+				// 迟到且早退时保留迟到状态，早退分钟数照常记录
+				if record.Status == 1 {
+					record.Status = 3
+				}
+				record.EarlyMinutes = early
+			}
+		}
+	}
+
+	if err := s.db.Save(record).Error; err != nil {
+		return nil, err
+	}
+	metrics.AttendanceChecks.WithLabelValues("out").Inc()
+	return record, nil
+}
+
+// 获取员工当天的考勤记录，不存在时返回未保存的新记录
+func (s *AttendanceService) todayRecord(employeeID uint, now time.Time) (*model.AttendanceRecord, error) {
+	date := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+
+	var record model.AttendanceRecord
+	err := s.db.Where("employee_id = ? AND date = ?", employeeID, date).First(&record).Error
+	if err == gorm.ErrRecordNotFound {
+		return &model.AttendanceRecord{EmployeeID: employeeID, Date: &date, Status: 1}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &record, nil
+}
+
+// 获取指定时间生效的考勤规则，没有时返回nil
+func (s *AttendanceService) effectiveRule(now time.Time) (*model.AttendanceRule, error) {
+	var rule model.AttendanceRule
+	err := s.db.Where("status = ?", 1).
+		Where("effective_date IS NULL OR effective_date <= ?", now).
+		Where("expiration_date IS NULL OR expiration_date >= ?", now).
+		Order("effective_date desc").
+		First(&rule).Error
+	if err == gorm.ErrRecordNotFound {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &rule, nil
+}
+
+// 将规则中HH:mm格式的时间换算为当天的时刻
+func ruleClock(now time.Time, clock string) (time.Time, bool) {
+	t, err := time.Parse("15:04", clock)
+	if err != nil {
+		return time.Time{}, false
+	}
+	return time.Date(now.Year(), now.Month(), now.Day(), t.Hour(), t.Minute(), 0, 0, now.Location()), true
+}
+
 // UpdateAttendanceRecord 更新考勤记录
 func (s *AttendanceService) UpdateAttendanceRecord(record *model.AttendanceRecord) error {
 	if record.ID == 0 {
@@ -132,11 +256,12 @@ func (s *AttendanceService) DeleteAttendanceRecord(id uint) error {
 }
 
 // GetLeaveApplicationList 获取请假申请列表
-func (s *AttendanceService) GetLeaveApplicationList(employeeID uint, status int, startDate, endDate *time.Time, page, pageSize int) ([]model.LeaveApplication, int64, error) {
+func (s *AttendanceService) GetLeaveApplicationList(employeeID uint, status int, startDate, endDate *time.Time, page, pageSize int, scope *DataScope) ([]model.LeaveApplication, int64, error) {
 	var applications []model.LeaveApplication
 	var total int64
 
-	query := s.db.Model(&model.LeaveApplication{})
+	query := s.db.Model(&model.LeaveApplication{}).
+		Scopes(scope.Filter(leaveApplicationScope))
 	if employeeID > 0 {
 		query = query.Where("employee_id = ?", employeeID)
 	}
@@ -164,9 +289,9 @@ func (s *AttendanceService) GetLeaveApplicationList(employeeID uint, status int,
 }
 
 // GetLeaveApplicationByID 根据ID获取请假申请
-func (s *AttendanceService) GetLeaveApplicationByID(id uint) (*model.LeaveApplication, error) {
+func (s *AttendanceService) GetLeaveApplicationByID(id uint, scope *DataScope) (*model.LeaveApplication, error) {
 	var application model.LeaveApplication
-	err := s.db.First(&application, id).Error
+	err := s.db.Scopes(scope.Filter(leaveApplicationScope)).First(&application, id).Error
 	if err != nil {
 		return nil, err
 	}
@@ -182,7 +307,7 @@ func (s *AttendanceService) CreateLeaveApplication(application *model.LeaveAppli
 	return s.db.Create(application).Error
 }
 
-// UpdateLeaveApplication 更新请假申请
+// UpdateLeaveApplication 更新请假申请，只能修改本人待审批的申请
 func (s *AttendanceService) UpdateLeaveApplication(application *model.LeaveApplication) error {
 	if application.ID == 0 {
 		return errors.New("leave application id is required")
@@ -192,20 +317,38 @@ func (s *AttendanceService) UpdateLeaveApplication(application *model.LeaveAppli
 	days := application.EndTime.Sub(*application.StartTime).Hours() / 24
 	application.Days = float64(days)
 
-	return s.db.Model(application).Updates(application).Error
+	// 状态只能通过审批接口修改
+	application.Status = 0
+
+	result := s.db.Model(application).Where("employee_id = ? AND status = ?", application.EmployeeID, 1).Updates(application)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return errors.New("can only update your own pending leave applications")
+	}
+	return nil
 }
 
-// DeleteLeaveApplication 删除请假申请
-func (s *AttendanceService) DeleteLeaveApplication(id uint) error {
-	return s.db.Delete(&model.LeaveApplication{}, id).Error
+// DeleteLeaveApplication 删除请假申请，只能删除本人待审批的申请
+func (s *AttendanceService) DeleteLeaveApplication(id, employeeID uint) error {
+	result := s.db.Where("id = ? AND employee_id = ? AND status = ?", id, employeeID, 1).Delete(&model.LeaveApplication{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return errors.New("can only delete your own pending leave applications")
+	}
+	return nil
 }
 
 // GetOvertimeApplicationList 获取加班申请列表
-func (s *AttendanceService) GetOvertimeApplicationList(employeeID uint, status int, startDate, endDate *time.Time, page, pageSize int) ([]model.OvertimeApplication, int64, error) {
+func (s *AttendanceService) GetOvertimeApplicationList(employeeID uint, status int, startDate, endDate *time.Time, page, pageSize int, scope *DataScope) ([]model.OvertimeApplication, int64, error) {
 	var applications []model.OvertimeApplication
 	var total int64
 
-	query := s.db.Model(&model.OvertimeApplication{})
+	query := s.db.Model(&model.OvertimeApplication{}).
+		Scopes(scope.Filter(overtimeScope))
 	if employeeID > 0 {
 		query = query.Where("employee_id = ?", employeeID)
 	}
@@ -233,9 +376,9 @@ func (s *AttendanceService) GetOvertimeApplicationList(employeeID uint, status i
 }
 
 // GetOvertimeApplicationByID 根据ID获取加班申请
-func (s *AttendanceService) GetOvertimeApplicationByID(id uint) (*model.OvertimeApplication, error) {
+func (s *AttendanceService) GetOvertimeApplicationByID(id uint, scope *DataScope) (*model.OvertimeApplication, error) {
 	var application model.OvertimeApplication
-	err := s.db.First(&application, id).Error
+	err := s.db.Scopes(scope.Filter(overtimeScope)).First(&application, id).Error
 	if err != nil {
 		return nil, err
 	}
@@ -251,7 +394,7 @@ func (s *AttendanceService) CreateOvertimeApplication(application *model.Overtim
 	return s.db.Create(application).Error
 }
 
-// UpdateOvertimeApplication 更新加班申请
+// UpdateOvertimeApplication 更新加班申请，只能修改本人待审批的申请
 func (s *AttendanceService) UpdateOvertimeApplication(application *model.OvertimeApplication) error {
 	if application.ID == 0 {
 		return errors.New("overtime application id is required")
@@ -261,20 +404,38 @@ func (s *AttendanceService) UpdateOvertimeApplication(application *model.Overtim
 	hours := application.EndTime.Sub(*application.StartTime).Hours()
 	application.Hours = float64(hours)
 
-	return s.db.Model(application).Updates(application).Error
+	// 状态只能通过审批接口修改
+	application.Status = 0
+
+	result := s.db.Model(application).Where("employee_id = ? AND status = ?", application.EmployeeID, 1).Updates(application)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return errors.New("can only update your own pending overtime applications")
+	}
+	return nil
 }
 
-// DeleteOvertimeApplication 删除加班申请
-func (s *AttendanceService) DeleteOvertimeApplication(id uint) error {
-	return s.db.Delete(&model.OvertimeApplication{}, id).Error
+// DeleteOvertimeApplication 删除加班申请，只能删除本人待审批的申请
+func (s *AttendanceService) DeleteOvertimeApplication(id, employeeID uint) error {
+	result := s.db.Where("id = ? AND employee_id = ? AND status = ?", id, employeeID, 1).Delete(&model.OvertimeApplication{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return errors.New("can only delete your own pending overtime applications")
+	}
+	return nil
 }
 
 // GetBusinessTripApplicationList 获取出差申请列表
-func (s *AttendanceService) GetBusinessTripApplicationList(employeeID uint, status int, startDate, endDate *time.Time, page, pageSize int) ([]model.BusinessTripApplication, int64, error) {
+func (s *AttendanceService) GetBusinessTripApplicationList(employeeID uint, status int, startDate, endDate *time.Time, page, pageSize int, scope *DataScope) ([]model.BusinessTripApplication, int64, error) {
 	var applications []model.BusinessTripApplication
 	var total int64
 
-	query := s.db.Model(&model.BusinessTripApplication{})
+	query := s.db.Model(&model.BusinessTripApplication{}).
+		Scopes(scope.Filter(businessTripScope))
 	if employeeID > 0 {
 		query = query.Where("employee_id = ?", employeeID)
 	}
@@ -302,9 +463,9 @@ func (s *AttendanceService) GetBusinessTripApplicationList(employeeID uint, stat
 }
 
 // GetBusinessTripApplicationByID 根据ID获取出差申请
-func (s *AttendanceService) GetBusinessTripApplicationByID(id uint) (*model.BusinessTripApplication, error) {
+func (s *AttendanceService) GetBusinessTripApplicationByID(id uint, scope *DataScope) (*model.BusinessTripApplication, error) {
 	var application model.BusinessTripApplication
-	err := s.db.First(&application, id).Error
+	err := s.db.Scopes(scope.Filter(businessTripScope)).First(&application, id).Error
 	if err != nil {
 		return nil, err
 	}
@@ -320,7 +481,7 @@ func (s *AttendanceService) CreateBusinessTripApplication(application *model.Bus
 	return s.db.Create(application).Error
 }
 
-// UpdateBusinessTripApplication 更新出差申请
+// UpdateBusinessTripApplication 更新出差申请，只能修改本人待审批的申请
 func (s *AttendanceService) UpdateBusinessTripApplication(application *model.BusinessTripApplication) error {
 	if application.ID == 0 {
 		return errors.New("business trip application id is required")
@@ -330,12 +491,29 @@ func (s *AttendanceService) UpdateBusinessTripApplication(application *model.Bus
 	days := application.EndTime.Sub(*application.StartTime).Hours() / 24
 	application.Days = float64(days)
 
-	return s.db.Model(application).Updates(application).Error
+	// 状态只能通过审批接口修改
+	application.Status = 0
+
+	result := s.db.Model(application).Where("employee_id = ? AND status = ?", application.EmployeeID, 1).Updates(application)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return errors.New("can only update your own pending business trip applications")
+	}
+	return nil
 }
 
-// DeleteBusinessTripApplication 删除出差申请
-func (s *AttendanceService) DeleteBusinessTripApplication(id uint) error {
-	return s.db.Delete(&model.BusinessTripApplication{}, id).Error
+// DeleteBusinessTripApplication 删除出差申请，只能删除本人待审批的申请
+func (s *AttendanceService) DeleteBusinessTripApplication(id, employeeID uint) error {
+	result := s.db.Where("id = ? AND employee_id = ? AND status = ?", id, employeeID, 1).Delete(&model.BusinessTripApplication{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return errors.New("can only delete your own pending business trip applications")
+	}
+	return nil
 }
 
 // ApproveLeaveApplication 审批通过请假申请
@@ -1,65 +1,265 @@
 package service
 
 import (
+	"context"
 	"crypto/rand"
-	"crypto/sha512"
+	"crypto/sha256"
 	"encoding/hex"
 	"errors"
 	"time"
 
+	"github.com/lemonoa/LemonOA-Go/metrics"
 	"github.com/lemonoa/LemonOA-Go/model"
 
 	"github.com/dgrijalva/jwt-go"
 	"github.com/spf13/viper"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type AuthService struct {
 	db *gorm.DB
 }
 
+// TokenPair 登录/刷新后签发的令牌
+type TokenPair struct {
+	AccessToken  string `json:"token"`         // 访问令牌
+	RefreshToken string `json:"refresh_token"` // 刷新令牌
+	ExpiresIn    int64  `json:"expires_in"`    // 访问令牌有效期(秒)
+}
+
+var (
+	ErrInvalidRefreshToken = errors.New("无效的刷新令牌")
+	ErrRefreshTokenExpired = errors.New("刷新令牌已过期")
+)
+
 func NewAuthService(db *gorm.DB) *AuthService {
 	return &AuthService{db: db}
 }
 
-// Login 用户登录
-func (s *AuthService) Login(username, password string, ip, userAgent string) (string, error) {
-	var user model.User
-	if err := s.db.Where("username = ?", username).First(&user).Error; err != nil {
-		if err == gorm.ErrRecordNotFound {
-			return "", errors.New("用户不存在")
+// WithContext 返回使用请求上下文的服务，写入数据时由数据库回调填充当前操作用户
+func (s *AuthService) WithContext(ctx context.Context) *AuthService {
+	return &AuthService{db: s.db.WithContext(ctx)}
+}
+
+// Login 用户登录，开启两步验证的用户返回挑战令牌，需调用 VerifyLogin2FA 完成登录
+// 密码已过期或被管理员重置的用户返回修改密码的挑战令牌，需调用 ChangeExpiredPassword 完成登录
+func (s *AuthService) Login(username, password string, ip, userAgent string) (*LoginResult, error) {
+	cfg := loadLoginGuardConfig()
+
+	// 同一IP失败次数过多时直接拒绝，防止对多个账号撞库
+	if err := s.checkIPThrottle(cfg, ip); err != nil {
+		return nil, err
+	}
+
+	// 本地用户不存在时可能是首次登录的LDAP用户，由认证方式决定是否接受
+	var existing *model.User
+	var found model.User
+	if err := s.db.Where("username = ?", username).First(&found).Error; err != nil {
+		if err != gorm.ErrRecordNotFound {
+			return nil, err
 		}
-		return "", err
+	} else {
+		existing = &found
 	}
 
-	if user.Status != 1 {
-		return "", errors.New("用户已被禁用")
+	if existing != nil {
+		// 服务账号只能通过API密钥访问
+		if existing.Type == model.UserTypeService {
+			verifyDummyPassword(password)
+			s.createLoginLog(existing.ID, ip, userAgent, 2, "服务账号不能登录")
+			return nil, ErrInvalidCredentials
+		}
+
+		if existing.LockedUntil != nil && time.Now().Before(*existing.LockedUntil) {
+			s.createLoginLog(existing.ID, ip, userAgent, 2, "账号已锁定")
+			return nil, ErrAccountLocked
+		}
 	}
 
-	// 验证密码
-	if s.encryptPassword(password, user.Salt) != user.Password {
+	// 按配置的认证方式依次验证密码
+	user, err := s.authenticate(existing, username, password)
+	if err != nil {
+		if err != ErrInvalidCredentials {
+			return nil, err
+		}
 		// 记录登录失败日志
-		s.createLoginLog(user.ID, ip, userAgent, 2, "密码错误")
-		return "", errors.New("密码错误")
+		if existing == nil {
+			s.createLoginLog(0, ip, userAgent, 2, "用户不存在")
+			return nil, ErrInvalidCredentials
+		}
+		s.createLoginLog(existing.ID, ip, userAgent, 2, "密码错误")
+		if err := s.recordLoginFailure(cfg, existing); err != nil {
+			return nil, err
+		}
+		return nil, ErrInvalidCredentials
+	}
+
+	// 密码正确后再提示禁用状态，避免暴露账号是否存在
+	if user.Status != 1 {
+		s.createLoginLog(user.ID, ip, userAgent, 2, "用户已被禁用")
+		return nil, errors.New("用户已被禁用")
+	}
+
+	return s.continueLogin(user, ip, userAgent)
+}
+
+// continueLogin 身份验证通过后的登录流程：开启了两步验证，或所属角色要求两步验证但尚未绑定时，
+// 签发挑战令牌进入第二步，否则直接完成登录
+func (s *AuthService) continueLogin(user *model.User, ip, userAgent string) (*LoginResult, error) {
+	required, err := s.requires2FA(user.ID)
+	if err != nil {
+		return nil, err
 	}
+	if user.TOTPEnabled || required {
+		challenge, err := s.generateChallengeToken(user.ID, !user.TOTPEnabled)
+		if err != nil {
+			return nil, err
+		}
+		return &LoginResult{
+			TwoFactorRequired: user.TOTPEnabled,
+			TwoFactorSetup:    !user.TOTPEnabled,
+			ChallengeToken:    challenge,
+		}, nil
+	}
+
+	return s.finishLogin(user, ip, userAgent)
+}
 
-	// 更新最后登录信息
+// 完成登录：更新登录信息、记录日志并签发令牌
+func (s *AuthService) completeLogin(user *model.User, ip, userAgent string) (*TokenPair, error) {
+	// 更新最后登录信息，登录成功后清零失败次数
 	now := time.Now()
-	s.db.Model(&user).Updates(map[string]interface{}{
-		"last_login_at": &now,
-		"last_login_ip": ip,
+	s.db.Model(user).Updates(map[string]interface{}{
+		"last_login_at":  &now,
+		"last_login_ip":  ip,
+		"login_failures": 0,
+		"locked_until":   nil,
 	})
 
 	// 记录登录成功日志
 	s.createLoginLog(user.ID, ip, userAgent, 1, "")
 
-	// 生成JWT token
-	token, err := s.generateToken(user.ID)
+	// 签发令牌，每次登录开启一个新的会话及刷新令牌族
+	familyID, err := randomHex(16)
 	if err != nil {
-		return "", err
+		return nil, err
+	}
+
+	var pair *TokenPair
+	err = s.db.Transaction(func(tx *gorm.DB) error {
+		if err := s.createSession(tx, user.ID, familyID, ip, userAgent); err != nil {
+			return err
+		}
+
+		var err error
+		pair, err = s.issueTokenPair(tx, user.ID, familyID, ip, userAgent)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return pair, nil
+}
+
+// RefreshToken 使用刷新令牌换取新的令牌，旧的刷新令牌随即作废
+func (s *AuthService) RefreshToken(refreshToken, ip, userAgent string) (*TokenPair, error) {
+	var rt model.RefreshToken
+	if err := s.db.Where("token_hash = ?", hashToken(refreshToken)).First(&rt).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, ErrInvalidRefreshToken
+		}
+		return nil, err
+	}
+
+	// 已轮换过的令牌再次出现，说明令牌可能被盗用，吊销整个令牌族
+	if rt.RevokedAt != nil {
+		s.revokeRefreshTokenFamily(s.db, rt.FamilyID)
+		return nil, ErrInvalidRefreshToken
+	}
+	if time.Now().After(rt.ExpiresAt) {
+		return nil, ErrRefreshTokenExpired
+	}
+
+	var user model.User
+	if err := s.db.First(&user, rt.UserID).Error; err != nil {
+		return nil, ErrInvalidRefreshToken
+	}
+	if user.Status != 1 {
+		return nil, errors.New("用户已被禁用")
+	}
+	if user.TokenRevokedAt != nil && rt.CreatedAt.Before(*user.TokenRevokedAt) {
+		return nil, ErrInvalidRefreshToken
 	}
 
-	return token, nil
+	var pair *TokenPair
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		// 以条件更新作废旧令牌，防止并发请求重复使用同一个刷新令牌
+		now := time.Now()
+		result := tx.Model(&model.RefreshToken{}).
+			Where("id = ? AND revoked_at IS NULL", rt.ID).
+			Update("revoked_at", &now)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return ErrInvalidRefreshToken
+		}
+
+		// 会话已被用户或管理员终止
+		if err := s.renewSession(tx, user.ID, rt.FamilyID, ip, userAgent); err != nil {
+			if err == ErrSessionRevoked {
+				return ErrInvalidRefreshToken
+			}
+			return err
+		}
+
+		var err error
+		pair, err = s.issueTokenPair(tx, user.ID, rt.FamilyID, ip, userAgent)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return pair, nil
+}
+
+// Logout 退出登录，吊销当前访问令牌及其所属的刷新令牌族
+func (s *AuthService) Logout(userID uint, jti string, expiresAt time.Time, refreshToken string) error {
+	return s.db.Transaction(func(tx *gorm.DB) error {
+		if jti != "" {
+			revoked := &model.RevokedToken{
+				JTI:       jti,
+				UserID:    userID,
+				ExpiresAt: expiresAt,
+			}
+			if err := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(revoked).Error; err != nil {
+				return err
+			}
+			markTokenRevoked(jti)
+		}
+
+		if refreshToken != "" {
+			var rt model.RefreshToken
+			err := tx.Where("token_hash = ? AND user_id = ?", hashToken(refreshToken), userID).First(&rt).Error
+			if err != nil && err != gorm.ErrRecordNotFound {
+				return err
+			}
+			if err == nil {
+				return s.revokeRefreshTokenFamily(tx, rt.FamilyID)
+			}
+		}
+
+		return nil
+	})
+}
+
+// ForceLogout 强制用户下线，吊销其所有已签发的令牌
+func (s *AuthService) ForceLogout(userID uint) error {
+	return s.db.Transaction(func(tx *gorm.DB) error {
+		return s.revokeUserTokens(tx, userID)
+	})
 }
 
 // GetUserInfo 获取用户信息
@@ -74,13 +274,12 @@ func (s *AuthService) GetUserInfo(userID uint) (*model.User, error) {
 // GetUserPermissions 获取用户权限列表
 func (s *AuthService) GetUserPermissions(userID uint) ([]model.Permission, error) {
 	var permissions []model.Permission
-	err := s.db.Raw(`
-		SELECT DISTINCT p.* FROM permissions p
-		INNER JOIN role_permissions rp ON p.id = rp.permission_id
-		INNER JOIN user_roles ur ON rp.role_id = ur.role_id
-		WHERE ur.user_id = ? AND p.status = 1
-		ORDER BY p.sort ASC
-	`, userID).Find(&permissions).Error
+	err := s.db.Model(&model.Permission{}).Distinct().
+		Joins("INNER JOIN role_permissions ON role_permissions.permission_id = permissions.id AND role_permissions.deleted_at IS NULL").
+		Joins("INNER JOIN user_roles ON user_roles.role_id = role_permissions.role_id AND user_roles.deleted_at IS NULL").
+		Where("user_roles.user_id = ? AND permissions.status = 1", userID).
+		Order("permissions.sort ASC").
+		Find(&permissions).Error
 	return permissions, err
 }
 
@@ -91,49 +290,123 @@ func (s *AuthService) ChangePassword(userID uint, oldPassword, newPassword strin
 		return err
 	}
 
-	if s.encryptPassword(oldPassword, user.Salt) != user.Password {
+	if !isLocalUser(&user) {
+		return errors.New("该账号由外部系统认证，请在对应系统中修改密码")
+	}
+
+	if ok, _ := VerifyPassword(oldPassword, user.Password, user.Salt); !ok {
 		return errors.New("原密码错误")
 	}
 
-	// 生成新的盐值和密码
-	salt := s.generateSalt()
-	password := s.encryptPassword(newPassword, salt)
+	return s.db.Transaction(func(tx *gorm.DB) error {
+		if err := s.setPassword(tx, &user, newPassword, false); err != nil {
+			return err
+		}
+
+		// 修改密码后需要重新登录
+		return s.revokeUserTokens(tx, user.ID)
+	})
+}
+
+// 签发访问令牌和刷新令牌
+func (s *AuthService) issueTokenPair(db *gorm.DB, userID uint, familyID, ip, userAgent string) (*TokenPair, error) {
+	accessToken, err := s.generateToken(userID, familyID)
+	if err != nil {
+		return nil, err
+	}
+
+	refreshToken, err := randomHex(32)
+	if err != nil {
+		return nil, err
+	}
 
-	return s.db.Model(&user).Updates(map[string]interface{}{
-		"salt":     salt,
-		"password": password,
-	}).Error
+	rt := &model.RefreshToken{
+		UserID:    userID,
+		TokenHash: hashToken(refreshToken),
+		FamilyID:  familyID,
+		ExpiresAt: time.Now().Add(time.Duration(viper.GetInt("jwt.refresh_expire")) * time.Second),
+		IP:        ip,
+		UserAgent: userAgent,
+	}
+	if err := db.Create(rt).Error; err != nil {
+		return nil, err
+	}
+
+	return &TokenPair{
+		AccessToken:  accessToken,
+		RefreshToken: refreshToken,
+		ExpiresIn:    viper.GetInt64("jwt.expire"),
+	}, nil
 }
 
-// 生成JWT token
-func (s *AuthService) generateToken(userID uint) (string, error) {
+// 生成JWT token，sid为所属会话ID
+func (s *AuthService) generateToken(userID uint, sessionID string) (string, error) {
+	jti, err := randomHex(16)
+	if err != nil {
+		return "", err
+	}
+
+	now := time.Now()
 	claims := jwt.MapClaims{
 		"user_id": userID,
-		"exp":     time.Now().Add(time.Duration(viper.GetInt("jwt.expire")) * time.Second).Unix(),
+		"sid":     sessionID,
+		"jti":     jti,
+		"iat":     now.Unix(),
+		"exp":     now.Add(time.Duration(viper.GetInt("jwt.expire")) * time.Second).Unix(),
 	}
 
 	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
 	return token.SignedString([]byte(viper.GetString("jwt.secret")))
 }
 
-// 生成32位随机盐值
-func (s *AuthService) generateSalt() string {
-	bytes := make([]byte, 16)
+// 吊销整个刷新令牌族及对应的会话
+func (s *AuthService) revokeRefreshTokenFamily(db *gorm.DB, familyID string) error {
+	if err := db.Model(&model.RefreshToken{}).
+		Where("family_id = ? AND revoked_at IS NULL", familyID).
+		Update("revoked_at", time.Now()).Error; err != nil {
+		return err
+	}
+	return s.revokeSessions(db, "session_id = ?", familyID)
+}
+
+// 吊销用户的所有令牌：此前签发的访问令牌由JWT中间件拒绝，刷新令牌及会话全部作废
+func (s *AuthService) revokeUserTokens(db *gorm.DB, userID uint) error {
+	now := time.Now()
+	if err := db.Model(&model.User{}).Where("id = ?", userID).Update("token_revoked_at", &now).Error; err != nil {
+		return err
+	}
+	invalidateTokenUserState(userID)
+	if err := db.Model(&model.RefreshToken{}).
+		Where("user_id = ? AND revoked_at IS NULL", userID).
+		Update("revoked_at", &now).Error; err != nil {
+		return err
+	}
+	return s.revokeSessions(db, "user_id = ?", userID)
+}
+
+// 生成指定字节数的随机十六进制串
+func randomHex(n int) (string, error) {
+	bytes := make([]byte, n)
 	if _, err := rand.Read(bytes); err != nil {
-		return time.Now().Format("20060102150405")
+		return "", err
 	}
-	return hex.EncodeToString(bytes)
+	return hex.EncodeToString(bytes), nil
 }
 
-// 加密密码
-func (s *AuthService) encryptPassword(password, salt string) string {
-	hash := sha512.New()
-	hash.Write([]byte(password + salt))
-	return hex.EncodeToString(hash.Sum(nil))
+// 计算令牌的SHA-256哈希，数据库中只保存哈希值
+func hashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
 }
 
-// 创建登录日志
+// 创建登录日志，同时计入登录指标
 func (s *AuthService) createLoginLog(userID uint, ip, userAgent string, status int, message string) {
+	if status == 1 {
+		metrics.Logins.WithLabelValues("success").Inc()
+	} else {
+		metrics.Logins.WithLabelValues("failure").Inc()
+	}
+
 	log := &model.LoginLog{
 		UserID:    userID,
 		IP:        ip,
@@ -170,34 +443,100 @@ func (s *AuthService) GetUserList(status int, keyword string, page, pageSize int
 	return users, total, nil
 }
 
-// CreateUser 创建用户
+// CreateUser 创建用户，密码需符合密码策略
 func (s *AuthService) CreateUser(user *model.User) error {
-	// 生成密码盐值
-	user.Salt = s.generateSalt()
-	// 加密密码
-	user.Password = s.encryptPassword(user.Password, user.Salt)
+	policy, err := GetPasswordPolicy(s.db)
+	if err != nil {
+		return err
+	}
+	if err := policy.Validate(user.Password, user.Username); err != nil {
+		return err
+	}
+	if user.EmployeeID != nil {
+		if err := s.checkEmployeeBindable(s.db, *user.EmployeeID, 0); err != nil {
+			return err
+		}
+	}
+
+	return s.createUser(policy, user)
+}
+
+// 加密密码并创建用户，同时记录首个历史密码
+func (s *AuthService) createUser(policy *PasswordPolicy, user *model.User) error {
+	// 加密密码，盐值保存在哈希编码串中
+	password, err := HashPassword(user.Password)
+	if err != nil {
+		return err
+	}
+	now := time.Now()
+	user.Password = password
+	user.Salt = ""
+	user.PasswordChangedAt = &now
 
-	return s.db.Create(user).Error
+	return s.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(user).Error; err != nil {
+			return err
+		}
+		return s.recordPasswordHistory(tx, policy, user.ID, password)
+	})
 }
 
-// UpdateUser 更新用户
+// UpdateUser 更新用户资料，只修改姓名、头像、邮箱、手机号和状态，重置密码使用 ResetPassword
+// 账号类型、来源、锁定状态等由各自的接口维护，员工关联通过单独的接口维护
 func (s *AuthService) UpdateUser(user *model.User) error {
 	if user.ID == 0 {
 		return errors.New("user id is required")
 	}
+	if user.Status != 0 && user.Status != 1 && user.Status != 2 {
+		return errors.New("invalid status")
+	}
+
+	profile := &model.User{
+		ID:       user.ID,
+		RealName: user.RealName,
+		Avatar:   user.Avatar,
+		Email:    user.Email,
+		Mobile:   user.Mobile,
+		Status:   user.Status,
+	}
+	ClearMaskedSensitive(profile)
 
-	// 如果密码不为空,则需要重新加密
-	if user.Password != "" {
-		user.Salt = s.generateSalt()
-		user.Password = s.encryptPassword(user.Password, user.Salt)
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(profile).Updates(profile).Error; err != nil {
+			return err
+		}
+
+		// 禁用用户后，已签发的令牌全部失效
+		if user.Status == 2 {
+			return s.revokeUserTokens(tx, user.ID)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
 	}
 
-	return s.db.Model(user).Updates(user).Error
+	// 用户状态可能发生变化，清除权限缓存和令牌校验使用的用户状态缓存
+	InvalidateUserPermissions(user.ID)
+	invalidateTokenUserState(user.ID)
+	return nil
 }
 
 // DeleteUser 删除用户
 func (s *AuthService) DeleteUser(id uint) error {
-	return s.db.Delete(&model.User{}, id).Error
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		if err := s.revokeUserTokens(tx, id); err != nil {
+			return err
+		}
+		return tx.Delete(&model.User{}, id).Error
+	})
+	if err != nil {
+		return err
+	}
+
+	InvalidateUserPermissions(id)
+	invalidateTokenUserState(id)
+	return nil
 }
 
 // ResetPassword 重置用户密码
@@ -207,14 +546,21 @@ func (s *AuthService) ResetPassword(id uint, password string) error {
 		return err
 	}
 
-	// 生成新的盐值和密码
-	user.Salt = s.generateSalt()
-	user.Password = s.encryptPassword(password, user.Salt)
+	return s.db.Transaction(func(tx *gorm.DB) error {
+		// 管理员重置的密码，用户下次登录时需修改
+		if err := s.setPassword(tx, user, password, true); err != nil {
+			return err
+		}
 
-	return s.db.Model(user).Updates(map[string]interface{}{
-		"salt":     user.Salt,
-		"password": user.Password,
-	}).Error
+		// 管理员重置密码同时解除账号锁定
+		if err := tx.Model(user).Updates(map[string]interface{}{
+			"login_failures": 0,
+			"locked_until":   nil,
+		}).Error; err != nil {
+			return err
+		}
+		return s.revokeUserTokens(tx, user.ID)
+	})
 }
 
 // GetRoleList 获取角色列表
@@ -229,12 +575,57 @@ func (s *AuthService) CreateRole(role *model.Role) error {
 	return s.db.Create(role).Error
 }
 
+// RoleUpdate 角色可修改的字段，未传的字段保持不变；角色编码创建后不可修改，
+// 避免普通角色被改为超级管理员
+type RoleUpdate struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	Status      int    `json:"status"`
+	Require2FA  *bool  `json:"require_2fa"`
+	DataScope   int    `json:"data_scope"`
+}
+
 // UpdateRole 更新角色
-func (s *AuthService) UpdateRole(role *model.Role) error {
-	if role.ID == 0 {
-		return errors.New("role id is required")
+func (s *AuthService) UpdateRole(id uint, req RoleUpdate) (*model.Role, error) {
+	if id == 0 {
+		return nil, errors.New("role id is required")
 	}
-	return s.db.Model(role).Updates(role).Error
+	if req.Status != 0 && req.Status != 1 && req.Status != 2 {
+		return nil, errors.New("invalid status")
+	}
+	if req.DataScope != 0 && (req.DataScope < model.DataScopeAll || req.DataScope > model.DataScopeSelf) {
+		return nil, errors.New("invalid data scope")
+	}
+
+	role := &model.Role{ID: id}
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.First(role, id).Error; err != nil {
+			return err
+		}
+		updates := model.Role{
+			Name:        req.Name,
+			Description: req.Description,
+			Status:      req.Status,
+			DataScope:   req.DataScope,
+		}
+		if err := tx.Model(role).Updates(updates).Error; err != nil {
+			return err
+		}
+		// 零值字段不会被Updates写入，require_2fa 仅在请求中传入时单独更新
+		if req.Require2FA != nil {
+			return tx.Model(role).Update("require_2fa", *req.Require2FA).Error
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// 状态、数据权限变更影响该角色用户的权限，清除其权限缓存
+	if err := invalidateRolePermissions(s.db, role.ID); err != nil {
+		return nil, err
+	}
+	return role, nil
 }
 
 // DeleteRole 删除角色
@@ -248,7 +639,16 @@ func (s *AuthService) DeleteRole(id uint) error {
 		return errors.New("cannot delete role with associated users")
 	}
 
-	return s.db.Delete(&model.Role{}, id).Error
+	// 删除角色的同时删除角色的权限及数据权限部门
+	return s.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("role_id = ?", id).Delete(&model.RolePermission{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("role_id = ?", id).Delete(&model.RoleDepartment{}).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&model.Role{}, id).Error
+	})
 }
 
 // GetRolePermissions 获取角色权限
@@ -263,7 +663,7 @@ func (s *AuthService) GetRolePermissions(roleID uint) ([]model.Permission, error
 
 // UpdateRolePermissions 更新角色权限
 func (s *AuthService) UpdateRolePermissions(roleID uint, permissionIDs []uint) error {
-	return s.db.Transaction(func(tx *gorm.DB) error {
+	err := s.db.Transaction(func(tx *gorm.DB) error {
 		// 删除原有权限
 		if err := tx.Where("role_id = ?", roleID).Delete(&model.RolePermission{}).Error; err != nil {
 			return err
@@ -282,6 +682,59 @@ func (s *AuthService) UpdateRolePermissions(roleID uint, permissionIDs []uint) e
 
 		return nil
 	})
+	if err != nil {
+		return err
+	}
+
+	return invalidateRolePermissions(s.db, roleID)
+}
+
+// GetUserRoles 获取用户角色
+func (s *AuthService) GetUserRoles(userID uint) ([]model.Role, error) {
+	var roles []model.Role
+	err := s.db.Model(&model.Role{}).
+		Joins("JOIN user_roles ON roles.id = user_roles.role_id AND user_roles.deleted_at IS NULL").
+		Where("user_roles.user_id = ?", userID).
+		Find(&roles).Error
+	return roles, err
+}
+
+// UpdateUserRoles 更新用户角色
+func (s *AuthService) UpdateUserRoles(userID uint, roleIDs []uint, operatorID uint) error {
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.First(&model.User{}, userID).Error; err != nil {
+			return err
+		}
+		return s.replaceUserRoles(tx, userID, roleIDs, operatorID)
+	})
+	if err != nil {
+		return err
+	}
+
+	InvalidateUserPermissions(userID)
+	return nil
+}
+
+// 替换用户的全部角色
+func (s *AuthService) replaceUserRoles(tx *gorm.DB, userID uint, roleIDs []uint, operatorID uint) error {
+	// 删除原有角色
+	if err := tx.Where("user_id = ?", userID).Delete(&model.UserRole{}).Error; err != nil {
+		return err
+	}
+
+	// 添加新角色
+	for _, rid := range roleIDs {
+		ur := &model.UserRole{
+			UserID:    userID,
+			RoleID:    rid,
+			CreatedBy: operatorID,
+		}
+		if err := tx.Create(ur).Error; err != nil {
+			return err
+		}
+	}
+
+	return nil
 }
 
 // GetPermissionList 获取权限列表
@@ -293,7 +746,13 @@ func (s *AuthService) GetPermissionList() ([]model.Permission, error) {
 
 // CreatePermission 创建权限
 func (s *AuthService) CreatePermission(permission *model.Permission) error {
-	return s.db.Create(permission).Error
+	if err := s.db.Create(permission).Error; err != nil {
+		return err
+	}
+
+	// 新增的接口权限可能保护已有路由
+	invalidateRoutePermissions()
+	return nil
 }
 
 // UpdatePermission 更新权限
@@ -301,7 +760,14 @@ func (s *AuthService) UpdatePermission(permission *model.Permission) error {
 	if permission.ID == 0 {
 		return errors.New("permission id is required")
 	}
-	return s.db.Model(permission).Updates(permission).Error
+	if err := s.db.Model(permission).Updates(permission).Error; err != nil {
+		return err
+	}
+
+	// 权限编码或状态变化影响所有用户
+	permissionCache.InvalidateAll()
+	invalidateRoutePermissions()
+	return nil
 }
 
 // DeletePermission 删除权限
@@ -315,5 +781,11 @@ func (s *AuthService) DeletePermission(id uint) error {
 		return errors.New("cannot delete permission with associated roles")
 	}
 
-	return s.db.Delete(&model.Permission{}, id).Error
+	if err := s.db.Delete(&model.Permission{}, id).Error; err != nil {
+		return err
+	}
+
+	permissionCache.InvalidateAll()
+	invalidateRoutePermissions()
+	return nil
 }
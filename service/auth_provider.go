@@ -0,0 +1,128 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"sync"
+
+	"github.com/lemonoa/LemonOA-Go/logging"
+	"github.com/lemonoa/LemonOA-Go/model"
+
+	"github.com/spf13/viper"
+)
+
+var ErrAuthProviderUnavailable = errors.New("认证服务暂不可用，请稍后重试")
+
+// AuthProvider 认证方式，登录时按 auth.providers 配置的顺序依次尝试
+type AuthProvider interface {
+	// Name 认证方式名称，与配置文件中的名称对应
+	Name() string
+	// Authenticate 校验用户名密码，user 为本地已存在的用户，不存在时为nil
+	// 不接受该凭据时返回 ErrInvalidCredentials，继续尝试下一个认证方式
+	// 外部服务不可用时返回 ErrAuthProviderUnavailable
+	Authenticate(s *AuthService, user *model.User, username, password string) (*model.User, error)
+}
+
+var (
+	authProvidersMu sync.RWMutex
+	authProviders   = map[string]AuthProvider{}
+)
+
+func init() {
+	RegisterAuthProvider(localAuthProvider{})
+	RegisterAuthProvider(&ldapAuthProvider{})
+}
+
+// RegisterAuthProvider 注册认证方式，同名的认证方式会被替换
+func RegisterAuthProvider(provider AuthProvider) {
+	authProvidersMu.Lock()
+	authProviders[provider.Name()] = provider
+	authProvidersMu.Unlock()
+}
+
+// 按配置顺序返回启用的认证方式，未配置时只使用本地认证
+func authProviderChain(ctx context.Context) []AuthProvider {
+	names := viper.GetStringSlice("auth.providers")
+	if len(names) == 0 {
+		names = []string{model.UserSourceLocal}
+	}
+
+	authProvidersMu.RLock()
+	defer authProvidersMu.RUnlock()
+
+	chain := make([]AuthProvider, 0, len(names))
+	for _, name := range names {
+		provider, ok := authProviders[name]
+		if !ok {
+			logging.FromContext(ctx).WarnContext(ctx, "未知的认证方式", "provider", name)
+			continue
+		}
+		chain = append(chain, provider)
+	}
+	return chain
+}
+
+// 依次尝试各认证方式，有一个通过即认证成功
+// 全部不通过时，如有认证方式不可用则返回 ErrAuthProviderUnavailable，避免服务故障被计为密码错误
+func (s *AuthService) authenticate(user *model.User, username, password string) (*model.User, error) {
+	var unavailable bool
+	for _, provider := range authProviderChain(s.db.Statement.Context) {
+		authenticated, err := provider.Authenticate(s, user, username, password)
+		switch err {
+		case nil:
+			return authenticated, nil
+		case ErrInvalidCredentials:
+			continue
+		case ErrAuthProviderUnavailable:
+			unavailable = true
+			continue
+		default:
+			return nil, err
+		}
+	}
+
+	if unavailable {
+		return nil, ErrAuthProviderUnavailable
+	}
+	return nil, ErrInvalidCredentials
+}
+
+// localAuthProvider 本地认证，校验users表中的密码哈希
+type localAuthProvider struct{}
+
+func (localAuthProvider) Name() string {
+	return model.UserSourceLocal
+}
+
+func (localAuthProvider) Authenticate(s *AuthService, user *model.User, username, password string) (*model.User, error) {
+	if user == nil {
+		// 用户不存在时同样计算一次哈希，使响应时间与密码错误一致
+		verifyDummyPassword(password)
+		return nil, ErrInvalidCredentials
+	}
+	if !isLocalUser(user) {
+		return nil, ErrInvalidCredentials
+	}
+
+	ok, needsRehash := VerifyPassword(password, user.Password, user.Salt)
+	if !ok {
+		return nil, ErrInvalidCredentials
+	}
+
+	// 旧算法或低强度参数的哈希，借登录时的明文密码升级为当前默认算法
+	if needsRehash {
+		if hash, err := HashPassword(password); err == nil {
+			s.db.Model(user).Updates(map[string]interface{}{
+				"salt":     "",
+				"password": hash,
+			})
+		}
+	}
+
+	return user, nil
+}
+
+// 是否本地账号，来源字段为空的是该字段加入前创建的本地账号
+func isLocalUser(user *model.User) bool {
+	return user.Source == "" || user.Source == model.UserSourceLocal
+}
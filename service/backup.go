@@ -0,0 +1,1032 @@
+package service
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"context"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/lemonoa/LemonOA-Go/database"
+	"github.com/lemonoa/LemonOA-Go/logging"
+	"github.com/lemonoa/LemonOA-Go/model"
+
+	"github.com/spf13/viper"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"gorm.io/gorm/schema"
+)
+
+// 备份文件格式版本，格式变化时递增，恢复时拒绝高于当前版本的备份
+const backupFormatVersion = 1
+
+const (
+	// 导出和导入数据时每批处理的记录数
+	backupBatchSize = 500
+
+	backupManifestName = "manifest.json"
+	backupTablePrefix  = "tables/"
+	backupFilePrefix   = "files/"
+)
+
+var (
+	ErrBackupNotFound    = errors.New("backup not found")
+	ErrBackupTypeInvalid = errors.New("invalid backup type")
+	ErrBackupRunning     = errors.New("another backup or restore is running")
+	ErrBackupNotReady    = errors.New("backup is not completed")
+	ErrBackupInUse       = errors.New("backup is the base of other incremental backups")
+	ErrBackupBaseMissing = errors.New("base backup not found")
+	ErrBackupCorrupted   = errors.New("backup file is corrupted")
+	ErrBackupVersion     = errors.New("unsupported backup format version")
+)
+
+// 同一时间只允许一个备份或恢复任务
+var backupMu sync.Mutex
+
+// BackupOptions 备份选项
+type BackupOptions struct {
+	Type         int  `json:"type"`          // 1:全量备份 2:增量备份
+	IncludeFiles bool `json:"include_files"` // 是否包含附件文件
+}
+
+// backupManifest 备份清单，作为备份文件的第一项，记录各表和文件的校验和
+type backupManifest struct {
+	Version   int           `json:"version"`
+	Type      int           `json:"type"`
+	BaseID    uint          `json:"base_id,omitempty"`
+	Since     *time.Time    `json:"since,omitempty"`
+	Dialect   string        `json:"dialect"`
+	CreatedAt time.Time     `json:"created_at"`
+	Tables    []backupTable `json:"tables"`
+	Files     []backupFile  `json:"files"`
+}
+
+// backupTable 备份中的一张表，每行一条JSON记录；Incremental 为 false 时是整表数据，恢复时替换整表，否则按主键合并
+type backupTable struct {
+	Name        string `json:"name"`
+	Incremental bool   `json:"incremental"`
+	Rows        int64  `json:"rows"`
+	SHA256      string `json:"sha256"`
+}
+
+// backupFile 备份中的附件文件，Path 为存储路径
+type backupFile struct {
+	Path   string `json:"path"`
+	Size   int64  `json:"size"`
+	SHA256 string `json:"sha256"`
+}
+
+// 备份文件保存目录
+func backupDir() string {
+	if dir := viper.GetString("backup.path"); dir != "" {
+		return dir
+	}
+	return "./backups"
+}
+
+// 参与备份的表，备份记录本身不备份，恢复时也不会被覆盖
+func backupSchemas(db *gorm.DB) ([]*schema.Schema, error) {
+	var schemas []*schema.Schema
+	for _, m := range database.Models() {
+		if _, ok := m.(*model.BackupRecord); ok {
+			continue
+		}
+		stmt := &gorm.Statement{DB: db}
+		if err := stmt.Parse(m); err != nil {
+			return nil, err
+		}
+		schemas = append(schemas, stmt.Schema)
+	}
+	return schemas, nil
+}
+
+// Backup 执行备份并等待完成，用于定时任务等后台调用
+func (s *SystemService) Backup(opts BackupOptions) (*model.BackupRecord, error) {
+	if !backupMu.TryLock() {
+		return nil, ErrBackupRunning
+	}
+	defer backupMu.Unlock()
+
+	record, err := s.prepareBackup(opts)
+	if err != nil {
+		return nil, err
+	}
+	s.runBackup(record)
+	return record, nil
+}
+
+// StartBackup 创建备份记录并在后台执行备份，通过备份记录的状态和进度查看结果
+func (s *SystemService) StartBackup(opts BackupOptions) (*model.BackupRecord, error) {
+	if !backupMu.TryLock() {
+		return nil, ErrBackupRunning
+	}
+
+	record, err := s.prepareBackup(opts)
+	if err != nil {
+		backupMu.Unlock()
+		return nil, err
+	}
+
+	// 请求结束后继续执行，不使用请求上下文
+	background := &SystemService{db: s.db.WithContext(context.Background()), auth: s.auth}
+	go func() {
+		defer backupMu.Unlock()
+		background.runBackup(record)
+	}()
+	return record, nil
+}
+
+// WaitBackup 等待运行中的备份或恢复结束，服务退出前调用，ctx 到期后不再等待
+func WaitBackup(ctx context.Context) {
+	ticker := time.NewTicker(200 * time.Millisecond)
+	defer ticker.Stop()
+	for !backupMu.TryLock() {
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			slog.WarnContext(ctx, "等待备份任务结束超时")
+			return
+		}
+	}
+	backupMu.Unlock()
+}
+
+// 创建备份记录，增量备份基于最近一次成功的备份
+func (s *SystemService) prepareBackup(opts BackupOptions) (*model.BackupRecord, error) {
+	if opts.Type == 0 {
+		opts.Type = model.BackupTypeFull
+	}
+	if opts.Type != model.BackupTypeFull && opts.Type != model.BackupTypeIncremental {
+		return nil, ErrBackupTypeInvalid
+	}
+
+	now := time.Now()
+	kind := "full"
+	record := &model.BackupRecord{
+		Type:         opts.Type,
+		Status:       model.BackupStatusRunning,
+		IncludeFiles: opts.IncludeFiles,
+		Version:      backupFormatVersion,
+	}
+	if opts.Type == model.BackupTypeIncremental {
+		var base model.BackupRecord
+		err := s.db.Where("status = ?", model.BackupStatusSuccess).Order("id desc").First(&base).Error
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return nil, ErrBackupBaseMissing
+			}
+			return nil, err
+		}
+		// 以上一次备份开始的时间为起点，备份期间修改的记录会再次包含在本次备份中
+		record.BaseID = base.ID
+		record.Since = &base.CreatedAt
+		kind = "incremental"
+	}
+
+	record.Name = fmt.Sprintf("lemonoa-%s-%s.tar.gz", kind, now.Format("20060102-150405.000"))
+	record.Path = filepath.Join(backupDir(), record.Name)
+	if err := s.db.Create(record).Error; err != nil {
+		return nil, err
+	}
+	return record, nil
+}
+
+// 执行备份并更新备份记录，成功后按保留策略清理旧备份
+func (s *SystemService) runBackup(record *model.BackupRecord) {
+	ctx := s.db.Statement.Context
+	err := s.writeBackup(record)
+	now := time.Now()
+	record.FinishedAt = &now
+	if err != nil {
+		logging.FromContext(ctx).ErrorContext(ctx, "备份失败", "backup_id", record.ID, "error", err)
+		os.Remove(record.Path)
+		record.Status = model.BackupStatusFailed
+		record.Message = truncateRunes(err.Error(), 500)
+	} else {
+		record.Status = model.BackupStatusSuccess
+		record.Progress = 100
+	}
+	if err := s.db.Model(record).Select("status", "message", "progress", "size", "checksum", "table_count", "row_count", "file_count", "finished_at").Updates(record).Error; err != nil {
+		logging.FromContext(ctx).ErrorContext(ctx, "更新备份记录失败", "backup_id", record.ID, "error", err)
+		return
+	}
+
+	if record.Status == model.BackupStatusSuccess {
+		if _, err := s.PruneBackups(); err != nil {
+			logging.FromContext(ctx).ErrorContext(ctx, "清理旧备份失败", "error", err)
+		}
+	}
+}
+
+// 导出各表数据和附件文件，写入 tar.gz 格式的备份文件
+func (s *SystemService) writeBackup(record *model.BackupRecord) error {
+	if err := os.MkdirAll(backupDir(), 0o755); err != nil {
+		return err
+	}
+	work, err := os.MkdirTemp(backupDir(), ".backup-*")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(work)
+
+	schemas, err := backupSchemas(s.db)
+	if err != nil {
+		return err
+	}
+
+	var files []backupFile
+	if record.IncludeFiles {
+		if files, err = s.backupFiles(record.Since); err != nil {
+			return err
+		}
+	}
+
+	manifest := backupManifest{
+		Version:   backupFormatVersion,
+		Type:      record.Type,
+		BaseID:    record.BaseID,
+		Since:     record.Since,
+		Dialect:   s.db.Dialector.Name(),
+		CreatedAt: record.CreatedAt,
+		Files:     files,
+	}
+	progress := newBackupProgress(s.db, record, len(schemas)+len(files))
+
+	// 先逐表导出到临时文件，得到行数和校验和后再写入清单
+	// 全部表在同一个只读的可重复读事务中导出，各表数据取自同一时刻的快照；SQLite的事务本身即为快照
+	err = s.db.Transaction(func(tx *gorm.DB) error {
+		for _, sch := range schemas {
+			if !tx.Migrator().HasTable(sch.Table) {
+				progress.step()
+				continue
+			}
+			table, err := dumpTableFile(tx, sch, record.Since, filepath.Join(work, sch.Table+".jsonl"))
+			if err != nil {
+				return fmt.Errorf("backup table %s: %w", sch.Table, err)
+			}
+			manifest.Tables = append(manifest.Tables, *table)
+			record.RowCount += table.Rows
+			progress.step()
+		}
+		return nil
+	}, &sql.TxOptions{Isolation: sql.LevelRepeatableRead, ReadOnly: true})
+	if err != nil {
+		return err
+	}
+	record.TableCount = len(manifest.Tables)
+	record.FileCount = len(files)
+
+	tmp, err := os.CreateTemp(backupDir(), ".archive-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	hash := sha256.New()
+	size, err := s.writeArchive(io.MultiWriter(tmp, hash), &manifest, work, progress)
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return err
+	}
+	if err := os.Rename(tmp.Name(), record.Path); err != nil {
+		return err
+	}
+
+	record.Size = size
+	record.Checksum = hex.EncodeToString(hash.Sum(nil))
+	return nil
+}
+
+// 写入备份文件，顺序为清单、各表数据、附件文件，返回写入的字节数
+func (s *SystemService) writeArchive(dst io.Writer, manifest *backupManifest, work string, progress *backupProgress) (int64, error) {
+	counter := &countingWriter{w: dst}
+	gz := gzip.NewWriter(counter)
+	tw := tar.NewWriter(gz)
+
+	data, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return 0, err
+	}
+	if err := writeTarEntry(tw, backupManifestName, int64(len(data)), strings.NewReader(string(data))); err != nil {
+		return 0, err
+	}
+
+	for _, table := range manifest.Tables {
+		if err := copyFileToTar(tw, backupTablePrefix+table.Name+".jsonl", filepath.Join(work, table.Name+".jsonl")); err != nil {
+			return 0, err
+		}
+	}
+
+	ctx := s.db.Statement.Context
+	for _, file := range manifest.Files {
+		src, err := fileStorage.Open(ctx, file.Path)
+		if err != nil {
+			return 0, fmt.Errorf("backup file %s: %w", file.Path, err)
+		}
+		err = writeTarEntry(tw, backupFilePrefix+file.Path, file.Size, src)
+		src.Close()
+		if err != nil {
+			return 0, fmt.Errorf("backup file %s: %w", file.Path, err)
+		}
+		progress.step()
+	}
+
+	if err := tw.Close(); err != nil {
+		return 0, err
+	}
+	if err := gz.Close(); err != nil {
+		return 0, err
+	}
+	return counter.n, nil
+}
+
+// 需要备份的附件文件，增量备份只包含起始时间之后上传的文件
+func (s *SystemService) backupFiles(since *time.Time) ([]backupFile, error) {
+	query := s.db.Model(&model.Attachment{}).
+		Select("DISTINCT path, hash, size").
+		Where("hash <> '' AND storage = ?", fileStorage.Name())
+	if since != nil {
+		query = query.Where("created_at >= ?", *since)
+	}
+	var attachments []model.Attachment
+	if err := query.Order("path").Find(&attachments).Error; err != nil {
+		return nil, err
+	}
+
+	files := make([]backupFile, 0, len(attachments))
+	for _, attachment := range attachments {
+		src, err := fileStorage.Open(s.db.Statement.Context, attachment.Path)
+		if err != nil {
+			if errors.Is(err, ErrStorageObjectNotFound) {
+				logging.FromContext(s.db.Statement.Context).WarnContext(s.db.Statement.Context, "备份时附件文件不存在，已跳过", "path", attachment.Path)
+				continue
+			}
+			return nil, err
+		}
+		size, err := src.Seek(0, io.SeekEnd)
+		src.Close()
+		if err != nil {
+			return nil, err
+		}
+		files = append(files, backupFile{Path: attachment.Path, Size: size, SHA256: attachment.Hash})
+	}
+	return files, nil
+}
+
+// 导出一张表到文件，每行一条JSON记录；指定起始时间且表有 updated_at 和 deleted_at 字段时只导出之后新增、修改和软删除的记录
+// 增量方式无法发现被物理删除的记录，没有 deleted_at 字段的表(如关联表)会被物理删除，始终导出整表
+func dumpTableFile(db *gorm.DB, sch *schema.Schema, since *time.Time, path string) (*backupTable, error) {
+	file, err := os.Create(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	table := &backupTable{Name: sch.Table}
+	query := db.Table(sch.Table)
+	if since != nil && sch.LookUpField("updated_at") != nil && sch.LookUpField("deleted_at") != nil {
+		table.Incremental = true
+		query = query.Where(clause.Or(
+			clause.Gte{Column: clause.Column{Name: "updated_at"}, Value: *since},
+			clause.Gte{Column: clause.Column{Name: "deleted_at"}, Value: *since},
+		))
+	}
+
+	hash := sha256.New()
+	encoder := json.NewEncoder(io.MultiWriter(file, hash))
+	err = scanTable(query, sch, func(rows []map[string]interface{}) error {
+		for _, row := range rows {
+			if err := encoder.Encode(row); err != nil {
+				return err
+			}
+		}
+		table.Rows += int64(len(rows))
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	table.SHA256 = hex.EncodeToString(hash.Sum(nil))
+	return table, file.Close()
+}
+
+// 按主键顺序分批读取表中全部记录(包括软删除的记录)，单一数字主键时按主键翻页，否则按偏移量翻页
+func scanTable(query *gorm.DB, sch *schema.Schema, fn func(rows []map[string]interface{}) error) error {
+	pk := sch.PrioritizedPrimaryField
+	keyset := len(sch.PrimaryFields) == 1 && pk != nil && (pk.DataType == schema.Int || pk.DataType == schema.Uint)
+
+	orders := make([]clause.OrderByColumn, 0, len(sch.PrimaryFields))
+	for _, field := range sch.PrimaryFields {
+		orders = append(orders, clause.OrderByColumn{Column: clause.Column{Name: field.DBName}})
+	}
+
+	var last interface{}
+	for offset := 0; ; offset += backupBatchSize {
+		batch := query.Session(&gorm.Session{}).Clauses(clause.OrderBy{Columns: orders}).Limit(backupBatchSize)
+		if keyset {
+			if last != nil {
+				batch = batch.Where(clause.Gt{Column: clause.Column{Name: pk.DBName}, Value: last})
+			}
+		} else {
+			batch = batch.Offset(offset)
+		}
+
+		var rows []map[string]interface{}
+		if err := batch.Find(&rows).Error; err != nil {
+			return err
+		}
+		if len(rows) == 0 {
+			return nil
+		}
+		if err := fn(rows); err != nil {
+			return err
+		}
+		if len(rows) < backupBatchSize {
+			return nil
+		}
+		if keyset {
+			last = rows[len(rows)-1][pk.DBName]
+		}
+	}
+}
+
+// RestoreBackup 从备份恢复数据：增量备份会先恢复其基于的全量备份再依次合并，
+// 附件文件先写入存储，各表数据在同一事务中恢复，任一步失败时数据库保持不变
+func (s *SystemService) RestoreBackup(id uint) error {
+	if !backupMu.TryLock() {
+		return ErrBackupRunning
+	}
+	defer backupMu.Unlock()
+
+	chain, err := s.backupChain(id)
+	if err != nil {
+		return err
+	}
+
+	manifests := make([]*backupManifest, len(chain))
+	for i, record := range chain {
+		if manifests[i], err = verifyBackupFile(record); err != nil {
+			return fmt.Errorf("%s: %w", record.Name, err)
+		}
+	}
+
+	for i, record := range chain {
+		if len(manifests[i].Files) == 0 {
+			continue
+		}
+		if err := s.restoreFiles(record, manifests[i]); err != nil {
+			return fmt.Errorf("%s: %w", record.Name, err)
+		}
+	}
+
+	schemas, err := backupSchemas(s.db)
+	if err != nil {
+		return err
+	}
+	tables := make(map[string]*schema.Schema, len(schemas))
+	for _, sch := range schemas {
+		tables[sch.Table] = sch
+	}
+
+	err = s.db.Transaction(func(tx *gorm.DB) error {
+		if err := setForeignKeyChecks(tx, false); err != nil {
+			return err
+		}
+		defer setForeignKeyChecks(tx, true)
+
+		for i, record := range chain {
+			if err := restoreTables(tx, record, manifests[i], tables); err != nil {
+				return fmt.Errorf("%s: %w", record.Name, err)
+			}
+		}
+		return resetSequences(tx, schemas)
+	})
+	if err != nil {
+		return err
+	}
+
+	// 恢复后角色和权限可能已变化
+	permissionCache.InvalidateAll()
+	invalidateRoutePermissions()
+
+	now := time.Now()
+	return s.db.Model(chain[len(chain)-1]).UpdateColumn("restored_at", now).Error
+}
+
+// 恢复指定备份需要依次应用的备份，从全量备份开始
+func (s *SystemService) backupChain(id uint) ([]*model.BackupRecord, error) {
+	var chain []*model.BackupRecord
+	for {
+		var record model.BackupRecord
+		if err := s.db.First(&record, id).Error; err != nil {
+			if !errors.Is(err, gorm.ErrRecordNotFound) {
+				return nil, err
+			}
+			if len(chain) == 0 {
+				return nil, ErrBackupNotFound
+			}
+			return nil, ErrBackupBaseMissing
+		}
+		if record.Status != model.BackupStatusSuccess {
+			if len(chain) == 0 {
+				return nil, ErrBackupNotReady
+			}
+			return nil, ErrBackupBaseMissing
+		}
+
+		chain = append([]*model.BackupRecord{&record}, chain...)
+		if record.Type != model.BackupTypeIncremental {
+			return chain, nil
+		}
+		id = record.BaseID
+	}
+}
+
+// 校验备份文件的校验和及格式版本，返回备份清单
+func verifyBackupFile(record *model.BackupRecord) (*backupManifest, error) {
+	file, err := os.Open(record.Path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, ErrBackupNotFound
+		}
+		return nil, err
+	}
+	defer file.Close()
+
+	hash := sha256.New()
+	if _, err := io.Copy(hash, file); err != nil {
+		return nil, err
+	}
+	if record.Checksum != "" && hex.EncodeToString(hash.Sum(nil)) != record.Checksum {
+		return nil, ErrBackupCorrupted
+	}
+
+	if _, err := file.Seek(0, io.SeekStart); err != nil {
+		return nil, err
+	}
+	manifest, _, closer, err := openBackupArchive(file)
+	if err != nil {
+		return nil, err
+	}
+	closer.Close()
+	return manifest, nil
+}
+
+// 打开备份文件并读取清单，返回的 tar.Reader 位于清单之后
+func openBackupArchive(r io.Reader) (*backupManifest, *tar.Reader, io.Closer, error) {
+	gz, err := gzip.NewReader(r)
+	if err != nil {
+		return nil, nil, nil, ErrBackupCorrupted
+	}
+	tr := tar.NewReader(gz)
+
+	header, err := tr.Next()
+	if err != nil || header.Name != backupManifestName {
+		gz.Close()
+		return nil, nil, nil, ErrBackupCorrupted
+	}
+	var manifest backupManifest
+	if err := json.NewDecoder(tr).Decode(&manifest); err != nil {
+		gz.Close()
+		return nil, nil, nil, ErrBackupCorrupted
+	}
+	if manifest.Version < 1 || manifest.Version > backupFormatVersion {
+		gz.Close()
+		return nil, nil, nil, ErrBackupVersion
+	}
+	return &manifest, tr, gz, nil
+}
+
+// 遍历备份文件中指定前缀的项
+func walkBackupArchive(record *model.BackupRecord, prefix string, fn func(name string, header *tar.Header, r io.Reader) error) (*backupManifest, error) {
+	file, err := os.Open(record.Path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	manifest, tr, closer, err := openBackupArchive(file)
+	if err != nil {
+		return nil, err
+	}
+	defer closer.Close()
+
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			return manifest, nil
+		}
+		if err != nil {
+			return nil, ErrBackupCorrupted
+		}
+		if !strings.HasPrefix(header.Name, prefix) {
+			continue
+		}
+		if err := fn(strings.TrimPrefix(header.Name, prefix), header, tr); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// 将备份中的附件文件写入存储，存储路径由内容哈希生成，已存在的文件会被相同内容覆盖
+func (s *SystemService) restoreFiles(record *model.BackupRecord, manifest *backupManifest) error {
+	expected := make(map[string]string, len(manifest.Files))
+	for _, file := range manifest.Files {
+		expected[file.Path] = file.SHA256
+	}
+
+	ctx := s.db.Statement.Context
+	_, err := walkBackupArchive(record, backupFilePrefix, func(path string, header *tar.Header, r io.Reader) error {
+		sum, ok := expected[path]
+		if !ok {
+			return ErrBackupCorrupted
+		}
+		hash := sha256.New()
+		if err := fileStorage.Put(ctx, path, io.TeeReader(r, hash), header.Size, ""); err != nil {
+			return fmt.Errorf("restore file %s: %w", path, err)
+		}
+		if hex.EncodeToString(hash.Sum(nil)) != sum {
+			fileStorage.Delete(ctx, path)
+			return ErrBackupCorrupted
+		}
+		delete(expected, path)
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	if len(expected) > 0 {
+		return ErrBackupCorrupted
+	}
+	return nil
+}
+
+// 在事务中恢复备份中的各表：整表数据先清空再写入，增量数据按主键合并；
+// 当前版本已不存在的表和字段会被忽略
+func restoreTables(tx *gorm.DB, record *model.BackupRecord, manifest *backupManifest, tables map[string]*schema.Schema) error {
+	expected := make(map[string]backupTable, len(manifest.Tables))
+	for _, table := range manifest.Tables {
+		expected[table.Name+".jsonl"] = table
+	}
+
+	_, err := walkBackupArchive(record, backupTablePrefix, func(name string, header *tar.Header, r io.Reader) error {
+		table, ok := expected[name]
+		if !ok {
+			return ErrBackupCorrupted
+		}
+		delete(expected, name)
+
+		hash := sha256.New()
+		r = io.TeeReader(r, hash)
+
+		sch := tables[table.Name]
+		if sch == nil {
+			logging.FromContext(tx.Statement.Context).WarnContext(tx.Statement.Context, "恢复备份时跳过已不存在的表", "table", table.Name)
+			io.Copy(io.Discard, r)
+		} else if err := restoreTable(tx, sch, table, r); err != nil {
+			return fmt.Errorf("restore table %s: %w", table.Name, err)
+		}
+
+		if hex.EncodeToString(hash.Sum(nil)) != table.SHA256 {
+			return ErrBackupCorrupted
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	if len(expected) > 0 {
+		return ErrBackupCorrupted
+	}
+	return nil
+}
+
+func restoreTable(tx *gorm.DB, sch *schema.Schema, table backupTable, r io.Reader) error {
+	if !table.Incremental {
+		if err := tx.Exec("DELETE FROM ?", clause.Table{Name: sch.Table}).Error; err != nil {
+			return err
+		}
+	}
+
+	keys := make([]clause.Column, 0, len(sch.PrimaryFieldDBNames))
+	for _, name := range sch.PrimaryFieldDBNames {
+		keys = append(keys, clause.Column{Name: name})
+	}
+
+	insert := func(rows []map[string]interface{}) error {
+		query := tx.Table(sch.Table)
+		if table.Incremental {
+			// 同一张表的记录字段相同，主键已存在时更新备份中的全部字段
+			columns := make([]string, 0, len(rows[0]))
+			for column := range rows[0] {
+				columns = append(columns, column)
+			}
+			query = query.Clauses(clause.OnConflict{Columns: keys, DoUpdates: clause.AssignmentColumns(columns)})
+		}
+		return query.Create(&rows).Error
+	}
+
+	decoder := json.NewDecoder(r)
+	decoder.UseNumber()
+	rows := make([]map[string]interface{}, 0, backupBatchSize)
+	for {
+		var raw map[string]interface{}
+		if err := decoder.Decode(&raw); err == io.EOF {
+			break
+		} else if err != nil {
+			return ErrBackupCorrupted
+		}
+		row, err := restoreRow(sch, raw)
+		if err != nil {
+			return err
+		}
+		rows = append(rows, row)
+		if len(rows) == backupBatchSize {
+			if err := insert(rows); err != nil {
+				return err
+			}
+			rows = rows[:0]
+		}
+	}
+	if len(rows) > 0 {
+		return insert(rows)
+	}
+	return nil
+}
+
+// 按当前表结构转换备份中的记录，JSON中的时间、数字等按字段类型还原
+func restoreRow(sch *schema.Schema, raw map[string]interface{}) (map[string]interface{}, error) {
+	row := make(map[string]interface{}, len(raw))
+	for column, value := range raw {
+		field := sch.LookUpField(column)
+		if field == nil || field.DBName != column {
+			continue
+		}
+		converted, err := restoreValue(field, value)
+		if err != nil {
+			return nil, fmt.Errorf("column %s: %w", column, err)
+		}
+		row[column] = converted
+	}
+	return row, nil
+}
+
+func restoreValue(field *schema.Field, value interface{}) (interface{}, error) {
+	// 序列化字段(如加密字段)按原值写入
+	if value == nil || field.TagSettings["SERIALIZER"] != "" {
+		return value, nil
+	}
+
+	switch v := value.(type) {
+	case string:
+		switch field.DataType {
+		case schema.Time:
+			return time.Parse(time.RFC3339Nano, v)
+		case schema.Bytes:
+			return []byte(v), nil
+		}
+	case json.Number:
+		switch field.DataType {
+		case schema.Bool:
+			return v.String() != "0", nil
+		case schema.Int:
+			return v.Int64()
+		case schema.Uint:
+			return strconv.ParseUint(v.String(), 10, 64)
+		case schema.Float:
+			return v.Float64()
+		case schema.String:
+			return v.String(), nil
+		}
+		return v.String(), nil
+	}
+	return value, nil
+}
+
+// 恢复数据时暂时关闭外键检查，各表按任意顺序写入
+func setForeignKeyChecks(tx *gorm.DB, enabled bool) error {
+	switch tx.Dialector.Name() {
+	case database.DriverMySQL:
+		if enabled {
+			return tx.Exec("SET FOREIGN_KEY_CHECKS = 1").Error
+		}
+		return tx.Exec("SET FOREIGN_KEY_CHECKS = 0").Error
+	case database.DriverSQLite:
+		// 推迟到提交时检查，事务结束后自动恢复
+		if !enabled {
+			return tx.Exec("PRAGMA defer_foreign_keys = ON").Error
+		}
+	}
+	return nil
+}
+
+// PostgreSQL的自增序列不随写入的主键变化，恢复后同步为各表当前最大ID
+func resetSequences(tx *gorm.DB, schemas []*schema.Schema) error {
+	if tx.Dialector.Name() != database.DriverPostgres {
+		return nil
+	}
+	for _, sch := range schemas {
+		pk := sch.PrioritizedPrimaryField
+		if pk == nil || !pk.AutoIncrement {
+			continue
+		}
+		err := tx.Exec("SELECT setval(pg_get_serial_sequence(?, ?), COALESCE(MAX(?), 0) + 1, false) FROM ?",
+			sch.Table, pk.DBName, clause.Column{Name: pk.DBName}, clause.Table{Name: sch.Table}).Error
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// PruneBackups 按保留策略清理旧备份：backup.keep_count 为至少保留的全量备份数，
+// 除此之外超过 backup.retention_days 天的备份及失败的备份会被删除；增量备份随其基于的全量备份一起保留或删除
+func (s *SystemService) PruneBackups() (int, error) {
+	keepCount := viper.GetInt("backup.keep_count")
+	if keepCount <= 0 {
+		keepCount = 3
+	}
+	retentionDays := viper.GetInt("backup.retention_days")
+	if retentionDays <= 0 {
+		retentionDays = 30
+	}
+	cutoff := time.Now().AddDate(0, 0, -retentionDays)
+
+	var records []model.BackupRecord
+	if err := s.db.Order("id desc").Find(&records).Error; err != nil {
+		return 0, err
+	}
+	byID := make(map[uint]*model.BackupRecord, len(records))
+	for i := range records {
+		byID[records[i].ID] = &records[i]
+	}
+
+	// 每个备份所属的全量备份，以及每个全量备份链中最新一次成功备份的时间
+	root := func(record *model.BackupRecord) *model.BackupRecord {
+		for record != nil && record.Type == model.BackupTypeIncremental {
+			record = byID[record.BaseID]
+		}
+		return record
+	}
+	latest := make(map[uint]time.Time)
+	kept := make(map[uint]bool)
+	for i := range records {
+		record := &records[i]
+		if record.Status != model.BackupStatusSuccess {
+			continue
+		}
+		if r := root(record); r != nil && record.CreatedAt.After(latest[r.ID]) {
+			latest[r.ID] = record.CreatedAt
+		}
+		if record.Type != model.BackupTypeIncremental && len(kept) < keepCount {
+			kept[record.ID] = true
+		}
+	}
+
+	removed := 0
+	for i := range records {
+		record := &records[i]
+		if record.Status == model.BackupStatusRunning {
+			continue
+		}
+
+		expired := record.CreatedAt.Before(cutoff)
+		if record.Status == model.BackupStatusSuccess {
+			r := root(record)
+			switch {
+			case r == nil:
+				// 基于的全量备份已删除，无法恢复
+			case kept[r.ID]:
+				expired = false
+			default:
+				expired = latest[r.ID].Before(cutoff)
+			}
+		}
+		if !expired {
+			continue
+		}
+
+		if err := s.removeBackup(record); err != nil {
+			return removed, err
+		}
+		removed++
+	}
+	return removed, nil
+}
+
+// OpenBackup 打开备份文件用于下载，调用方负责关闭
+func (s *SystemService) OpenBackup(id uint) (*model.BackupRecord, *os.File, error) {
+	record, err := s.findBackup(id)
+	if err != nil {
+		return nil, nil, err
+	}
+	if record.Status != model.BackupStatusSuccess {
+		return nil, nil, ErrBackupNotReady
+	}
+	file, err := os.Open(record.Path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, nil, ErrBackupNotFound
+		}
+		return nil, nil, err
+	}
+	return record, file, nil
+}
+
+func (s *SystemService) findBackup(id uint) (*model.BackupRecord, error) {
+	var record model.BackupRecord
+	if err := s.db.First(&record, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrBackupNotFound
+		}
+		return nil, err
+	}
+	return &record, nil
+}
+
+// 删除备份文件和备份记录
+func (s *SystemService) removeBackup(record *model.BackupRecord) error {
+	if err := os.Remove(record.Path); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	return s.db.Delete(record).Error
+}
+
+// backupProgress 按已完成的步骤(导出一张表或一个文件)更新备份进度，全部完成后由备份结果置为100
+type backupProgress struct {
+	db      *gorm.DB
+	record  *model.BackupRecord
+	total   int
+	done    int
+	percent int
+}
+
+func newBackupProgress(db *gorm.DB, record *model.BackupRecord, total int) *backupProgress {
+	return &backupProgress{db: db, record: record, total: total + 1}
+}
+
+func (p *backupProgress) step() {
+	p.done++
+	percent := p.done * 100 / p.total
+	if percent == p.percent {
+		return
+	}
+	p.percent = percent
+	p.record.Progress = percent
+	p.db.Model(p.record).UpdateColumn("progress", percent)
+}
+
+// countingWriter 统计写入的字节数
+type countingWriter struct {
+	w io.Writer
+	n int64
+}
+
+func (w *countingWriter) Write(p []byte) (int, error) {
+	n, err := w.w.Write(p)
+	w.n += int64(n)
+	return n, err
+}
+
+func writeTarEntry(tw *tar.Writer, name string, size int64, r io.Reader) error {
+	header := &tar.Header{
+		Name:    name,
+		Mode:    0o644,
+		Size:    size,
+		ModTime: time.Now(),
+	}
+	if err := tw.WriteHeader(header); err != nil {
+		return err
+	}
+	_, err := io.CopyN(tw, r, size)
+	return err
+}
+
+func copyFileToTar(tw *tar.Writer, name, path string) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	info, err := file.Stat()
+	if err != nil {
+		return err
+	}
+	return writeTarEntry(tw, name, info.Size(), file)
+}
@@ -1,6 +1,7 @@
 package service
 
 import (
+	"context"
 	"errors"
 
 	"github.com/lemonoa/LemonOA-Go/model"
@@ -17,6 +18,11 @@ func NewBasicAdminService(db *gorm.DB) *BasicAdminService {
 	return &BasicAdminService{db: db}
 }
 
+// WithContext 返回使用请求上下文的服务，写入数据时由数据库回调填充当前操作用户
+func (s *BasicAdminService) WithContext(ctx context.Context) *BasicAdminService {
+	return &BasicAdminService{db: s.db.WithContext(ctx)}
+}
+
 // GetAssetCategoryList 获取资产分类列表
 func (s *BasicAdminService) GetAssetCategoryList(parentID *uint) ([]model.AssetCategory, error) {
 	var categories []model.AssetCategory
@@ -1,6 +1,7 @@
 package service
 
 import (
+	"context"
 	"errors"
 
 	"github.com/lemonoa/LemonOA-Go/model"
@@ -17,6 +18,11 @@ func NewBasicCommonService(db *gorm.DB) *BasicCommonService {
 	return &BasicCommonService{db: db}
 }
 
+// WithContext 返回使用请求上下文的服务，写入数据时由数据库回调填充当前操作用户
+func (s *BasicCommonService) WithContext(ctx context.Context) *BasicCommonService {
+	return &BasicCommonService{db: s.db.WithContext(ctx)}
+}
+
 // GetEnterpriseList 获取企业主体列表
 func (s *BasicCommonService) GetEnterpriseList() ([]model.Enterprise, error) {
 	var enterprises []model.Enterprise
@@ -1,6 +1,7 @@
 package service
 
 import (
+	"context"
 	"errors"
 
 	"github.com/lemonoa/LemonOA-Go/model"
@@ -17,6 +18,11 @@ func NewBasicContractService(db *gorm.DB) *BasicContractService {
 	return &BasicContractService{db: db}
 }
 
+// WithContext 返回使用请求上下文的服务，写入数据时由数据库回调填充当前操作用户
+func (s *BasicContractService) WithContext(ctx context.Context) *BasicContractService {
+	return &BasicContractService{db: s.db.WithContext(ctx)}
+}
+
 // GetContractCategoryList 获取合同分类列表
 func (s *BasicContractService) GetContractCategoryList() ([]model.ContractCategory, error) {
 	var categories []model.ContractCategory
@@ -1,6 +1,7 @@
 package service
 
 import (
+	"context"
 	"errors"
 
 	"github.com/lemonoa/LemonOA-Go/model"
@@ -17,6 +18,11 @@ func NewBasicCustomerService(db *gorm.DB) *BasicCustomerService {
 	return &BasicCustomerService{db: db}
 }
 
+// WithContext 返回使用请求上下文的服务，写入数据时由数据库回调填充当前操作用户
+func (s *BasicCustomerService) WithContext(ctx context.Context) *BasicCustomerService {
+	return &BasicCustomerService{db: s.db.WithContext(ctx)}
+}
+
 // GetCustomerLevelList 获取客户等级列表
 func (s *BasicCustomerService) GetCustomerLevelList() ([]model.CustomerLevel, error) {
 	var levels []model.CustomerLevel
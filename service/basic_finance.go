@@ -1,6 +1,7 @@
 package service
 
 import (
+	"context"
 	"errors"
 
 	"github.com/lemonoa/LemonOA-Go/model"
@@ -17,6 +18,11 @@ func NewBasicFinanceService(db *gorm.DB) *BasicFinanceService {
 	return &BasicFinanceService{db: db}
 }
 
+// WithContext 返回使用请求上下文的服务，写入数据时由数据库回调填充当前操作用户
+func (s *BasicFinanceService) WithContext(ctx context.Context) *BasicFinanceService {
+	return &BasicFinanceService{db: s.db.WithContext(ctx)}
+}
+
 // GetExpenseTypeList 获取费用类型列表
 func (s *BasicFinanceService) GetExpenseTypeList(parentID *uint) ([]model.ExpenseType, error) {
 	var types []model.ExpenseType
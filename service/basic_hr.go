@@ -1,6 +1,7 @@
 package service
 
 import (
+	"context"
 	"errors"
 
 	"github.com/lemonoa/LemonOA-Go/model"
@@ -17,6 +18,11 @@ func NewBasicHRService(db *gorm.DB) *BasicHRService {
 	return &BasicHRService{db: db}
 }
 
+// WithContext 返回使用请求上下文的服务，写入数据时由数据库回调填充当前操作用户
+func (s *BasicHRService) WithContext(ctx context.Context) *BasicHRService {
+	return &BasicHRService{db: s.db.WithContext(ctx)}
+}
+
 // GetRewardPunishmentList 获取奖惩项目列表
 func (s *BasicHRService) GetRewardPunishmentList(rewardType int) ([]model.RewardPunishment, error) {
 	var items []model.RewardPunishment
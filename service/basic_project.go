@@ -1,6 +1,7 @@
 package service
 
 import (
+	"context"
 	"errors"
 
 	"github.com/lemonoa/LemonOA-Go/model"
@@ -17,6 +18,11 @@ func NewBasicProjectService(db *gorm.DB) *BasicProjectService {
 	return &BasicProjectService{db: db}
 }
 
+// WithContext 返回使用请求上下文的服务，写入数据时由数据库回调填充当前操作用户
+func (s *BasicProjectService) WithContext(ctx context.Context) *BasicProjectService {
+	return &BasicProjectService{db: s.db.WithContext(ctx)}
+}
+
 // GetProjectStageList 获取项目阶段列表
 func (s *BasicProjectService) GetProjectStageList() ([]model.ProjectStage, error) {
 	var stages []model.ProjectStage
@@ -0,0 +1,253 @@
+package service
+
+import (
+	"strings"
+
+	"github.com/lemonoa/LemonOA-Go/model"
+
+	"gorm.io/gorm"
+)
+
+// DataScope 当前用户可访问的数据范围，由用户所有角色的数据权限合并得出
+type DataScope struct {
+	All           bool   `json:"all"`            // 可访问全部数据
+	UserID        uint   `json:"user_id"`        // 当前用户ID
+	EmployeeID    uint   `json:"employee_id"`    // 当前用户关联的员工ID，未关联时为0
+	DepartmentIDs []uint `json:"department_ids"` // 可访问的部门ID
+}
+
+// DataScopeColumns 业务表中用于数据权限过滤的字段，为空表示该表没有对应字段
+type DataScopeColumns struct {
+	User       string // 所属用户ID字段
+	Creator    string // 创建人ID字段
+	Employee   string // 所属员工ID字段
+	Department string // 所属部门ID字段
+}
+
+// 各业务表的数据权限字段，带表名前缀避免联表查询时字段冲突
+var (
+	attendanceRecordScope   = DataScopeColumns{Employee: "attendance_records.employee_id"}
+	leaveApplicationScope   = DataScopeColumns{Employee: "leave_applications.employee_id"}
+	overtimeScope           = DataScopeColumns{Employee: "overtime_applications.employee_id"}
+	businessTripScope       = DataScopeColumns{Employee: "business_trip_applications.employee_id"}
+	employeeArchiveScope    = DataScopeColumns{Employee: "employee_archives.employee_id"}
+	rewardPunishmentScope   = DataScopeColumns{Employee: "reward_punishment_records.employee_id"}
+	careRecordScope         = DataScopeColumns{Employee: "care_records.employee_id"}
+	transferScope           = DataScopeColumns{Employee: "transfers.employee_id"}
+	resignationScope        = DataScopeColumns{Employee: "resignations.employee_id"}
+	contractScope           = DataScopeColumns{Employee: "contracts.employee_id"}
+	probationScope          = DataScopeColumns{Employee: "probations.employee_id"}
+	assetScope              = DataScopeColumns{User: "assets.user_id", Creator: "assets.created_by", Department: "assets.department_id"}
+	assetRepairScope        = DataScopeColumns{Creator: "asset_repairs.created_by"}
+	assetBorrowScope        = DataScopeColumns{User: "asset_borrows.borrower_id", Creator: "asset_borrows.created_by", Department: "asset_borrows.department_id"}
+	assetDisposalScope      = DataScopeColumns{Creator: "asset_disposals.created_by"}
+	vehicleScope            = DataScopeColumns{User: "vehicles.user_id", Creator: "vehicles.created_by", Department: "vehicles.department_id"}
+	vehicleApplicationScope = DataScopeColumns{User: "vehicle_applications.user_id", Creator: "vehicle_applications.created_by", Department: "vehicle_applications.department_id"}
+	sealScope               = DataScopeColumns{User: "seals.keeper_id", Creator: "seals.created_by"}
+	sealApplicationScope    = DataScopeColumns{User: "seal_applications.user_id", Department: "seal_applications.department_id"}
+	sealRecordScope         = DataScopeColumns{Creator: "seal_records.created_by"}
+	documentScope           = DataScopeColumns{User: "documents.draft_user_id", Creator: "documents.created_by", Department: "documents.draft_dept_id"}
+	approvalRecordScope     = DataScopeColumns{User: "approval_records.applicant_id"}
+	attachmentScope         = DataScopeColumns{Creator: "attachments.uploaded_by"}
+)
+
+// GetDataScope 获取用户的数据权限范围，与用户权限一同缓存，返回副本可放心修改
+func GetDataScope(db *gorm.DB, userID uint) (*DataScope, error) {
+	perms, err := GetUserEffectivePermissions(db, userID)
+	if err != nil {
+		return nil, err
+	}
+	scope := *perms.Scope
+	scope.DepartmentIDs = append([]uint(nil), perms.Scope.DepartmentIDs...)
+	return &scope, nil
+}
+
+// 计算用户的数据权限范围
+// 超级管理员或任一角色为全部数据时不做限制；本人的数据始终可见
+func loadDataScope(db *gorm.DB, userID uint, superAdmin bool) (*DataScope, error) {
+	scope := &DataScope{UserID: userID}
+
+	var user model.User
+	if err := db.Select("id", "employee_id").First(&user, userID).Error; err != nil {
+		return nil, err
+	}
+
+	var departmentID uint
+	if user.EmployeeID != nil {
+		scope.EmployeeID = *user.EmployeeID
+		var employee model.Employee
+		err := db.Select("id", "department_id").First(&employee, *user.EmployeeID).Error
+		if err != nil && err != gorm.ErrRecordNotFound {
+			return nil, err
+		}
+		departmentID = employee.DepartmentID
+	}
+
+	if superAdmin {
+		scope.All = true
+		return scope, nil
+	}
+
+	var roles []model.Role
+	if err := db.Model(&model.Role{}).
+		Joins("INNER JOIN user_roles ON user_roles.role_id = roles.id AND user_roles.deleted_at IS NULL").
+		Where("user_roles.user_id = ? AND roles.status = 1", userID).
+		Find(&roles).Error; err != nil {
+		return nil, err
+	}
+
+	departments := make(map[uint]struct{})
+	var customRoleIDs []uint
+	for _, role := range roles {
+		switch role.DataScope {
+		case model.DataScopeAll:
+			scope.All = true
+			return scope, nil
+		case model.DataScopeCustom:
+			customRoleIDs = append(customRoleIDs, role.ID)
+		case model.DataScopeDeptTree:
+			if departmentID > 0 {
+				ids, err := departmentSubtree(db, departmentID)
+				if err != nil {
+					return nil, err
+				}
+				for _, id := range ids {
+					departments[id] = struct{}{}
+				}
+			}
+		case model.DataScopeDept:
+			if departmentID > 0 {
+				departments[departmentID] = struct{}{}
+			}
+		}
+	}
+
+	if len(customRoleIDs) > 0 {
+		var ids []uint
+		if err := db.Model(&model.RoleDepartment{}).Where("role_id IN ?", customRoleIDs).Pluck("department_id", &ids).Error; err != nil {
+			return nil, err
+		}
+		for _, id := range ids {
+			departments[id] = struct{}{}
+		}
+	}
+
+	for id := range departments {
+		scope.DepartmentIDs = append(scope.DepartmentIDs, id)
+	}
+	return scope, nil
+}
+
+// Filter 返回按数据权限过滤的GORM scope，scope为nil表示内部调用，不做限制
+func (ds *DataScope) Filter(cols DataScopeColumns) func(*gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		if ds == nil || ds.All {
+			return db
+		}
+
+		var conds []string
+		var args []interface{}
+
+		// 本人的数据
+		if cols.User != "" {
+			conds = append(conds, cols.User+" = ?")
+			args = append(args, ds.UserID)
+		}
+		if cols.Creator != "" {
+			conds = append(conds, cols.Creator+" = ?")
+			args = append(args, ds.UserID)
+		}
+		if cols.Employee != "" && ds.EmployeeID > 0 {
+			conds = append(conds, cols.Employee+" = ?")
+			args = append(args, ds.EmployeeID)
+		}
+
+		// 可访问部门的数据，优先使用表中的部门字段，否则通过员工或用户关联到部门
+		if len(ds.DepartmentIDs) > 0 {
+			switch {
+			case cols.Department != "":
+				conds = append(conds, cols.Department+" IN ?")
+				args = append(args, ds.DepartmentIDs)
+			case cols.Employee != "":
+				conds = append(conds, cols.Employee+" IN (SELECT id FROM employees WHERE department_id IN ? AND deleted_at IS NULL)")
+				args = append(args, ds.DepartmentIDs)
+			case cols.User != "" || cols.Creator != "":
+				column := cols.User
+				if column == "" {
+					column = cols.Creator
+				}
+				conds = append(conds, column+" IN (SELECT users.id FROM users INNER JOIN employees ON employees.id = users.employee_id WHERE employees.department_id IN ? AND employees.deleted_at IS NULL)")
+				args = append(args, ds.DepartmentIDs)
+			}
+		}
+
+		if len(conds) == 0 {
+			return db.Where("1 = 0")
+		}
+		return db.Where("("+strings.Join(conds, " OR ")+")", args...)
+	}
+}
+
+// 获取部门及其所有下级部门ID
+func departmentSubtree(db *gorm.DB, rootID uint) ([]uint, error) {
+	var departments []model.Department
+	if err := db.Select("id", "parent_id").Find(&departments).Error; err != nil {
+		return nil, err
+	}
+
+	children := make(map[uint][]uint)
+	for _, d := range departments {
+		if d.ParentID != nil {
+			children[*d.ParentID] = append(children[*d.ParentID], d.ID)
+		}
+	}
+
+	ids := []uint{rootID}
+	visited := map[uint]bool{rootID: true}
+	for i := 0; i < len(ids); i++ {
+		for _, child := range children[ids[i]] {
+			if !visited[child] {
+				visited[child] = true
+				ids = append(ids, child)
+			}
+		}
+	}
+	return ids, nil
+}
+
+// GetRoleDepartments 获取角色自定义数据权限的部门ID
+func (s *AuthService) GetRoleDepartments(roleID uint) ([]uint, error) {
+	var ids []uint
+	err := s.db.Model(&model.RoleDepartment{}).Where("role_id = ?", roleID).Pluck("department_id", &ids).Error
+	return ids, err
+}
+
+// UpdateRoleDepartments 更新角色自定义数据权限的部门
+func (s *AuthService) UpdateRoleDepartments(roleID uint, departmentIDs []uint, operatorID uint) error {
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.First(&model.Role{}, roleID).Error; err != nil {
+			return err
+		}
+
+		if err := tx.Where("role_id = ?", roleID).Delete(&model.RoleDepartment{}).Error; err != nil {
+			return err
+		}
+
+		for _, did := range departmentIDs {
+			rd := &model.RoleDepartment{
+				RoleID:       roleID,
+				DepartmentID: did,
+				CreatedBy:    operatorID,
+			}
+			if err := tx.Create(rd).Error; err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	return invalidateRolePermissions(s.db, roleID)
+}
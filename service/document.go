@@ -1,6 +1,7 @@
 package service
 
 import (
+	"context"
 	"errors"
 	"time"
 
@@ -17,12 +18,18 @@ func NewDocumentService(db *gorm.DB) *DocumentService {
 	return &DocumentService{db: db}
 }
 
+// WithContext 返回使用请求上下文的服务，写入数据时由数据库回调填充当前操作用户
+func (s *DocumentService) WithContext(ctx context.Context) *DocumentService {
+	return &DocumentService{db: s.db.WithContext(ctx)}
+}
+
 // GetDocumentList 获取公文列表
-func (s *DocumentService) GetDocumentList(typeID uint, status int, keyword string, page, pageSize int) ([]model.Document, int64, error) {
+func (s *DocumentService) GetDocumentList(typeID uint, status int, keyword string, page, pageSize int, scope *DataScope) ([]model.Document, int64, error) {
 	var documents []model.Document
 	var total int64
 
-	query := s.db.Model(&model.Document{})
+	query := s.db.Model(&model.Document{}).
+		Scopes(scope.Filter(documentScope))
 	if typeID > 0 {
 		query = query.Where("type_id = ?", typeID)
 	}
@@ -47,9 +54,9 @@ func (s *DocumentService) GetDocumentList(typeID uint, status int, keyword strin
 }
 
 // GetDocumentByID 根据ID获取公文
-func (s *DocumentService) GetDocumentByID(id uint) (*model.Document, error) {
+func (s *DocumentService) GetDocumentByID(id uint, scope *DataScope) (*model.Document, error) {
 	var document model.Document
-	err := s.db.First(&document, id).Error
+	err := s.db.Scopes(scope.Filter(documentScope)).First(&document, id).Error
 	if err != nil {
 		return nil, err
 	}
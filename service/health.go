@@ -0,0 +1,56 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/lemonoa/LemonOA-Go/database"
+)
+
+// HealthCheck 单项依赖的检查结果
+type HealthCheck struct {
+	Name     string `json:"name"`
+	OK       bool   `json:"ok"`
+	Error    string `json:"error,omitempty"`
+	Duration int64  `json:"duration"` // 毫秒
+}
+
+type healthChecker struct {
+	name  string
+	check func(ctx context.Context) error
+}
+
+// CheckReadiness 检查数据库，以及已启用的Redis和附件存储是否可用，全部可用时返回true
+func CheckReadiness(ctx context.Context) ([]HealthCheck, bool) {
+	checks := []healthChecker{{"database", pingDatabase}}
+	if database.Redis != nil {
+		checks = append(checks, healthChecker{"redis", func(ctx context.Context) error {
+			return database.Redis.Ping(ctx).Err()
+		}})
+	}
+	if fileStorage != nil {
+		checks = append(checks, healthChecker{"storage", fileStorage.Ping})
+	}
+
+	results := make([]HealthCheck, 0, len(checks))
+	ready := true
+	for _, c := range checks {
+		start := time.Now()
+		err := c.check(ctx)
+		result := HealthCheck{Name: c.name, OK: err == nil, Duration: time.Since(start).Milliseconds()}
+		if err != nil {
+			result.Error = err.Error()
+			ready = false
+		}
+		results = append(results, result)
+	}
+	return results, ready
+}
+
+func pingDatabase(ctx context.Context) error {
+	sqlDB, err := database.DB.DB()
+	if err != nil {
+		return err
+	}
+	return sqlDB.PingContext(ctx)
+}
@@ -0,0 +1,35 @@
+package service
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/lemonoa/LemonOA-Go/database"
+
+	"github.com/spf13/viper"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// 创建临时SQLite数据库并建表，使用测试用的加密密钥和低成本的密码哈希
+func newTestDB(t *testing.T) *gorm.DB {
+	t.Helper()
+	viper.Set("encryption.active_key", "k1")
+	viper.Set("encryption.keys", map[string]string{"k1": "MDEyMzQ1Njc4OWFiY2RlZjAxMjM0NTY3ODlhYmNkZWY="})
+	viper.Set("password_hash.algorithm", "bcrypt")
+	viper.Set("password_hash.bcrypt_cost", 4)
+	t.Cleanup(viper.Reset)
+	if err := database.InitFieldEncryption(); err != nil {
+		t.Fatal(err)
+	}
+
+	db, err := gorm.Open(sqlite.Open(filepath.Join(t.TempDir(), "test.db")), &gorm.Config{Logger: logger.Discard})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := db.AutoMigrate(database.Models()...); err != nil {
+		t.Fatal(err)
+	}
+	return db
+}
@@ -1,6 +1,7 @@
 package service
 
 import (
+	"context"
 	"errors"
 
 	"github.com/lemonoa/LemonOA-Go/model"
@@ -16,6 +17,11 @@ func NewHRService(db *gorm.DB) *HRService {
 	return &HRService{db: db}
 }
 
+// WithContext 返回使用请求上下文的服务，写入数据时由数据库回调填充当前操作用户
+func (s *HRService) WithContext(ctx context.Context) *HRService {
+	return &HRService{db: s.db.WithContext(ctx)}
+}
+
 // GetPositionList 获取岗位职称列表
 func (s *HRService) GetPositionList() ([]model.Position, error) {
 	var positions []model.Position
@@ -61,9 +67,9 @@ func (s *HRService) DeletePosition(id uint) error {
 }
 
 // GetEmployeeArchiveByEmployeeID 根据员工ID获取档案
-func (s *HRService) GetEmployeeArchiveByEmployeeID(employeeID uint) (*model.EmployeeArchive, error) {
+func (s *HRService) GetEmployeeArchiveByEmployeeID(employeeID uint, scope *DataScope) (*model.EmployeeArchive, error) {
 	var archive model.EmployeeArchive
-	err := s.db.Where("employee_id = ?", employeeID).First(&archive).Error
+	err := s.db.Scopes(scope.Filter(employeeArchiveScope)).Where("employee_id = ?", employeeID).First(&archive).Error
 	if err != nil {
 		return nil, err
 	}
@@ -102,6 +108,7 @@ func (s *HRService) UpdateEmployeeArchive(archive *model.EmployeeArchive) error
 		return errors.New("employee not found")
 	}
 
+	ClearMaskedSensitive(archive)
 	return s.db.Model(archive).Updates(archive).Error
 }
 
@@ -111,12 +118,13 @@ func (s *HRService) DeleteEmployeeArchive(id uint) error {
 }
 
 // GetEmployeeArchiveList 获取员工档案列表
-func (s *HRService) GetEmployeeArchiveList(departmentID uint, page, pageSize int) ([]model.EmployeeArchive, int64, error) {
+func (s *HRService) GetEmployeeArchiveList(departmentID uint, page, pageSize int, scope *DataScope) ([]model.EmployeeArchive, int64, error) {
 	var archives []model.EmployeeArchive
 	var total int64
 
 	query := s.db.Model(&model.EmployeeArchive{}).
-		Joins("LEFT JOIN employees ON employee_archives.employee_id = employees.id")
+		Joins("LEFT JOIN employees ON employee_archives.employee_id = employees.id").
+		Scopes(scope.Filter(employeeArchiveScope))
 
 	if departmentID > 0 {
 		query = query.Where("employees.department_id = ?", departmentID)
@@ -136,11 +144,12 @@ func (s *HRService) GetEmployeeArchiveList(departmentID uint, page, pageSize int
 }
 
 // GetRewardPunishmentRecordList 获取奖惩记录列表
-func (s *HRService) GetRewardPunishmentRecordList(employeeID uint, page, pageSize int) ([]model.RewardPunishmentRecord, int64, error) {
+func (s *HRService) GetRewardPunishmentRecordList(employeeID uint, page, pageSize int, scope *DataScope) ([]model.RewardPunishmentRecord, int64, error) {
 	var records []model.RewardPunishmentRecord
 	var total int64
 
-	query := s.db.Model(&model.RewardPunishmentRecord{})
+	query := s.db.Model(&model.RewardPunishmentRecord{}).
+		Scopes(scope.Filter(rewardPunishmentScope))
 	if employeeID > 0 {
 		query = query.Where("employee_id = ?", employeeID)
 	}
@@ -159,9 +168,9 @@ func (s *HRService) GetRewardPunishmentRecordList(employeeID uint, page, pageSiz
 }
 
 // GetRewardPunishmentRecordByID 根据ID获取奖惩记录
-func (s *HRService) GetRewardPunishmentRecordByID(id uint) (*model.RewardPunishmentRecord, error) {
+func (s *HRService) GetRewardPunishmentRecordByID(id uint, scope *DataScope) (*model.RewardPunishmentRecord, error) {
 	var record model.RewardPunishmentRecord
-	err := s.db.First(&record, id).Error
+	err := s.db.Scopes(scope.Filter(rewardPunishmentScope)).First(&record, id).Error
 	if err != nil {
 		return nil, err
 	}
@@ -212,11 +221,12 @@ func (s *HRService) DeleteRewardPunishmentRecord(id uint) error {
 }
 
 // GetCareRecordList 获取关怀记录列表
-func (s *HRService) GetCareRecordList(employeeID uint, page, pageSize int) ([]model.CareRecord, int64, error) {
+func (s *HRService) GetCareRecordList(employeeID uint, page, pageSize int, scope *DataScope) ([]model.CareRecord, int64, error) {
 	var records []model.CareRecord
 	var total int64
 
-	query := s.db.Model(&model.CareRecord{})
+	query := s.db.Model(&model.CareRecord{}).
+		Scopes(scope.Filter(careRecordScope))
 	if employeeID > 0 {
 		query = query.Where("employee_id = ?", employeeID)
 	}
@@ -235,9 +245,9 @@ func (s *HRService) GetCareRecordList(employeeID uint, page, pageSize int) ([]mo
 }
 
 // GetCareRecordByID 根据ID获取关怀记录
-func (s *HRService) GetCareRecordByID(id uint) (*model.CareRecord, error) {
+func (s *HRService) GetCareRecordByID(id uint, scope *DataScope) (*model.CareRecord, error) {
 	var record model.CareRecord
-	err := s.db.First(&record, id).Error
+	err := s.db.Scopes(scope.Filter(careRecordScope)).First(&record, id).Error
 	if err != nil {
 		return nil, err
 	}
@@ -288,11 +298,12 @@ func (s *HRService) DeleteCareRecord(id uint) error {
 }
 
 // GetTransferList 获取人事调动列表
-func (s *HRService) GetTransferList(employeeID uint, status int, page, pageSize int) ([]model.Transfer, int64, error) {
+func (s *HRService) GetTransferList(employeeID uint, status int, page, pageSize int, scope *DataScope) ([]model.Transfer, int64, error) {
 	var transfers []model.Transfer
 	var total int64
 
-	query := s.db.Model(&model.Transfer{})
+	query := s.db.Model(&model.Transfer{}).
+		Scopes(scope.Filter(transferScope))
 	if employeeID > 0 {
 		query = query.Where("employee_id = ?", employeeID)
 	}
@@ -314,9 +325,9 @@ func (s *HRService) GetTransferList(employeeID uint, status int, page, pageSize
 }
 
 // GetTransferByID 根据ID获取人事调动
-func (s *HRService) GetTransferByID(id uint) (*model.Transfer, error) {
+func (s *HRService) GetTransferByID(id uint, scope *DataScope) (*model.Transfer, error) {
 	var transfer model.Transfer
-	err := s.db.First(&transfer, id).Error
+	err := s.db.Scopes(scope.Filter(transferScope)).First(&transfer, id).Error
 	if err != nil {
 		return nil, err
 	}
@@ -404,7 +415,8 @@ func (s *HRService) DeleteTransfer(id uint) error {
 
 // ApproveTransfer 审批通过人事调动
 func (s *HRService) ApproveTransfer(id uint) error {
-	return s.db.Transaction(func(tx *gorm.DB) error {
+	var employeeID uint
+	err := s.db.Transaction(func(tx *gorm.DB) error {
 		var transfer model.Transfer
 		if err := tx.First(&transfer, id).Error; err != nil {
 			return err
@@ -423,8 +435,13 @@ func (s *HRService) ApproveTransfer(id uint) error {
 			return err
 		}
 
+		employeeID = transfer.EmployeeID
 		return nil
 	})
+	if err != nil {
+		return err
+	}
+	return invalidateEmployeePermissions(s.db, employeeID)
 }
 
 // RejectTransfer 审批驳回人事调动
@@ -433,11 +450,12 @@ func (s *HRService) RejectTransfer(id uint) error {
 }
 
 // GetResignationList 获取离职档案列表
-func (s *HRService) GetResignationList(employeeID uint, status int, page, pageSize int) ([]model.Resignation, int64, error) {
+func (s *HRService) GetResignationList(employeeID uint, status int, page, pageSize int, scope *DataScope) ([]model.Resignation, int64, error) {
 	var resignations []model.Resignation
 	var total int64
 
-	query := s.db.Model(&model.Resignation{})
+	query := s.db.Model(&model.Resignation{}).
+		Scopes(scope.Filter(resignationScope))
 	if employeeID > 0 {
 		query = query.Where("employee_id = ?", employeeID)
 	}
@@ -459,9 +477,9 @@ func (s *HRService) GetResignationList(employeeID uint, status int, page, pageSi
 }
 
 // GetResignationByID 根据ID获取离职档案
-func (s *HRService) GetResignationByID(id uint) (*model.Resignation, error) {
+func (s *HRService) GetResignationByID(id uint, scope *DataScope) (*model.Resignation, error) {
 	var resignation model.Resignation
-	err := s.db.First(&resignation, id).Error
+	err := s.db.Scopes(scope.Filter(resignationScope)).First(&resignation, id).Error
 	if err != nil {
 		return nil, err
 	}
@@ -543,11 +561,12 @@ func (s *HRService) RejectResignation(id uint) error {
 }
 
 // GetContractList 获取员工合同列表
-func (s *HRService) GetContractList(employeeID uint, status int, page, pageSize int) ([]model.Contract, int64, error) {
+func (s *HRService) GetContractList(employeeID uint, status int, page, pageSize int, scope *DataScope) ([]model.Contract, int64, error) {
 	var contracts []model.Contract
 	var total int64
 
-	query := s.db.Model(&model.Contract{})
+	query := s.db.Model(&model.Contract{}).
+		Scopes(scope.Filter(contractScope))
 	if employeeID > 0 {
 		query = query.Where("employee_id = ?", employeeID)
 	}
@@ -569,9 +588,9 @@ func (s *HRService) GetContractList(employeeID uint, status int, page, pageSize
 }
 
 // GetContractByID 根据ID获取员工合同
-func (s *HRService) GetContractByID(id uint) (*model.Contract, error) {
+func (s *HRService) GetContractByID(id uint, scope *DataScope) (*model.Contract, error) {
 	var contract model.Contract
-	err := s.db.First(&contract, id).Error
+	err := s.db.Scopes(scope.Filter(contractScope)).First(&contract, id).Error
 	if err != nil {
 		return nil, err
 	}
@@ -633,11 +652,12 @@ func (s *HRService) TerminateContract(id uint) error {
 }
 
 // GetProbationList 获取转正列表
-func (s *HRService) GetProbationList(employeeID uint, status int, page, pageSize int) ([]model.Probation, int64, error) {
+func (s *HRService) GetProbationList(employeeID uint, status int, page, pageSize int, scope *DataScope) ([]model.Probation, int64, error) {
 	var probations []model.Probation
 	var total int64
 
-	query := s.db.Model(&model.Probation{})
+	query := s.db.Model(&model.Probation{}).
+		Scopes(scope.Filter(probationScope))
 	if employeeID > 0 {
 		query = query.Where("employee_id = ?", employeeID)
 	}
@@ -659,9 +679,9 @@ func (s *HRService) GetProbationList(employeeID uint, status int, page, pageSize
 }
 
 // GetProbationByID 根据ID获取转正
-func (s *HRService) GetProbationByID(id uint) (*model.Probation, error) {
+func (s *HRService) GetProbationByID(id uint, scope *DataScope) (*model.Probation, error) {
 	var probation model.Probation
-	err := s.db.First(&probation, id).Error
+	err := s.db.Scopes(scope.Filter(probationScope)).First(&probation, id).Error
 	if err != nil {
 		return nil, err
 	}
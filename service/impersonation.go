@@ -0,0 +1,129 @@
+package service
+
+import (
+	"errors"
+	"time"
+
+	"github.com/lemonoa/LemonOA-Go/model"
+
+	"github.com/dgrijalva/jwt-go"
+	"github.com/spf13/viper"
+	"gorm.io/gorm"
+)
+
+// 操作日志中模拟登录的模块名
+const ImpersonationLogModule = "impersonation"
+
+var (
+	ErrImpersonationForbidden = errors.New("只有超级管理员可以模拟用户登录")
+	ErrImpersonationTarget    = errors.New("不能模拟该用户登录")
+	ErrImpersonationDenied    = errors.New("模拟登录期间不允许该操作")
+)
+
+// ImpersonationToken 模拟登录令牌，不签发刷新令牌，过期后需重新申请
+type ImpersonationToken struct {
+	AccessToken string      `json:"access_token"`
+	ExpiresIn   int64       `json:"expires_in"` // 访问令牌有效期(秒)
+	User        *model.User `json:"user"`       // 被模拟的用户
+}
+
+// Impersonate 超级管理员以目标用户的身份签发限时访问令牌，令牌同时携带实际操作人
+// 令牌挂在操作人当前的会话上，操作人退出登录或会话被终止时模拟登录随之失效
+func (s *AuthService) Impersonate(operatorID uint, sessionID string, targetID uint, reason, ip, userAgent string) (*ImpersonationToken, error) {
+	perms, err := GetUserEffectivePermissions(s.db, operatorID)
+	if err != nil {
+		return nil, err
+	}
+	// 使用API密钥访问时没有会话，不能模拟登录
+	if !perms.SuperAdmin || sessionID == "" {
+		return nil, ErrImpersonationForbidden
+	}
+
+	var target model.User
+	if err := s.db.First(&target, targetID).Error; err != nil {
+		return nil, err
+	}
+	if target.ID == operatorID || target.Type == model.UserTypeService {
+		return nil, ErrImpersonationTarget
+	}
+	if target.Status != 1 {
+		return nil, errors.New("用户已被禁用")
+	}
+	// 不能模拟其他超级管理员，避免操作记录归属不清
+	targetPerms, err := GetUserEffectivePermissions(s.db, target.ID)
+	if err != nil {
+		return nil, err
+	}
+	if targetPerms.SuperAdmin {
+		return nil, ErrImpersonationTarget
+	}
+
+	expire := viper.GetInt64("impersonation.expire")
+	if expire <= 0 {
+		expire = 1800
+	}
+	token, err := s.generateImpersonationToken(operatorID, target.ID, sessionID, time.Duration(expire)*time.Second)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.db.Create(&model.OperationLog{
+		UserID:             operatorID,
+		ImpersonatedUserID: target.ID,
+		Module:             ImpersonationLogModule,
+		Action:             "start",
+		Method:             "POST",
+		Params:             reason,
+		IP:                 ip,
+		UserAgent:          userAgent,
+	}).Error; err != nil {
+		return nil, err
+	}
+
+	return &ImpersonationToken{
+		AccessToken: token,
+		ExpiresIn:   expire,
+		User:        &target,
+	}, nil
+}
+
+// 生成模拟登录的JWT token，user_id为被模拟的用户，impersonator_id为实际操作人
+func (s *AuthService) generateImpersonationToken(operatorID, targetID uint, sessionID string, expire time.Duration) (string, error) {
+	jti, err := randomHex(16)
+	if err != nil {
+		return "", err
+	}
+
+	now := time.Now()
+	claims := jwt.MapClaims{
+		"user_id":         targetID,
+		"impersonator_id": operatorID,
+		"sid":             sessionID,
+		"jti":             jti,
+		"iat":             now.Unix(),
+		"exp":             now.Add(expire).Unix(),
+	}
+
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+	return token.SignedString([]byte(viper.GetString("jwt.secret")))
+}
+
+// ValidateImpersonator 校验模拟登录的实际操作人仍为有效的超级管理员，且令牌签发后未被吊销
+func ValidateImpersonator(db *gorm.DB, impersonatorID uint, issuedAt time.Time) error {
+	state, err := loadTokenUserState(db, impersonatorID)
+	if err != nil {
+		return ErrImpersonationForbidden
+	}
+	if state.Status != 1 || (state.TokenRevokedAt != nil && issuedAt.Unix() < state.TokenRevokedAt.Unix()) {
+		return ErrImpersonationForbidden
+	}
+
+	perms, err := GetUserEffectivePermissions(db, impersonatorID)
+	if err != nil {
+		return err
+	}
+	if !perms.SuperAdmin {
+		return ErrImpersonationForbidden
+	}
+	return nil
+}
@@ -0,0 +1,95 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/lemonoa/LemonOA-Go/model"
+
+	"github.com/spf13/viper"
+	"gorm.io/gorm"
+)
+
+// 内置的定时任务
+func init() {
+	RegisterJob("backup", `数据备份，参数如 {"type": 1, "include_files": true}，type 1:全量 2:增量`, backupJob)
+	RegisterJob("prune_backups", "按保留策略清理旧备份", pruneBackupsJob)
+	RegisterJob("clean_expired_uploads", "清理过期未完成的分片上传", cleanExpiredUploadsJob)
+	RegisterJob("clean_expired_tokens", "清理已过期的吊销令牌和单点登录状态", cleanExpiredTokensJob)
+	RegisterJob("clean_task_runs", `清理定时任务运行记录，参数如 {"days": 30}，默认保留 scheduler.history_days 天`, cleanTaskRunsJob)
+}
+
+// 解析任务参数，参数为空时使用默认值
+func parseJobParams(params string, v interface{}) error {
+	if params == "" {
+		return nil
+	}
+	if err := json.Unmarshal([]byte(params), v); err != nil {
+		return fmt.Errorf("invalid params: %w", err)
+	}
+	return nil
+}
+
+func backupJob(ctx context.Context, db *gorm.DB, params string) (string, error) {
+	var opts BackupOptions
+	if err := parseJobParams(params, &opts); err != nil {
+		return "", err
+	}
+
+	record, err := NewSystemService(db).Backup(opts)
+	if err != nil {
+		return "", err
+	}
+	if record.Status != model.BackupStatusSuccess {
+		return "", fmt.Errorf("backup %s failed: %s", record.Name, record.Message)
+	}
+	return fmt.Sprintf("备份完成: %s，%d张表，%d条记录，%d个文件，%d字节",
+		record.Name, record.TableCount, record.RowCount, record.FileCount, record.Size), nil
+}
+
+func pruneBackupsJob(ctx context.Context, db *gorm.DB, params string) (string, error) {
+	removed, err := NewSystemService(db).PruneBackups()
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("删除了%d个旧备份", removed), nil
+}
+
+func cleanExpiredUploadsJob(ctx context.Context, db *gorm.DB, params string) (string, error) {
+	NewSystemService(db).cleanExpiredUploads()
+	return "", nil
+}
+
+func cleanExpiredTokensJob(ctx context.Context, db *gorm.DB, params string) (string, error) {
+	now := time.Now()
+	revoked := db.Where("expires_at < ?", now).Delete(&model.RevokedToken{})
+	if revoked.Error != nil {
+		return "", revoked.Error
+	}
+	states := db.Where("expires_at < ?", now).Delete(&model.OIDCLoginState{})
+	if states.Error != nil {
+		return "", states.Error
+	}
+	return fmt.Sprintf("删除了%d个吊销令牌，%d个单点登录状态", revoked.RowsAffected, states.RowsAffected), nil
+}
+
+func cleanTaskRunsJob(ctx context.Context, db *gorm.DB, params string) (string, error) {
+	opts := struct {
+		Days int `json:"days"`
+	}{Days: viper.GetInt("scheduler.history_days")}
+	if err := parseJobParams(params, &opts); err != nil {
+		return "", err
+	}
+	if opts.Days <= 0 {
+		opts.Days = 30
+	}
+
+	result := db.Where("started_at < ? AND status <> ?", time.Now().AddDate(0, 0, -opts.Days), model.TaskRunRunning).
+		Delete(&model.ScheduledTaskRun{})
+	if result.Error != nil {
+		return "", result.Error
+	}
+	return fmt.Sprintf("删除了%d条运行记录", result.RowsAffected), nil
+}
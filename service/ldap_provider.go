@@ -0,0 +1,278 @@
+package service
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"log/slog"
+	"net"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/lemonoa/LemonOA-Go/logging"
+	"github.com/lemonoa/LemonOA-Go/model"
+
+	"github.com/go-ldap/ldap/v3"
+	"github.com/spf13/viper"
+	"gorm.io/gorm"
+)
+
+// ldapGroupMapping LDAP组到系统角色的映射
+type ldapGroupMapping struct {
+	Group string   `mapstructure:"group"` // 组的DN，不区分大小写
+	Roles []string `mapstructure:"roles"` // 角色编码
+}
+
+// ldapConfig LDAP认证配置，对应 config.yaml 中的 ldap 节点
+type ldapConfig struct {
+	URL                string
+	StartTLS           bool
+	InsecureSkipVerify bool
+	Timeout            time.Duration
+	BindDN             string
+	BindPassword       string
+	BaseDN             string
+	UserFilter         string
+	UsernameAttr       string
+	RealNameAttr       string
+	EmailAttr          string
+	MobileAttr         string
+	GroupAttr          string
+	GroupMappings      []ldapGroupMapping
+	DefaultRoles       []string
+	SyncOnLogin        bool
+}
+
+func loadLDAPConfig() ldapConfig {
+	cfg := ldapConfig{
+		URL:                viper.GetString("ldap.url"),
+		StartTLS:           viper.GetBool("ldap.start_tls"),
+		InsecureSkipVerify: viper.GetBool("ldap.insecure_skip_verify"),
+		Timeout:            time.Duration(viper.GetInt("ldap.timeout")) * time.Second,
+		BindDN:             viper.GetString("ldap.bind_dn"),
+		BindPassword:       viper.GetString("ldap.bind_password"),
+		BaseDN:             viper.GetString("ldap.base_dn"),
+		UserFilter:         viper.GetString("ldap.user_filter"),
+		UsernameAttr:       viper.GetString("ldap.attributes.username"),
+		RealNameAttr:       viper.GetString("ldap.attributes.real_name"),
+		EmailAttr:          viper.GetString("ldap.attributes.email"),
+		MobileAttr:         viper.GetString("ldap.attributes.mobile"),
+		GroupAttr:          viper.GetString("ldap.attributes.groups"),
+		DefaultRoles:       viper.GetStringSlice("ldap.default_roles"),
+		SyncOnLogin:        viper.GetBool("ldap.sync_on_login"),
+	}
+	if err := viper.UnmarshalKey("ldap.group_mappings", &cfg.GroupMappings); err != nil {
+		slog.Error("LDAP组映射配置错误", "error", err)
+	}
+
+	if cfg.Timeout <= 0 {
+		cfg.Timeout = 5 * time.Second
+	}
+	if cfg.UserFilter == "" {
+		cfg.UserFilter = "(sAMAccountName=%s)"
+	}
+	if cfg.RealNameAttr == "" {
+		cfg.RealNameAttr = "displayName"
+	}
+	if cfg.EmailAttr == "" {
+		cfg.EmailAttr = "mail"
+	}
+	if cfg.MobileAttr == "" {
+		cfg.MobileAttr = "mobile"
+	}
+	if cfg.GroupAttr == "" {
+		cfg.GroupAttr = "memberOf"
+	}
+	return cfg
+}
+
+// 根据用户所属的LDAP组计算角色编码，没有匹配的组时使用默认角色
+func (c ldapConfig) roleCodes(groups []string) []string {
+	seen := make(map[string]bool)
+	var codes []string
+	for _, group := range groups {
+		for _, mapping := range c.GroupMappings {
+			if !strings.EqualFold(strings.TrimSpace(mapping.Group), strings.TrimSpace(group)) {
+				continue
+			}
+			for _, code := range mapping.Roles {
+				if !seen[code] {
+					seen[code] = true
+					codes = append(codes, code)
+				}
+			}
+		}
+	}
+	if len(codes) == 0 {
+		return c.DefaultRoles
+	}
+	return codes
+}
+
+// ldapDial 建立LDAP连接，可替换为连接测试用的LDAP服务
+var ldapDial = func(rawURL string, timeout time.Duration) (ldap.Client, error) {
+	return ldap.DialURL(rawURL, ldap.DialWithDialer(&net.Dialer{Timeout: timeout}))
+}
+
+// ldapAuthProvider LDAP/AD认证：先用服务账号查找用户DN，再以用户DN和密码绑定
+type ldapAuthProvider struct{}
+
+func (*ldapAuthProvider) Name() string {
+	return model.UserSourceLDAP
+}
+
+func (p *ldapAuthProvider) Authenticate(s *AuthService, user *model.User, username, password string) (*model.User, error) {
+	// 本地账号和服务账号不走LDAP认证
+	if user != nil && user.Source != model.UserSourceLDAP {
+		return nil, ErrInvalidCredentials
+	}
+	// 空密码会被LDAP服务器当作匿名绑定而成功，必须拒绝
+	if username == "" || password == "" {
+		return nil, ErrInvalidCredentials
+	}
+
+	cfg := loadLDAPConfig()
+	if cfg.URL == "" || cfg.BaseDN == "" {
+		return nil, ErrInvalidCredentials
+	}
+
+	entry, err := p.bind(s.db.Statement.Context, cfg, username, password)
+	if err != nil {
+		return nil, err
+	}
+	return s.syncLDAPUser(cfg, user, username, entry)
+}
+
+// 查找并以用户身份绑定，返回用户条目
+func (p *ldapAuthProvider) bind(ctx context.Context, cfg ldapConfig, username, password string) (*ldap.Entry, error) {
+	conn, err := ldapDial(cfg.URL, cfg.Timeout)
+	if err != nil {
+		logging.FromContext(ctx).ErrorContext(ctx, "连接LDAP服务失败", "error", err)
+		return nil, ErrAuthProviderUnavailable
+	}
+	defer conn.Close()
+	conn.SetTimeout(cfg.Timeout)
+
+	if cfg.StartTLS {
+		serverName := ""
+		if u, err := url.Parse(cfg.URL); err == nil {
+			serverName = u.Hostname()
+		}
+		if err := conn.StartTLS(&tls.Config{ServerName: serverName, InsecureSkipVerify: cfg.InsecureSkipVerify}); err != nil {
+			logging.FromContext(ctx).ErrorContext(ctx, "LDAP StartTLS失败", "error", err)
+			return nil, ErrAuthProviderUnavailable
+		}
+	}
+
+	if cfg.BindDN != "" {
+		if err := conn.Bind(cfg.BindDN, cfg.BindPassword); err != nil {
+			logging.FromContext(ctx).ErrorContext(ctx, "LDAP服务账号绑定失败", "error", err)
+			return nil, ErrAuthProviderUnavailable
+		}
+	}
+
+	attributes := []string{cfg.RealNameAttr, cfg.EmailAttr, cfg.MobileAttr, cfg.GroupAttr}
+	if cfg.UsernameAttr != "" {
+		attributes = append(attributes, cfg.UsernameAttr)
+	}
+	request := ldap.NewSearchRequest(
+		cfg.BaseDN,
+		ldap.ScopeWholeSubtree,
+		ldap.NeverDerefAliases,
+		2,
+		int(cfg.Timeout/time.Second),
+		false,
+		fmt.Sprintf(cfg.UserFilter, ldap.EscapeFilter(username)),
+		attributes,
+		nil,
+	)
+	result, err := conn.Search(request)
+	if err != nil && !ldap.IsErrorWithCode(err, ldap.LDAPResultSizeLimitExceeded) {
+		logging.FromContext(ctx).ErrorContext(ctx, "LDAP查找用户失败", "error", err)
+		return nil, ErrAuthProviderUnavailable
+	}
+	// 找不到或匹配到多个用户都视为认证失败
+	if result == nil || len(result.Entries) != 1 {
+		return nil, ErrInvalidCredentials
+	}
+	entry := result.Entries[0]
+
+	if err := conn.Bind(entry.DN, password); err != nil {
+		if ldap.IsErrorWithCode(err, ldap.LDAPResultInvalidCredentials) {
+			return nil, ErrInvalidCredentials
+		}
+		logging.FromContext(ctx).ErrorContext(ctx, "LDAP用户绑定失败", "error", err)
+		return nil, ErrAuthProviderUnavailable
+	}
+
+	return entry, nil
+}
+
+// 首次登录时创建本地用户并按LDAP组分配角色，之后按配置在每次登录时同步资料和角色
+func (s *AuthService) syncLDAPUser(cfg ldapConfig, user *model.User, username string, entry *ldap.Entry) (*model.User, error) {
+	if cfg.UsernameAttr != "" {
+		if value := entry.GetAttributeValue(cfg.UsernameAttr); value != "" && !strings.EqualFold(value, username) {
+			return nil, ErrInvalidCredentials
+		}
+	}
+
+	// 以结构体更新，空值不覆盖本地资料；手机号为加密字段，不能用map更新
+	profile := model.User{
+		RealName: entry.GetAttributeValue(cfg.RealNameAttr),
+		Email:    entry.GetAttributeValue(cfg.EmailAttr),
+		Mobile:   entry.GetAttributeValue(cfg.MobileAttr),
+	}
+
+	created := user == nil
+	if !created && !cfg.SyncOnLogin {
+		return user, nil
+	}
+
+	var roleIDs []uint
+	if codes := cfg.roleCodes(entry.GetAttributeValues(cfg.GroupAttr)); len(codes) > 0 {
+		if err := s.db.Model(&model.Role{}).Where("code IN ?", codes).Pluck("id", &roleIDs).Error; err != nil {
+			return nil, err
+		}
+	}
+
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		if created {
+			// LDAP用户的密码不保存在本地，设置一个随机密码使其无法通过本地认证登录
+			random, err := randomHex(32)
+			if err != nil {
+				return err
+			}
+			hash, err := HashPassword(random)
+			if err != nil {
+				return err
+			}
+			now := time.Now()
+			user = &model.User{
+				Username:          username,
+				Password:          hash,
+				RealName:          profile.RealName,
+				Email:             profile.Email,
+				Mobile:            profile.Mobile,
+				Source:            model.UserSourceLDAP,
+				Status:            1,
+				PasswordChangedAt: &now,
+			}
+			if err := tx.Create(user).Error; err != nil {
+				return err
+			}
+		} else if profile.RealName != "" || profile.Email != "" || profile.Mobile != "" {
+			if err := tx.Model(user).Updates(&profile).Error; err != nil {
+				return err
+			}
+		}
+
+		return s.replaceUserRoles(tx, user.ID, roleIDs, 0)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	InvalidateUserPermissions(user.ID)
+	return user, nil
+}
@@ -0,0 +1,282 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/lemonoa/LemonOA-Go/model"
+
+	"github.com/go-ldap/ldap/v3"
+	"github.com/spf13/viper"
+	"gorm.io/gorm"
+)
+
+const (
+	testLDAPBindDN   = "cn=svc,dc=example,dc=com"
+	testLDAPAliceDN  = "uid=alice,ou=people,dc=example,dc=com"
+	testLDAPAdminsDN = "cn=admins,ou=groups,dc=example,dc=com"
+)
+
+// fakeLDAP 进程内的LDAP服务，只实现认证用到的方法
+type fakeLDAP struct {
+	ldap.Client
+	passwords map[string]string // DN到密码
+	entries   []*ldap.Entry
+	searchErr error
+	binds     []string
+	filter    string
+}
+
+func (f *fakeLDAP) SetTimeout(time.Duration) {}
+
+func (f *fakeLDAP) Close() error {
+	return nil
+}
+
+func (f *fakeLDAP) Bind(dn, password string) error {
+	f.binds = append(f.binds, dn)
+	if expected, ok := f.passwords[dn]; ok && expected == password {
+		return nil
+	}
+	return ldap.NewError(ldap.LDAPResultInvalidCredentials, errors.New("invalid credentials"))
+}
+
+func (f *fakeLDAP) Search(request *ldap.SearchRequest) (*ldap.SearchResult, error) {
+	f.filter = request.Filter
+	return &ldap.SearchResult{Entries: f.entries}, f.searchErr
+}
+
+func aliceEntry(groups ...string) *ldap.Entry {
+	return ldap.NewEntry(testLDAPAliceDN, map[string][]string{
+		"uid":         {"alice"},
+		"displayName": {"Alice"},
+		"mail":        {"alice@example.com"},
+		"mobile":      {"13800001234"},
+		"memberOf":    groups,
+	})
+}
+
+func newFakeLDAP(entries ...*ldap.Entry) *fakeLDAP {
+	return &fakeLDAP{
+		passwords: map[string]string{
+			testLDAPBindDN:  "svc-secret",
+			testLDAPAliceDN: "alice-secret",
+		},
+		entries: entries,
+	}
+}
+
+// 使用测试LDAP配置，并将连接替换为 conn；err 不为空时模拟连接失败
+func useFakeLDAP(t *testing.T, conn *fakeLDAP, err error) *int {
+	t.Helper()
+	viper.Set("ldap.url", "ldap://ldap.example.com:389")
+	viper.Set("ldap.bind_dn", testLDAPBindDN)
+	viper.Set("ldap.bind_password", "svc-secret")
+	viper.Set("ldap.base_dn", "dc=example,dc=com")
+	viper.Set("ldap.user_filter", "(uid=%s)")
+	viper.Set("ldap.attributes.username", "uid")
+	viper.Set("ldap.group_mappings", []map[string]interface{}{
+		{"group": testLDAPAdminsDN, "roles": []string{"admin"}},
+	})
+	viper.Set("ldap.default_roles", []string{"staff"})
+	viper.Set("ldap.sync_on_login", true)
+
+	dials := 0
+	original := ldapDial
+	ldapDial = func(string, time.Duration) (ldap.Client, error) {
+		dials++
+		if err != nil {
+			return nil, err
+		}
+		return conn, nil
+	}
+	t.Cleanup(func() {
+		ldapDial = original
+		viper.Reset()
+	})
+	return &dials
+}
+
+// 创建使用临时SQLite数据库的认证服务，预置 admin 和 staff 角色
+func newLDAPTestService(t *testing.T) *AuthService {
+	t.Helper()
+	db := newTestDB(t)
+	for _, code := range []string{"admin", "staff"} {
+		if err := db.Create(&model.Role{Name: code, Code: code, Status: 1}).Error; err != nil {
+			t.Fatal(err)
+		}
+	}
+	return NewAuthService(db)
+}
+
+func userRoleCodes(t *testing.T, db *gorm.DB, userID uint) []string {
+	t.Helper()
+	var codes []string
+	err := db.Model(&model.Role{}).
+		Joins("JOIN user_roles ON user_roles.role_id = roles.id").
+		Where("user_roles.user_id = ?", userID).
+		Pluck("roles.code", &codes).Error
+	if err != nil {
+		t.Fatal(err)
+	}
+	sort.Strings(codes)
+	return codes
+}
+
+func TestLDAPBind(t *testing.T) {
+	tests := []struct {
+		name     string
+		password string
+		bindPass string // 服务账号密码
+		wantErr  error
+	}{
+		{name: "success", password: "alice-secret", bindPass: "svc-secret"},
+		{name: "wrong password", password: "wrong", bindPass: "svc-secret", wantErr: ErrInvalidCredentials},
+		{name: "service account rejected", password: "alice-secret", bindPass: "wrong", wantErr: ErrAuthProviderUnavailable},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			conn := newFakeLDAP(aliceEntry())
+			useFakeLDAP(t, conn, nil)
+			viper.Set("ldap.bind_password", tt.bindPass)
+
+			entry, err := (&ldapAuthProvider{}).bind(context.Background(), loadLDAPConfig(), "alice", tt.password)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("bind() error = %v, want %v", err, tt.wantErr)
+			}
+			if tt.wantErr == nil && entry.DN != testLDAPAliceDN {
+				t.Errorf("bind() DN = %q, want %q", entry.DN, testLDAPAliceDN)
+			}
+			// 服务账号绑定失败时不会查找用户
+			if tt.wantErr != ErrAuthProviderUnavailable && conn.filter != "(uid=alice)" {
+				t.Errorf("search filter = %q, want (uid=alice)", conn.filter)
+			}
+		})
+	}
+}
+
+func TestLDAPBindEscapesUsername(t *testing.T) {
+	conn := newFakeLDAP()
+	useFakeLDAP(t, conn, nil)
+
+	_, err := (&ldapAuthProvider{}).bind(context.Background(), loadLDAPConfig(), "*)(uid=*", "x")
+	if !errors.Is(err, ErrInvalidCredentials) {
+		t.Fatalf("bind() error = %v, want %v", err, ErrInvalidCredentials)
+	}
+	if conn.filter != `(uid=\2a\29\28uid=\2a)` {
+		t.Errorf("search filter = %q, want escaped username", conn.filter)
+	}
+}
+
+func TestLDAPAuthenticateRejectsEmptyPassword(t *testing.T) {
+	dials := useFakeLDAP(t, newFakeLDAP(aliceEntry()), nil)
+
+	_, err := (&ldapAuthProvider{}).Authenticate(nil, nil, "alice", "")
+	if !errors.Is(err, ErrInvalidCredentials) {
+		t.Fatalf("Authenticate() error = %v, want %v", err, ErrInvalidCredentials)
+	}
+	if *dials != 0 {
+		t.Errorf("empty password reached the LDAP server")
+	}
+}
+
+func TestLDAPBindAmbiguousSearchResult(t *testing.T) {
+	tests := []struct {
+		name      string
+		searchErr error
+	}{
+		{name: "two entries"},
+		{name: "size limit exceeded", searchErr: ldap.NewError(ldap.LDAPResultSizeLimitExceeded, errors.New("size limit exceeded"))},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			duplicate := ldap.NewEntry("uid=alice,ou=contractors,dc=example,dc=com", map[string][]string{"uid": {"alice"}})
+			conn := newFakeLDAP(aliceEntry(), duplicate)
+			conn.searchErr = tt.searchErr
+			useFakeLDAP(t, conn, nil)
+
+			_, err := (&ldapAuthProvider{}).bind(context.Background(), loadLDAPConfig(), "alice", "alice-secret")
+			if !errors.Is(err, ErrInvalidCredentials) {
+				t.Fatalf("bind() error = %v, want %v", err, ErrInvalidCredentials)
+			}
+			for _, dn := range conn.binds {
+				if dn != testLDAPBindDN {
+					t.Errorf("bound as %q although the search was ambiguous", dn)
+				}
+			}
+		})
+	}
+}
+
+func TestLDAPAuthenticateProvisionsUser(t *testing.T) {
+	tests := []struct {
+		name   string
+		groups []string
+		want   []string
+	}{
+		{name: "mapped group", groups: []string{"CN=Admins,OU=Groups,DC=example,DC=com"}, want: []string{"admin"}},
+		{name: "default roles", groups: []string{"cn=others,ou=groups,dc=example,dc=com"}, want: []string{"staff"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			useFakeLDAP(t, newFakeLDAP(aliceEntry(tt.groups...)), nil)
+			s := newLDAPTestService(t)
+
+			user, err := (&ldapAuthProvider{}).Authenticate(s, nil, "alice", "alice-secret")
+			if err != nil {
+				t.Fatalf("Authenticate() error = %v", err)
+			}
+
+			var stored model.User
+			if err := s.db.First(&stored, user.ID).Error; err != nil {
+				t.Fatal(err)
+			}
+			if stored.Username != "alice" || stored.Source != model.UserSourceLDAP {
+				t.Errorf("user = %q/%q, want alice/%s", stored.Username, stored.Source, model.UserSourceLDAP)
+			}
+			if stored.RealName != "Alice" || stored.Email != "alice@example.com" || stored.Mobile != "13800001234" {
+				t.Errorf("profile = %q/%q/%q, want synced from LDAP", stored.RealName, stored.Email, stored.Mobile)
+			}
+			if got := userRoleCodes(t, s.db, user.ID); len(got) != len(tt.want) || got[0] != tt.want[0] {
+				t.Errorf("roles = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLDAPAuthenticateRejectsLocalUser(t *testing.T) {
+	dials := useFakeLDAP(t, newFakeLDAP(aliceEntry()), nil)
+	local := &model.User{ID: 1, Username: "alice", Source: model.UserSourceLocal}
+
+	_, err := (&ldapAuthProvider{}).Authenticate(nil, local, "alice", "alice-secret")
+	if !errors.Is(err, ErrInvalidCredentials) {
+		t.Fatalf("Authenticate() error = %v, want %v", err, ErrInvalidCredentials)
+	}
+	if *dials != 0 {
+		t.Errorf("local account was authenticated against LDAP")
+	}
+}
+
+func TestLDAPServerUnavailable(t *testing.T) {
+	t.Run("dial", func(t *testing.T) {
+		useFakeLDAP(t, nil, ldap.NewError(ldap.ErrorNetwork, errors.New("connection refused")))
+
+		_, err := (&ldapAuthProvider{}).bind(context.Background(), loadLDAPConfig(), "alice", "alice-secret")
+		if !errors.Is(err, ErrAuthProviderUnavailable) {
+			t.Fatalf("bind() error = %v, want %v", err, ErrAuthProviderUnavailable)
+		}
+	})
+	t.Run("search", func(t *testing.T) {
+		conn := newFakeLDAP()
+		conn.searchErr = ldap.NewError(ldap.ErrorNetwork, errors.New("connection reset"))
+		useFakeLDAP(t, conn, nil)
+
+		_, err := (&ldapAuthProvider{}).bind(context.Background(), loadLDAPConfig(), "alice", "alice-secret")
+		if !errors.Is(err, ErrAuthProviderUnavailable) {
+			t.Fatalf("bind() error = %v, want %v", err, ErrAuthProviderUnavailable)
+		}
+	})
+}
@@ -0,0 +1,154 @@
+package service
+
+import (
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/lemonoa/LemonOA-Go/model"
+
+	"github.com/spf13/viper"
+	"gorm.io/gorm"
+)
+
+var (
+	// ErrInvalidCredentials 用户名不存在与密码错误统一返回该错误，避免用户名被枚举
+	ErrInvalidCredentials = errors.New("用户名或密码错误")
+	ErrAccountLocked      = errors.New("登录失败次数过多，账号已被临时锁定，请稍后再试")
+	ErrTooManyAttempts    = errors.New("登录尝试过于频繁，请稍后再试")
+)
+
+// loginGuardConfig 登录防暴力破解配置
+type loginGuardConfig struct {
+	maxFailures     int           // 账号连续失败多少次后锁定
+	lockDuration    time.Duration // 首次锁定时长，之后每次锁定翻倍
+	maxLockDuration time.Duration // 锁定时长上限
+	ipMaxFailures   int           // 单个IP在统计窗口内允许的失败次数
+	ipWindow        time.Duration // IP失败次数统计窗口
+}
+
+func loadLoginGuardConfig() loginGuardConfig {
+	c := loginGuardConfig{
+		maxFailures:     viper.GetInt("login.max_failures"),
+		lockDuration:    time.Duration(viper.GetInt("login.lock_duration")) * time.Second,
+		maxLockDuration: time.Duration(viper.GetInt("login.max_lock_duration")) * time.Second,
+		ipMaxFailures:   viper.GetInt("login.ip_max_failures"),
+		ipWindow:        time.Duration(viper.GetInt("login.ip_window")) * time.Second,
+	}
+	if c.maxFailures <= 0 {
+		c.maxFailures = 5
+	}
+	if c.lockDuration <= 0 {
+		c.lockDuration = 15 * time.Minute
+	}
+	if c.maxLockDuration <= 0 {
+		c.maxLockDuration = 24 * time.Hour
+	}
+	if c.ipMaxFailures <= 0 {
+		c.ipMaxFailures = 20
+	}
+	if c.ipWindow <= 0 {
+		c.ipWindow = 15 * time.Minute
+	}
+	return c
+}
+
+// lockDurationFor 计算第failures次失败后的锁定时长，每满maxFailures次锁定一次，时长按次数指数增长
+func (c loginGuardConfig) lockDurationFor(failures int) time.Duration {
+	if failures < c.maxFailures || failures%c.maxFailures != 0 {
+		return 0
+	}
+
+	d := c.lockDuration
+	for i := 1; i < failures/c.maxFailures; i++ {
+		d *= 2
+		if d >= c.maxLockDuration {
+			return c.maxLockDuration
+		}
+	}
+	if d > c.maxLockDuration {
+		return c.maxLockDuration
+	}
+	return d
+}
+
+var (
+	dummyPasswordOnce sync.Once
+	dummyPasswordHash string
+)
+
+// 用户不存在时也执行一次完整的哈希校验，使响应耗时与密码错误时一致
+func verifyDummyPassword(password string) {
+	dummyPasswordOnce.Do(func() {
+		dummyPasswordHash, _ = HashPassword("lemonoa-dummy-password")
+	})
+	VerifyPassword(password, dummyPasswordHash, "")
+}
+
+// 检查IP在统计窗口内的登录失败次数是否超限
+func (s *AuthService) checkIPThrottle(cfg loginGuardConfig, ip string) error {
+	var count int64
+	if err := s.db.Model(&model.LoginLog{}).
+		Where("ip = ? AND status = ? AND created_at > ?", ip, 2, time.Now().Add(-cfg.ipWindow)).
+		Count(&count).Error; err != nil {
+		return err
+	}
+	if count >= int64(cfg.ipMaxFailures) {
+		return ErrTooManyAttempts
+	}
+	return nil
+}
+
+// 记录一次密码错误，达到阈值时锁定账号
+func (s *AuthService) recordLoginFailure(cfg loginGuardConfig, user *model.User) error {
+	return s.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(user).UpdateColumn("login_failures", gorm.Expr("login_failures + ?", 1)).Error; err != nil {
+			return err
+		}
+		if err := tx.Model(user).Select("login_failures").First(user).Error; err != nil {
+			return err
+		}
+
+		if d := cfg.lockDurationFor(user.LoginFailures); d > 0 {
+			lockedUntil := time.Now().Add(d)
+			user.LockedUntil = &lockedUntil
+			return tx.Model(user).UpdateColumn("locked_until", &lockedUntil).Error
+		}
+		return nil
+	})
+}
+
+// GetLockedUsers 获取当前处于锁定状态的用户列表
+func (s *AuthService) GetLockedUsers(page, pageSize int) ([]model.User, int64, error) {
+	var users []model.User
+	var total int64
+
+	query := s.db.Model(&model.User{}).Where("locked_until > ?", time.Now())
+
+	err := query.Count(&total).Error
+	if err != nil {
+		return nil, 0, err
+	}
+
+	err = query.Order("locked_until DESC").Offset((page - 1) * pageSize).Limit(pageSize).Find(&users).Error
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return users, total, nil
+}
+
+// UnlockUser 解除账号锁定并清零失败次数
+func (s *AuthService) UnlockUser(id uint) error {
+	result := s.db.Model(&model.User{}).Where("id = ?", id).Updates(map[string]interface{}{
+		"login_failures": 0,
+		"locked_until":   nil,
+	})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
@@ -1,6 +1,7 @@
 package service
 
 import (
+	"context"
 	"errors"
 	"time"
 
@@ -17,6 +18,11 @@ func NewMeetingService(db *gorm.DB) *MeetingService {
 	return &MeetingService{db: db}
 }
 
+// WithContext 返回使用请求上下文的服务，写入数据时由数据库回调填充当前操作用户
+func (s *MeetingService) WithContext(ctx context.Context) *MeetingService {
+	return &MeetingService{db: s.db.WithContext(ctx)}
+}
+
 // GetMeetingRoomList 获取会议室列表
 func (s *MeetingService) GetMeetingRoomList(status int, keyword string, page, pageSize int) ([]model.MeetingRoom, int64, error) {
 	var rooms []model.MeetingRoom
@@ -162,7 +168,7 @@ func (s *MeetingService) CreateMeetingReservation(reservation *model.MeetingRese
 	return s.db.Create(reservation).Error
 }
 
-// UpdateMeetingReservation 更新会议室预约
+// UpdateMeetingReservation 更新会议室预约，只能修改本人待审批的预约
 func (s *MeetingService) UpdateMeetingReservation(reservation *model.MeetingReservation) error {
 	if reservation.ID == 0 {
 		return errors.New("reservation id is required")
@@ -201,13 +207,23 @@ func (s *MeetingService) UpdateMeetingReservation(reservation *model.MeetingRese
 		return errors.New("meeting room is already booked during this period")
 	}
 
-	return s.db.Model(reservation).Updates(reservation).Error
+	// 状态只能通过审批、取消接口修改
+	reservation.Status = 0
+
+	result := s.db.Model(reservation).Where("user_id = ? AND status = ?", reservation.UserID, 1).Updates(reservation)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return errors.New("can only update your own pending reservations")
+	}
+	return nil
 }
 
 // DeleteMeetingReservation 删除会议室预约
-func (s *MeetingService) DeleteMeetingReservation(id uint) error {
-	// 只能删除待审批的预约
-	result := s.db.Where("id = ? AND status = ?", id, 1).Delete(&model.MeetingReservation{})
+func (s *MeetingService) DeleteMeetingReservation(id, userID uint) error {
+	// 只能删除本人待审批的预约
+	result := s.db.Where("id = ? AND user_id = ? AND status = ?", id, userID, 1).Delete(&model.MeetingReservation{})
 	if result.Error != nil {
 		return result.Error
 	}
@@ -255,11 +271,11 @@ func (s *MeetingService) RejectMeetingReservation(id, approverID uint) error {
 	return nil
 }
 
-// CancelMeetingReservation 取消会议室预约
-func (s *MeetingService) CancelMeetingReservation(id uint, reason string) error {
+// CancelMeetingReservation 取消会议室预约，只能取消本人的预约
+func (s *MeetingService) CancelMeetingReservation(id, userID uint, reason string) error {
 	now := time.Now()
 	result := s.db.Model(&model.MeetingReservation{}).
-		Where("id = ? AND status IN (1,2)", id).
+		Where("id = ? AND user_id = ? AND status IN (1,2)", id, userID).
 		Updates(map[string]interface{}{
 			"status":        4,
 			"cancel_reason": reason,
@@ -274,11 +290,11 @@ func (s *MeetingService) CancelMeetingReservation(id uint, reason string) error
 	return nil
 }
 
-// CheckInMeeting 会议签到
-func (s *MeetingService) CheckInMeeting(id uint) error {
+// CheckInMeeting 会议签到，由预约人签到
+func (s *MeetingService) CheckInMeeting(id, userID uint) error {
 	now := time.Now()
 	result := s.db.Model(&model.MeetingReservation{}).
-		Where("id = ? AND status = ? AND check_in_time IS NULL", id, 2).
+		Where("id = ? AND user_id = ? AND status = ? AND check_in_time IS NULL", id, userID, 2).
 		Update("check_in_time", &now)
 	if result.Error != nil {
 		return result.Error
@@ -289,11 +305,11 @@ func (s *MeetingService) CheckInMeeting(id uint) error {
 	return nil
 }
 
-// CheckOutMeeting 会议签退
-func (s *MeetingService) CheckOutMeeting(id uint) error {
+// CheckOutMeeting 会议签退，由预约人签退
+func (s *MeetingService) CheckOutMeeting(id, userID uint) error {
 	now := time.Now()
 	result := s.db.Model(&model.MeetingReservation{}).
-		Where("id = ? AND status = ? AND check_in_time IS NOT NULL AND check_out_time IS NULL", id, 2).
+		Where("id = ? AND user_id = ? AND status = ? AND check_in_time IS NOT NULL AND check_out_time IS NULL", id, userID, 2).
 		Update("check_out_time", &now)
 	if result.Error != nil {
 		return result.Error
@@ -1,6 +1,7 @@
 package service
 
 import (
+	"context"
 	"errors"
 	"time"
 
@@ -17,6 +18,11 @@ func NewNoticeService(db *gorm.DB) *NoticeService {
 	return &NoticeService{db: db}
 }
 
+// WithContext 返回使用请求上下文的服务，写入数据时由数据库回调填充当前操作用户
+func (s *NoticeService) WithContext(ctx context.Context) *NoticeService {
+	return &NoticeService{db: s.db.WithContext(ctx)}
+}
+
 // GetNoticeList 获取公告列表
 func (s *NoticeService) GetNoticeList(typeID uint, status int, keyword string, page, pageSize int) ([]model.Notice, int64, error) {
 	var notices []model.Notice
@@ -156,13 +162,7 @@ func (s *NoticeService) ReadNotice(noticeID, userID uint) error {
 // GetUnreadNoticeCount 获取未读公告数量
 func (s *NoticeService) GetUnreadNoticeCount(userID uint) (int64, error) {
 	var count int64
-	err := s.db.Raw(`
-		SELECT COUNT(*) FROM notices n
-		WHERE n.status = 2
-		AND n.id NOT IN (
-			SELECT notice_id FROM notice_reads
-			WHERE user_id = ?
-		)
-	`, userID).Count(&count).Error
+	read := s.db.Model(&model.NoticeRead{}).Select("notice_id").Where("user_id = ?", userID)
+	err := s.db.Model(&model.Notice{}).Where("status = ? AND id NOT IN (?)", 2, read).Count(&count).Error
 	return count, err
 }
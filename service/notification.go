@@ -1,6 +1,8 @@
 package service
 
 import (
+	"context"
+
 	"github.com/lemonoa/LemonOA-Go/model"
 
 	"gorm.io/gorm"
@@ -14,6 +16,11 @@ func NewNotificationService(db *gorm.DB) *NotificationService {
 	return &NotificationService{db: db}
 }
 
+// WithContext 返回使用请求上下文的服务，写入数据时由数据库回调填充当前操作用户
+func (s *NotificationService) WithContext(ctx context.Context) *NotificationService {
+	return &NotificationService{db: s.db.WithContext(ctx)}
+}
+
 // GetNotificationList 获取消息列表
 func (s *NotificationService) GetNotificationList(userID uint, page, pageSize int) ([]model.Notification, int64, error) {
 	var notifications []model.Notification
@@ -0,0 +1,364 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/lemonoa/LemonOA-Go/logging"
+	"github.com/lemonoa/LemonOA-Go/model"
+
+	"github.com/coreos/go-oidc/v3/oidc"
+	"github.com/spf13/viper"
+	"golang.org/x/oauth2"
+	"gorm.io/gorm"
+)
+
+// 授权请求的有效期，用户需在此时间内完成IdP登录
+const oidcStateExpire = 10 * time.Minute
+
+var (
+	ErrOIDCDisabled     = errors.New("未启用单点登录")
+	ErrOIDCInvalidState = errors.New("单点登录请求无效或已过期，请重新登录")
+	ErrOIDCUserNotFound = errors.New("未找到对应的用户，请联系管理员开通账号")
+	ErrOIDCLoginFailed  = errors.New("单点登录失败")
+)
+
+// oidcConfig OIDC单点登录配置，对应 config.yaml 中的 oidc 节点
+type oidcConfig struct {
+	Enabled       bool
+	Issuer        string
+	ClientID      string
+	ClientSecret  string
+	RedirectURL   string
+	Scopes        []string
+	UsernameClaim string
+	EmailClaim    string
+	NameClaim     string
+	AutoProvision bool
+	DefaultRoles  []string
+	TrustIdPMFA   bool // IdP声明已完成多因素认证时不再要求本地两步验证
+}
+
+func loadOIDCConfig() oidcConfig {
+	cfg := oidcConfig{
+		Enabled:       viper.GetBool("oidc.enabled"),
+		Issuer:        viper.GetString("oidc.issuer"),
+		ClientID:      viper.GetString("oidc.client_id"),
+		ClientSecret:  viper.GetString("oidc.client_secret"),
+		RedirectURL:   viper.GetString("oidc.redirect_url"),
+		Scopes:        viper.GetStringSlice("oidc.scopes"),
+		UsernameClaim: viper.GetString("oidc.claims.username"),
+		EmailClaim:    viper.GetString("oidc.claims.email"),
+		NameClaim:     viper.GetString("oidc.claims.name"),
+		AutoProvision: viper.GetBool("oidc.auto_provision"),
+		DefaultRoles:  viper.GetStringSlice("oidc.default_roles"),
+		TrustIdPMFA:   viper.GetBool("oidc.trust_idp_mfa"),
+	}
+	if len(cfg.Scopes) == 0 {
+		cfg.Scopes = []string{oidc.ScopeOpenID, "profile", "email"}
+	}
+	if cfg.UsernameClaim == "" {
+		cfg.UsernameClaim = "preferred_username"
+	}
+	if cfg.EmailClaim == "" {
+		cfg.EmailClaim = "email"
+	}
+	if cfg.NameClaim == "" {
+		cfg.NameClaim = "name"
+	}
+	return cfg
+}
+
+// 通过发现文档获取的IdP信息，首次使用时加载，加载失败下次重试
+var (
+	oidcProviderMu     sync.Mutex
+	oidcProvider       *oidc.Provider
+	oidcProviderIssuer string
+)
+
+func getOIDCProvider(ctx context.Context, issuer string) (*oidc.Provider, error) {
+	oidcProviderMu.Lock()
+	defer oidcProviderMu.Unlock()
+
+	if oidcProvider != nil && oidcProviderIssuer == issuer {
+		return oidcProvider, nil
+	}
+
+	provider, err := oidc.NewProvider(ctx, issuer)
+	if err != nil {
+		logging.FromContext(ctx).ErrorContext(ctx, "加载OIDC发现文档失败", "issuer", issuer, "error", err)
+		return nil, ErrAuthProviderUnavailable
+	}
+	oidcProvider = provider
+	oidcProviderIssuer = issuer
+	return provider, nil
+}
+
+func (c oidcConfig) oauth2Config(provider *oidc.Provider) *oauth2.Config {
+	return &oauth2.Config{
+		ClientID:     c.ClientID,
+		ClientSecret: c.ClientSecret,
+		RedirectURL:  c.RedirectURL,
+		Endpoint:     provider.Endpoint(),
+		Scopes:       c.Scopes,
+	}
+}
+
+// OIDCAuthorizeURL 生成IdP授权地址，前端跳转到该地址完成登录
+func (s *AuthService) OIDCAuthorizeURL() (string, error) {
+	cfg := loadOIDCConfig()
+	if !cfg.Enabled {
+		return "", ErrOIDCDisabled
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	provider, err := getOIDCProvider(ctx, cfg.Issuer)
+	if err != nil {
+		return "", err
+	}
+
+	state, err := randomHex(16)
+	if err != nil {
+		return "", err
+	}
+	nonce, err := randomHex(16)
+	if err != nil {
+		return "", err
+	}
+	verifier := oauth2.GenerateVerifier()
+
+	// 顺带清理过期的授权请求
+	s.db.Where("expires_at < ?", time.Now()).Delete(&model.OIDCLoginState{})
+
+	if err := s.db.Create(&model.OIDCLoginState{
+		State:        state,
+		Nonce:        nonce,
+		CodeVerifier: verifier,
+		ExpiresAt:    time.Now().Add(oidcStateExpire),
+	}).Error; err != nil {
+		return "", err
+	}
+
+	return cfg.oauth2Config(provider).AuthCodeURL(state,
+		oidc.Nonce(nonce),
+		oauth2.S256ChallengeOption(verifier),
+	), nil
+}
+
+// OIDCCallback 处理IdP回调：用授权码换取ID令牌并校验，匹配或创建用户后签发LemonOA令牌
+func (s *AuthService) OIDCCallback(code, state, ip, userAgent string) (*LoginResult, error) {
+	cfg := loadOIDCConfig()
+	if !cfg.Enabled {
+		return nil, ErrOIDCDisabled
+	}
+
+	// state只能使用一次，以条件删除保证并发回调时只有一个成功
+	var loginState model.OIDCLoginState
+	if err := s.db.Where("state = ?", state).First(&loginState).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, ErrOIDCInvalidState
+		}
+		return nil, err
+	}
+	result := s.db.Where("id = ?", loginState.ID).Delete(&model.OIDCLoginState{})
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	if result.RowsAffected == 0 || time.Now().After(loginState.ExpiresAt) {
+		return nil, ErrOIDCInvalidState
+	}
+
+	ctx, cancel := context.WithTimeout(s.db.Statement.Context, 15*time.Second)
+	defer cancel()
+	provider, err := getOIDCProvider(ctx, cfg.Issuer)
+	if err != nil {
+		return nil, err
+	}
+
+	token, err := cfg.oauth2Config(provider).Exchange(ctx, code, oauth2.VerifierOption(loginState.CodeVerifier))
+	if err != nil {
+		logging.FromContext(ctx).ErrorContext(ctx, "OIDC授权码换取令牌失败", "error", err)
+		return nil, ErrOIDCLoginFailed
+	}
+	rawIDToken, ok := token.Extra("id_token").(string)
+	if !ok || rawIDToken == "" {
+		return nil, ErrOIDCLoginFailed
+	}
+
+	// 校验签名(JWKS)、签发方、受众和有效期
+	idToken, err := provider.Verifier(&oidc.Config{ClientID: cfg.ClientID}).Verify(ctx, rawIDToken)
+	if err != nil {
+		logging.FromContext(ctx).ErrorContext(ctx, "OIDC ID令牌校验失败", "error", err)
+		return nil, ErrOIDCLoginFailed
+	}
+	if idToken.Nonce != loginState.Nonce {
+		return nil, ErrOIDCLoginFailed
+	}
+
+	var claims map[string]interface{}
+	if err := idToken.Claims(&claims); err != nil {
+		return nil, ErrOIDCLoginFailed
+	}
+
+	user, err := s.resolveOIDCUser(cfg, idToken.Issuer, idToken.Subject, claims)
+	if err != nil {
+		if err == ErrOIDCUserNotFound {
+			s.createLoginLog(0, ip, userAgent, 2, "单点登录用户不存在: "+idToken.Subject)
+		}
+		return nil, err
+	}
+
+	if user.Status != 1 {
+		s.createLoginLog(user.ID, ip, userAgent, 2, "用户已被禁用")
+		return nil, errors.New("用户已被禁用")
+	}
+
+	// 只有显式配置信任IdP且 amr 声明包含多因素认证时才跳过本地两步验证
+	if cfg.TrustIdPMFA && oidcMFAPerformed(claims) {
+		pair, err := s.completeLogin(user, ip, userAgent)
+		if err != nil {
+			return nil, err
+		}
+		return &LoginResult{TokenPair: pair}, nil
+	}
+	return s.continueLogin(user, ip, userAgent)
+}
+
+// 表示已完成多因素认证或使用了第二因素的 amr 取值，见 RFC 8176
+var oidcMFAMethods = map[string]bool{
+	"mfa":  true,
+	"otp":  true,
+	"hwk":  true,
+	"swk":  true,
+	"sms":  true,
+	"fpt":  true,
+	"face": true,
+}
+
+// oidcMFAPerformed 判断 ID Token 的 amr 声明是否表明IdP已完成多因素认证
+func oidcMFAPerformed(claims map[string]interface{}) bool {
+	methods, _ := claims["amr"].([]interface{})
+	for _, method := range methods {
+		if name, ok := method.(string); ok && oidcMFAMethods[name] {
+			return true
+		}
+	}
+	return false
+}
+
+// 按签发方和 sub 查找已关联的用户；未关联时只匹配尚未关联的单点登录来源用户，并记录关联关系；
+// 都不存在且允许自动创建时创建用户。本地及LDAP账号不会按用户名或邮箱匹配
+func (s *AuthService) resolveOIDCUser(cfg oidcConfig, issuer, subject string, claims map[string]interface{}) (*model.User, error) {
+	if issuer == "" || subject == "" {
+		return nil, ErrOIDCLoginFailed
+	}
+
+	var user model.User
+	err := s.db.Where("oidc_issuer = ? AND oidc_subject = ?", issuer, subject).First(&user).Error
+	if err == nil {
+		// 服务账号不能通过单点登录
+		if user.Type == model.UserTypeService {
+			return nil, ErrInvalidCredentials
+		}
+		return &user, nil
+	}
+	if err != gorm.ErrRecordNotFound {
+		return nil, err
+	}
+
+	username := oidcClaimString(claims, cfg.UsernameClaim)
+	email := oidcClaimString(claims, cfg.EmailClaim)
+	// 只有IdP明确标记已验证的邮箱才能用于匹配用户
+	if verified, ok := claims["email_verified"].(bool); !ok || !verified {
+		email = ""
+	}
+
+	// 管理员预先开通或关联前自动创建的单点登录用户，首次登录时关联
+	unlinked := s.db.Where("source = ? AND type <> ? AND oidc_subject IS NULL", model.UserSourceOIDC, model.UserTypeService).Session(&gorm.Session{})
+	err = gorm.ErrRecordNotFound
+	if username != "" {
+		err = unlinked.Where("username = ?", username).First(&user).Error
+	}
+	if err == gorm.ErrRecordNotFound && email != "" {
+		err = unlinked.Where("email = ?", email).First(&user).Error
+	}
+
+	switch {
+	case err == nil:
+		result := s.db.Model(&model.User{}).
+			Where("id = ? AND oidc_subject IS NULL", user.ID).
+			Updates(map[string]interface{}{"oidc_issuer": issuer, "oidc_subject": subject})
+		if result.Error != nil {
+			return nil, result.Error
+		}
+		if result.RowsAffected == 0 {
+			return nil, ErrOIDCLoginFailed
+		}
+		user.OIDCIssuer, user.OIDCSubject = &issuer, &subject
+		return &user, nil
+	case err != gorm.ErrRecordNotFound:
+		return nil, err
+	case !cfg.AutoProvision || username == "":
+		return nil, ErrOIDCUserNotFound
+	}
+
+	// 用户名已被其他账号占用时不能自动创建，需管理员处理
+	var count int64
+	if err := s.db.Unscoped().Model(&model.User{}).Where("username = ?", username).Count(&count).Error; err != nil {
+		return nil, err
+	}
+	if count > 0 {
+		return nil, ErrOIDCUserNotFound
+	}
+
+	var roleIDs []uint
+	if len(cfg.DefaultRoles) > 0 {
+		if err := s.db.Model(&model.Role{}).Where("code IN ?", cfg.DefaultRoles).Pluck("id", &roleIDs).Error; err != nil {
+			return nil, err
+		}
+	}
+
+	// 单点登录用户的密码不保存在本地，设置一个随机密码使其无法通过本地认证登录
+	random, err := randomHex(32)
+	if err != nil {
+		return nil, err
+	}
+	hash, err := HashPassword(random)
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	user = model.User{
+		Username:          username,
+		Password:          hash,
+		RealName:          oidcClaimString(claims, cfg.NameClaim),
+		Email:             email,
+		Source:            model.UserSourceOIDC,
+		OIDCIssuer:        &issuer,
+		OIDCSubject:       &subject,
+		Status:            1,
+		PasswordChangedAt: &now,
+	}
+
+	err = s.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(&user).Error; err != nil {
+			return err
+		}
+		return s.replaceUserRoles(tx, user.ID, roleIDs, 0)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	InvalidateUserPermissions(user.ID)
+	return &user, nil
+}
+
+func oidcClaimString(claims map[string]interface{}, name string) string {
+	value, _ := claims[name].(string)
+	return strings.TrimSpace(value)
+}
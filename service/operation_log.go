@@ -0,0 +1,218 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/lemonoa/LemonOA-Go/logging"
+	"github.com/lemonoa/LemonOA-Go/model"
+
+	"github.com/spf13/viper"
+	"gorm.io/gorm"
+)
+
+const (
+	// 请求参数和响应摘要记录的最大长度，超出部分截断
+	operationLogMaxParams   = 4000
+	operationLogMaxResponse = 500
+
+	// 单次导出操作日志的最大条数
+	operationLogExportLimit = 50000
+)
+
+// 请求参数中需要脱敏的字段，字段名包含其中任意一个即脱敏
+var sensitiveParamKeys = []string{
+	"password", "secret", "token", "api_key", "private_key", "recovery_code",
+	// 个人敏感信息，与模型中 mask 标签标记的字段对应
+	"id_card", "phone", "mobile", "address", "birthday",
+}
+
+// operationLogWriter 操作日志异步写入器，请求只负责入队，由后台协程批量写入数据库
+type operationLogWriter struct {
+	db        *gorm.DB
+	queue     chan *model.OperationLog
+	batchSize int
+	interval  time.Duration
+	done      chan struct{}
+
+	mu     sync.RWMutex
+	closed bool
+}
+
+var logWriter *operationLogWriter
+
+// InitOperationLogWriter 启动操作日志写入器，audit.queue_size 为队列长度，
+// 队列中的日志达到 audit.batch_size 条或每隔 audit.flush_interval 秒写入一次
+func InitOperationLogWriter(db *gorm.DB) {
+	queueSize := viper.GetInt("audit.queue_size")
+	if queueSize <= 0 {
+		queueSize = 10000
+	}
+	batchSize := viper.GetInt("audit.batch_size")
+	if batchSize <= 0 {
+		batchSize = 100
+	}
+	interval := viper.GetInt("audit.flush_interval")
+	if interval <= 0 {
+		interval = 2
+	}
+
+	logWriter = &operationLogWriter{
+		db:        db,
+		queue:     make(chan *model.OperationLog, queueSize),
+		batchSize: batchSize,
+		interval:  time.Duration(interval) * time.Second,
+		done:      make(chan struct{}),
+	}
+	go logWriter.run()
+}
+
+// CloseOperationLogWriter 停止接收新日志并写入队列中剩余的日志，服务退出前调用
+func CloseOperationLogWriter() {
+	if logWriter == nil {
+		return
+	}
+	logWriter.mu.Lock()
+	if !logWriter.closed {
+		logWriter.closed = true
+		close(logWriter.queue)
+	}
+	logWriter.mu.Unlock()
+	<-logWriter.done
+}
+
+// RecordOperationLog 记录操作日志，不等待写入数据库；队列已满时丢弃并输出告警，避免影响请求
+func RecordOperationLog(ctx context.Context, entry *model.OperationLog) {
+	if logWriter == nil {
+		logging.FromContext(ctx).WarnContext(ctx, "操作日志写入器未启动，丢弃日志", "method", entry.Method, "path", entry.Path)
+		return
+	}
+
+	logWriter.mu.RLock()
+	defer logWriter.mu.RUnlock()
+	if logWriter.closed {
+		logging.FromContext(ctx).WarnContext(ctx, "操作日志写入器已停止，丢弃日志", "method", entry.Method, "path", entry.Path)
+		return
+	}
+	select {
+	case logWriter.queue <- entry:
+	default:
+		logging.FromContext(ctx).WarnContext(ctx, "操作日志队列已满，丢弃日志", "method", entry.Method, "path", entry.Path)
+	}
+}
+
+func (w *operationLogWriter) run() {
+	defer close(w.done)
+
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+
+	batch := make([]*model.OperationLog, 0, w.batchSize)
+	for {
+		select {
+		case entry, ok := <-w.queue:
+			if !ok {
+				w.flush(batch)
+				return
+			}
+			batch = append(batch, entry)
+			if len(batch) >= w.batchSize {
+				w.flush(batch)
+				batch = batch[:0]
+			}
+		case <-ticker.C:
+			w.flush(batch)
+			batch = batch[:0]
+		}
+	}
+}
+
+func (w *operationLogWriter) flush(batch []*model.OperationLog) {
+	if len(batch) == 0 {
+		return
+	}
+	if err := w.db.CreateInBatches(batch, w.batchSize).Error; err != nil {
+		slog.Error("写入操作日志失败，已丢弃", "count", len(batch), "error", err)
+	}
+}
+
+// SanitizeParams 整理请求参数用于记录日志：JSON请求体中的密码、令牌等字段替换为 ******，
+// authFields 为true时(认证相关接口)验证码字段 code 同样脱敏；非JSON请求体不记录内容
+func SanitizeParams(query string, body []byte, authFields bool) string {
+	var parts []string
+	if query != "" {
+		parts = append(parts, query)
+	}
+
+	if len(body) > 0 {
+		var data interface{}
+		if err := json.Unmarshal(body, &data); err != nil {
+			parts = append(parts, "[非JSON请求体]")
+		} else {
+			redactParams(data, authFields)
+			if sanitized, err := json.Marshal(data); err == nil {
+				parts = append(parts, string(sanitized))
+			}
+		}
+	}
+
+	return truncateRunes(strings.Join(parts, " "), operationLogMaxParams)
+}
+
+func redactParams(data interface{}, authFields bool) {
+	switch v := data.(type) {
+	case map[string]interface{}:
+		for key, value := range v {
+			if isSensitiveParam(key, authFields) {
+				v[key] = "******"
+				continue
+			}
+			redactParams(value, authFields)
+		}
+	case []interface{}:
+		for _, item := range v {
+			redactParams(item, authFields)
+		}
+	}
+}
+
+func isSensitiveParam(key string, authFields bool) bool {
+	key = strings.ToLower(key)
+	if authFields && key == "code" {
+		return true
+	}
+	for _, word := range sensitiveParamKeys {
+		if strings.Contains(key, word) {
+			return true
+		}
+	}
+	return false
+}
+
+// SummarizeResponse 提取响应摘要：失败时为错误信息，成功时为返回的记录ID，不记录完整响应以免泄露数据
+func SummarizeResponse(body []byte) string {
+	var data map[string]interface{}
+	if err := json.Unmarshal(body, &data); err != nil {
+		return ""
+	}
+	if msg, ok := data["error"].(string); ok {
+		return truncateRunes(msg, operationLogMaxResponse)
+	}
+	if id, ok := data["id"].(float64); ok {
+		return "id=" + strconv.FormatUint(uint64(id), 10)
+	}
+	return ""
+}
+
+func truncateRunes(s string, limit int) string {
+	runes := []rune(s)
+	if len(runes) <= limit {
+		return s
+	}
+	return string(runes[:limit]) + "..."
+}
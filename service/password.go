@@ -0,0 +1,239 @@
+package service
+
+import (
+	"crypto/rand"
+	"crypto/sha512"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/spf13/viper"
+	"golang.org/x/crypto/argon2"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// PasswordHasher 密码哈希算法
+// 编码后的哈希串自带算法标识和计算参数，调整配置后旧哈希仍可校验，并在登录时自动升级
+type PasswordHasher interface {
+	// Name 算法名称，对应配置项 password_hash.algorithm
+	Name() string
+	// Identify 判断编码串是否由该算法生成
+	Identify(encoded string) bool
+	// Hash 计算密码哈希，返回编码串
+	Hash(password string) (string, error)
+	// Verify 校验密码与编码串是否匹配
+	Verify(password, encoded string) bool
+	// NeedsRehash 编码串的计算参数是否低于当前配置
+	NeedsRehash(encoded string) bool
+}
+
+var passwordHashers = []PasswordHasher{
+	&argon2idHasher{},
+	&bcryptHasher{},
+}
+
+var ErrUnknownPasswordHasher = errors.New("unknown password hash algorithm")
+
+// RegisterPasswordHasher 注册自定义密码哈希算法
+func RegisterPasswordHasher(hasher PasswordHasher) {
+	passwordHashers = append(passwordHashers, hasher)
+}
+
+// HashPassword 使用配置的默认算法计算密码哈希
+func HashPassword(password string) (string, error) {
+	hasher, err := defaultPasswordHasher()
+	if err != nil {
+		return "", err
+	}
+	return hasher.Hash(password)
+}
+
+// VerifyPassword 校验密码，needsRehash 表示哈希应使用当前默认算法重新计算
+// salt 仅用于旧版 SHA-512 哈希，新算法的盐值保存在编码串中
+func VerifyPassword(password, encoded, salt string) (ok bool, needsRehash bool) {
+	defaultHasher, err := defaultPasswordHasher()
+	if err != nil {
+		return false, false
+	}
+
+	for _, hasher := range passwordHashers {
+		if !hasher.Identify(encoded) {
+			continue
+		}
+		if !hasher.Verify(password, encoded) {
+			return false, false
+		}
+		return true, hasher.Name() != defaultHasher.Name() || hasher.NeedsRehash(encoded)
+	}
+
+	// 旧版哈希：hex(SHA-512(密码 + 盐值))
+	if subtle.ConstantTimeCompare([]byte(legacySHA512(password, salt)), []byte(encoded)) == 1 {
+		return true, true
+	}
+	return false, false
+}
+
+func defaultPasswordHasher() (PasswordHasher, error) {
+	name := viper.GetString("password_hash.algorithm")
+	if name == "" {
+		name = "argon2id"
+	}
+	for _, hasher := range passwordHashers {
+		if hasher.Name() == name {
+			return hasher, nil
+		}
+	}
+	return nil, fmt.Errorf("%w: %s", ErrUnknownPasswordHasher, name)
+}
+
+func legacySHA512(password, salt string) string {
+	hash := sha512.New()
+	hash.Write([]byte(password + salt))
+	return hex.EncodeToString(hash.Sum(nil))
+}
+
+// bcryptHasher bcrypt算法，编码格式 $2a$<cost>$<salt+hash>
+type bcryptHasher struct{}
+
+func (h *bcryptHasher) Name() string {
+	return "bcrypt"
+}
+
+func (h *bcryptHasher) cost() int {
+	cost := viper.GetInt("password_hash.bcrypt_cost")
+	if cost < bcrypt.MinCost {
+		return 12
+	}
+	return cost
+}
+
+func (h *bcryptHasher) Identify(encoded string) bool {
+	return strings.HasPrefix(encoded, "$2a$") || strings.HasPrefix(encoded, "$2b$") || strings.HasPrefix(encoded, "$2y$")
+}
+
+func (h *bcryptHasher) Hash(password string) (string, error) {
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), h.cost())
+	if err != nil {
+		return "", err
+	}
+	return string(hash), nil
+}
+
+func (h *bcryptHasher) Verify(password, encoded string) bool {
+	return bcrypt.CompareHashAndPassword([]byte(encoded), []byte(password)) == nil
+}
+
+func (h *bcryptHasher) NeedsRehash(encoded string) bool {
+	cost, err := bcrypt.Cost([]byte(encoded))
+	return err != nil || cost < h.cost()
+}
+
+// argon2idHasher argon2id算法，编码格式 $argon2id$v=19$m=<内存KB>,t=<迭代次数>,p=<并行度>$<盐值>$<哈希>
+type argon2idHasher struct{}
+
+type argon2Params struct {
+	memory      uint32
+	iterations  uint32
+	parallelism uint8
+}
+
+const (
+	argon2SaltLength = 16
+	argon2KeyLength  = 32
+)
+
+func (h *argon2idHasher) Name() string {
+	return "argon2id"
+}
+
+func (h *argon2idHasher) params() argon2Params {
+	p := argon2Params{
+		memory:      uint32(viper.GetUint("password_hash.argon2_memory")),
+		iterations:  uint32(viper.GetUint("password_hash.argon2_iterations")),
+		parallelism: uint8(viper.GetUint("password_hash.argon2_parallelism")),
+	}
+	if p.memory == 0 {
+		p.memory = 64 * 1024
+	}
+	if p.iterations == 0 {
+		p.iterations = 3
+	}
+	if p.parallelism == 0 {
+		p.parallelism = 2
+	}
+	return p
+}
+
+func (h *argon2idHasher) Identify(encoded string) bool {
+	return strings.HasPrefix(encoded, "$argon2id$")
+}
+
+func (h *argon2idHasher) Hash(password string) (string, error) {
+	p := h.params()
+	salt := make([]byte, argon2SaltLength)
+	if _, err := rand.Read(salt); err != nil {
+		return "", err
+	}
+
+	key := argon2.IDKey([]byte(password), salt, p.iterations, p.memory, p.parallelism, argon2KeyLength)
+	return fmt.Sprintf("$argon2id$v=%d$m=%d,t=%d,p=%d$%s$%s",
+		argon2.Version, p.memory, p.iterations, p.parallelism,
+		base64.RawStdEncoding.EncodeToString(salt),
+		base64.RawStdEncoding.EncodeToString(key),
+	), nil
+}
+
+func (h *argon2idHasher) Verify(password, encoded string) bool {
+	p, salt, key, err := h.decode(encoded)
+	if err != nil {
+		return false
+	}
+
+	other := argon2.IDKey([]byte(password), salt, p.iterations, p.memory, p.parallelism, uint32(len(key)))
+	return subtle.ConstantTimeCompare(key, other) == 1
+}
+
+func (h *argon2idHasher) NeedsRehash(encoded string) bool {
+	p, _, _, err := h.decode(encoded)
+	if err != nil {
+		return true
+	}
+
+	current := h.params()
+	return p.memory < current.memory || p.iterations < current.iterations || p.parallelism < current.parallelism
+}
+
+func (h *argon2idHasher) decode(encoded string) (argon2Params, []byte, []byte, error) {
+	var p argon2Params
+
+	parts := strings.Split(encoded, "$")
+	if len(parts) != 6 {
+		return p, nil, nil, errors.New("invalid argon2id hash")
+	}
+
+	var version int
+	if _, err := fmt.Sscanf(parts[2], "v=%d", &version); err != nil {
+		return p, nil, nil, err
+	}
+	if version != argon2.Version {
+		return p, nil, nil, errors.New("incompatible argon2 version")
+	}
+
+	if _, err := fmt.Sscanf(parts[3], "m=%d,t=%d,p=%d", &p.memory, &p.iterations, &p.parallelism); err != nil {
+		return p, nil, nil, err
+	}
+
+	salt, err := base64.RawStdEncoding.DecodeString(parts[4])
+	if err != nil {
+		return p, nil, nil, err
+	}
+	key, err := base64.RawStdEncoding.DecodeString(parts[5])
+	if err != nil {
+		return p, nil, nil, err
+	}
+
+	return p, salt, key, nil
+}
@@ -0,0 +1,349 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/lemonoa/LemonOA-Go/model"
+
+	"github.com/dgrijalva/jwt-go"
+	"github.com/spf13/viper"
+	"gorm.io/gorm"
+)
+
+// 密码策略在系统配置中的键
+const (
+	PasswordPolicyMinLength       = "password.min_length"       // 最小长度
+	PasswordPolicyMinClasses      = "password.min_classes"      // 至少包含的字符种类数(大写、小写、数字、特殊字符)
+	PasswordPolicyDictionaryCheck = "password.dictionary_check" // 是否禁止常见弱密码及包含用户名的密码
+	PasswordPolicyHistoryCount    = "password.history_count"    // 不能与最近几次的密码相同
+	PasswordPolicyMaxAgeDays      = "password.max_age_days"     // 密码有效天数，0表示永不过期
+)
+
+const passwordChangeTokenType = "password_change"
+
+var (
+	ErrPasswordReused             = errors.New("新密码不能与最近使用过的密码相同")
+	ErrPasswordInDictionary       = errors.New("密码过于简单，请勿使用常见密码或包含用户名")
+	ErrInvalidPasswordChangeToken = errors.New("修改密码请求无效或已过期，请重新登录")
+	ErrPasswordChangeNotRequired  = errors.New("当前无需修改密码")
+)
+
+// PasswordPolicy 密码策略
+type PasswordPolicy struct {
+	MinLength       int  `json:"min_length"`
+	MinClasses      int  `json:"min_classes"`
+	DictionaryCheck bool `json:"dictionary_check"`
+	HistoryCount    int  `json:"history_count"`
+	MaxAgeDays      int  `json:"max_age_days"`
+}
+
+// 默认密码策略，系统配置中未设置的项使用默认值
+var defaultPasswordPolicy = PasswordPolicy{
+	MinLength:       8,
+	MinClasses:      3,
+	DictionaryCheck: true,
+	HistoryCount:    5,
+	MaxAgeDays:      0,
+}
+
+// 常见弱密码，比较时忽略大小写
+var passwordDictionary = map[string]struct{}{
+	"password": {}, "password1": {}, "password123": {}, "passw0rd": {}, "p@ssw0rd": {}, "p@ssword": {},
+	"12345678": {}, "123456789": {}, "1234567890": {}, "87654321": {}, "11111111": {}, "88888888": {},
+	"66666666": {}, "00000000": {}, "12341234": {}, "1qaz2wsx": {}, "1q2w3e4r": {}, "1q2w3e4r5t": {},
+	"qwerty123": {}, "qwertyuiop": {}, "asdfghjkl": {}, "zxcvbnm123": {}, "abc12345": {}, "abcd1234": {},
+	"a1234567": {}, "aa123456": {}, "admin123": {}, "admin@123": {}, "admin888": {}, "administrator": {},
+	"root1234": {}, "welcome1": {}, "welcome123": {}, "iloveyou": {}, "letmein1": {}, "changeme": {},
+	"woaini1314": {}, "woaini520": {}, "5201314520": {}, "qq123456": {}, "test1234": {}, "lemonoa123": {},
+}
+
+// GetPasswordPolicy 读取系统配置中的密码策略
+func GetPasswordPolicy(db *gorm.DB) (*PasswordPolicy, error) {
+	policy := defaultPasswordPolicy
+
+	var configs []model.SystemConfig
+	if err := db.Where(map[string]interface{}{"key": []string{
+		PasswordPolicyMinLength,
+		PasswordPolicyMinClasses,
+		PasswordPolicyDictionaryCheck,
+		PasswordPolicyHistoryCount,
+		PasswordPolicyMaxAgeDays,
+	}}).Find(&configs).Error; err != nil {
+		return nil, err
+	}
+
+	for _, config := range configs {
+		value := strings.TrimSpace(config.Value)
+		switch config.Key {
+		case PasswordPolicyDictionaryCheck:
+			if b, err := strconv.ParseBool(value); err == nil {
+				policy.DictionaryCheck = b
+			}
+		default:
+			n, err := strconv.Atoi(value)
+			if err != nil || n < 0 {
+				continue
+			}
+			switch config.Key {
+			case PasswordPolicyMinLength:
+				policy.MinLength = n
+			case PasswordPolicyMinClasses:
+				policy.MinClasses = n
+			case PasswordPolicyHistoryCount:
+				policy.HistoryCount = n
+			case PasswordPolicyMaxAgeDays:
+				policy.MaxAgeDays = n
+			}
+		}
+	}
+
+	return &policy, nil
+}
+
+// Validate 校验密码是否符合策略
+func (p *PasswordPolicy) Validate(password, username string) error {
+	if strings.TrimSpace(password) == "" {
+		return errors.New("密码不能为空")
+	}
+	if len([]rune(password)) < p.MinLength {
+		return fmt.Errorf("密码长度不能少于%d位", p.MinLength)
+	}
+
+	var upper, lower, digit, symbol bool
+	for _, r := range password {
+		switch {
+		case unicode.IsUpper(r):
+			upper = true
+		case unicode.IsLower(r):
+			lower = true
+		case unicode.IsDigit(r):
+			digit = true
+		default:
+			symbol = true
+		}
+	}
+	classes := 0
+	for _, ok := range []bool{upper, lower, digit, symbol} {
+		if ok {
+			classes++
+		}
+	}
+	if classes < p.MinClasses {
+		return fmt.Errorf("密码需至少包含大写字母、小写字母、数字、特殊字符中的%d种", p.MinClasses)
+	}
+
+	if p.DictionaryCheck {
+		lowered := strings.ToLower(password)
+		if _, ok := passwordDictionary[lowered]; ok {
+			return ErrPasswordInDictionary
+		}
+		if username != "" && len(username) >= 3 && strings.Contains(lowered, strings.ToLower(username)) {
+			return ErrPasswordInDictionary
+		}
+	}
+
+	return nil
+}
+
+// Expired 密码是否已超过有效期
+func (p *PasswordPolicy) Expired(user *model.User, now time.Time) bool {
+	if p.MaxAgeDays <= 0 {
+		return false
+	}
+	changedAt := user.CreatedAt
+	if user.PasswordChangedAt != nil {
+		changedAt = *user.PasswordChangedAt
+	}
+	return now.After(changedAt.AddDate(0, 0, p.MaxAgeDays))
+}
+
+// 校验新密码的强度，并检查是否与当前密码或最近的历史密码相同
+func (s *AuthService) checkNewPassword(db *gorm.DB, policy *PasswordPolicy, user *model.User, password string) error {
+	if err := policy.Validate(password, user.Username); err != nil {
+		return err
+	}
+	if policy.HistoryCount <= 0 {
+		return nil
+	}
+
+	if user.Password != "" {
+		if ok, _ := VerifyPassword(password, user.Password, user.Salt); ok {
+			return ErrPasswordReused
+		}
+	}
+
+	var histories []model.PasswordHistory
+	if err := db.Where("user_id = ?", user.ID).
+		Order("id DESC").
+		Limit(policy.HistoryCount).
+		Find(&histories).Error; err != nil {
+		return err
+	}
+	for _, h := range histories {
+		if ok, _ := VerifyPassword(password, h.Password, ""); ok {
+			return ErrPasswordReused
+		}
+	}
+	return nil
+}
+
+// 设置用户密码：校验策略、保存哈希并记录历史密码
+// mustChange 为true时用户下次登录需修改密码，用于管理员重置密码
+func (s *AuthService) setPassword(tx *gorm.DB, user *model.User, password string, mustChange bool) error {
+	policy, err := GetPasswordPolicy(tx)
+	if err != nil {
+		return err
+	}
+	if err := s.checkNewPassword(tx, policy, user, password); err != nil {
+		return err
+	}
+
+	hash, err := HashPassword(password)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	if err := tx.Model(user).Updates(map[string]interface{}{
+		"salt":                 "",
+		"password":             hash,
+		"password_changed_at":  &now,
+		"must_change_password": mustChange,
+	}).Error; err != nil {
+		return err
+	}
+
+	return s.recordPasswordHistory(tx, policy, user.ID, hash)
+}
+
+// 记录历史密码，只保留策略要求的条数
+func (s *AuthService) recordPasswordHistory(tx *gorm.DB, policy *PasswordPolicy, userID uint, hash string) error {
+	if err := tx.Create(&model.PasswordHistory{UserID: userID, Password: hash}).Error; err != nil {
+		return err
+	}
+
+	var keepIDs []uint
+	if err := tx.Model(&model.PasswordHistory{}).
+		Where("user_id = ?", userID).
+		Order("id DESC").
+		Limit(policy.HistoryCount).
+		Pluck("id", &keepIDs).Error; err != nil {
+		return err
+	}
+	query := tx.Where("user_id = ?", userID)
+	if len(keepIDs) > 0 {
+		query = query.Where("id NOT IN ?", keepIDs)
+	}
+	return query.Delete(&model.PasswordHistory{}).Error
+}
+
+// 是否需要在登录时修改密码：本地账号被管理员重置过密码，或密码已过期
+func (s *AuthService) passwordChangeRequired(user *model.User) (bool, error) {
+	// 外部认证的用户密码由外部系统管理
+	if !isLocalUser(user) {
+		return false, nil
+	}
+	if user.MustChangePassword {
+		return true, nil
+	}
+	policy, err := GetPasswordPolicy(s.db)
+	if err != nil {
+		return false, err
+	}
+	return policy.Expired(user, time.Now()), nil
+}
+
+// 密码校验及两步验证通过后完成登录，需要修改密码时签发修改密码的挑战令牌
+func (s *AuthService) finishLogin(user *model.User, ip, userAgent string) (*LoginResult, error) {
+	required, err := s.passwordChangeRequired(user)
+	if err != nil {
+		return nil, err
+	}
+	if required {
+		challenge, err := s.generatePasswordChangeToken(user.ID)
+		if err != nil {
+			return nil, err
+		}
+		return &LoginResult{PasswordChangeRequired: true, ChallengeToken: challenge}, nil
+	}
+
+	pair, err := s.completeLogin(user, ip, userAgent)
+	if err != nil {
+		return nil, err
+	}
+	return &LoginResult{TokenPair: pair}, nil
+}
+
+// ChangeExpiredPassword 登录过程中修改已过期或被管理员重置的密码，修改成功后签发令牌
+func (s *AuthService) ChangeExpiredPassword(challengeToken, newPassword, ip, userAgent string) (*LoginResult, error) {
+	userID, err := s.parsePasswordChangeToken(challengeToken)
+	if err != nil {
+		return nil, err
+	}
+
+	user, err := s.loadChallengeUser(userID)
+	if err != nil {
+		return nil, err
+	}
+	required, err := s.passwordChangeRequired(user)
+	if err != nil {
+		return nil, err
+	}
+	if !required {
+		return nil, ErrPasswordChangeNotRequired
+	}
+
+	if err := s.db.Transaction(func(tx *gorm.DB) error {
+		return s.setPassword(tx, user, newPassword, false)
+	}); err != nil {
+		return nil, err
+	}
+
+	pair, err := s.completeLogin(user, ip, userAgent)
+	if err != nil {
+		return nil, err
+	}
+	return &LoginResult{TokenPair: pair}, nil
+}
+
+// 生成登录时修改密码的挑战令牌
+func (s *AuthService) generatePasswordChangeToken(userID uint) (string, error) {
+	now := time.Now()
+	claims := jwt.MapClaims{
+		"user_id": userID,
+		"typ":     passwordChangeTokenType,
+		"iat":     now.Unix(),
+		"exp":     now.Add(challengeTokenExpire).Unix(),
+	}
+
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+	return token.SignedString([]byte(viper.GetString("jwt.secret")))
+}
+
+// 解析登录时修改密码的挑战令牌
+func (s *AuthService) parsePasswordChangeToken(tokenString string) (uint, error) {
+	token, err := jwt.Parse(tokenString, func(token *jwt.Token) (interface{}, error) {
+		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
+			return nil, ErrInvalidPasswordChangeToken
+		}
+		return []byte(viper.GetString("jwt.secret")), nil
+	})
+	if err != nil {
+		return 0, ErrInvalidPasswordChangeToken
+	}
+
+	claims, ok := token.Claims.(jwt.MapClaims)
+	if !ok || !token.Valid || claims["typ"] != passwordChangeTokenType {
+		return 0, ErrInvalidPasswordChangeToken
+	}
+	userID, ok := claims["user_id"].(float64)
+	if !ok {
+		return 0, ErrInvalidPasswordChangeToken
+	}
+	return uint(userID), nil
+}
@@ -0,0 +1,316 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/lemonoa/LemonOA-Go/database"
+	"github.com/lemonoa/LemonOA-Go/model"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/spf13/viper"
+	"gorm.io/gorm"
+)
+
+// UserPermissions 用户的有效权限集合
+type UserPermissions struct {
+	HasRole    bool                `json:"has_role"`    // 是否分配了任何角色
+	SuperAdmin bool                `json:"super_admin"` // 是否超级管理员
+	Codes      map[string]struct{} `json:"-"`           // 权限编码集合
+	CodeList   []string            `json:"codes"`       // 权限编码列表，用于序列化
+	Scope      *DataScope          `json:"scope"`       // 数据权限范围
+}
+
+// Has 是否拥有指定权限，超级管理员拥有全部权限
+func (p *UserPermissions) Has(code string) bool {
+	if p.SuperAdmin {
+		return true
+	}
+	_, ok := p.Codes[code]
+	return ok
+}
+
+// PermissionCache 用户权限及路由权限表缓存
+type PermissionCache interface {
+	Get(userID uint) (*UserPermissions, bool)
+	Set(userID uint, perms *UserPermissions)
+	// Invalidate 使指定用户的缓存失效
+	Invalidate(userIDs ...uint)
+	// InvalidateAll 使全部用户的缓存失效，用于权限定义本身发生变化
+	InvalidateAll()
+	// GetRoutes 获取路由权限表，键为 "方法 路由模板"
+	GetRoutes() (map[string][]string, bool)
+	SetRoutes(rules map[string][]string)
+	// InvalidateRoutes 使路由权限表失效，用于接口权限发生变化
+	InvalidateRoutes()
+}
+
+var permissionCache PermissionCache = newMemoryPermissionCache(permissionCacheTTL())
+
+// InitPermissionCache 初始化权限缓存及令牌状态缓存，启用Redis时使用Redis保证多实例间一致
+func InitPermissionCache() {
+	if database.Redis != nil {
+		permissionCache = newRedisPermissionCache(database.Redis, permissionCacheTTL())
+		tokenStateCache = newRedisTokenStateCache(database.Redis, tokenStateCacheTTL())
+		return
+	}
+	permissionCache = newMemoryPermissionCache(permissionCacheTTL())
+	tokenStateCache = newMemoryTokenStateCache(tokenStateCacheTTL())
+}
+
+func permissionCacheTTL() time.Duration {
+	if ttl := viper.GetInt("permission_cache.ttl"); ttl > 0 {
+		return time.Duration(ttl) * time.Second
+	}
+	return 5 * time.Minute
+}
+
+// GetUserEffectivePermissions 获取用户的有效权限集合，优先读取缓存
+func GetUserEffectivePermissions(db *gorm.DB, userID uint) (*UserPermissions, error) {
+	if perms, ok := permissionCache.Get(userID); ok {
+		return perms, nil
+	}
+
+	perms, err := loadUserPermissions(db, userID)
+	if err != nil {
+		return nil, err
+	}
+	permissionCache.Set(userID, perms)
+	return perms, nil
+}
+
+// InvalidateUserPermissions 使指定用户的权限缓存失效
+func InvalidateUserPermissions(userIDs ...uint) {
+	permissionCache.Invalidate(userIDs...)
+}
+
+// 使拥有指定角色的用户权限缓存失效
+func invalidateRolePermissions(db *gorm.DB, roleIDs ...uint) error {
+	var userIDs []uint
+	if err := db.Model(&model.UserRole{}).Where("role_id IN ?", roleIDs).Distinct().Pluck("user_id", &userIDs).Error; err != nil {
+		return err
+	}
+	permissionCache.Invalidate(userIDs...)
+	return nil
+}
+
+// 使关联了指定员工的用户权限缓存失效，员工所属部门变化会影响其数据权限范围
+func invalidateEmployeePermissions(db *gorm.DB, employeeIDs ...uint) error {
+	var userIDs []uint
+	if err := db.Model(&model.User{}).Where("employee_id IN ?", employeeIDs).Pluck("id", &userIDs).Error; err != nil {
+		return err
+	}
+	permissionCache.Invalidate(userIDs...)
+	return nil
+}
+
+// 从数据库加载用户的角色、权限及数据权限范围
+func loadUserPermissions(db *gorm.DB, userID uint) (*UserPermissions, error) {
+	perms, err := loadRolePermissions(db, userID)
+	if err != nil {
+		return nil, err
+	}
+	if perms.Scope, err = loadDataScope(db, userID, perms.SuperAdmin); err != nil {
+		return nil, err
+	}
+	return perms, nil
+}
+
+func loadRolePermissions(db *gorm.DB, userID uint) (*UserPermissions, error) {
+	perms := &UserPermissions{Codes: map[string]struct{}{}}
+
+	var roleIDs []uint
+	if err := db.Model(&model.UserRole{}).Where("user_id = ?", userID).Pluck("role_id", &roleIDs).Error; err != nil {
+		return nil, err
+	}
+	if len(roleIDs) == 0 {
+		return perms, nil
+	}
+	perms.HasRole = true
+
+	var count int64
+	if err := db.Model(&model.Role{}).Where("id IN ? AND code = ?", roleIDs, "super_admin").Count(&count).Error; err != nil {
+		return nil, err
+	}
+	perms.SuperAdmin = count > 0
+
+	if err := db.Model(&model.Permission{}).Distinct().
+		Joins("INNER JOIN role_permissions ON role_permissions.permission_id = permissions.id AND role_permissions.deleted_at IS NULL").
+		Where("role_permissions.role_id IN ? AND permissions.status = 1", roleIDs).
+		Pluck("permissions.code", &perms.CodeList).Error; err != nil {
+		return nil, err
+	}
+	for _, code := range perms.CodeList {
+		perms.Codes[code] = struct{}{}
+	}
+
+	return perms, nil
+}
+
+// memoryPermissionCache 进程内缓存，单实例部署时使用
+type memoryPermissionCache struct {
+	mu              sync.RWMutex
+	ttl             time.Duration
+	entries         map[uint]memoryPermissionEntry
+	routes          map[string][]string
+	routesExpiresAt time.Time
+}
+
+type memoryPermissionEntry struct {
+	perms     *UserPermissions
+	expiresAt time.Time
+}
+
+func newMemoryPermissionCache(ttl time.Duration) *memoryPermissionCache {
+	return &memoryPermissionCache{
+		ttl:     ttl,
+		entries: make(map[uint]memoryPermissionEntry),
+	}
+}
+
+func (c *memoryPermissionCache) Get(userID uint) (*UserPermissions, bool) {
+	c.mu.RLock()
+	entry, ok := c.entries[userID]
+	c.mu.RUnlock()
+	if !ok || time.Now().After(entry.expiresAt) {
+		return nil, false
+	}
+	return entry.perms, true
+}
+
+func (c *memoryPermissionCache) Set(userID uint, perms *UserPermissions) {
+	c.mu.Lock()
+	c.entries[userID] = memoryPermissionEntry{perms: perms, expiresAt: time.Now().Add(c.ttl)}
+	c.mu.Unlock()
+}
+
+func (c *memoryPermissionCache) Invalidate(userIDs ...uint) {
+	c.mu.Lock()
+	for _, id := range userIDs {
+		delete(c.entries, id)
+	}
+	c.mu.Unlock()
+}
+
+func (c *memoryPermissionCache) InvalidateAll() {
+	c.mu.Lock()
+	c.entries = make(map[uint]memoryPermissionEntry)
+	c.mu.Unlock()
+}
+
+func (c *memoryPermissionCache) GetRoutes() (map[string][]string, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	if c.routes == nil || time.Now().After(c.routesExpiresAt) {
+		return nil, false
+	}
+	return c.routes, true
+}
+
+func (c *memoryPermissionCache) SetRoutes(rules map[string][]string) {
+	c.mu.Lock()
+	c.routes = rules
+	c.routesExpiresAt = time.Now().Add(c.ttl)
+	c.mu.Unlock()
+}
+
+func (c *memoryPermissionCache) InvalidateRoutes() {
+	c.mu.Lock()
+	c.routes = nil
+	c.mu.Unlock()
+}
+
+// redisPermissionCache Redis缓存，多实例部署时共享，失效操作对所有实例立即生效
+type redisPermissionCache struct {
+	client *redis.Client
+	ttl    time.Duration
+}
+
+const (
+	redisPermissionKeyPrefix = "lemonoa:permissions:user:"
+	redisRoutePermissionKey  = "lemonoa:permissions:routes"
+)
+
+func newRedisPermissionCache(client *redis.Client, ttl time.Duration) *redisPermissionCache {
+	return &redisPermissionCache{client: client, ttl: ttl}
+}
+
+func (c *redisPermissionCache) key(userID uint) string {
+	return fmt.Sprintf("%s%d", redisPermissionKeyPrefix, userID)
+}
+
+func (c *redisPermissionCache) Get(userID uint) (*UserPermissions, bool) {
+	data, err := c.client.Get(context.Background(), c.key(userID)).Bytes()
+	if err != nil {
+		return nil, false
+	}
+
+	var perms UserPermissions
+	if err := json.Unmarshal(data, &perms); err != nil {
+		return nil, false
+	}
+	perms.Codes = make(map[string]struct{}, len(perms.CodeList))
+	for _, code := range perms.CodeList {
+		perms.Codes[code] = struct{}{}
+	}
+	return &perms, true
+}
+
+func (c *redisPermissionCache) Set(userID uint, perms *UserPermissions) {
+	data, err := json.Marshal(perms)
+	if err != nil {
+		return
+	}
+	c.client.Set(context.Background(), c.key(userID), data, c.ttl)
+}
+
+func (c *redisPermissionCache) Invalidate(userIDs ...uint) {
+	if len(userIDs) == 0 {
+		return
+	}
+	keys := make([]string, 0, len(userIDs))
+	for _, id := range userIDs {
+		keys = append(keys, c.key(id))
+	}
+	c.client.Del(context.Background(), keys...)
+}
+
+func (c *redisPermissionCache) InvalidateAll() {
+	ctx := context.Background()
+	iter := c.client.Scan(ctx, 0, redisPermissionKeyPrefix+"*", 100).Iterator()
+	var keys []string
+	for iter.Next(ctx) {
+		keys = append(keys, iter.Val())
+	}
+	if len(keys) > 0 {
+		c.client.Del(ctx, keys...)
+	}
+}
+
+func (c *redisPermissionCache) GetRoutes() (map[string][]string, bool) {
+	data, err := c.client.Get(context.Background(), redisRoutePermissionKey).Bytes()
+	if err != nil {
+		return nil, false
+	}
+
+	var rules map[string][]string
+	if err := json.Unmarshal(data, &rules); err != nil {
+		return nil, false
+	}
+	return rules, true
+}
+
+func (c *redisPermissionCache) SetRoutes(rules map[string][]string) {
+	data, err := json.Marshal(rules)
+	if err != nil {
+		return
+	}
+	c.client.Set(context.Background(), redisRoutePermissionKey, data, c.ttl)
+}
+
+func (c *redisPermissionCache) InvalidateRoutes() {
+	c.client.Del(context.Background(), redisRoutePermissionKey)
+}
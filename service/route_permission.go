@@ -0,0 +1,55 @@
+package service
+
+import (
+	"strings"
+
+	"github.com/lemonoa/LemonOA-Go/model"
+
+	"gorm.io/gorm"
+)
+
+// GetRoutePermissions 获取访问指定路由所需的权限编码，路由未配置接口权限时返回空
+// 同一路由配置多个权限时，拥有其中任意一个即可访问
+func GetRoutePermissions(db *gorm.DB, method, path string) ([]string, error) {
+	rules, err := loadRoutePermissions(db)
+	if err != nil {
+		return nil, err
+	}
+
+	codes := append([]string{}, rules[strings.ToUpper(method)+" "+path]...)
+	codes = append(codes, rules["* "+path]...)
+	return codes, nil
+}
+
+// 使路由权限表失效，启用Redis时对所有实例生效，下次请求时重新加载
+func invalidateRoutePermissions() {
+	permissionCache.InvalidateRoutes()
+}
+
+// 加载接口类型权限构成的路由权限表，优先读取缓存
+// 键为 "方法 路由模板"，方法为空的权限以 "* 路由模板" 为键
+func loadRoutePermissions(db *gorm.DB) (map[string][]string, error) {
+	if rules, ok := permissionCache.GetRoutes(); ok {
+		return rules, nil
+	}
+
+	var permissions []model.Permission
+	if err := db.Select("code", "path", "method").
+		Where("type = ? AND status = 1 AND path <> ''", 3).
+		Find(&permissions).Error; err != nil {
+		return nil, err
+	}
+
+	rules := make(map[string][]string, len(permissions))
+	for _, p := range permissions {
+		method := strings.ToUpper(strings.TrimSpace(p.Method))
+		if method == "" {
+			method = "*"
+		}
+		key := method + " " + p.Path
+		rules[key] = append(rules[key], p.Code)
+	}
+
+	permissionCache.SetRoutes(rules)
+	return rules, nil
+}
@@ -0,0 +1,425 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/lemonoa/LemonOA-Go/model"
+
+	"github.com/robfig/cron/v3"
+	"github.com/spf13/viper"
+	"gorm.io/gorm"
+)
+
+// 定时任务的触发方式
+const (
+	TaskTriggerSchedule = "schedule"
+	TaskTriggerManual   = "manual"
+)
+
+// 运行记录中输出和错误信息的最大长度，超出部分截断
+const taskRunMaxOutput = 60000
+
+var (
+	ErrTaskNotFound      = errors.New("scheduled task not found")
+	ErrTaskCronInvalid   = errors.New("invalid cron expression")
+	ErrTaskJobNotFound   = errors.New("job is not registered")
+	ErrTaskStatusInvalid = errors.New("invalid task status")
+	ErrTaskRunning       = errors.New("task is running")
+	ErrSchedulerStopped  = errors.New("scheduler is not running")
+)
+
+// cron表达式：5位(分 时 日 月 周)或带秒的6位，以及 @daily、@every 1h 等写法
+var cronParser = cron.NewParser(cron.SecondOptional | cron.Minute | cron.Hour | cron.Dom | cron.Month | cron.Dow | cron.Descriptor)
+
+// JobHandler 定时任务处理器，params 为任务配置的参数，返回的输出记录在运行历史中
+type JobHandler func(ctx context.Context, db *gorm.DB, params string) (string, error)
+
+// JobInfo 已注册的任务
+type JobInfo struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+}
+
+type registeredJob struct {
+	info    JobInfo
+	handler JobHandler
+}
+
+var (
+	jobsMu sync.RWMutex
+	jobs   = map[string]registeredJob{}
+)
+
+// RegisterJob 注册定时任务处理器，定时任务的 command 为处理器名称，名称重复时覆盖
+func RegisterJob(name, description string, handler JobHandler) {
+	jobsMu.Lock()
+	defer jobsMu.Unlock()
+	jobs[name] = registeredJob{info: JobInfo{Name: name, Description: description}, handler: handler}
+}
+
+// RegisteredJobs 获取已注册的任务，按名称排序
+func RegisteredJobs() []JobInfo {
+	jobsMu.RLock()
+	defer jobsMu.RUnlock()
+
+	list := make([]JobInfo, 0, len(jobs))
+	for _, job := range jobs {
+		list = append(list, job.info)
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].Name < list[j].Name })
+	return list
+}
+
+func lookupJob(name string) (JobHandler, bool) {
+	jobsMu.RLock()
+	defer jobsMu.RUnlock()
+	job, ok := jobs[name]
+	return job.handler, ok
+}
+
+// taskScheduler 进程内的定时任务调度器，多实例部署时通过数据库中的运行锁保证同一任务同一时间只在一个实例运行
+type taskScheduler struct {
+	db       *gorm.DB
+	cron     *cron.Cron
+	instance string
+	lockTTL  time.Duration
+	enabled  bool // 是否按计划运行任务
+	ctx      context.Context
+	cancel   context.CancelFunc
+	running  sync.WaitGroup // 手动运行的任务
+
+	mu      sync.Mutex
+	entries map[uint]scheduledEntry
+}
+
+type scheduledEntry struct {
+	id   cron.EntryID
+	spec string
+}
+
+var scheduler *taskScheduler
+
+// InitScheduler 启动定时任务调度器，加载启用的任务，并每隔 scheduler.sync_interval 秒重新加载以获取其他实例修改的任务；
+// scheduler.enabled 为false时本实例不按计划运行任务，仍可手动运行
+func InitScheduler(db *gorm.DB) error {
+	lockTTL := viper.GetInt("scheduler.lock_ttl")
+	if lockTTL <= 0 {
+		lockTTL = 3600
+	}
+	syncInterval := viper.GetInt("scheduler.sync_interval")
+	if syncInterval <= 0 {
+		syncInterval = 30
+	}
+	host, _ := os.Hostname()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	s := &taskScheduler{
+		db:       db.WithContext(ctx),
+		cron:     cron.New(cron.WithParser(cronParser)),
+		instance: fmt.Sprintf("%s-%d", host, os.Getpid()),
+		lockTTL:  time.Duration(lockTTL) * time.Second,
+		ctx:      ctx,
+		cancel:   cancel,
+		entries:  make(map[uint]scheduledEntry),
+	}
+	scheduler = s
+
+	if viper.IsSet("scheduler.enabled") && !viper.GetBool("scheduler.enabled") {
+		return nil
+	}
+	s.enabled = true
+	if err := s.sync(); err != nil {
+		return err
+	}
+	s.cron.Start()
+
+	go func() {
+		ticker := time.NewTicker(time.Duration(syncInterval) * time.Second)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				if err := s.sync(); err != nil {
+					slog.Error("加载定时任务失败", "error", err)
+				}
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return nil
+}
+
+// StopScheduler 停止调度新的任务并等待运行中的任务结束，ctx 到期后取消仍在运行的任务
+func StopScheduler(ctx context.Context) {
+	if scheduler == nil {
+		return
+	}
+	done := make(chan struct{})
+	go func() {
+		<-scheduler.cron.Stop().Done()
+		scheduler.running.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-ctx.Done():
+		slog.WarnContext(ctx, "等待定时任务结束超时，取消运行中的任务")
+	}
+	scheduler.cancel()
+}
+
+// 任务变更后重新加载，使修改立即在本实例生效
+func reloadScheduler() {
+	if scheduler == nil || !scheduler.enabled {
+		return
+	}
+	if err := scheduler.sync(); err != nil {
+		slog.Error("加载定时任务失败", "error", err)
+	}
+}
+
+// 按数据库中启用的任务更新调度，cron表达式变化的任务重新调度
+func (s *taskScheduler) sync() error {
+	var tasks []model.ScheduledTask
+	if err := s.db.Where("status = ?", 1).Find(&tasks).Error; err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	active := make(map[uint]bool, len(tasks))
+	for _, task := range tasks {
+		active[task.ID] = true
+		entry, ok := s.entries[task.ID]
+		if ok && entry.spec == task.Cron {
+			continue
+		}
+		if ok {
+			s.cron.Remove(entry.id)
+			delete(s.entries, task.ID)
+		}
+
+		schedule, err := cronParser.Parse(task.Cron)
+		if err != nil {
+			slog.Error("定时任务的cron表达式无效", "task", task.Name, "cron", task.Cron, "error", err)
+			continue
+		}
+		taskID := task.ID
+		id := s.cron.Schedule(schedule, cron.FuncJob(func() {
+			s.runScheduled(taskID, schedule)
+		}))
+		s.entries[task.ID] = scheduledEntry{id: id, spec: task.Cron}
+	}
+
+	for taskID, entry := range s.entries {
+		if !active[taskID] {
+			s.cron.Remove(entry.id)
+			delete(s.entries, taskID)
+		}
+	}
+	return nil
+}
+
+// 按计划运行任务，同一计划时间已由其他实例运行或任务正在运行时跳过
+func (s *taskScheduler) runScheduled(taskID uint, schedule cron.Schedule) {
+	fire, since := fireTime(schedule, time.Now())
+	task, ok, err := s.acquire(taskID, &fire, since)
+	if err != nil {
+		slog.Error("获取定时任务运行锁失败", "task_id", taskID, "error", err)
+		return
+	}
+	if !ok {
+		return
+	}
+	s.finish(task, s.start(task, TaskTriggerSchedule, 0))
+}
+
+// 计算本次计划时间，以及判断该计划是否已运行的界限：上次运行时间早于界限才运行。
+// 各实例的触发时刻可能略有偏差，按计划时间而不是当前时间判断，保证同一计划只运行一次
+func fireTime(schedule cron.Schedule, now time.Time) (time.Time, time.Time) {
+	if every, ok := schedule.(cron.ConstantDelaySchedule); ok {
+		fire := now.Truncate(time.Second)
+		return fire, fire.Add(-every.Delay / 2)
+	}
+
+	fire := now.Truncate(time.Second)
+	for t := schedule.Next(now.Add(-time.Minute)); !t.After(now); t = schedule.Next(t) {
+		fire = t
+	}
+	return fire, fire
+}
+
+// 获取任务运行锁，运行锁未过期时其他实例不能运行该任务；按计划运行时同时记录计划时间
+func (s *taskScheduler) acquire(taskID uint, fire *time.Time, since time.Time) (*model.ScheduledTask, bool, error) {
+	now := time.Now()
+	updates := map[string]interface{}{
+		"locked_by":    s.instance,
+		"locked_until": now.Add(s.lockTTL),
+		"last_status":  model.TaskRunRunning,
+	}
+	query := s.db.Model(&model.ScheduledTask{}).Where("id = ? AND (locked_until IS NULL OR locked_until < ?)", taskID, now)
+	if fire != nil {
+		updates["last_run_at"] = *fire
+		query = query.Where("status = ? AND (last_run_at IS NULL OR last_run_at < ?)", 1, since)
+	}
+	result := query.Updates(updates)
+	if result.Error != nil || result.RowsAffected == 0 {
+		return nil, false, result.Error
+	}
+
+	var task model.ScheduledTask
+	if err := s.db.First(&task, taskID).Error; err != nil {
+		s.release(taskID, model.TaskRunFailed)
+		return nil, false, err
+	}
+	return &task, true, nil
+}
+
+// 释放运行锁并记录运行结果
+func (s *taskScheduler) release(taskID uint, status int) {
+	err := s.db.Model(&model.ScheduledTask{}).
+		Where("id = ? AND locked_by = ?", taskID, s.instance).
+		Updates(map[string]interface{}{"locked_by": "", "locked_until": nil, "last_status": status}).Error
+	if err != nil {
+		slog.Error("释放定时任务运行锁失败", "task_id", taskID, "error", err)
+	}
+}
+
+// 创建运行记录
+func (s *taskScheduler) start(task *model.ScheduledTask, trigger string, userID uint) *model.ScheduledTaskRun {
+	run := &model.ScheduledTaskRun{
+		TaskID:      task.ID,
+		Command:     task.Command,
+		TriggerType: trigger,
+		TriggeredBy: userID,
+		Instance:    s.instance,
+		Status:      model.TaskRunRunning,
+		StartedAt:   time.Now(),
+	}
+	if err := s.db.Create(run).Error; err != nil {
+		slog.Error("创建定时任务运行记录失败", "task", task.Name, "error", err)
+	}
+	return run
+}
+
+// 执行任务，记录输出、错误和耗时后释放运行锁
+func (s *taskScheduler) finish(task *model.ScheduledTask, run *model.ScheduledTaskRun) {
+	output, err := s.invoke(task)
+
+	finishedAt := time.Now()
+	run.FinishedAt = &finishedAt
+	run.Duration = finishedAt.Sub(run.StartedAt).Milliseconds()
+	run.Output = truncateRunes(output, taskRunMaxOutput)
+	run.Status = model.TaskRunSuccess
+	if err != nil {
+		run.Status = model.TaskRunFailed
+		run.Error = truncateRunes(err.Error(), taskRunMaxOutput)
+		slog.Error("定时任务运行失败", "task", task.Name, "run_id", run.ID, "error", err)
+	}
+
+	if run.ID != 0 {
+		if err := s.db.Model(run).Select("status", "output", "error", "finished_at", "duration").Updates(run).Error; err != nil {
+			slog.Error("更新定时任务运行记录失败", "task", task.Name, "run_id", run.ID, "error", err)
+		}
+	}
+	s.release(task.ID, run.Status)
+}
+
+func (s *taskScheduler) invoke(task *model.ScheduledTask) (output string, err error) {
+	handler, ok := lookupJob(task.Command)
+	if !ok {
+		return "", fmt.Errorf("%w: %s", ErrTaskJobNotFound, task.Command)
+	}
+
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("panic: %v", r)
+		}
+	}()
+	return handler(s.ctx, s.db, task.Params)
+}
+
+// 校验定时任务的cron表达式、任务名称和状态，更新时只校验传入的字段
+func validateScheduledTask(task *model.ScheduledTask, partial bool) error {
+	if task.Cron != "" || !partial {
+		if _, err := cronParser.Parse(task.Cron); err != nil {
+			return fmt.Errorf("%w: %v", ErrTaskCronInvalid, err)
+		}
+	}
+	if task.Command != "" || !partial {
+		if _, ok := lookupJob(task.Command); !ok {
+			return ErrTaskJobNotFound
+		}
+	}
+	if task.Status != 0 && task.Status != 1 && task.Status != 2 {
+		return ErrTaskStatusInvalid
+	}
+	return nil
+}
+
+// RunScheduledTask 立即运行定时任务，任务在后台执行，通过返回的运行记录查看结果；任务正在运行时返回 ErrTaskRunning
+func (s *SystemService) RunScheduledTask(id, userID uint) (*model.ScheduledTaskRun, error) {
+	if scheduler == nil {
+		return nil, ErrSchedulerStopped
+	}
+
+	var task model.ScheduledTask
+	if err := s.db.First(&task, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrTaskNotFound
+		}
+		return nil, err
+	}
+	if _, ok := lookupJob(task.Command); !ok {
+		return nil, ErrTaskJobNotFound
+	}
+
+	locked, ok, err := scheduler.acquire(id, nil, time.Time{})
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, ErrTaskRunning
+	}
+
+	run := scheduler.start(locked, TaskTriggerManual, userID)
+	scheduler.running.Add(1)
+	go func() {
+		defer scheduler.running.Done()
+		scheduler.finish(locked, run)
+	}()
+	return run, nil
+}
+
+// GetScheduledTaskRunList 获取定时任务运行记录
+func (s *SystemService) GetScheduledTaskRunList(taskID uint, status, page, pageSize int) ([]model.ScheduledTaskRun, int64, error) {
+	var runs []model.ScheduledTaskRun
+	var total int64
+
+	query := s.db.Model(&model.ScheduledTaskRun{}).Where("task_id = ?", taskID)
+	if status > 0 {
+		query = query.Where("status = ?", status)
+	}
+
+	err := query.Count(&total).Error
+	if err != nil {
+		return nil, 0, err
+	}
+
+	err = query.Order("id desc").Offset((page - 1) * pageSize).Limit(pageSize).Find(&runs).Error
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return runs, total, nil
+}
@@ -1,6 +1,7 @@
 package service
 
 import (
+	"context"
 	"errors"
 	"time"
 
@@ -17,12 +18,18 @@ func NewSealService(db *gorm.DB) *SealService {
 	return &SealService{db: db}
 }
 
+// WithContext 返回使用请求上下文的服务，写入数据时由数据库回调填充当前操作用户
+func (s *SealService) WithContext(ctx context.Context) *SealService {
+	return &SealService{db: s.db.WithContext(ctx)}
+}
+
 // GetSealList 获取印章列表
-func (s *SealService) GetSealList(typeID uint, status int, keyword string, page, pageSize int) ([]model.Seal, int64, error) {
+func (s *SealService) GetSealList(typeID uint, status int, keyword string, page, pageSize int, scope *DataScope) ([]model.Seal, int64, error) {
 	var seals []model.Seal
 	var total int64
 
-	query := s.db.Model(&model.Seal{})
+	query := s.db.Model(&model.Seal{}).
+		Scopes(scope.Filter(sealScope))
 	if typeID > 0 {
 		query = query.Where("type_id = ?", typeID)
 	}
@@ -47,9 +54,9 @@ func (s *SealService) GetSealList(typeID uint, status int, keyword string, page,
 }
 
 // GetSealByID 根据ID获取印章
-func (s *SealService) GetSealByID(id uint) (*model.Seal, error) {
+func (s *SealService) GetSealByID(id uint, scope *DataScope) (*model.Seal, error) {
 	var seal model.Seal
-	err := s.db.First(&seal, id).Error
+	err := s.db.Scopes(scope.Filter(sealScope)).First(&seal, id).Error
 	if err != nil {
 		return nil, err
 	}
@@ -109,11 +116,12 @@ func (s *SealService) DeleteSeal(id uint) error {
 }
 
 // GetSealApplicationList 获取用印申请列表
-func (s *SealService) GetSealApplicationList(sealID, userID uint, status int, page, pageSize int) ([]model.SealApplication, int64, error) {
+func (s *SealService) GetSealApplicationList(sealID, userID uint, status int, page, pageSize int, scope *DataScope) ([]model.SealApplication, int64, error) {
 	var applications []model.SealApplication
 	var total int64
 
-	query := s.db.Model(&model.SealApplication{})
+	query := s.db.Model(&model.SealApplication{}).
+		Scopes(scope.Filter(sealApplicationScope))
 	if sealID > 0 {
 		query = query.Where("seal_id = ?", sealID)
 	}
@@ -138,9 +146,9 @@ func (s *SealService) GetSealApplicationList(sealID, userID uint, status int, pa
 }
 
 // GetSealApplicationByID 根据ID获取用印申请
-func (s *SealService) GetSealApplicationByID(id uint) (*model.SealApplication, error) {
+func (s *SealService) GetSealApplicationByID(id uint, scope *DataScope) (*model.SealApplication, error) {
 	var application model.SealApplication
-	err := s.db.First(&application, id).Error
+	err := s.db.Scopes(scope.Filter(sealApplicationScope)).First(&application, id).Error
 	if err != nil {
 		return nil, err
 	}
@@ -306,11 +314,12 @@ func (s *SealService) CancelSealApplication(id uint) error {
 }
 
 // GetSealRecordList 获取用印记录列表
-func (s *SealService) GetSealRecordList(applicationID uint, status int, page, pageSize int) ([]model.SealRecord, int64, error) {
+func (s *SealService) GetSealRecordList(applicationID uint, status int, page, pageSize int, scope *DataScope) ([]model.SealRecord, int64, error) {
 	var records []model.SealRecord
 	var total int64
 
-	query := s.db.Model(&model.SealRecord{})
+	query := s.db.Model(&model.SealRecord{}).
+		Scopes(scope.Filter(sealRecordScope))
 	if applicationID > 0 {
 		query = query.Where("application_id = ?", applicationID)
 	}
@@ -332,9 +341,9 @@ func (s *SealService) GetSealRecordList(applicationID uint, status int, page, pa
 }
 
 // GetSealRecordByID 根据ID获取用印记录
-func (s *SealService) GetSealRecordByID(id uint) (*model.SealRecord, error) {
+func (s *SealService) GetSealRecordByID(id uint, scope *DataScope) (*model.SealRecord, error) {
 	var record model.SealRecord
-	err := s.db.First(&record, id).Error
+	err := s.db.Scopes(scope.Filter(sealRecordScope)).First(&record, id).Error
 	if err != nil {
 		return nil, err
 	}
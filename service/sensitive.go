@@ -0,0 +1,104 @@
+package service
+
+import (
+	"reflect"
+	"strings"
+)
+
+// 敏感字段通过结构体标签 `mask:"类型"` 标记，没有查看敏感信息权限时按类型脱敏后返回
+const (
+	maskMobile  = "mobile"  // 手机号，保留前3位和后4位
+	maskIDCard  = "id_card" // 身份证号，保留前3位和后4位
+	maskAddress = "address" // 地址，保留前6个字
+	maskDate    = "date"    // 日期，整体隐藏
+)
+
+// MaskSensitive 对数据中的敏感字段脱敏，支持结构体、指针、切片及嵌套结构，返回脱敏的字段数
+func MaskSensitive(v interface{}) int {
+	return walkSensitive(reflect.ValueOf(v), maskValue)
+}
+
+// CountSensitive 统计数据中有值的敏感字段数，用于判断是否需要记录敏感信息查看日志
+func CountSensitive(v interface{}) int {
+	return walkSensitive(reflect.ValueOf(v), func(reflect.Value, string) {})
+}
+
+// 遍历有值的敏感字段
+func walkSensitive(v reflect.Value, fn func(field reflect.Value, kind string)) int {
+	switch v.Kind() {
+	case reflect.Ptr, reflect.Interface:
+		if v.IsNil() {
+			return 0
+		}
+		return walkSensitive(v.Elem(), fn)
+	case reflect.Slice, reflect.Array:
+		count := 0
+		for i := 0; i < v.Len(); i++ {
+			count += walkSensitive(v.Index(i), fn)
+		}
+		return count
+	case reflect.Map:
+		count := 0
+		for _, key := range v.MapKeys() {
+			count += walkSensitive(v.MapIndex(key), fn)
+		}
+		return count
+	case reflect.Struct:
+		count := 0
+		t := v.Type()
+		for i := 0; i < t.NumField(); i++ {
+			if !t.Field(i).IsExported() {
+				continue
+			}
+			field := v.Field(i)
+			kind := t.Field(i).Tag.Get("mask")
+			if kind == "" {
+				count += walkSensitive(field, fn)
+				continue
+			}
+			if field.IsZero() || !field.CanSet() {
+				continue
+			}
+			fn(field, kind)
+			count++
+		}
+		return count
+	}
+	return 0
+}
+
+// ClearMaskedSensitive 清除敏感字段中提交回来的脱敏值(含星号)，更新前调用，
+// 避免没有查看权限的调用方把列表或详情中的脱敏结果写回数据库；按结构体更新时零值字段不会被修改
+func ClearMaskedSensitive(v interface{}) {
+	walkSensitive(reflect.ValueOf(v), func(field reflect.Value, kind string) {
+		if field.Kind() == reflect.String && strings.Contains(field.String(), "*") {
+			field.SetString("")
+		}
+	})
+}
+
+func maskValue(field reflect.Value, kind string) {
+	if field.Kind() != reflect.String {
+		// 日期等非字符串字段整体隐藏
+		field.Set(reflect.Zero(field.Type()))
+		return
+	}
+
+	runes := []rune(field.String())
+	switch kind {
+	case maskMobile, maskIDCard:
+		field.SetString(maskMiddle(runes, 3, 4))
+	case maskAddress:
+		field.SetString(maskMiddle(runes, 6, 0))
+	default:
+		field.SetString(strings.Repeat("*", len(runes)))
+	}
+}
+
+// 保留开头和结尾的字符，中间替换为星号，过短时全部替换
+func maskMiddle(runes []rune, head, tail int) string {
+	if len(runes) <= head+tail {
+		return strings.Repeat("*", len(runes))
+	}
+	return string(runes[:head]) + strings.Repeat("*", len(runes)-head-tail) + string(runes[len(runes)-tail:])
+}
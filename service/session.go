@@ -0,0 +1,187 @@
+package service
+
+import (
+	"errors"
+	"time"
+
+	"github.com/lemonoa/LemonOA-Go/model"
+
+	"github.com/spf13/viper"
+	"gorm.io/gorm"
+)
+
+// 会话最近活跃时间的更新间隔，避免每次请求都写库
+const sessionTouchInterval = time.Minute
+
+var ErrSessionRevoked = errors.New("会话已失效，请重新登录")
+
+// 登录成功后创建会话
+func (s *AuthService) createSession(db *gorm.DB, userID uint, sessionID, ip, userAgent string) error {
+	now := time.Now()
+	session := &model.UserSession{
+		UserID:     userID,
+		SessionID:  sessionID,
+		IP:         ip,
+		UserAgent:  userAgent,
+		IssuedAt:   now,
+		LastSeenAt: now,
+		ExpiresAt:  now.Add(time.Duration(viper.GetInt("jwt.refresh_expire")) * time.Second),
+	}
+	return db.Create(session).Error
+}
+
+// 刷新令牌时续期会话，会话已吊销时返回错误
+// 会话功能上线前签发的刷新令牌没有对应的会话，首次刷新时补建
+func (s *AuthService) renewSession(db *gorm.DB, userID uint, sessionID, ip, userAgent string) error {
+	var session model.UserSession
+	err := db.Where("session_id = ?", sessionID).First(&session).Error
+	if err == gorm.ErrRecordNotFound {
+		return s.createSession(db, userID, sessionID, ip, userAgent)
+	}
+	if err != nil {
+		return err
+	}
+	if session.RevokedAt != nil {
+		return ErrSessionRevoked
+	}
+
+	now := time.Now()
+	if err := db.Model(&session).Updates(map[string]interface{}{
+		"ip":           ip,
+		"last_seen_at": now,
+		"expires_at":   now.Add(time.Duration(viper.GetInt("jwt.refresh_expire")) * time.Second),
+	}).Error; err != nil {
+		return err
+	}
+	invalidateTokenSessions(sessionID)
+	return nil
+}
+
+// 吊销会话，同时使会话状态缓存失效
+func (s *AuthService) revokeSessions(db *gorm.DB, query interface{}, args ...interface{}) error {
+	var sessionIDs []string
+	if err := db.Model(&model.UserSession{}).
+		Where(query, args...).
+		Where("revoked_at IS NULL").
+		Pluck("session_id", &sessionIDs).Error; err != nil {
+		return err
+	}
+	if len(sessionIDs) == 0 {
+		return nil
+	}
+	if err := db.Model(&model.UserSession{}).
+		Where("session_id IN ? AND revoked_at IS NULL", sessionIDs).
+		Update("revoked_at", time.Now()).Error; err != nil {
+		return err
+	}
+	invalidateTokenSessions(sessionIDs...)
+	return nil
+}
+
+// ValidateSession 校验访问令牌所属的会话未被吊销，会话状态经缓存读取
+// 最近活跃时间和IP每隔 sessionTouchInterval 才写入一次数据库
+func ValidateSession(db *gorm.DB, sessionID, ip string) error {
+	key := tokenStateSessionKeyPrefix + sessionID
+	var state tokenSessionState
+	if !tokenStateCache.Get(key, &state) {
+		var session model.UserSession
+		err := db.Select("id", "revoked_at", "expires_at", "last_seen_at", "ip").
+			Where("session_id = ?", sessionID).
+			First(&session).Error
+		switch {
+		case err == gorm.ErrRecordNotFound:
+			state = tokenSessionState{Revoked: true}
+		case err != nil:
+			return err
+		default:
+			state = tokenSessionState{
+				Revoked:    session.RevokedAt != nil,
+				ExpiresAt:  session.ExpiresAt,
+				LastSeenAt: session.LastSeenAt,
+				IP:         session.IP,
+			}
+		}
+		tokenStateCache.Set(key, state)
+	}
+
+	now := time.Now()
+	if state.Revoked || now.After(state.ExpiresAt) {
+		return ErrSessionRevoked
+	}
+
+	if now.Sub(state.LastSeenAt) >= sessionTouchInterval {
+		db.Model(&model.UserSession{}).
+			Where("session_id = ? AND revoked_at IS NULL", sessionID).
+			UpdateColumns(map[string]interface{}{
+				"last_seen_at": now,
+				"ip":           ip,
+			})
+		state.LastSeenAt = now
+		state.IP = ip
+		tokenStateCache.Set(key, state)
+	}
+	return nil
+}
+
+// GetMySessions 获取用户当前有效的会话，即"我的设备"，currentSessionID 对应的会话标记为当前会话
+func (s *AuthService) GetMySessions(userID uint, currentSessionID string) ([]model.UserSession, error) {
+	var sessions []model.UserSession
+	err := s.db.Where("user_id = ? AND revoked_at IS NULL AND expires_at > ?", userID, time.Now()).
+		Order("last_seen_at DESC").
+		Find(&sessions).Error
+	for i := range sessions {
+		sessions[i].Current = sessions[i].SessionID == currentSessionID
+	}
+	return sessions, err
+}
+
+// RevokeMySession 用户注销自己的某个会话
+func (s *AuthService) RevokeMySession(userID, id uint) error {
+	var session model.UserSession
+	if err := s.db.Where("id = ? AND user_id = ?", id, userID).First(&session).Error; err != nil {
+		return err
+	}
+	return s.terminateSession(&session)
+}
+
+// GetSessionList 获取会话列表，默认只返回有效的会话
+func (s *AuthService) GetSessionList(userID uint, includeRevoked bool, page, pageSize int) ([]model.UserSession, int64, error) {
+	var sessions []model.UserSession
+	var total int64
+
+	query := s.db.Model(&model.UserSession{})
+	if userID > 0 {
+		query = query.Where("user_id = ?", userID)
+	}
+	if !includeRevoked {
+		query = query.Where("revoked_at IS NULL AND expires_at > ?", time.Now())
+	}
+
+	err := query.Count(&total).Error
+	if err != nil {
+		return nil, 0, err
+	}
+
+	err = query.Order("last_seen_at DESC").Offset((page - 1) * pageSize).Limit(pageSize).Find(&sessions).Error
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return sessions, total, nil
+}
+
+// RevokeSession 管理员终止任意会话
+func (s *AuthService) RevokeSession(id uint) error {
+	var session model.UserSession
+	if err := s.db.First(&session, id).Error; err != nil {
+		return err
+	}
+	return s.terminateSession(&session)
+}
+
+// 终止会话：会话和刷新令牌族同时吊销，该会话的访问令牌由JWT中间件拒绝
+func (s *AuthService) terminateSession(session *model.UserSession) error {
+	return s.db.Transaction(func(tx *gorm.DB) error {
+		return s.revokeRefreshTokenFamily(tx, session.SessionID)
+	})
+}
@@ -0,0 +1,158 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/viper"
+)
+
+// 存储方式
+const (
+	StorageLocal = "local"
+	StorageS3    = "s3"
+)
+
+var ErrStorageObjectNotFound = errors.New("文件不存在")
+
+// Storage 附件存储后端，key 为存储路径，由附件服务按内容哈希生成
+type Storage interface {
+	// Name 存储方式，记录在附件中
+	Name() string
+	// Put 保存文件，key 已存在时覆盖
+	Put(ctx context.Context, key string, r io.Reader, size int64, contentType string) error
+	// Open 打开文件用于读取，支持Seek以响应Range请求
+	Open(ctx context.Context, key string) (io.ReadSeekCloser, error)
+	// Delete 删除文件，文件不存在时不报错
+	Delete(ctx context.Context, key string) error
+	// Ping 检查存储是否可用，用于就绪检查
+	Ping(ctx context.Context) error
+}
+
+var fileStorage Storage
+
+// InitStorage 按 upload.storage 配置初始化附件存储：local 保存到 upload.save_path，s3 保存到S3兼容的对象存储(如MinIO)
+func InitStorage() error {
+	switch storage := viper.GetString("upload.storage"); storage {
+	case "", StorageLocal:
+		local, err := NewLocalStorage(uploadSavePath())
+		if err != nil {
+			return err
+		}
+		fileStorage = local
+	case StorageS3:
+		s3, err := NewS3Storage(loadS3Config())
+		if err != nil {
+			return err
+		}
+		fileStorage = s3
+	default:
+		return fmt.Errorf("unsupported upload storage %q", storage)
+	}
+	return nil
+}
+
+func uploadSavePath() string {
+	if path := viper.GetString("upload.save_path"); path != "" {
+		return path
+	}
+	return "./uploads"
+}
+
+// LocalStorage 本地磁盘存储
+type LocalStorage struct {
+	root string
+}
+
+// NewLocalStorage 创建本地磁盘存储，root 为保存目录
+func NewLocalStorage(root string) (*LocalStorage, error) {
+	if err := os.MkdirAll(root, 0o755); err != nil {
+		return nil, err
+	}
+	return &LocalStorage{root: root}, nil
+}
+
+// Name 存储方式
+func (s *LocalStorage) Name() string {
+	return StorageLocal
+}
+
+// Ping 检查保存目录是否存在
+func (s *LocalStorage) Ping(ctx context.Context) error {
+	info, err := os.Stat(s.root)
+	if err != nil {
+		return err
+	}
+	if !info.IsDir() {
+		return fmt.Errorf("%s is not a directory", s.root)
+	}
+	return nil
+}
+
+// 存储路径转换为本地路径，不允许越出保存目录
+func (s *LocalStorage) path(key string) (string, error) {
+	clean := filepath.Clean("/" + key)
+	if clean == "/" || strings.Contains(key, "..") {
+		return "", fmt.Errorf("invalid storage key %q", key)
+	}
+	return filepath.Join(s.root, filepath.FromSlash(clean)), nil
+}
+
+// Put 先写入临时文件再重命名，避免读取到写了一半的文件
+func (s *LocalStorage) Put(ctx context.Context, key string, r io.Reader, size int64, contentType string) error {
+	path, err := s.path(key)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".upload-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := io.Copy(tmp, r); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+// Open 打开文件
+func (s *LocalStorage) Open(ctx context.Context, key string) (io.ReadSeekCloser, error) {
+	path, err := s.path(key)
+	if err != nil {
+		return nil, err
+	}
+	file, err := os.Open(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, ErrStorageObjectNotFound
+		}
+		return nil, err
+	}
+	return file, nil
+}
+
+// Delete 删除文件
+func (s *LocalStorage) Delete(ctx context.Context, key string) error {
+	path, err := s.path(key)
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(path); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,115 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/minio/minio-go/v7"
+	"github.com/minio/minio-go/v7/pkg/credentials"
+	"github.com/spf13/viper"
+)
+
+// s3Config S3兼容存储配置，对应 config.yaml 中的 upload.s3 节点
+type s3Config struct {
+	Endpoint  string
+	AccessKey string
+	SecretKey string
+	Bucket    string
+	Region    string
+	UseSSL    bool
+}
+
+func loadS3Config() s3Config {
+	return s3Config{
+		Endpoint:  viper.GetString("upload.s3.endpoint"),
+		AccessKey: viper.GetString("upload.s3.access_key"),
+		SecretKey: viper.GetString("upload.s3.secret_key"),
+		Bucket:    viper.GetString("upload.s3.bucket"),
+		Region:    viper.GetString("upload.s3.region"),
+		UseSSL:    viper.GetBool("upload.s3.use_ssl"),
+	}
+}
+
+// S3Storage S3兼容的对象存储，如MinIO、AWS S3
+type S3Storage struct {
+	client *minio.Client
+	bucket string
+}
+
+// NewS3Storage 创建S3存储，存储桶不存在时自动创建
+func NewS3Storage(cfg s3Config) (*S3Storage, error) {
+	if cfg.Endpoint == "" || cfg.Bucket == "" {
+		return nil, errors.New("upload.s3.endpoint and upload.s3.bucket are required")
+	}
+
+	client, err := minio.New(cfg.Endpoint, &minio.Options{
+		Creds:  credentials.NewStaticV4(cfg.AccessKey, cfg.SecretKey, ""),
+		Secure: cfg.UseSSL,
+		Region: cfg.Region,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	exists, err := client.BucketExists(ctx, cfg.Bucket)
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		if err := client.MakeBucket(ctx, cfg.Bucket, minio.MakeBucketOptions{Region: cfg.Region}); err != nil {
+			return nil, err
+		}
+	}
+
+	return &S3Storage{client: client, bucket: cfg.Bucket}, nil
+}
+
+// Name 存储方式
+func (s *S3Storage) Name() string {
+	return StorageS3
+}
+
+// Ping 检查存储桶是否可访问
+func (s *S3Storage) Ping(ctx context.Context) error {
+	exists, err := s.client.BucketExists(ctx, s.bucket)
+	if err != nil {
+		return err
+	}
+	if !exists {
+		return fmt.Errorf("bucket %s does not exist", s.bucket)
+	}
+	return nil
+}
+
+// Put 上传文件
+func (s *S3Storage) Put(ctx context.Context, key string, r io.Reader, size int64, contentType string) error {
+	_, err := s.client.PutObject(ctx, s.bucket, key, r, size, minio.PutObjectOptions{ContentType: contentType})
+	return err
+}
+
+// Open 打开文件，返回的对象支持Seek，按需分段下载
+func (s *S3Storage) Open(ctx context.Context, key string) (io.ReadSeekCloser, error) {
+	object, err := s.client.GetObject(ctx, s.bucket, key, minio.GetObjectOptions{})
+	if err != nil {
+		return nil, err
+	}
+	// GetObject不会立即请求，通过Stat确认对象存在
+	if _, err := object.Stat(); err != nil {
+		object.Close()
+		if minio.ToErrorResponse(err).Code == "NoSuchKey" {
+			return nil, ErrStorageObjectNotFound
+		}
+		return nil, err
+	}
+	return object, nil
+}
+
+// Delete 删除文件
+func (s *S3Storage) Delete(ctx context.Context, key string) error {
+	return s.client.RemoveObject(ctx, s.bucket, key, minio.RemoveObjectOptions{})
+}
@@ -0,0 +1,122 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+	"time"
+)
+
+func TestLocalStoragePath(t *testing.T) {
+	root := t.TempDir()
+	s, err := NewLocalStorage(root)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		key     string
+		want    string
+		wantErr bool
+	}{
+		{key: "ab/cd/abcd.pdf", want: filepath.Join(root, "ab", "cd", "abcd.pdf")},
+		{key: "/ab/abcd.pdf", want: filepath.Join(root, "ab", "abcd.pdf")},
+		{key: "", wantErr: true},
+		{key: "/", wantErr: true},
+		{key: "../etc/passwd", wantErr: true},
+		{key: "ab/../../etc/passwd", wantErr: true},
+		{key: "ab/..", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.key, func(t *testing.T) {
+			got, err := s.path(tt.key)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("path(%q) error = %v, wantErr %v", tt.key, err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("path(%q) = %q, want %q", tt.key, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLocalStorage(t *testing.T) {
+	s, err := NewLocalStorage(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	testStorage(t, s)
+}
+
+// 需要可用的S3兼容存储，如本地启动的MinIO：
+// LEMONOA_TEST_S3_ENDPOINT=127.0.0.1:9000 LEMONOA_TEST_S3_ACCESS_KEY=minioadmin LEMONOA_TEST_S3_SECRET_KEY=minioadmin
+func TestS3Storage(t *testing.T) {
+	endpoint := os.Getenv("LEMONOA_TEST_S3_ENDPOINT")
+	if endpoint == "" {
+		t.Skip("LEMONOA_TEST_S3_ENDPOINT is not set")
+	}
+	s, err := NewS3Storage(s3Config{
+		Endpoint:  endpoint,
+		AccessKey: os.Getenv("LEMONOA_TEST_S3_ACCESS_KEY"),
+		SecretKey: os.Getenv("LEMONOA_TEST_S3_SECRET_KEY"),
+		Bucket:    "lemonoa-test-" + strconv.FormatInt(time.Now().UnixNano(), 36),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		s.client.RemoveBucket(context.Background(), s.bucket)
+	})
+	testStorage(t, s)
+}
+
+// 各存储后端共同的行为：写入、覆盖、Seek读取、删除及删除不存在的文件
+func testStorage(t *testing.T, s Storage) {
+	t.Helper()
+	ctx := context.Background()
+	const key = "ab/cd/abcdef.txt"
+
+	if err := s.Ping(ctx); err != nil {
+		t.Fatalf("Ping() error = %v", err)
+	}
+	if _, err := s.Open(ctx, key); !errors.Is(err, ErrStorageObjectNotFound) {
+		t.Fatalf("Open() missing object error = %v, want %v", err, ErrStorageObjectNotFound)
+	}
+
+	for _, content := range []string{"first version", "0123456789"} {
+		if err := s.Put(ctx, key, bytes.NewBufferString(content), int64(len(content)), "text/plain"); err != nil {
+			t.Fatalf("Put() error = %v", err)
+		}
+	}
+
+	file, err := s.Open(ctx, key)
+	if err != nil {
+		t.Fatalf("Open() error = %v", err)
+	}
+	if _, err := file.Seek(4, io.SeekStart); err != nil {
+		t.Fatalf("Seek() error = %v", err)
+	}
+	got, err := io.ReadAll(file)
+	file.Close()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(got) != "456789" {
+		t.Errorf("content after Seek(4) = %q, want %q", got, "456789")
+	}
+
+	if err := s.Delete(ctx, key); err != nil {
+		t.Fatalf("Delete() error = %v", err)
+	}
+	if _, err := s.Open(ctx, key); !errors.Is(err, ErrStorageObjectNotFound) {
+		t.Errorf("Open() after Delete() error = %v, want %v", err, ErrStorageObjectNotFound)
+	}
+	if err := s.Delete(ctx, key); err != nil {
+		t.Errorf("Delete() missing object error = %v, want nil", err)
+	}
+}
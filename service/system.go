@@ -1,20 +1,33 @@
 package service
 
 import (
+	"context"
+	"encoding/csv"
 	"errors"
+	"io"
+	"strconv"
+	"strings"
 	"time"
 
+	"github.com/lemonoa/LemonOA-Go/database"
 	"github.com/lemonoa/LemonOA-Go/model"
 
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type SystemService struct {
-	db *gorm.DB
+	db   *gorm.DB
+	auth *AuthService // 角色和功能节点统一由认证服务管理
 }
 
 func NewSystemService(db *gorm.DB) *SystemService {
-	return &SystemService{db: db}
+	return &SystemService{db: db, auth: NewAuthService(db)}
+}
+
+// WithContext 返回使用请求上下文的服务，写入数据时由数据库回调填充当前操作用户
+func (s *SystemService) WithContext(ctx context.Context) *SystemService {
+	return &SystemService{db: s.db.WithContext(ctx), auth: s.auth.WithContext(ctx)}
 }
 
 // GetSystemConfigList 获取系统配置列表
@@ -27,7 +40,8 @@ func (s *SystemService) GetSystemConfigList() ([]model.SystemConfig, error) {
 // GetSystemConfigByKey 根据Key获取系统配置
 func (s *SystemService) GetSystemConfigByKey(key string) (*model.SystemConfig, error) {
 	var config model.SystemConfig
-	err := s.db.Where("key = ?", key).First(&config).Error
+	// key 是MySQL保留字，由GORM按数据库转义字段名
+	err := s.db.Where(clause.Eq{Column: clause.Column{Name: "key"}, Value: key}).First(&config).Error
 	if err != nil {
 		return nil, err
 	}
@@ -103,10 +117,10 @@ func (s *SystemService) UpdateModuleConfig(config *model.ModuleConfig) error {
 	return s.db.Model(config).Updates(config).Error
 }
 
-// GetFunctionNodeList 获取功能节点列表
+// GetFunctionNodeList 获取功能节点列表，功能节点即权限
 func (s *SystemService) GetFunctionNodeList(moduleID uint) ([]model.FunctionNode, error) {
 	var nodes []model.FunctionNode
-	query := s.db.Model(&model.FunctionNode{})
+	query := s.db.Model(&model.Permission{})
 	if moduleID > 0 {
 		query = query.Where("module_id = ?", moduleID)
 	}
@@ -116,7 +130,7 @@ func (s *SystemService) GetFunctionNodeList(moduleID uint) ([]model.FunctionNode
 
 // CreateFunctionNode 创建功能节点
 func (s *SystemService) CreateFunctionNode(node *model.FunctionNode) error {
-	return s.db.Create(node).Error
+	return s.auth.CreatePermission(node)
 }
 
 // UpdateFunctionNode 更新功能节点
@@ -124,98 +138,90 @@ func (s *SystemService) UpdateFunctionNode(node *model.FunctionNode) error {
 	if node.ID == 0 {
 		return errors.New("function node id is required")
 	}
-	return s.db.Model(node).Updates(node).Error
+	return s.auth.UpdatePermission(node)
 }
 
 // DeleteFunctionNode 删除功能节点
 func (s *SystemService) DeleteFunctionNode(id uint) error {
-	// 检查是否有角色关联
-	var count int64
-	if err := s.db.Model(&model.RoleFunction{}).Where("function_node_id = ?", id).Count(&count).Error; err != nil {
-		return err
-	}
-	if count > 0 {
-		return errors.New("cannot delete function node with associated roles")
-	}
-
-	return s.db.Delete(&model.FunctionNode{}, id).Error
+	return s.auth.DeletePermission(id)
 }
 
 // GetRoleList 获取角色列表
 func (s *SystemService) GetRoleList() ([]model.Role, error) {
-	var roles []model.Role
-	err := s.db.Find(&roles).Error
-	return roles, err
+	return s.auth.GetRoleList()
 }
 
 // CreateRole 创建角色
 func (s *SystemService) CreateRole(role *model.Role) error {
-	return s.db.Create(role).Error
+	return s.auth.CreateRole(role)
 }
 
 // UpdateRole 更新角色
-func (s *SystemService) UpdateRole(role *model.Role) error {
-	if role.ID == 0 {
-		return errors.New("role id is required")
-	}
-	return s.db.Model(role).Updates(role).Error
+func (s *SystemService) UpdateRole(id uint, req RoleUpdate) (*model.Role, error) {
+	return s.auth.UpdateRole(id, req)
 }
 
 // DeleteRole 删除角色
 func (s *SystemService) DeleteRole(id uint) error {
-	// 删除角色的同时删除角色的功能权限
-	return s.db.Transaction(func(tx *gorm.DB) error {
-		if err := tx.Delete(&model.RoleFunction{}, "role_id = ?", id).Error; err != nil {
-			return err
-		}
-		return tx.Delete(&model.Role{}, id).Error
-	})
+	return s.auth.DeleteRole(id)
 }
 
 // GetRoleFunctions 获取角色的功能权限
 func (s *SystemService) GetRoleFunctions(roleID uint) ([]model.FunctionNode, error) {
-	var nodes []model.FunctionNode
-	err := s.db.Joins("JOIN role_functions ON function_nodes.id = role_functions.function_node_id").
-		Where("role_functions.role_id = ?", roleID).
-		Find(&nodes).Error
-	return nodes, err
+	return s.auth.GetRolePermissions(roleID)
 }
 
 // UpdateRoleFunctions 更新角色的功能权限
 func (s *SystemService) UpdateRoleFunctions(roleID uint, functionNodeIDs []uint) error {
-	return s.db.Transaction(func(tx *gorm.DB) error {
-		// 删除原有的权限
-		if err := tx.Delete(&model.RoleFunction{}, "role_id = ?", roleID).Error; err != nil {
-			return err
-		}
+	return s.auth.UpdateRolePermissions(roleID, functionNodeIDs)
+}
 
-		// 添加新的权限
-		for _, nodeID := range functionNodeIDs {
-			roleFunction := &model.RoleFunction{
-				RoleID:         roleID,
-				FunctionNodeID: nodeID,
-			}
-			if err := tx.Create(roleFunction).Error; err != nil {
-				return err
-			}
-		}
+// OperationLogFilter 操作日志查询条件，列表和导出共用
+type OperationLogFilter struct {
+	UserID    uint       // 实际操作人
+	Module    string     // 模块
+	Action    string     // 操作
+	Method    string     // 请求方法
+	Keyword   string     // 请求路径关键字
+	Failed    bool       // 只查询失败的操作(响应状态码>=400)
+	StartTime *time.Time // 开始时间
+	EndTime   *time.Time // 结束时间
+}
 
-		return nil
-	})
+func (f OperationLogFilter) scope(db *gorm.DB) *gorm.DB {
+	if f.UserID > 0 {
+		db = db.Where("operation_logs.user_id = ?", f.UserID)
+	}
+	if f.Module != "" {
+		db = db.Where("operation_logs.module = ?", f.Module)
+	}
+	if f.Action != "" {
+		db = db.Where("operation_logs.action = ?", f.Action)
+	}
+	if f.Method != "" {
+		db = db.Where("operation_logs.method = ?", strings.ToUpper(f.Method))
+	}
+	if f.Keyword != "" {
+		db = db.Where("operation_logs.path LIKE ?", "%"+f.Keyword+"%")
+	}
+	if f.Failed {
+		db = db.Where("operation_logs.status >= ?", 400)
+	}
+	if f.StartTime != nil {
+		db = db.Where("operation_logs.created_at >= ?", f.StartTime)
+	}
+	if f.EndTime != nil {
+		db = db.Where("operation_logs.created_at < ?", f.EndTime)
+	}
+	return db
 }
 
 // GetOperationLogList 获取操作日志列表
-func (s *SystemService) GetOperationLogList(userID uint, module string, page, pageSize int) ([]model.OperationLog, int64, error) {
+func (s *SystemService) GetOperationLogList(filter OperationLogFilter, page, pageSize int) ([]model.OperationLog, int64, error) {
 	var logs []model.OperationLog
 	var total int64
 
-	query := s.db.Model(&model.OperationLog{})
-	if userID > 0 {
-		query = query.Where("user_id = ?", userID)
-	}
-	if module != "" {
-		query = query.Where("module = ?", module)
-	}
+	query := s.db.Model(&model.OperationLog{}).Scopes(filter.scope)
 
 	err := query.Count(&total).Error
 	if err != nil {
@@ -230,45 +236,76 @@ func (s *SystemService) GetOperationLogList(userID uint, module string, page, pa
 	return logs, total, nil
 }
 
-// CreateOperationLog 创建操作日志
-func (s *SystemService) CreateOperationLog(log *model.OperationLog) error {
-	return s.db.Create(log).Error
-}
-
-// GetAttachmentList 获取附件列表
-func (s *SystemService) GetAttachmentList(module string, relatedID uint, page, pageSize int) ([]model.Attachment, int64, error) {
-	var attachments []model.Attachment
-	var total int64
-
-	query := s.db.Model(&model.Attachment{})
-	if module != "" {
-		query = query.Where("module = ?", module)
+// ExportOperationLogs 按查询条件导出操作日志为CSV，最多导出 operationLogExportLimit 条
+func (s *SystemService) ExportOperationLogs(filter OperationLogFilter, w io.Writer) error {
+	// 写入BOM，避免Excel打开时中文乱码
+	if _, err := io.WriteString(w, "\xEF\xBB\xBF"); err != nil {
+		return err
 	}
-	if relatedID > 0 {
-		query = query.Where("related_id = ?", relatedID)
+	writer := csv.NewWriter(w)
+	if err := writer.Write([]string{"时间", "操作人ID", "操作人", "被模拟用户ID", "模块", "操作", "请求方法", "请求路径", "请求参数", "状态码", "结果", "IP", "User-Agent"}); err != nil {
+		return err
 	}
 
-	err := query.Count(&total).Error
-	if err != nil {
-		return nil, 0, err
+	type logRow struct {
+		model.OperationLog
+		Username string
 	}
 
-	err = query.Order("created_at desc").Offset((page - 1) * pageSize).Limit(pageSize).Find(&attachments).Error
-	if err != nil {
-		return nil, 0, err
+	var lastID uint
+	exported := 0
+	for exported < operationLogExportLimit {
+		var rows []logRow
+		err := s.db.Model(&model.OperationLog{}).
+			Select("operation_logs.*, users.username").
+			Joins("LEFT JOIN users ON users.id = operation_logs.user_id").
+			Scopes(filter.scope).
+			Where("operation_logs.id > ?", lastID).
+			Order("operation_logs.id").
+			Limit(min(500, operationLogExportLimit-exported)).
+			Scan(&rows).Error
+		if err != nil {
+			return err
+		}
+		if len(rows) == 0 {
+			break
+		}
+
+		for _, row := range rows {
+			if err := writer.Write([]string{
+				row.CreatedAt.Format("2006-01-02 15:04:05"),
+				strconv.FormatUint(uint64(row.UserID), 10),
+				row.Username,
+				strconv.FormatUint(uint64(row.ImpersonatedUserID), 10),
+				row.Module,
+				row.Action,
+				row.Method,
+				row.Path,
+				row.Params,
+				strconv.Itoa(row.Status),
+				row.Response,
+				row.IP,
+				row.UserAgent,
+			}); err != nil {
+				return err
+			}
+		}
+		lastID = rows[len(rows)-1].ID
+		exported += len(rows)
 	}
 
-	return attachments, total, nil
+	writer.Flush()
+	return writer.Error()
 }
 
-// CreateAttachment 创建附件
-func (s *SystemService) CreateAttachment(attachment *model.Attachment) error {
-	return s.db.Create(attachment).Error
+// CreateOperationLog 创建操作日志
+func (s *SystemService) CreateOperationLog(log *model.OperationLog) error {
+	return s.db.Create(log).Error
 }
 
-// DeleteAttachment 删除附件
-func (s *SystemService) DeleteAttachment(id uint) error {
-	return s.db.Delete(&model.Attachment{}, id).Error
+// ReencryptFields 使用当前密钥重新加密全部敏感字段，轮换密钥后调用，完成后旧密钥才可以从配置中移除
+func (s *SystemService) ReencryptFields() (int64, error) {
+	return database.ReencryptFields(s.db)
 }
 
 // GetBackupRecordList 获取备份记录列表
@@ -289,22 +326,24 @@ func (s *SystemService) GetBackupRecordList(page, pageSize int) ([]model.BackupR
 	return records, total, nil
 }
 
-// CreateBackupRecord 创建备份记录
-func (s *SystemService) CreateBackupRecord(record *model.BackupRecord) error {
-	return s.db.Create(record).Error
-}
-
-// UpdateBackupRecord 更新备份记录
-func (s *SystemService) UpdateBackupRecord(record *model.BackupRecord) error {
-	if record.ID == 0 {
-		return errors.New("backup record id is required")
+// DeleteBackupRecord 删除备份记录及备份文件，仍被增量备份依赖的备份不能删除
+func (s *SystemService) DeleteBackupRecord(id uint) error {
+	record, err := s.findBackup(id)
+	if err != nil {
+		return err
+	}
+	if record.Status == model.BackupStatusRunning {
+		return ErrBackupRunning
 	}
-	return s.db.Model(record).Updates(record).Error
-}
 
-// DeleteBackupRecord 删除备份记录
-func (s *SystemService) DeleteBackupRecord(id uint) error {
-	return s.db.Delete(&model.BackupRecord{}, id).Error
+	var count int64
+	if err := s.db.Model(&model.BackupRecord{}).Where("base_id = ? AND status = ?", id, model.BackupStatusSuccess).Count(&count).Error; err != nil {
+		return err
+	}
+	if count > 0 {
+		return ErrBackupInUse
+	}
+	return s.removeBackup(record)
 }
 
 // GetScheduledTaskList 获取定时任务列表
@@ -316,7 +355,16 @@ func (s *SystemService) GetScheduledTaskList() ([]model.ScheduledTask, error) {
 
 // CreateScheduledTask 创建定时任务
 func (s *SystemService) CreateScheduledTask(task *model.ScheduledTask) error {
-	return s.db.Create(task).Error
+	if err := validateScheduledTask(task, false); err != nil {
+		return err
+	}
+	// 运行状态由调度器维护
+	task.LastRunAt, task.LastStatus, task.LockedBy, task.LockedUntil = nil, 0, "", nil
+	if err := s.db.Create(task).Error; err != nil {
+		return err
+	}
+	reloadScheduler()
+	return nil
 }
 
 // UpdateScheduledTask 更新定时任务
@@ -324,17 +372,35 @@ func (s *SystemService) UpdateScheduledTask(task *model.ScheduledTask) error {
 	if task.ID == 0 {
 		return errors.New("scheduled task id is required")
 	}
-	return s.db.Model(task).Updates(task).Error
+	if err := validateScheduledTask(task, true); err != nil {
+		return err
+	}
+	if err := s.db.Model(task).Omit("last_run_at", "last_status", "locked_by", "locked_until").Updates(task).Error; err != nil {
+		return err
+	}
+	reloadScheduler()
+	return nil
 }
 
 // DeleteScheduledTask 删除定时任务
 func (s *SystemService) DeleteScheduledTask(id uint) error {
-	return s.db.Delete(&model.ScheduledTask{}, id).Error
+	if err := s.db.Delete(&model.ScheduledTask{}, id).Error; err != nil {
+		return err
+	}
+	reloadScheduler()
+	return nil
 }
 
-// UpdateTaskStatus 更新任务状态
+// UpdateTaskStatus 更新任务状态，启用后按计划运行，禁用后不再调度，已在运行的任务不受影响
 func (s *SystemService) UpdateTaskStatus(id uint, status int) error {
-	return s.db.Model(&model.ScheduledTask{}).Where("id = ?", id).Update("status", status).Error
+	if status != 1 && status != 2 {
+		return ErrTaskStatusInvalid
+	}
+	if err := s.db.Model(&model.ScheduledTask{}).Where("id = ?", id).Update("status", status).Error; err != nil {
+		return err
+	}
+	reloadScheduler()
+	return nil
 }
 
 // UpdateTaskLastRunAt 更新任务最后运行时间
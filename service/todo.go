@@ -1,6 +1,7 @@
 package service
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"time"
@@ -18,6 +19,11 @@ func NewTodoService(db *gorm.DB) *TodoService {
 	return &TodoService{db: db}
 }
 
+// WithContext 返回使用请求上下文的服务，写入数据时由数据库回调填充当前操作用户
+func (s *TodoService) WithContext(ctx context.Context) *TodoService {
+	return &TodoService{db: s.db.WithContext(ctx)}
+}
+
 // GetTodoList 获取待办事项列表
 func (s *TodoService) GetTodoList(userID uint, status int, page, pageSize int) ([]model.Todo, int64, error) {
 	var todos []model.Todo
@@ -0,0 +1,224 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/lemonoa/LemonOA-Go/model"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/spf13/viper"
+	"gorm.io/gorm"
+)
+
+var (
+	ErrTokenUserNotFound = errors.New("用户不存在")
+	ErrTokenUserDisabled = errors.New("用户已被禁用")
+	ErrTokenRevoked      = errors.New("token已失效")
+)
+
+// TokenStateCache 访问令牌校验所需的用户状态、令牌吊销及会话状态缓存，值以JSON保存
+// 与权限缓存使用相同的后端，启用Redis时多实例共享，吊销操作对所有实例生效
+type TokenStateCache interface {
+	Get(key string, dest interface{}) bool
+	Set(key string, value interface{})
+	Delete(keys ...string)
+}
+
+var tokenStateCache TokenStateCache = newMemoryTokenStateCache(tokenStateCacheTTL())
+
+// 令牌状态缓存有效期，吊销时会主动失效，有效期只限制缓存与数据库不一致的最长时间
+func tokenStateCacheTTL() time.Duration {
+	if ttl := viper.GetInt("permission_cache.token_state_ttl"); ttl > 0 {
+		return time.Duration(ttl) * time.Second
+	}
+	return time.Minute
+}
+
+const (
+	tokenStateUserKeyPrefix    = "lemonoa:token:user:"
+	tokenStateJTIKeyPrefix     = "lemonoa:token:jti:"
+	tokenStateSessionKeyPrefix = "lemonoa:token:session:"
+)
+
+func tokenStateUserKey(userID uint) string {
+	return fmt.Sprintf("%s%d", tokenStateUserKeyPrefix, userID)
+}
+
+// 用户的启用状态及令牌吊销时间
+type tokenUserState struct {
+	Status         int        `json:"status"`
+	TokenRevokedAt *time.Time `json:"token_revoked_at"`
+}
+
+// 会话状态，会话不存在时视为已吊销
+type tokenSessionState struct {
+	Revoked    bool      `json:"revoked"`
+	ExpiresAt  time.Time `json:"expires_at"`
+	LastSeenAt time.Time `json:"last_seen_at"`
+	IP         string    `json:"ip"`
+}
+
+// CheckAccessToken 校验访问令牌未被吊销：用户存在且已启用、令牌签发于用户令牌吊销时间之后、且未退出登录
+func CheckAccessToken(db *gorm.DB, userID uint, jti string, issuedAt time.Time) error {
+	state, err := loadTokenUserState(db, userID)
+	if err != nil {
+		return err
+	}
+	if state.Status != 1 {
+		return ErrTokenUserDisabled
+	}
+	if state.TokenRevokedAt != nil && issuedAt.Unix() < state.TokenRevokedAt.Unix() {
+		return ErrTokenRevoked
+	}
+
+	key := tokenStateJTIKeyPrefix + jti
+	var revoked bool
+	if !tokenStateCache.Get(key, &revoked) {
+		var count int64
+		if err := db.Model(&model.RevokedToken{}).Where("jti = ?", jti).Count(&count).Error; err != nil {
+			return err
+		}
+		revoked = count > 0
+		tokenStateCache.Set(key, revoked)
+	}
+	if revoked {
+		return ErrTokenRevoked
+	}
+	return nil
+}
+
+func loadTokenUserState(db *gorm.DB, userID uint) (*tokenUserState, error) {
+	key := tokenStateUserKey(userID)
+	var state tokenUserState
+	if tokenStateCache.Get(key, &state) {
+		return &state, nil
+	}
+
+	var user model.User
+	if err := db.Select("id", "status", "token_revoked_at").First(&user, userID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrTokenUserNotFound
+		}
+		return nil, err
+	}
+	state = tokenUserState{Status: user.Status, TokenRevokedAt: user.TokenRevokedAt}
+	tokenStateCache.Set(key, state)
+	return &state, nil
+}
+
+// 使用户状态缓存失效，用户状态或令牌吊销时间变化时调用
+func invalidateTokenUserState(userIDs ...uint) {
+	keys := make([]string, 0, len(userIDs))
+	for _, id := range userIDs {
+		keys = append(keys, tokenStateUserKey(id))
+	}
+	tokenStateCache.Delete(keys...)
+}
+
+// 标记访问令牌已吊销，退出登录时调用
+func markTokenRevoked(jti string) {
+	tokenStateCache.Set(tokenStateJTIKeyPrefix+jti, true)
+}
+
+// 使会话状态缓存失效，会话续期或吊销时调用
+func invalidateTokenSessions(sessionIDs ...string) {
+	keys := make([]string, 0, len(sessionIDs))
+	for _, sid := range sessionIDs {
+		keys = append(keys, tokenStateSessionKeyPrefix+sid)
+	}
+	tokenStateCache.Delete(keys...)
+}
+
+// memoryTokenStateCache 进程内缓存，单实例部署时使用
+type memoryTokenStateCache struct {
+	mu      sync.RWMutex
+	ttl     time.Duration
+	entries map[string]memoryTokenStateEntry
+}
+
+type memoryTokenStateEntry struct {
+	data      []byte
+	expiresAt time.Time
+}
+
+func newMemoryTokenStateCache(ttl time.Duration) *memoryTokenStateCache {
+	return &memoryTokenStateCache{
+		ttl:     ttl,
+		entries: make(map[string]memoryTokenStateEntry),
+	}
+}
+
+func (c *memoryTokenStateCache) Get(key string, dest interface{}) bool {
+	c.mu.RLock()
+	entry, ok := c.entries[key]
+	c.mu.RUnlock()
+	if !ok || time.Now().After(entry.expiresAt) {
+		return false
+	}
+	return json.Unmarshal(entry.data, dest) == nil
+}
+
+func (c *memoryTokenStateCache) Set(key string, value interface{}) {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return
+	}
+	now := time.Now()
+	c.mu.Lock()
+	// 顺带清理过期的缓存，避免已失效的令牌一直占用内存
+	if len(c.entries) >= 10000 {
+		for k, entry := range c.entries {
+			if now.After(entry.expiresAt) {
+				delete(c.entries, k)
+			}
+		}
+	}
+	c.entries[key] = memoryTokenStateEntry{data: data, expiresAt: now.Add(c.ttl)}
+	c.mu.Unlock()
+}
+
+func (c *memoryTokenStateCache) Delete(keys ...string) {
+	c.mu.Lock()
+	for _, key := range keys {
+		delete(c.entries, key)
+	}
+	c.mu.Unlock()
+}
+
+// redisTokenStateCache Redis缓存，多实例部署时共享
+type redisTokenStateCache struct {
+	client *redis.Client
+	ttl    time.Duration
+}
+
+func newRedisTokenStateCache(client *redis.Client, ttl time.Duration) *redisTokenStateCache {
+	return &redisTokenStateCache{client: client, ttl: ttl}
+}
+
+func (c *redisTokenStateCache) Get(key string, dest interface{}) bool {
+	data, err := c.client.Get(context.Background(), key).Bytes()
+	if err != nil {
+		return false
+	}
+	return json.Unmarshal(data, dest) == nil
+}
+
+func (c *redisTokenStateCache) Set(key string, value interface{}) {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return
+	}
+	c.client.Set(context.Background(), key, data, c.ttl)
+}
+
+func (c *redisTokenStateCache) Delete(keys ...string) {
+	if len(keys) == 0 {
+		return
+	}
+	c.client.Del(context.Background(), keys...)
+}
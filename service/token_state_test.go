@@ -0,0 +1,121 @@
+package service
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/lemonoa/LemonOA-Go/model"
+
+	"github.com/spf13/viper"
+)
+
+// 创建认证服务及一个已启用的用户，令牌状态使用独立的进程内缓存
+func newTokenStateTestService(t *testing.T) (*AuthService, *model.User) {
+	t.Helper()
+	s := NewAuthService(newTestDB(t))
+
+	original := tokenStateCache
+	tokenStateCache = newMemoryTokenStateCache(time.Minute)
+	t.Cleanup(func() {
+		tokenStateCache = original
+	})
+
+	user := &model.User{Username: "alice", Password: "x", Status: 1, Source: model.UserSourceLocal}
+	if err := s.db.Create(user).Error; err != nil {
+		t.Fatal(err)
+	}
+	return s, user
+}
+
+func TestCheckAccessTokenRevocation(t *testing.T) {
+	s, user := newTokenStateTestService(t)
+	issuedAt := time.Now().Add(-10 * time.Second)
+
+	// 首次校验后用户状态和吊销记录均已缓存
+	if err := CheckAccessToken(s.db, user.ID, "jti-1", issuedAt); err != nil {
+		t.Fatalf("CheckAccessToken() error = %v", err)
+	}
+
+	if err := s.Logout(user.ID, "jti-1", time.Now().Add(time.Hour), ""); err != nil {
+		t.Fatal(err)
+	}
+	if err := CheckAccessToken(s.db, user.ID, "jti-1", issuedAt); !errors.Is(err, ErrTokenRevoked) {
+		t.Errorf("CheckAccessToken() after Logout error = %v, want %v", err, ErrTokenRevoked)
+	}
+
+	if err := CheckAccessToken(s.db, user.ID, "jti-2", issuedAt); err != nil {
+		t.Fatalf("CheckAccessToken() error = %v", err)
+	}
+	if err := s.ForceLogout(user.ID); err != nil {
+		t.Fatal(err)
+	}
+	if err := CheckAccessToken(s.db, user.ID, "jti-2", issuedAt); !errors.Is(err, ErrTokenRevoked) {
+		t.Errorf("CheckAccessToken() after ForceLogout error = %v, want %v", err, ErrTokenRevoked)
+	}
+
+	if err := s.UpdateUser(&model.User{ID: user.ID, Status: 2}); err != nil {
+		t.Fatal(err)
+	}
+	later := time.Now().Add(time.Minute)
+	if err := CheckAccessToken(s.db, user.ID, "jti-3", later); !errors.Is(err, ErrTokenUserDisabled) {
+		t.Errorf("CheckAccessToken() for disabled user error = %v, want %v", err, ErrTokenUserDisabled)
+	}
+	if err := s.UpdateUser(&model.User{ID: user.ID, Status: 1}); err != nil {
+		t.Fatal(err)
+	}
+	if err := CheckAccessToken(s.db, user.ID, "jti-3", later); err != nil {
+		t.Errorf("CheckAccessToken() after re-enabling error = %v", err)
+	}
+
+	if err := CheckAccessToken(s.db, user.ID+1, "jti-4", later); !errors.Is(err, ErrTokenUserNotFound) {
+		t.Errorf("CheckAccessToken() for missing user error = %v, want %v", err, ErrTokenUserNotFound)
+	}
+}
+
+func TestValidateSession(t *testing.T) {
+	s, user := newTokenStateTestService(t)
+	viper.Set("jwt.refresh_expire", 3600)
+	if err := s.createSession(s.db, user.ID, "sid-1", "10.0.0.1", "test"); err != nil {
+		t.Fatal(err)
+	}
+	lastSeen := func() model.UserSession {
+		var session model.UserSession
+		if err := s.db.Where("session_id = ?", "sid-1").First(&session).Error; err != nil {
+			t.Fatal(err)
+		}
+		return session
+	}
+
+	// 活跃时间在更新间隔内时不写库
+	if err := ValidateSession(s.db, "sid-1", "10.0.0.2"); err != nil {
+		t.Fatalf("ValidateSession() error = %v", err)
+	}
+	if session := lastSeen(); session.IP != "10.0.0.1" {
+		t.Errorf("session IP = %q, want unchanged within the touch interval", session.IP)
+	}
+
+	// 超过更新间隔后写入最近活跃时间和IP
+	stale := time.Now().Add(-2 * sessionTouchInterval)
+	tokenStateCache.Set(tokenStateSessionKeyPrefix+"sid-1", tokenSessionState{
+		ExpiresAt:  time.Now().Add(time.Hour),
+		LastSeenAt: stale,
+		IP:         "10.0.0.1",
+	})
+	if err := ValidateSession(s.db, "sid-1", "10.0.0.3"); err != nil {
+		t.Fatalf("ValidateSession() error = %v", err)
+	}
+	if session := lastSeen(); session.IP != "10.0.0.3" || !session.LastSeenAt.After(stale) {
+		t.Errorf("session = %q/%v, want touched with the new IP", session.IP, session.LastSeenAt)
+	}
+
+	if err := s.RevokeSession(lastSeen().ID); err != nil {
+		t.Fatal(err)
+	}
+	if err := ValidateSession(s.db, "sid-1", "10.0.0.3"); !errors.Is(err, ErrSessionRevoked) {
+		t.Errorf("ValidateSession() after RevokeSession error = %v, want %v", err, ErrSessionRevoked)
+	}
+	if err := ValidateSession(s.db, "sid-missing", "10.0.0.3"); !errors.Is(err, ErrSessionRevoked) {
+		t.Errorf("ValidateSession() for missing session error = %v, want %v", err, ErrSessionRevoked)
+	}
+}
@@ -0,0 +1,464 @@
+package service
+
+import (
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/lemonoa/LemonOA-Go/model"
+
+	"github.com/dgrijalva/jwt-go"
+	"github.com/spf13/viper"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+const (
+	challengeTokenType   = "2fa_challenge"
+	challengeTokenExpire = 5 * time.Minute
+	recoveryCodeCount    = 10
+)
+
+var (
+	ErrInvalid2FACode         = errors.New("两步验证码错误")
+	ErrInvalidChallengeToken  = errors.New("验证请求无效或已过期，请重新登录")
+	Err2FAAlreadyEnabled      = errors.New("两步验证已开启")
+	Err2FANotEnabled          = errors.New("两步验证未开启")
+	Err2FASetupNotStarted     = errors.New("请先生成两步验证密钥")
+	Err2FARequiredByRole      = errors.New("所属角色要求开启两步验证，无法关闭")
+	ErrChallengeSetupRequired = errors.New("请先完成两步验证绑定")
+)
+
+// LoginResult 登录结果，开启两步验证或需要修改密码的用户需凭挑战令牌完成后续步骤
+type LoginResult struct {
+	*TokenPair
+	TwoFactorRequired      bool     `json:"two_factor_required,omitempty"`      // 需要输入两步验证码
+	TwoFactorSetup         bool     `json:"two_factor_setup,omitempty"`         // 所属角色要求两步验证但尚未开启，需先完成绑定
+	PasswordChangeRequired bool     `json:"password_change_required,omitempty"` // 密码已过期或被管理员重置，需先修改密码
+	ChallengeToken         string   `json:"challenge_token,omitempty"`          // 两步验证或修改密码的挑战令牌
+	RecoveryCodes          []string `json:"recovery_codes,omitempty"`           // 登录时完成绑定返回的恢复码
+}
+
+// TOTPSetup 两步验证绑定信息
+type TOTPSetup struct {
+	Secret     string `json:"secret"`      // Base32密钥，用于手动输入
+	OTPAuthURI string `json:"otpauth_uri"` // otpauth URI，用于生成二维码
+}
+
+// VerifyLogin2FA 登录第二步：校验TOTP验证码或恢复码后签发令牌
+func (s *AuthService) VerifyLogin2FA(challengeToken, code, ip, userAgent string) (*LoginResult, error) {
+	challenge, err := s.parseChallengeToken(challengeToken)
+	if err != nil {
+		return nil, err
+	}
+	if challenge.setup {
+		return nil, ErrChallengeSetupRequired
+	}
+
+	user, err := s.loadChallengeUser(challenge.userID)
+	if err != nil {
+		return nil, err
+	}
+	if !user.TOTPEnabled {
+		return nil, ErrInvalidChallengeToken
+	}
+
+	ok, err := s.verifySecondFactor(s.db, user, code)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		s.createLoginLog(user.ID, ip, userAgent, 2, "两步验证码错误")
+		if err := s.recordLoginFailure(loadLoginGuardConfig(), user); err != nil {
+			return nil, err
+		}
+		return nil, ErrInvalid2FACode
+	}
+
+	if err := s.consumeChallengeToken(challenge); err != nil {
+		return nil, err
+	}
+	return s.finishLogin(user, ip, userAgent)
+}
+
+// SetupLogin2FA 角色要求两步验证的用户在登录过程中生成绑定密钥
+func (s *AuthService) SetupLogin2FA(challengeToken string) (*TOTPSetup, error) {
+	challenge, err := s.parseChallengeToken(challengeToken)
+	if err != nil {
+		return nil, err
+	}
+	if !challenge.setup {
+		return nil, ErrInvalidChallengeToken
+	}
+	return s.Setup2FA(challenge.userID)
+}
+
+// ConfirmLogin2FASetup 登录过程中确认两步验证绑定，开启后签发令牌并返回恢复码
+func (s *AuthService) ConfirmLogin2FASetup(challengeToken, code, ip, userAgent string) (*LoginResult, error) {
+	challenge, err := s.parseChallengeToken(challengeToken)
+	if err != nil {
+		return nil, err
+	}
+	if !challenge.setup {
+		return nil, ErrInvalidChallengeToken
+	}
+
+	user, err := s.loadChallengeUser(challenge.userID)
+	if err != nil {
+		return nil, err
+	}
+
+	codes, err := s.Enable2FA(user.ID, code)
+	if err != nil {
+		if err == ErrInvalid2FACode {
+			s.createLoginLog(user.ID, ip, userAgent, 2, "两步验证码错误")
+			if err := s.recordLoginFailure(loadLoginGuardConfig(), user); err != nil {
+				return nil, err
+			}
+		}
+		return nil, err
+	}
+
+	if err := s.consumeChallengeToken(challenge); err != nil {
+		return nil, err
+	}
+	result, err := s.finishLogin(user, ip, userAgent)
+	if err != nil {
+		return nil, err
+	}
+	result.RecoveryCodes = codes
+	return result, nil
+}
+
+// Setup2FA 生成新的TOTP密钥，验证通过后才会正式开启
+func (s *AuthService) Setup2FA(userID uint) (*TOTPSetup, error) {
+	var user model.User
+	if err := s.db.First(&user, userID).Error; err != nil {
+		return nil, err
+	}
+	if user.TOTPEnabled {
+		return nil, Err2FAAlreadyEnabled
+	}
+
+	secret, err := generateTOTPSecret()
+	if err != nil {
+		return nil, err
+	}
+	if err := s.db.Model(&user).Updates(map[string]interface{}{
+		"totp_secret":       secret,
+		"totp_last_counter": 0,
+	}).Error; err != nil {
+		return nil, err
+	}
+
+	return &TOTPSetup{
+		Secret:     secret,
+		OTPAuthURI: totpURI(totpIssuer(), user.Username, secret),
+	}, nil
+}
+
+// Enable2FA 校验验证码并开启两步验证，返回一次性恢复码
+func (s *AuthService) Enable2FA(userID uint, code string) ([]string, error) {
+	var user model.User
+	if err := s.db.First(&user, userID).Error; err != nil {
+		return nil, err
+	}
+	if user.TOTPEnabled {
+		return nil, Err2FAAlreadyEnabled
+	}
+	if user.TOTPSecret == "" {
+		return nil, Err2FASetupNotStarted
+	}
+
+	counter, ok := verifyTOTP(user.TOTPSecret, code, user.TOTPLastCounter, time.Now())
+	if !ok {
+		return nil, ErrInvalid2FACode
+	}
+
+	var codes []string
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&user).Updates(map[string]interface{}{
+			"totp_enabled":      true,
+			"totp_last_counter": counter,
+		}).Error; err != nil {
+			return err
+		}
+
+		var err error
+		codes, err = s.replaceRecoveryCodes(tx, user.ID)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return codes, nil
+}
+
+// Disable2FA 关闭两步验证，需要同时提供密码和验证码
+func (s *AuthService) Disable2FA(userID uint, password, code string) error {
+	var user model.User
+	if err := s.db.First(&user, userID).Error; err != nil {
+		return err
+	}
+	if !user.TOTPEnabled {
+		return Err2FANotEnabled
+	}
+	if ok, _ := VerifyPassword(password, user.Password, user.Salt); !ok {
+		return errors.New("密码错误")
+	}
+
+	required, err := s.requires2FA(user.ID)
+	if err != nil {
+		return err
+	}
+	if required {
+		return Err2FARequiredByRole
+	}
+
+	ok, err := s.verifySecondFactor(s.db, &user, code)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return ErrInvalid2FACode
+	}
+
+	return s.db.Transaction(func(tx *gorm.DB) error {
+		return s.clear2FA(tx, user.ID)
+	})
+}
+
+// RegenerateRecoveryCodes 重新生成恢复码，原有恢复码全部作废
+func (s *AuthService) RegenerateRecoveryCodes(userID uint, code string) ([]string, error) {
+	var user model.User
+	if err := s.db.First(&user, userID).Error; err != nil {
+		return nil, err
+	}
+	if !user.TOTPEnabled {
+		return nil, Err2FANotEnabled
+	}
+
+	counter, ok := verifyTOTP(user.TOTPSecret, code, user.TOTPLastCounter, time.Now())
+	if !ok {
+		return nil, ErrInvalid2FACode
+	}
+
+	var codes []string
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&user).Update("totp_last_counter", counter).Error; err != nil {
+			return err
+		}
+
+		var err error
+		codes, err = s.replaceRecoveryCodes(tx, user.ID)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return codes, nil
+}
+
+// Reset2FA 管理员重置用户的两步验证，用于用户丢失验证设备
+func (s *AuthService) Reset2FA(userID uint) error {
+	return s.db.Transaction(func(tx *gorm.DB) error {
+		if err := s.clear2FA(tx, userID); err != nil {
+			return err
+		}
+		// 已登录的会话需要重新登录
+		return s.revokeUserTokens(tx, userID)
+	})
+}
+
+// 清除用户的两步验证配置及恢复码
+func (s *AuthService) clear2FA(tx *gorm.DB, userID uint) error {
+	if err := tx.Model(&model.User{}).Where("id = ?", userID).Updates(map[string]interface{}{
+		"totp_secret":       "",
+		"totp_enabled":      false,
+		"totp_last_counter": 0,
+	}).Error; err != nil {
+		return err
+	}
+	return tx.Where("user_id = ?", userID).Delete(&model.UserRecoveryCode{}).Error
+}
+
+// 用户所属的启用角色中是否有要求两步验证的角色
+func (s *AuthService) requires2FA(userID uint) (bool, error) {
+	var count int64
+	err := s.db.Model(&model.Role{}).
+		Joins("INNER JOIN user_roles ur ON ur.role_id = roles.id AND ur.deleted_at IS NULL").
+		Where("ur.user_id = ? AND roles.status = 1 AND roles.require_2fa = ?", userID, true).
+		Count(&count).Error
+	return count > 0, err
+}
+
+// 校验TOTP验证码或恢复码，验证码与恢复码均只能使用一次
+func (s *AuthService) verifySecondFactor(db *gorm.DB, user *model.User, code string) (bool, error) {
+	code = strings.TrimSpace(code)
+
+	if counter, ok := verifyTOTP(user.TOTPSecret, code, user.TOTPLastCounter, time.Now()); ok {
+		// 条件更新防止同一验证码被并发请求重复使用
+		result := db.Model(&model.User{}).
+			Where("id = ? AND totp_last_counter < ?", user.ID, counter).
+			Update("totp_last_counter", counter)
+		if result.Error != nil {
+			return false, result.Error
+		}
+		return result.RowsAffected > 0, nil
+	}
+
+	now := time.Now()
+	result := db.Model(&model.UserRecoveryCode{}).
+		Where("user_id = ? AND code_hash = ? AND used_at IS NULL", user.ID, hashToken(normalizeRecoveryCode(code))).
+		Update("used_at", &now)
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected > 0, nil
+}
+
+// 生成新的恢复码并替换原有恢复码，明文只在此时返回一次
+func (s *AuthService) replaceRecoveryCodes(tx *gorm.DB, userID uint) ([]string, error) {
+	if err := tx.Where("user_id = ?", userID).Delete(&model.UserRecoveryCode{}).Error; err != nil {
+		return nil, err
+	}
+
+	codes := make([]string, 0, recoveryCodeCount)
+	records := make([]model.UserRecoveryCode, 0, recoveryCodeCount)
+	for i := 0; i < recoveryCodeCount; i++ {
+		raw, err := randomHex(5)
+		if err != nil {
+			return nil, err
+		}
+		codes = append(codes, raw[:5]+"-"+raw[5:])
+		records = append(records, model.UserRecoveryCode{
+			UserID:   userID,
+			CodeHash: hashToken(raw),
+		})
+	}
+	if err := tx.Create(&records).Error; err != nil {
+		return nil, err
+	}
+
+	return codes, nil
+}
+
+// 恢复码忽略大小写和分隔符
+func normalizeRecoveryCode(code string) string {
+	return strings.ToLower(strings.ReplaceAll(code, "-", ""))
+}
+
+// 两步验证挑战令牌中的信息
+type challengeClaims struct {
+	userID    uint
+	setup     bool
+	jti       string
+	expiresAt time.Time
+}
+
+// 生成两步验证挑战令牌，setup表示用户需先完成两步验证绑定
+func (s *AuthService) generateChallengeToken(userID uint, setup bool) (string, error) {
+	jti, err := randomHex(16)
+	if err != nil {
+		return "", err
+	}
+
+	now := time.Now()
+	claims := jwt.MapClaims{
+		"user_id": userID,
+		"jti":     jti,
+		"typ":     challengeTokenType,
+		"setup":   setup,
+		"iat":     now.Unix(),
+		"exp":     now.Add(challengeTokenExpire).Unix(),
+	}
+
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+	return token.SignedString([]byte(viper.GetString("jwt.secret")))
+}
+
+// 解析两步验证挑战令牌，已使用过的令牌视为无效
+func (s *AuthService) parseChallengeToken(tokenString string) (*challengeClaims, error) {
+	token, err := jwt.Parse(tokenString, func(token *jwt.Token) (interface{}, error) {
+		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
+			return nil, ErrInvalidChallengeToken
+		}
+		return []byte(viper.GetString("jwt.secret")), nil
+	})
+	if err != nil {
+		return nil, ErrInvalidChallengeToken
+	}
+
+	claims, ok := token.Claims.(jwt.MapClaims)
+	if !ok || !token.Valid || claims["typ"] != challengeTokenType {
+		return nil, ErrInvalidChallengeToken
+	}
+	userID, ok := claims["user_id"].(float64)
+	if !ok {
+		return nil, ErrInvalidChallengeToken
+	}
+	jti, _ := claims["jti"].(string)
+	exp, _ := claims["exp"].(float64)
+	if jti == "" || exp == 0 {
+		return nil, ErrInvalidChallengeToken
+	}
+	setup, _ := claims["setup"].(bool)
+
+	var used int64
+	if err := s.db.Model(&model.RevokedToken{}).Where("jti = ?", jti).Count(&used).Error; err != nil {
+		return nil, err
+	}
+	if used > 0 {
+		return nil, ErrInvalidChallengeToken
+	}
+
+	return &challengeClaims{
+		userID:    uint(userID),
+		setup:     setup,
+		jti:       jti,
+		expiresAt: time.Unix(int64(exp), 0),
+	}, nil
+}
+
+// 挑战令牌验证通过后记入吊销表，同一令牌只能完成一次登录，并发使用时只有一个请求成功
+func (s *AuthService) consumeChallengeToken(challenge *challengeClaims) error {
+	result := s.db.Clauses(clause.OnConflict{DoNothing: true}).Create(&model.RevokedToken{
+		JTI:       challenge.jti,
+		UserID:    challenge.userID,
+		ExpiresAt: challenge.expiresAt,
+	})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrInvalidChallengeToken
+	}
+	return nil
+}
+
+// 加载挑战令牌对应的用户，并重新检查锁定和禁用状态
+func (s *AuthService) loadChallengeUser(userID uint) (*model.User, error) {
+	var user model.User
+	if err := s.db.First(&user, userID).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, ErrInvalidChallengeToken
+		}
+		return nil, err
+	}
+	if user.LockedUntil != nil && time.Now().Before(*user.LockedUntil) {
+		return nil, ErrAccountLocked
+	}
+	if user.Status != 1 {
+		return nil, errors.New("用户已被禁用")
+	}
+	return &user, nil
+}
+
+func totpIssuer() string {
+	if issuer := viper.GetString("totp.issuer"); issuer != "" {
+		return issuer
+	}
+	return "LemonOA"
+}
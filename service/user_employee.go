@@ -0,0 +1,114 @@
+package service
+
+import (
+	"errors"
+
+	"github.com/lemonoa/LemonOA-Go/model"
+
+	"gorm.io/gorm"
+)
+
+var (
+	ErrEmployeeNotBound     = errors.New("当前账号未关联员工，请联系管理员")
+	ErrEmployeeNotFound     = errors.New("员工不存在")
+	ErrEmployeeAlreadyBound = errors.New("该员工已关联其他账号")
+)
+
+// GetCurrentEmployee 获取用户关联的员工，未关联或员工已删除、离职时返回 ErrEmployeeNotBound
+func GetCurrentEmployee(db *gorm.DB, userID uint) (*model.Employee, error) {
+	var user model.User
+	if err := db.Select("id", "employee_id").First(&user, userID).Error; err != nil {
+		return nil, err
+	}
+	if user.EmployeeID == nil {
+		return nil, ErrEmployeeNotBound
+	}
+
+	var employee model.Employee
+	if err := db.First(&employee, *user.EmployeeID).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, ErrEmployeeNotBound
+		}
+		return nil, err
+	}
+	if employee.Status != 1 {
+		return nil, ErrEmployeeNotBound
+	}
+	return &employee, nil
+}
+
+// GetMyEmployee 获取当前用户关联的员工
+func (s *AuthService) GetMyEmployee(userID uint) (*model.Employee, error) {
+	return GetCurrentEmployee(s.db, userID)
+}
+
+// GetUserEmployee 获取用户关联的员工，未关联时返回nil
+func (s *AuthService) GetUserEmployee(userID uint) (*model.Employee, error) {
+	var user model.User
+	if err := s.db.Select("id", "employee_id").First(&user, userID).Error; err != nil {
+		return nil, err
+	}
+	if user.EmployeeID == nil {
+		return nil, nil
+	}
+
+	var employee model.Employee
+	if err := s.db.First(&employee, *user.EmployeeID).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &employee, nil
+}
+
+// BindUserEmployee 关联用户和员工，一个员工只能关联一个用户，已有的关联会被替换
+func (s *AuthService) BindUserEmployee(userID, employeeID uint) error {
+	var user model.User
+	if err := s.db.First(&user, userID).Error; err != nil {
+		return err
+	}
+	if user.Type == model.UserTypeService {
+		return errors.New("服务账号不能关联员工")
+	}
+	if err := s.checkEmployeeBindable(s.db, employeeID, userID); err != nil {
+		return err
+	}
+
+	if err := s.db.Model(&user).Update("employee_id", employeeID).Error; err != nil {
+		return err
+	}
+	InvalidateUserPermissions(userID)
+	return nil
+}
+
+// UnbindUserEmployee 解除用户和员工的关联
+func (s *AuthService) UnbindUserEmployee(userID uint) error {
+	if err := s.db.Model(&model.User{}).Where("id = ?", userID).Update("employee_id", nil).Error; err != nil {
+		return err
+	}
+	InvalidateUserPermissions(userID)
+	return nil
+}
+
+// 检查员工存在且未关联其他用户，userID 为当前要关联的用户
+func (s *AuthService) checkEmployeeBindable(db *gorm.DB, employeeID, userID uint) error {
+	var employee model.Employee
+	if err := db.Select("id").First(&employee, employeeID).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return ErrEmployeeNotFound
+		}
+		return err
+	}
+
+	var count int64
+	if err := db.Model(&model.User{}).
+		Where("employee_id = ? AND id <> ?", employeeID, userID).
+		Count(&count).Error; err != nil {
+		return err
+	}
+	if count > 0 {
+		return ErrEmployeeAlreadyBound
+	}
+	return nil
+}
@@ -1,6 +1,7 @@
 package service
 
 import (
+	"context"
 	"errors"
 
 	"github.com/lemonoa/LemonOA-Go/model"
@@ -16,12 +17,18 @@ func NewVehicleService(db *gorm.DB) *VehicleService {
 	return &VehicleService{db: db}
 }
 
+// WithContext 返回使用请求上下文的服务，写入数据时由数据库回调填充当前操作用户
+func (s *VehicleService) WithContext(ctx context.Context) *VehicleService {
+	return &VehicleService{db: s.db.WithContext(ctx)}
+}
+
 // GetVehicleList 获取车辆列表
-func (s *VehicleService) GetVehicleList(status int, keyword string, page, pageSize int) ([]model.Vehicle, int64, error) {
+func (s *VehicleService) GetVehicleList(status int, keyword string, page, pageSize int, scope *DataScope) ([]model.Vehicle, int64, error) {
 	var vehicles []model.Vehicle
 	var total int64
 
-	query := s.db.Model(&model.Vehicle{})
+	query := s.db.Model(&model.Vehicle{}).
+		Scopes(scope.Filter(vehicleScope))
 	if status > 0 {
 		query = query.Where("status = ?", status)
 	}
@@ -43,9 +50,9 @@ func (s *VehicleService) GetVehicleList(status int, keyword string, page, pageSi
 }
 
 // GetVehicleByID 根据ID获取车辆
-func (s *VehicleService) GetVehicleByID(id uint) (*model.Vehicle, error) {
+func (s *VehicleService) GetVehicleByID(id uint, scope *DataScope) (*model.Vehicle, error) {
 	var vehicle model.Vehicle
-	err := s.db.First(&vehicle, id).Error
+	err := s.db.Scopes(scope.Filter(vehicleScope)).First(&vehicle, id).Error
 	if err != nil {
 		return nil, err
 	}
@@ -684,11 +691,12 @@ func (s *VehicleService) HandleVehicleAccident(id uint, status int) error {
 }
 
 // GetVehicleApplicationList 获取用车申请列表
-func (s *VehicleService) GetVehicleApplicationList(vehicleID, userID uint, status int, page, pageSize int) ([]model.VehicleApplication, int64, error) {
+func (s *VehicleService) GetVehicleApplicationList(vehicleID, userID uint, status int, page, pageSize int, scope *DataScope) ([]model.VehicleApplication, int64, error) {
 	var applications []model.VehicleApplication
 	var total int64
 
-	query := s.db.Model(&model.VehicleApplication{})
+	query := s.db.Model(&model.VehicleApplication{}).
+		Scopes(scope.Filter(vehicleApplicationScope))
 	if vehicleID > 0 {
 		query = query.Where("vehicle_id = ?", vehicleID)
 	}
@@ -713,9 +721,9 @@ func (s *VehicleService) GetVehicleApplicationList(vehicleID, userID uint, statu
 }
 
 // GetVehicleApplicationByID 根据ID获取用车申请
-func (s *VehicleService) GetVehicleApplicationByID(id uint) (*model.VehicleApplication, error) {
+func (s *VehicleService) GetVehicleApplicationByID(id uint, scope *DataScope) (*model.VehicleApplication, error) {
 	var application model.VehicleApplication
-	err := s.db.First(&application, id).Error
+	err := s.db.Scopes(scope.Filter(vehicleApplicationScope)).First(&application, id).Error
 	if err != nil {
 		return nil, err
 	}
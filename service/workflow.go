@@ -1,6 +1,7 @@
 package service
 
 import (
+	"context"
 	"errors"
 	"time"
 
@@ -17,6 +18,11 @@ func NewWorkflowService(db *gorm.DB) *WorkflowService {
 	return &WorkflowService{db: db}
 }
 
+// WithContext 返回使用请求上下文的服务，写入数据时由数据库回调填充当前操作用户
+func (s *WorkflowService) WithContext(ctx context.Context) *WorkflowService {
+	return &WorkflowService{db: s.db.WithContext(ctx)}
+}
+
 // GetWorkflowTypeList 获取流程类型列表
 func (s *WorkflowService) GetWorkflowTypeList(status int, keyword string, page, pageSize int) ([]model.WorkflowType, int64, error) {
 	var types []model.WorkflowType